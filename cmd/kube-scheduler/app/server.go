@@ -19,6 +19,7 @@ package app
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -169,6 +170,7 @@ func Run(cc schedulerserverconfig.CompletedConfig, stopCh <-chan struct{}) error
 		cc.InformerFactory.Core().V1().ReplicationControllers(),
 		cc.InformerFactory.Apps().V1().ReplicaSets(),
 		cc.InformerFactory.Apps().V1().StatefulSets(),
+		cc.InformerFactory.Apps().V1().DaemonSets(),
 		cc.InformerFactory.Core().V1().Services(),
 		cc.InformerFactory.Policy().V1beta1().PodDisruptionBudgets(),
 		cc.InformerFactory.Storage().V1().StorageClasses(),
@@ -179,8 +181,15 @@ func Run(cc schedulerserverconfig.CompletedConfig, stopCh <-chan struct{}) error
 		scheduler.WithName(cc.ComponentConfig.SchedulerName),
 		scheduler.WithHardPodAffinitySymmetricWeight(cc.ComponentConfig.HardPodAffinitySymmetricWeight),
 		scheduler.WithPreemptionDisabled(cc.ComponentConfig.DisablePreemption),
+		scheduler.WithPreemptionSystemOnly(cc.ComponentConfig.PreemptionSystemOnly),
 		scheduler.WithPercentageOfNodesToScore(cc.ComponentConfig.PercentageOfNodesToScore),
-		scheduler.WithBindTimeoutSeconds(*cc.ComponentConfig.BindTimeoutSeconds))
+		scheduler.WithBindTimeoutSeconds(*cc.ComponentConfig.BindTimeoutSeconds),
+		scheduler.WithProvisioningBacklogThreshold(cc.ComponentConfig.ProvisioningBacklogThreshold),
+		scheduler.WithMinVictimPriorityDelta(cc.ComponentConfig.MinVictimPriorityDelta),
+		scheduler.WithPreemptionWaitSecondsByPriorityClass(cc.ComponentConfig.PreemptionWaitSecondsByPriorityClass),
+		scheduler.WithMaxPreemptedPodsByPriorityClass(cc.ComponentConfig.MaxPreemptedPodsByPriorityClass),
+		scheduler.WithPodPriorityAgingWindowSeconds(cc.ComponentConfig.PodPriorityAgingWindowSeconds),
+		scheduler.WithPodPriorityAgingMaxBoost(cc.ComponentConfig.PodPriorityAgingMaxBoost))
 	if err != nil {
 		return err
 	}
@@ -200,19 +209,19 @@ func Run(cc schedulerserverconfig.CompletedConfig, stopCh <-chan struct{}) error
 	// Start up the healthz server.
 	if cc.InsecureServing != nil {
 		separateMetrics := cc.InsecureMetricsServing != nil
-		handler := buildHandlerChain(newHealthzHandler(&cc.ComponentConfig, separateMetrics, checks...), nil, nil)
+		handler := buildHandlerChain(newHealthzHandler(&cc.ComponentConfig, sched, separateMetrics, checks...), nil, nil)
 		if err := cc.InsecureServing.Serve(handler, 0, stopCh); err != nil {
 			return fmt.Errorf("failed to start healthz server: %v", err)
 		}
 	}
 	if cc.InsecureMetricsServing != nil {
-		handler := buildHandlerChain(newMetricsHandler(&cc.ComponentConfig), nil, nil)
+		handler := buildHandlerChain(newMetricsHandler(&cc.ComponentConfig, sched), nil, nil)
 		if err := cc.InsecureMetricsServing.Serve(handler, 0, stopCh); err != nil {
 			return fmt.Errorf("failed to start metrics server: %v", err)
 		}
 	}
 	if cc.SecureServing != nil {
-		handler := buildHandlerChain(newHealthzHandler(&cc.ComponentConfig, false, checks...), cc.Authentication.Authenticator, cc.Authorization.Authorizer)
+		handler := buildHandlerChain(newHealthzHandler(&cc.ComponentConfig, sched, false, checks...), cc.Authentication.Authenticator, cc.Authorization.Authorizer)
 		// TODO: handle stoppedCh returned by c.SecureServing.Serve
 		if _, err := cc.SecureServing.Serve(handler, 0, stopCh); err != nil {
 			// fail early for secure handlers, removing the old error loop from above
@@ -294,10 +303,34 @@ func installMetricHandler(pathRecorderMux *mux.PathRecorderMux) {
 	})
 }
 
+// installPendingPodsHandler serves a JSON summary of the scheduling queue's current composition
+// (pending pod counts by priority and by unschedulable reason), so operators diagnosing a
+// capacity incident have a queryable alternative to grepping scheduler logs for the same
+// information the cache debugger already logs on SIGUSR2. Since computing the summary means
+// walking the whole queue, it also republishes it to the PendingPodsByPriority gauge, so the
+// gauge only costs anything on the same requests that already pay for the walk.
+func installPendingPodsHandler(pathRecorderMux *mux.PathRecorderMux, sched *scheduler.Scheduler) {
+	pathRecorderMux.HandleFunc("/debug/pods/pending", func(w http.ResponseWriter, req *http.Request) {
+		summary := sched.Config().SchedulingQueue.PendingPodsSummary()
+
+		byPriority := make(map[string]int, len(summary.ByPriority))
+		for _, band := range summary.ByPriority {
+			byPriority[band.PriorityClassName] += band.Count
+		}
+		metrics.ObservePendingPodsSummary(byPriority)
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(summary); err != nil {
+			klog.Errorf("Error encoding pending pods summary: %v", err)
+		}
+	})
+}
+
 // newMetricsHandler builds a metrics server from the config.
-func newMetricsHandler(config *kubeschedulerconfig.KubeSchedulerConfiguration) http.Handler {
+func newMetricsHandler(config *kubeschedulerconfig.KubeSchedulerConfiguration, sched *scheduler.Scheduler) http.Handler {
 	pathRecorderMux := mux.NewPathRecorderMux("kube-scheduler")
 	installMetricHandler(pathRecorderMux)
+	installPendingPodsHandler(pathRecorderMux, sched)
 	if config.EnableProfiling {
 		routes.Profiling{}.Install(pathRecorderMux)
 		if config.EnableContentionProfiling {
@@ -310,11 +343,12 @@ func newMetricsHandler(config *kubeschedulerconfig.KubeSchedulerConfiguration) h
 // newHealthzHandler creates a healthz server from the config, and will also
 // embed the metrics handler if the healthz and metrics address configurations
 // are the same.
-func newHealthzHandler(config *kubeschedulerconfig.KubeSchedulerConfiguration, separateMetrics bool, checks ...healthz.HealthzChecker) http.Handler {
+func newHealthzHandler(config *kubeschedulerconfig.KubeSchedulerConfiguration, sched *scheduler.Scheduler, separateMetrics bool, checks ...healthz.HealthzChecker) http.Handler {
 	pathRecorderMux := mux.NewPathRecorderMux("kube-scheduler")
 	healthz.InstallHandler(pathRecorderMux, checks...)
 	if !separateMetrics {
 		installMetricHandler(pathRecorderMux)
+		installPendingPodsHandler(pathRecorderMux, sched)
 	}
 	if config.EnableProfiling {
 		routes.Profiling{}.Install(pathRecorderMux)
@@ -26,6 +26,7 @@ import (
 
 	// libs that provide registration functions
 	"k8s.io/component-base/logs"
+	metricsoptions "k8s.io/component-base/metrics/options"
 	"k8s.io/klog"
 	"k8s.io/kubernetes/pkg/version/verflag"
 
@@ -34,6 +35,10 @@ import (
 	_ "k8s.io/kubernetes/pkg/credentialprovider/gcp"
 )
 
+// MetricsOptions holds the metrics-endpoint flags (e.g. --show-hidden-metrics-for-version)
+// registered by AddGlobalFlags, so Run can Validate and Apply them once flag parsing completes.
+var MetricsOptions = metricsoptions.NewOptions()
+
 // AddGlobalFlags explicitly registers flags that libraries (glog, verflag, etc.) register
 // against the global flagsets from "flag" and "github.com/spf13/pflag".
 // We do this in order to prevent unwanted flags from leaking into the Kubelet's flagset.
@@ -43,6 +48,7 @@ func AddGlobalFlags(fs *pflag.FlagSet) {
 	addCredentialProviderFlags(fs)
 	verflag.AddFlags(fs)
 	logs.AddFlags(fs)
+	MetricsOptions.AddFlags(fs)
 }
 
 // normalize replaces underscores with hyphens
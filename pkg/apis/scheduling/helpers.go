@@ -21,6 +21,21 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// PriorityQuotaGatedAnnotationKey is set to "true" by the priority admission plugin on a pod
+	// that was admitted despite its namespace having exhausted its per-PriorityClass pod quota
+	// (see priority admission's PriorityClassPodQuota configuration). The scheduler's
+	// PodFitsPriorityQuotaGate predicate treats its presence as unschedulable, so the pod queues
+	// rather than competing for a node, until a companion controller removes the annotation once
+	// quota frees up.
+	PriorityQuotaGatedAnnotationKey = "scheduling.k8s.io/priority-quota-gated"
+
+	// PriorityQuotaExceededConditionType is the PodCondition Type set alongside
+	// PriorityQuotaGatedAnnotationKey, so users and controllers can discover why a pod isn't
+	// progressing without having to know about the annotation.
+	PriorityQuotaExceededConditionType = "PriorityQuotaExceeded"
+)
+
 // SystemPriorityClasses define system priority classes that are auto-created at cluster bootstrapping.
 // Our API validation logic ensures that any priority class that has a system prefix or its value
 // is higher than HighestUserDefinablePriority is equal to one of these SystemPriorityClasses.
@@ -63,3 +78,20 @@ func IsKnownSystemPriorityClass(pc *PriorityClass) (bool, error) {
 	}
 	return false, fmt.Errorf("%v is not a known system priority class", pc.Name)
 }
+
+// PriorityClassPermittedInNamespace returns true if the given priority class name is
+// allowed to be used by objects created in the given namespace. It currently checks
+// that system priorities are only used in the system namespace, to prevent abuse or
+// incorrect usage of priorities that could preempt system critical components.
+//
+// This is shared by the priority admission plugin and anything else that needs to
+// reproduce its namespace policy check, such as external resolvers, so that they
+// cannot drift apart.
+func PriorityClassPermittedInNamespace(priorityClassName string, namespace string) bool {
+	for _, spc := range systemPriorityClasses {
+		if spc.Name == priorityClassName && namespace != metav1.NamespaceSystem {
+			return false
+		}
+	}
+	return true
+}
@@ -64,6 +64,7 @@ func autoConvert_v1_PriorityClass_To_scheduling_PriorityClass(in *v1.PriorityCla
 	out.Value = in.Value
 	out.GlobalDefault = in.GlobalDefault
 	out.Description = in.Description
+	out.DeprecatedAliases = *(*[]string)(unsafe.Pointer(&in.DeprecatedAliases))
 	return nil
 }
 
@@ -77,6 +78,7 @@ func autoConvert_scheduling_PriorityClass_To_v1_PriorityClass(in *scheduling.Pri
 	out.Value = in.Value
 	out.GlobalDefault = in.GlobalDefault
 	out.Description = in.Description
+	out.DeprecatedAliases = *(*[]string)(unsafe.Pointer(&in.DeprecatedAliases))
 	return nil
 }
 
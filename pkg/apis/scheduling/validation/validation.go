@@ -41,6 +41,17 @@ func ValidatePriorityClass(pc *scheduling.PriorityClass) field.ErrorList {
 		// Non-system critical priority classes are not allowed to have a value larger than HighestUserDefinablePriority.
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("value"), fmt.Sprintf("maximum allowed value of a user defined priority is %v", scheduling.HighestUserDefinablePriority)))
 	}
+	seenAliases := map[string]bool{}
+	for i, alias := range pc.DeprecatedAliases {
+		idxPath := field.NewPath("deprecatedAliases").Index(i)
+		if alias == pc.Name {
+			allErrs = append(allErrs, field.Invalid(idxPath, alias, "may not alias the PriorityClass's own name"))
+		}
+		if seenAliases[alias] {
+			allErrs = append(allErrs, field.Duplicate(idxPath, alias))
+		}
+		seenAliases[alias] = true
+	}
 	return allErrs
 }
 
@@ -52,5 +63,16 @@ func ValidatePriorityClassUpdate(pc, oldPc *scheduling.PriorityClass) field.Erro
 	if pc.Value != oldPc.Value {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("Value"), "may not be changed in an update."))
 	}
+	// The built-in system priority classes are relied on by every control-plane component, so
+	// none of their defining fields may be changed after creation, no matter who is making the
+	// change; this is checked here rather than left to admission so it cannot be bypassed by RBAC.
+	// Value is already covered above for every PriorityClass; GlobalDefault is the only other
+	// field IsKnownSystemPriorityClass pins to a fixed value for these classes.
+	//
+	// NOTE: system priority classes have no PreemptionPolicy field to pin down here, since that
+	// field was introduced in a later Kubernetes release than this tree tracks.
+	if strings.HasPrefix(pc.Name, scheduling.SystemPriorityClassPrefix) && pc.GlobalDefault != oldPc.GlobalDefault {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("globalDefault"), "globalDefault of a system priority class may not be changed in an update."))
+	}
 	return allErrs
 }
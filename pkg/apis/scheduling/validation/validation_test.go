@@ -43,6 +43,11 @@ func TestValidatePriorityClass(t *testing.T) {
 			GlobalDefault: spcs[0].GlobalDefault,
 			Description:   "system priority class 0",
 		},
+		"with deprecated aliases": {
+			ObjectMeta:        metav1.ObjectMeta{Name: "tier1", Namespace: ""},
+			Value:             100,
+			DeprecatedAliases: []string{"tier1-old", "tier1-legacy"},
+		},
 	}
 
 	for k, v := range successCases {
@@ -70,6 +75,16 @@ func TestValidatePriorityClass(t *testing.T) {
 			Value:         spcs[0].Value,
 			GlobalDefault: spcs[0].GlobalDefault,
 		},
+		"alias equal to own name": {
+			ObjectMeta:        metav1.ObjectMeta{Name: "tier1", Namespace: ""},
+			Value:             100,
+			DeprecatedAliases: []string{"tier1"},
+		},
+		"duplicate alias": {
+			ObjectMeta:        metav1.ObjectMeta{Name: "tier1", Namespace: ""},
+			Value:             100,
+			DeprecatedAliases: []string{"tier1-old", "tier1-old"},
+		},
 	}
 
 	for k, v := range errorCases {
@@ -158,3 +173,35 @@ func TestValidatePriorityClassUpdate(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateSystemPriorityClassUpdate(t *testing.T) {
+	spc := scheduling.SystemPriorityClasses()[0]
+	old := scheduling.PriorityClass{
+		ObjectMeta:    metav1.ObjectMeta{Name: spc.Name, Namespace: "", ResourceVersion: "1"},
+		Value:         spc.Value,
+		GlobalDefault: spc.GlobalDefault,
+	}
+
+	if errs := ValidatePriorityClassUpdate(&scheduling.PriorityClass{
+		ObjectMeta:    metav1.ObjectMeta{Name: spc.Name, Namespace: "", ResourceVersion: "2"},
+		Value:         spc.Value,
+		GlobalDefault: spc.GlobalDefault,
+		Description:   "an updated description is fine",
+	}, &old); len(errs) != 0 {
+		t.Errorf("Expected success updating a non-pinned field, got %v", errs)
+	}
+
+	errs := ValidatePriorityClassUpdate(&scheduling.PriorityClass{
+		ObjectMeta:    metav1.ObjectMeta{Name: spc.Name, Namespace: "", ResourceVersion: "2"},
+		Value:         spc.Value,
+		GlobalDefault: !spc.GlobalDefault,
+	}, &old)
+	if len(errs) == 0 {
+		t.Errorf("Expected error changing globalDefault of a system priority class, but it succeeded")
+	}
+	for _, err := range errs {
+		if err.Type != field.ErrorTypeForbidden {
+			t.Errorf("expected error to have type %s: %v", field.ErrorTypeForbidden, err)
+		}
+	}
+}
@@ -29,6 +29,11 @@ func (in *PriorityClass) DeepCopyInto(out *PriorityClass) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.DeprecatedAliases != nil {
+		in, out := &in.DeprecatedAliases, &out.DeprecatedAliases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
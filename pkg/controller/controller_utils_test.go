@@ -429,6 +429,41 @@ func TestSortingActivePods(t *testing.T) {
 	}
 }
 
+func TestSortingActivePodsWithPriority(t *testing.T) {
+	rc := newReplicationController(0)
+	podList := newPodList(nil, 3, v1.PodRunning, rc)
+
+	pods := make([]*v1.Pod, len(podList.Items))
+	for i := range podList.Items {
+		pods[i] = &podList.Items[i]
+	}
+	lowPriority := int32(0)
+	highPriority := int32(1000)
+	// pods[0] and pods[1] are both ready, scheduled and running, but pods[0] has lower priority
+	// and should be considered a better deletion candidate than pods[1].
+	for _, p := range pods[:2] {
+		p.Spec.NodeName = "foo"
+		p.Status.Phase = v1.PodRunning
+		p.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}
+	}
+	pods[0].Spec.Priority = &lowPriority
+	pods[1].Spec.Priority = &highPriority
+	// pods[2] is not scheduled, which should still outrank priority since an unscheduled pod is
+	// never a useful replica regardless of how important its priority class is.
+	pods[2].Spec.NodeName = ""
+	pods[2].Status.Phase = v1.PodPending
+	pods[2].Spec.Priority = &highPriority
+
+	expected := []string{pods[2].Name, pods[0].Name, pods[1].Name}
+
+	sort.Sort(ActivePods(pods))
+	actual := make([]string, len(pods))
+	for i := range pods {
+		actual[i] = pods[i].Name
+	}
+	assert.EqualValues(t, expected, actual, "expected %v, got %v", expected, actual)
+}
+
 func TestActiveReplicaSetsFiltering(t *testing.T) {
 	var replicaSets []*apps.ReplicaSet
 	replicaSets = append(replicaSets, newReplicaSet("zero", 0))
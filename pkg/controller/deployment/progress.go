@@ -25,6 +25,7 @@ import (
 
 	apps "k8s.io/api/apps/v1"
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kubernetes/pkg/controller/deployment/util"
 )
 
@@ -82,6 +83,14 @@ func (dc *DeploymentController) syncRolloutStatus(allRSs []*apps.ReplicaSet, new
 			}
 			util.SetDeploymentCondition(&newStatus, *condition)
 
+		case newRS != nil && dc.isRolloutBlockedByPreemption(newRS):
+			// The new replica set's pods keep losing their spot to preemption instead of
+			// running. Surface that explicitly instead of letting the rollout time out and
+			// churn ReplicaSets while nothing is actually wrong with the new pod template.
+			msg := fmt.Sprintf("ReplicaSet %q is repeatedly having its pods preempted", newRS.Name)
+			condition := util.NewDeploymentCondition(apps.DeploymentProgressing, v1.ConditionUnknown, util.ProgressBlockedByPreemptionReason, msg)
+			util.SetDeploymentCondition(&newStatus, *condition)
+
 		case util.DeploymentTimedOut(d, &newStatus):
 			// Update the deployment with a timeout condition. If the condition already exists,
 			// we ignore this update.
@@ -116,6 +125,29 @@ func (dc *DeploymentController) syncRolloutStatus(allRSs []*apps.ReplicaSet, new
 	return err
 }
 
+// preemptionBlockedRolloutThreshold is the fraction (0-1) of a new replica set's desired
+// replicas that must be observed blocked by preemption before the rollout is considered
+// blocked rather than merely slow.
+const preemptionBlockedRolloutThreshold = 0.5
+
+// isRolloutBlockedByPreemption returns true if a majority of newRS's pods are stuck because
+// they keep being preempted, rather than genuinely failing to make progress.
+func (dc *DeploymentController) isRolloutBlockedByPreemption(newRS *apps.ReplicaSet) bool {
+	if newRS.Spec.Replicas == nil || *newRS.Spec.Replicas == 0 {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(newRS.Spec.Selector)
+	if err != nil {
+		return false
+	}
+	pods, err := dc.podLister.Pods(newRS.Namespace).List(selector)
+	if err != nil {
+		return false
+	}
+	blocked := util.CountPodsBlockedByPreemption(pods)
+	return float64(blocked) >= float64(*newRS.Spec.Replicas)*preemptionBlockedRolloutThreshold
+}
+
 // getReplicaFailures will convert replica failure conditions from replica sets
 // to deployment conditions.
 func (dc *DeploymentController) getReplicaFailures(allRSs []*apps.ReplicaSet, newRS *apps.ReplicaSet) []apps.DeploymentCondition {
@@ -88,6 +88,10 @@ const (
 	// ResumedDeployReason is added in a deployment when it is resumed. Useful for not failing accidentally
 	// deployments that paused amidst a rollout and are bounded by a deadline.
 	ResumedDeployReason = "DeploymentResumed"
+	// ProgressBlockedByPreemptionReason is added in a deployment's Progressing condition when its
+	// new replica set's pods are repeatedly being preempted instead of running, so we stop treating
+	// the lack of progress as a failed rollout and stop churning ReplicaSets.
+	ProgressBlockedByPreemptionReason = "ProgressBlockedByPreemption"
 	//
 	// Available:
 
@@ -901,3 +905,30 @@ func HasProgressDeadline(d *apps.Deployment) bool {
 func HasRevisionHistoryLimit(d *apps.Deployment) bool {
 	return d.Spec.RevisionHistoryLimit != nil && *d.Spec.RevisionHistoryLimit != math.MaxInt32
 }
+
+// IsPodBlockedByPreemption returns true if pod is unscheduled and the scheduler has nominated a
+// node for it via preemption (NominatedNodeName is set) but the pod has not yet bound there,
+// which is the signal left behind when a pod is repeatedly preempted before it can start running.
+func IsPodBlockedByPreemption(pod *v1.Pod) bool {
+	if pod.Status.NominatedNodeName == "" {
+		return false
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == v1.PodScheduled && c.Status == v1.ConditionFalse && c.Reason == v1.PodReasonUnschedulable {
+			return true
+		}
+	}
+	return false
+}
+
+// CountPodsBlockedByPreemption returns the number of pods in pods which are blocked from
+// starting because they keep being preempted. See IsPodBlockedByPreemption.
+func CountPodsBlockedByPreemption(pods []*v1.Pod) int {
+	count := 0
+	for _, pod := range pods {
+		if IsPodBlockedByPreemption(pod) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,24 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package podpriority contains OverprovisionController, a controller that maintains pools of
+// low-priority placeholder pods sized to reserve scheduling headroom for a configured, higher
+// PriorityClass. Because placeholders run at low priority, the scheduler preempts them first
+// when a pod of the reserved class needs the room, giving that class effectively guaranteed
+// admission without a hard resource reservation. Pool sizes are right-sized automatically: they
+// grow when pods of the reserved class are pending or have recently triggered preemption, and
+// shrink back towards their configured minimum otherwise.
+package podpriority
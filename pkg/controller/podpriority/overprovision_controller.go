@@ -0,0 +1,287 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podpriority
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+	"k8s.io/kubernetes/pkg/controller"
+	"k8s.io/kubernetes/pkg/util/metrics"
+)
+
+const (
+	// placeholderPoolLabel names the pool a placeholder pod belongs to.
+	placeholderPoolLabel = "scheduling.k8s.io/overprovision-pool"
+
+	syncPeriod = 30 * time.Second
+
+	// preemptionWindow is how far back "Preempted" events are counted towards right-sizing.
+	preemptionWindow = 5 * syncPeriod
+
+	// defaultPlaceholderImage runs indefinitely without doing any work, the same image the
+	// kubelet itself uses for sandbox containers.
+	defaultPlaceholderImage = "k8s.gcr.io/pause:3.1"
+)
+
+// OverprovisionPool describes a pool of low-priority placeholder pods maintained to reserve
+// scheduling headroom for a specific, higher PriorityClass.
+type OverprovisionPool struct {
+	// Name identifies the pool. Placeholder pods are named "<Name>-overprovision-<n>" and
+	// labeled so the controller can tell pools apart.
+	Name string
+	// Namespace is where the pool's placeholder pods are created.
+	Namespace string
+	// PlaceholderPriorityClassName is the low PriorityClass assigned to placeholder pods, so
+	// they are the cheapest pods on a node to preempt.
+	PlaceholderPriorityClassName string
+	// ForPriorityClassName is the PriorityClass this pool reserves headroom for. Pending pods
+	// and preemption events for this class drive the pool's automatic right-sizing.
+	ForPriorityClassName string
+	// Resources is the amount of headroom a single placeholder pod reserves.
+	Resources v1.ResourceRequirements
+	// Image overrides the placeholder container image. Defaults to the pause image.
+	Image string
+	// MinReplicas and MaxReplicas bound the pool's automatic right-sizing.
+	MinReplicas int32
+	MaxReplicas int32
+}
+
+// OverprovisionController maintains configured OverprovisionPools, growing or shrinking each
+// pool's placeholder pods between MinReplicas and MaxReplicas based on scheduling pressure
+// observed for the pool's ForPriorityClassName.
+type OverprovisionController struct {
+	kubeClient clientset.Interface
+	pools      []OverprovisionPool
+
+	podLister       corelisters.PodLister
+	podListerSynced cache.InformerSynced
+
+	eventLister       corelisters.EventLister
+	eventListerSynced cache.InformerSynced
+}
+
+// NewOverprovisionController creates a new OverprovisionController for the given pools.
+func NewOverprovisionController(
+	kubeClient clientset.Interface,
+	podInformer coreinformers.PodInformer,
+	eventInformer coreinformers.EventInformer,
+	pools []OverprovisionPool,
+) *OverprovisionController {
+	if kubeClient != nil && kubeClient.CoreV1().RESTClient().GetRateLimiter() != nil {
+		metrics.RegisterMetricAndTrackRateLimiterUsage("overprovision_controller", kubeClient.CoreV1().RESTClient().GetRateLimiter())
+	}
+	c := &OverprovisionController{
+		kubeClient:        kubeClient,
+		pools:             pools,
+		podLister:         podInformer.Lister(),
+		podListerSynced:   podInformer.Informer().HasSynced,
+		eventLister:       eventInformer.Lister(),
+		eventListerSynced: eventInformer.Informer().HasSynced,
+	}
+	return c
+}
+
+// Run starts the controller's sync loop and blocks until stop is closed.
+func (c *OverprovisionController) Run(stop <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+
+	klog.Infof("Starting overprovision controller")
+	defer klog.Infof("Shutting down overprovision controller")
+
+	if !controller.WaitForCacheSync("overprovision", stop, c.podListerSynced, c.eventListerSynced) {
+		return
+	}
+
+	go wait.Until(c.sync, syncPeriod, stop)
+
+	<-stop
+}
+
+func (c *OverprovisionController) sync() {
+	for _, pool := range c.pools {
+		if err := c.syncPool(pool); err != nil {
+			klog.Errorf("Error syncing overprovision pool %q: %v", pool.Name, err)
+		}
+	}
+}
+
+func (c *OverprovisionController) syncPool(pool OverprovisionPool) error {
+	selector := labels.SelectorFromSet(labels.Set{placeholderPoolLabel: pool.Name})
+	existing, err := c.podLister.Pods(pool.Namespace).List(selector)
+	if err != nil {
+		return fmt.Errorf("listing placeholder pods: %v", err)
+	}
+
+	pending, err := c.pendingForPriorityClass(pool.ForPriorityClassName)
+	if err != nil {
+		return fmt.Errorf("counting pending pods: %v", err)
+	}
+	preemptions, err := c.recentPreemptions()
+	if err != nil {
+		return fmt.Errorf("counting recent preemptions: %v", err)
+	}
+
+	desired := desiredReplicas(pool, int32(len(existing)), pending, preemptions)
+	current := int32(len(existing))
+
+	klog.V(4).Infof("Overprovision pool %q: current=%d desired=%d pending=%d preemptions=%d", pool.Name, current, desired, pending, preemptions)
+
+	switch {
+	case desired > current:
+		return c.growPool(pool, existing, desired-current)
+	case desired < current:
+		return c.shrinkPool(pool, existing, current-desired)
+	}
+	return nil
+}
+
+// desiredReplicas computes the next replica count for pool. Any scheduling pressure observed
+// for the reserved priority class grows the pool by one towards MaxReplicas; the absence of
+// pressure shrinks it by one towards MinReplicas. Growing one step per sync keeps the pool from
+// overreacting to a single noisy sync.
+func desiredReplicas(pool OverprovisionPool, current int32, pending int32, preemptions int32) int32 {
+	desired := current
+	if pending > 0 || preemptions > 0 {
+		desired = current + 1
+	} else {
+		desired = current - 1
+	}
+	if desired < pool.MinReplicas {
+		desired = pool.MinReplicas
+	}
+	if desired > pool.MaxReplicas {
+		desired = pool.MaxReplicas
+	}
+	return desired
+}
+
+func (c *OverprovisionController) pendingForPriorityClass(priorityClassName string) (int32, error) {
+	pods, err := c.podLister.List(labels.Everything())
+	if err != nil {
+		return 0, err
+	}
+	var count int32
+	for _, pod := range pods {
+		if pod.Spec.PriorityClassName != priorityClassName {
+			continue
+		}
+		if pod.Spec.NodeName != "" {
+			continue
+		}
+		if pod.Status.Phase != v1.PodPending {
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (c *OverprovisionController) recentPreemptions() (int32, error) {
+	events, err := c.eventLister.List(labels.Everything())
+	if err != nil {
+		return 0, err
+	}
+	cutoff := metav1.NewTime(time.Now().Add(-preemptionWindow))
+	var count int32
+	for _, event := range events {
+		if event.Reason != "Preempted" {
+			continue
+		}
+		if event.LastTimestamp.Before(&cutoff) {
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (c *OverprovisionController) growPool(pool OverprovisionPool, existing []*v1.Pod, n int32) error {
+	taken := make(map[string]bool, len(existing))
+	for _, pod := range existing {
+		taken[pod.Name] = true
+	}
+	created := int32(0)
+	for i := 0; created < n; i++ {
+		name := fmt.Sprintf("%s-overprovision-%d", pool.Name, i)
+		if taken[name] {
+			continue
+		}
+		pod := newPlaceholderPod(pool, name)
+		if _, err := c.kubeClient.CoreV1().Pods(pool.Namespace).Create(pod); err != nil {
+			return fmt.Errorf("creating placeholder pod %s: %v", name, err)
+		}
+		created++
+	}
+	return nil
+}
+
+func (c *OverprovisionController) shrinkPool(pool OverprovisionPool, existing []*v1.Pod, n int32) error {
+	toDelete := append([]*v1.Pod{}, existing...)
+	sort.Slice(toDelete, func(i, j int) bool {
+		return toDelete[i].CreationTimestamp.Before(&toDelete[j].CreationTimestamp)
+	})
+	if int32(len(toDelete)) < n {
+		n = int32(len(toDelete))
+	}
+	// Delete the newest placeholders first, keeping the pool's longest-lived pods in place so
+	// the scheduler doesn't churn through recently-added headroom before it has had a chance
+	// to matter.
+	for i := int32(len(toDelete)) - 1; i >= int32(len(toDelete))-n; i-- {
+		pod := toDelete[i]
+		if err := c.kubeClient.CoreV1().Pods(pool.Namespace).Delete(pod.Name, metav1.NewDeleteOptions(0)); err != nil {
+			return fmt.Errorf("deleting placeholder pod %s: %v", pod.Name, err)
+		}
+	}
+	return nil
+}
+
+func newPlaceholderPod(pool OverprovisionPool, name string) *v1.Pod {
+	image := pool.Image
+	if image == "" {
+		image = defaultPlaceholderImage
+	}
+	priorityClassName := pool.PlaceholderPriorityClassName
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: pool.Namespace,
+			Labels:    map[string]string{placeholderPoolLabel: pool.Name},
+		},
+		Spec: v1.PodSpec{
+			PriorityClassName: priorityClassName,
+			Containers: []v1.Container{
+				{
+					Name:      "placeholder",
+					Image:     image,
+					Resources: pool.Resources,
+				},
+			},
+		},
+	}
+}
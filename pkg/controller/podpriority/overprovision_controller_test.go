@@ -0,0 +1,92 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podpriority
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDesiredReplicas(t *testing.T) {
+	pool := OverprovisionPool{MinReplicas: 1, MaxReplicas: 5}
+
+	cases := []struct {
+		name        string
+		current     int32
+		pending     int32
+		preemptions int32
+		want        int32
+	}{
+		{name: "grows on pending demand", current: 2, pending: 1, want: 3},
+		{name: "grows on preemption pressure", current: 2, preemptions: 1, want: 3},
+		{name: "shrinks with no pressure", current: 2, want: 1},
+		{name: "never exceeds max", current: 5, pending: 1, want: 5},
+		{name: "never drops below min", current: 1, want: 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := desiredReplicas(pool, c.current, c.pending, c.preemptions)
+			if got != c.want {
+				t.Errorf("desiredReplicas() = %d, want %d", got, c.want)
+			}
+		})
+	}
+}
+
+func TestSyncPoolGrowsWithPendingPod(t *testing.T) {
+	pool := OverprovisionPool{
+		Name:                         "critical-headroom",
+		Namespace:                    "kube-system",
+		PlaceholderPriorityClassName: "overprovision-low",
+		ForPriorityClassName:         "critical",
+		MinReplicas:                  0,
+		MaxReplicas:                  3,
+	}
+
+	pendingPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "default"},
+		Spec:       v1.PodSpec{PriorityClassName: "critical"},
+		Status:     v1.PodStatus{Phase: v1.PodPending},
+	}
+
+	client := fake.NewSimpleClientset(pendingPod)
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	podInformer := informerFactory.Core().V1().Pods()
+	eventInformer := informerFactory.Core().V1().Events()
+	podInformer.Informer().GetStore().Add(pendingPod)
+
+	c := NewOverprovisionController(client, podInformer, eventInformer, []OverprovisionPool{pool})
+
+	if err := c.syncPool(pool); err != nil {
+		t.Fatalf("syncPool() error = %v", err)
+	}
+
+	pods, err := client.CoreV1().Pods(pool.Namespace).List(metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("listing placeholder pods: %v", err)
+	}
+	if len(pods.Items) != 1 {
+		t.Fatalf("expected 1 placeholder pod to be created, got %d", len(pods.Items))
+	}
+	if pods.Items[0].Spec.PriorityClassName != pool.PlaceholderPriorityClassName {
+		t.Errorf("expected placeholder priority class %q, got %q", pool.PlaceholderPriorityClassName, pods.Items[0].Spec.PriorityClassName)
+	}
+}
@@ -98,6 +98,13 @@ type SchedulerVolumeBinder interface {
 	GetBindingsCache() PodBindingCache
 }
 
+// ProvisioningDelayChecker reports whether dynamic provisioning for pod's unbound PVCs should be
+// delayed for the current scheduling cycle. It lets the caller (typically the default scheduler)
+// steer expensive, hard-to-undo dynamic provisioning away from pods that are unlikely to run soon,
+// without this package needing to know why that is (priority backlog depth, imminent preemption,
+// or anything else).
+type ProvisioningDelayChecker func(pod *v1.Pod) bool
+
 type volumeBinder struct {
 	kubeClient  clientset.Interface
 	classLister storagelisters.StorageClassLister
@@ -112,25 +119,32 @@ type volumeBinder struct {
 
 	// Amount of time to wait for the bind operation to succeed
 	bindTimeout time.Duration
+
+	// provisioningDelayed, if non-nil, is consulted before dynamically provisioning volumes for a
+	// pod. A nil value, or one that returns false, never delays provisioning.
+	provisioningDelayed ProvisioningDelayChecker
 }
 
 // NewVolumeBinder sets up all the caches needed for the scheduler to make volume binding decisions.
+// provisioningDelayed may be nil, in which case dynamic provisioning is never delayed.
 func NewVolumeBinder(
 	kubeClient clientset.Interface,
 	nodeInformer coreinformers.NodeInformer,
 	pvcInformer coreinformers.PersistentVolumeClaimInformer,
 	pvInformer coreinformers.PersistentVolumeInformer,
 	storageClassInformer storageinformers.StorageClassInformer,
-	bindTimeout time.Duration) SchedulerVolumeBinder {
+	bindTimeout time.Duration,
+	provisioningDelayed ProvisioningDelayChecker) SchedulerVolumeBinder {
 
 	b := &volumeBinder{
-		kubeClient:      kubeClient,
-		classLister:     storageClassInformer.Lister(),
-		nodeInformer:    nodeInformer,
-		pvcCache:        NewPVCAssumeCache(pvcInformer.Informer()),
-		pvCache:         NewPVAssumeCache(pvInformer.Informer()),
-		podBindingCache: NewPodBindingCache(),
-		bindTimeout:     bindTimeout,
+		kubeClient:          kubeClient,
+		classLister:         storageClassInformer.Lister(),
+		nodeInformer:        nodeInformer,
+		pvcCache:            NewPVCAssumeCache(pvcInformer.Informer()),
+		pvCache:             NewPVAssumeCache(pvInformer.Informer()),
+		podBindingCache:     NewPodBindingCache(),
+		bindTimeout:         bindTimeout,
+		provisioningDelayed: provisioningDelayed,
 	}
 
 	return b
@@ -235,6 +249,10 @@ func (b *volumeBinder) FindPodVolumes(pod *v1.Pod, node *v1.Node) (unboundVolume
 
 		// Check for claims to provision
 		if len(claimsToProvision) > 0 {
+			if b.provisioningDelayed != nil && b.provisioningDelayed(pod) {
+				klog.V(4).Infof("Delaying dynamic provisioning for pod %q on node %q", podName, node.Name)
+				return false, boundVolumesSatisfied, nil
+			}
 			unboundVolumesSatisfied, provisionedClaims, err = b.checkVolumeProvisions(pod, claimsToProvision, node)
 			if err != nil {
 				return false, false, err
@@ -131,7 +131,8 @@ func newTestBinder(t *testing.T, stopCh <-chan struct{}) *testEnv {
 		pvcInformer,
 		informerFactory.Core().V1().PersistentVolumes(),
 		classInformer,
-		10*time.Second)
+		10*time.Second,
+		nil)
 
 	// Wait for informers cache sync
 	informerFactory.Start(stopCh)
@@ -440,6 +440,15 @@ const (
 	//
 	// Enables the regional PD feature on GCE.
 	deprecatedGCERegionalPersistentDisk featuregate.Feature = "GCERegionalPersistentDisk"
+
+	// owner: @bsalamat
+	// alpha: v1.14
+	//
+	// Gradually boosts the effective priority of pods that have been waiting in the
+	// scheduling queue for a long time, so low-priority pods cannot starve indefinitely on a
+	// busy cluster. PriorityClass ordering is still respected among pods that have aged by the
+	// same amount.
+	PodPriorityAging featuregate.Feature = "PodPriorityAging"
 )
 
 func init() {
@@ -514,6 +523,7 @@ var defaultKubernetesFeatureGates = map[featuregate.Feature]featuregate.FeatureS
 	TTLAfterFinished:                            {Default: false, PreRelease: featuregate.Alpha},
 	KubeletPodResources:                         {Default: false, PreRelease: featuregate.Alpha},
 	WindowsGMSA:                                 {Default: false, PreRelease: featuregate.Alpha},
+	PodPriorityAging:                            {Default: false, PreRelease: featuregate.Alpha},
 
 	// inherited features from generic apiserver, relisted here to get a conflict if it is changed
 	// unintentionally on either side:
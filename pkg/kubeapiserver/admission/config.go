@@ -63,10 +63,12 @@ func (c *Config) New(proxyTransport *http.Transport, serviceResolver webhook.Ser
 
 	discoveryClient := cacheddiscovery.NewMemCacheClient(clientset.Discovery())
 	discoveryRESTMapper := restmapper.NewDeferredDiscoveryRESTMapper(discoveryClient)
+	namespaceParamsGetter := NewNamespaceParamsGetter(c.ExternalInformers.Core().V1().Namespaces().Lister())
 	kubePluginInitializer := NewPluginInitializer(
 		cloudConfig,
 		discoveryRESTMapper,
 		quotainstall.NewQuotaConfigurationForAdmission(),
+		namespaceParamsGetter,
 	)
 
 	admissionPostStartHook := func(context genericapiserver.PostStartHookContext) error {
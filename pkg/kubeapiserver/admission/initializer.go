@@ -42,12 +42,20 @@ type WantsQuotaConfiguration interface {
 	admission.InitializationValidator
 }
 
+// WantsNamespaceParamsGetter defines a function which sets a NamespaceParamsGetter for admission
+// plugins that read generic, per-namespace admission parameters.
+type WantsNamespaceParamsGetter interface {
+	SetNamespaceParamsGetter(NamespaceParamsGetter)
+	admission.InitializationValidator
+}
+
 // PluginInitializer is used for initialization of the Kubernetes specific admission plugins.
 type PluginInitializer struct {
 	authorizer                        authorizer.Authorizer
 	cloudConfig                       []byte
 	restMapper                        meta.RESTMapper
 	quotaConfiguration                quota.Configuration
+	namespaceParamsGetter             NamespaceParamsGetter
 	serviceResolver                   webhook.ServiceResolver
 	authenticationInfoResolverWrapper webhook.AuthenticationInfoResolverWrapper
 }
@@ -61,11 +69,13 @@ func NewPluginInitializer(
 	cloudConfig []byte,
 	restMapper meta.RESTMapper,
 	quotaConfiguration quota.Configuration,
+	namespaceParamsGetter NamespaceParamsGetter,
 ) *PluginInitializer {
 	return &PluginInitializer{
-		cloudConfig:        cloudConfig,
-		restMapper:         restMapper,
-		quotaConfiguration: quotaConfiguration,
+		cloudConfig:           cloudConfig,
+		restMapper:            restMapper,
+		quotaConfiguration:    quotaConfiguration,
+		namespaceParamsGetter: namespaceParamsGetter,
 	}
 }
 
@@ -83,4 +93,8 @@ func (i *PluginInitializer) Initialize(plugin admission.Interface) {
 	if wants, ok := plugin.(WantsQuotaConfiguration); ok {
 		wants.SetQuotaConfiguration(i.quotaConfiguration)
 	}
+
+	if wants, ok := plugin.(WantsNamespaceParamsGetter); ok {
+		wants.SetNamespaceParamsGetter(i.namespaceParamsGetter)
+	}
 }
@@ -0,0 +1,65 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"encoding/json"
+
+	corev1listers "k8s.io/client-go/listers/core/v1"
+)
+
+// NamespaceParamsAnnotation is the Namespace annotation that holds generic, per-plugin admission
+// parameters as a JSON object of the form {"<pluginName>": {"<key>": "<value>", ...}, ...}. It
+// exists so built-in admission plugins can share one namespace-scoped parameter store instead of
+// each inventing its own annotation key.
+const NamespaceParamsAnnotation = "admission.kubernetes.io/params"
+
+// NamespaceParamsGetter returns generic, per-namespace admission policy parameters for a named
+// plugin, backed by an informer so lookups do not hit the API server on the request path.
+type NamespaceParamsGetter interface {
+	// GetNamespaceParams returns the parameters configured for pluginName in namespace, and
+	// whether any were found. A namespace with no NamespaceParamsAnnotation, or one that does
+	// not mention pluginName, returns (nil, false, nil).
+	GetNamespaceParams(pluginName, namespace string) (map[string]string, bool, error)
+}
+
+// namespaceParamsGetter is the informer-backed implementation of NamespaceParamsGetter.
+type namespaceParamsGetter struct {
+	namespaceLister corev1listers.NamespaceLister
+}
+
+// NewNamespaceParamsGetter returns a NamespaceParamsGetter backed by namespaceLister.
+func NewNamespaceParamsGetter(namespaceLister corev1listers.NamespaceLister) NamespaceParamsGetter {
+	return &namespaceParamsGetter{namespaceLister: namespaceLister}
+}
+
+func (g *namespaceParamsGetter) GetNamespaceParams(pluginName, namespace string) (map[string]string, bool, error) {
+	ns, err := g.namespaceLister.Get(namespace)
+	if err != nil {
+		return nil, false, err
+	}
+	raw, ok := ns.Annotations[NamespaceParamsAnnotation]
+	if !ok {
+		return nil, false, nil
+	}
+	var byPlugin map[string]map[string]string
+	if err := json.Unmarshal([]byte(raw), &byPlugin); err != nil {
+		return nil, false, err
+	}
+	params, ok := byPlugin[pluginName]
+	return params, ok, nil
+}
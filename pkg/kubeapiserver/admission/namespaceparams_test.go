@@ -0,0 +1,58 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNamespaceParamsGetter(t *testing.T) {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ns",
+			Annotations: map[string]string{
+				NamespaceParamsAnnotation: `{"PodTolerationRestriction":{"defaultTolerations":"[]"}}`,
+			},
+		},
+	}
+	client := fake.NewSimpleClientset(ns)
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	namespaceInformer := informerFactory.Core().V1().Namespaces()
+	namespaceInformer.Informer().GetStore().Add(ns)
+
+	getter := NewNamespaceParamsGetter(namespaceInformer.Lister())
+
+	params, ok, err := getter.GetNamespaceParams("PodTolerationRestriction", "ns")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("expected params to be found")
+	}
+	if params["defaultTolerations"] != "[]" {
+		t.Errorf("expected defaultTolerations param %q, got %q", "[]", params["defaultTolerations"])
+	}
+
+	if _, ok, err := getter.GetNamespaceParams("SomeOtherPlugin", "ns"); err != nil || ok {
+		t.Errorf("expected no params for unrelated plugin, got ok=%v err=%v", ok, err)
+	}
+}
@@ -57,6 +57,7 @@ import (
 	"k8s.io/kubernetes/pkg/kubectl/cmd/patch"
 	"k8s.io/kubernetes/pkg/kubectl/cmd/plugin"
 	"k8s.io/kubernetes/pkg/kubectl/cmd/portforward"
+	"k8s.io/kubernetes/pkg/kubectl/cmd/priority"
 	"k8s.io/kubernetes/pkg/kubectl/cmd/proxy"
 	"k8s.io/kubernetes/pkg/kubectl/cmd/replace"
 	"k8s.io/kubernetes/pkg/kubectl/cmd/rollingupdate"
@@ -505,6 +506,7 @@ func NewKubectlCommand(in io.Reader, out, err io.Writer) *cobra.Command {
 			Commands: []*cobra.Command{
 				describe.NewCmdDescribe("kubectl", f, ioStreams),
 				logs.NewCmdLogs(f, ioStreams),
+				priority.NewCmdPriority(f, ioStreams),
 				attach.NewCmdAttach(f, ioStreams),
 				cmdexec.NewCmdExec(f, ioStreams),
 				portforward.NewCmdPortForward(f, ioStreams),
@@ -0,0 +1,232 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priority
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	schedulingv1client "k8s.io/client-go/kubernetes/typed/scheduling/v1"
+	kubeapiserveradmission "k8s.io/kubernetes/pkg/kubeapiserver/admission"
+	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// priorityPluginName mirrors plugin/pkg/admission/priority.PluginName. It is duplicated here,
+// rather than imported, because that package pulls in the apiserver admission machinery, which
+// kubectl does not otherwise depend on.
+const priorityPluginName = "Priority"
+
+var (
+	explainLong = templates.LongDesc(i18n.T(`
+		Explain how a pod's scheduling priority was resolved.
+
+		Reports the pod's PriorityClassName and numeric priority, whether that PriorityClass
+		still exists, how the value was arrived at (an explicit PriorityClassName on the pod, a
+		per-namespace override recorded in the namespace's admission parameters annotation, or
+		the cluster-wide default PriorityClass), and how the pod ranks by priority among the
+		other pods on its node.`))
+
+	explainExample = templates.Examples(i18n.T(`
+		# Explain how mypod's priority was resolved
+		kubectl priority explain mypod`))
+)
+
+// ExplainOptions holds the state for "kubectl priority explain".
+type ExplainOptions struct {
+	PodName   string
+	Namespace string
+
+	PodClient           corev1client.PodsGetter
+	NamespaceClient     corev1client.NamespacesGetter
+	PriorityClassClient schedulingv1client.PriorityClassesGetter
+
+	genericclioptions.IOStreams
+}
+
+// NewCmdPriorityExplain returns the "explain" subcommand of "kubectl priority".
+func NewCmdPriorityExplain(f cmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &ExplainOptions{IOStreams: streams}
+
+	cmd := &cobra.Command{
+		Use:     "explain POD",
+		Short:   i18n.T("Explain how a pod's scheduling priority was resolved"),
+		Long:    explainLong,
+		Example: explainExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+	return cmd
+}
+
+// Complete fills in the fields needed to run the command from f and the command line.
+func (o *ExplainOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmdutil.UsageErrorf(cmd, "%s", cmd.Use)
+	}
+	o.PodName = args[0]
+
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	clientset, err := f.KubernetesClientSet()
+	if err != nil {
+		return err
+	}
+	o.PodClient = clientset.CoreV1()
+	o.NamespaceClient = clientset.CoreV1()
+	o.PriorityClassClient = clientset.SchedulingV1()
+
+	return nil
+}
+
+// Run fetches the pod and its scheduling context and prints the explanation.
+func (o *ExplainOptions) Run() error {
+	pod, err := o.PodClient.Pods(o.Namespace).Get(o.PodName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	className := pod.Spec.PriorityClassName
+	var value int32
+	if pod.Spec.Priority != nil {
+		value = *pod.Spec.Priority
+	}
+
+	fmt.Fprintf(o.Out, "Pod:            %s/%s\n", pod.Namespace, pod.Name)
+	if len(className) == 0 {
+		fmt.Fprintf(o.Out, "PriorityClass:  <none>\n")
+	} else {
+		fmt.Fprintf(o.Out, "PriorityClass:  %s\n", className)
+	}
+	fmt.Fprintf(o.Out, "Priority:       %d\n", value)
+	fmt.Fprintf(o.Out, "Resolved via:   %s\n", o.resolutionPath(className))
+
+	if len(className) > 0 {
+		if _, err := o.PriorityClassClient.PriorityClasses().Get(className, metav1.GetOptions{}); err != nil {
+			if errors.IsNotFound(err) {
+				fmt.Fprintf(o.Out, "PriorityClass %q no longer exists; the pod keeps its already-resolved priority.\n", className)
+			} else {
+				return err
+			}
+		}
+	}
+
+	rank, total, err := o.rankOnNode(pod)
+	if err != nil {
+		return err
+	}
+	if pod.Spec.NodeName == "" {
+		fmt.Fprintf(o.Out, "Rank on node:   pod is not yet scheduled to a node\n")
+	} else {
+		fmt.Fprintf(o.Out, "Rank on node:   %d of %d pods on %s, by priority\n", rank, total, pod.Spec.NodeName)
+	}
+
+	return nil
+}
+
+// resolutionPath returns a human-readable description of how className was arrived at.
+func (o *ExplainOptions) resolutionPath(className string) string {
+	if len(className) == 0 {
+		return "no PriorityClassName set; the default priority applies"
+	}
+
+	if override, ok := o.namespaceOverride(); ok && override == className {
+		return fmt.Sprintf("namespace override (%s annotation on %s)", kubeapiserveradmission.NamespaceParamsAnnotation, o.Namespace)
+	}
+
+	classes, err := o.PriorityClassClient.PriorityClasses().List(metav1.ListOptions{})
+	if err == nil {
+		for _, pc := range classes.Items {
+			if pc.Name == className && pc.GlobalDefault {
+				return "cluster-wide default PriorityClass (globalDefault)"
+			}
+		}
+	}
+
+	return "explicit PriorityClassName on the pod spec"
+}
+
+// namespaceOverride returns the "defaultPriorityClassName" parameter the Priority plugin's slice
+// of the namespace's admission parameters annotation requests, if any. See
+// kubeapiserveradmission.NamespaceParamsAnnotation.
+func (o *ExplainOptions) namespaceOverride() (string, bool) {
+	ns, err := o.NamespaceClient.Namespaces().Get(o.Namespace, metav1.GetOptions{})
+	if err != nil {
+		return "", false
+	}
+	raw, ok := ns.Annotations[kubeapiserveradmission.NamespaceParamsAnnotation]
+	if !ok {
+		return "", false
+	}
+	var byPlugin map[string]map[string]string
+	if err := json.Unmarshal([]byte(raw), &byPlugin); err != nil {
+		return "", false
+	}
+	override, ok := byPlugin[priorityPluginName]["defaultPriorityClassName"]
+	return override, ok
+}
+
+// rankOnNode returns pod's 1-based rank by descending priority among all pods on its node, and
+// the total number of pods considered. It returns (0, 0, nil) if pod is not yet scheduled.
+func (o *ExplainOptions) rankOnNode(pod *v1.Pod) (int, int, error) {
+	if pod.Spec.NodeName == "" {
+		return 0, 0, nil
+	}
+
+	list, err := o.PodClient.Pods(metav1.NamespaceAll).List(metav1.ListOptions{
+		FieldSelector: fields.SelectorFromSet(fields.Set{"spec.nodeName": pod.Spec.NodeName}).String(),
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	pods := list.Items
+	sort.Slice(pods, func(i, j int) bool {
+		return priorityOf(&pods[i]) > priorityOf(&pods[j])
+	})
+
+	for i := range pods {
+		if pods[i].UID == pod.UID {
+			return i + 1, len(pods), nil
+		}
+	}
+	return 0, len(pods), nil
+}
+
+func priorityOf(pod *v1.Pod) int32 {
+	if pod.Spec.Priority == nil {
+		return 0
+	}
+	return *pod.Spec.Priority
+}
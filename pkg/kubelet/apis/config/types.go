@@ -192,6 +192,10 @@ type KubeletConfiguration struct {
 	// driver that the kubelet uses to manipulate cgroups on the host (cgroupfs or systemd)
 	CgroupDriver string
 	// CPUManagerPolicy is the name of the policy to use.
+	// Possible values: "none", "static", "static-priority".
+	// "static-priority" behaves like "static", additionally preferring sockets already used by
+	// higher-priority Guaranteed pods over introducing a lower-priority pod to a new one, so a
+	// wave of low-priority pods cannot claim every socket before a high-priority pod arrives.
 	// Requires the CPUManager feature gate to be enabled.
 	CPUManagerPolicy string
 	// CPU Manager reconciliation period.
@@ -311,6 +315,33 @@ type KubeletConfiguration struct {
 	// This flag accepts a list of options. Acceptable options are `pods`, `system-reserved` & `kube-reserved`.
 	// Refer to [Node Allocatable](https://git.k8s.io/community/contributors/design-proposals/node/node-allocatable.md) doc for more information.
 	EnforceNodeAllocatable []string
+	// PriorityBandwidthShaping maps pod priority to a network bandwidth class that is applied
+	// when a pod does not already request explicit bandwidth via the
+	// kubernetes.io/{ingress,egress}-bandwidth annotations. Only network plugins that support
+	// bandwidth shaping (currently kubenet) honor this setting. Classes are matched by the
+	// highest configured Threshold that is greater than or equal to the pod's priority; a pod
+	// whose priority exceeds every configured Threshold is left unshaped.
+	PriorityBandwidthShaping []PriorityBandwidthClass
+	// NonCriticalRestartPriorityThreshold, if set, marks pods whose priority is below it as
+	// non-critical for the purposes of CrashLoopBackOff restart deferral: while the node reports
+	// memory, disk, or PID pressure, such a pod's failed container is held in backoff for
+	// NonCriticalRestartBackOffUnderPressure instead of the normal exponential backoff, so
+	// repeated restart attempts do not add to the pressure that is already causing the node
+	// trouble. Pods at or above the threshold are restarted as if the node were not under
+	// pressure. Leave unset to disable this behavior.
+	NonCriticalRestartPriorityThreshold *int32
+	// NonCriticalRestartBackOffUnderPressure is the backoff period applied, in place of the
+	// normal exponential CrashLoopBackOff period, to a non-critical pod's restarts while the
+	// node is under pressure. Has no effect unless NonCriticalRestartPriorityThreshold is set.
+	NonCriticalRestartBackOffUnderPressure metav1.Duration
+}
+
+// PriorityBandwidthClass caps egress bandwidth for pods at or below a given priority.
+type PriorityBandwidthClass struct {
+	// Threshold is the highest pod priority this class applies to.
+	Threshold int32
+	// EgressBandwidth is the egress bandwidth limit applied to pods in this class, e.g. "10M".
+	EgressBandwidth string
 }
 
 type KubeletAuthorizationMode string
@@ -328,6 +328,9 @@ func autoConvert_v1beta1_KubeletConfiguration_To_config_KubeletConfiguration(in
 	out.SystemReservedCgroup = in.SystemReservedCgroup
 	out.KubeReservedCgroup = in.KubeReservedCgroup
 	out.EnforceNodeAllocatable = *(*[]string)(unsafe.Pointer(&in.EnforceNodeAllocatable))
+	out.PriorityBandwidthShaping = *(*[]config.PriorityBandwidthClass)(unsafe.Pointer(&in.PriorityBandwidthShaping))
+	out.NonCriticalRestartPriorityThreshold = in.NonCriticalRestartPriorityThreshold
+	out.NonCriticalRestartBackOffUnderPressure = in.NonCriticalRestartBackOffUnderPressure
 	return nil
 }
 
@@ -458,6 +461,9 @@ func autoConvert_config_KubeletConfiguration_To_v1beta1_KubeletConfiguration(in
 	out.SystemReservedCgroup = in.SystemReservedCgroup
 	out.KubeReservedCgroup = in.KubeReservedCgroup
 	out.EnforceNodeAllocatable = *(*[]string)(unsafe.Pointer(&in.EnforceNodeAllocatable))
+	out.PriorityBandwidthShaping = *(*[]v1beta1.PriorityBandwidthClass)(unsafe.Pointer(&in.PriorityBandwidthShaping))
+	out.NonCriticalRestartPriorityThreshold = in.NonCriticalRestartPriorityThreshold
+	out.NonCriticalRestartBackOffUnderPressure = in.NonCriticalRestartBackOffUnderPressure
 	return nil
 }
 
@@ -180,6 +180,16 @@ func (in *KubeletConfiguration) DeepCopyInto(out *KubeletConfiguration) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PriorityBandwidthShaping != nil {
+		in, out := &in.PriorityBandwidthShaping, &out.PriorityBandwidthShaping
+		*out = make([]PriorityBandwidthClass, len(*in))
+		copy(*out, *in)
+	}
+	if in.NonCriticalRestartPriorityThreshold != nil {
+		in, out := &in.NonCriticalRestartPriorityThreshold, &out.NonCriticalRestartPriorityThreshold
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -252,6 +262,22 @@ func (in *KubeletX509Authentication) DeepCopy() *KubeletX509Authentication {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PriorityBandwidthClass) DeepCopyInto(out *PriorityBandwidthClass) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PriorityBandwidthClass.
+func (in *PriorityBandwidthClass) DeepCopy() *PriorityBandwidthClass {
+	if in == nil {
+		return nil
+	}
+	out := new(PriorityBandwidthClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SerializedNodeConfigSource) DeepCopyInto(out *SerializedNodeConfigSource) {
 	*out = *in
@@ -131,6 +131,23 @@ func NewManager(cpuPolicyName string, reconcilePeriod time.Duration, machineInfo
 		numReservedCPUs := int(math.Ceil(reservedCPUsFloat))
 		policy = NewStaticPolicy(topo, numReservedCPUs)
 
+	case PolicyStaticPriority:
+		topo, err := topology.Discover(machineInfo)
+		if err != nil {
+			return nil, err
+		}
+		klog.Infof("[cpumanager] detected CPU topology: %v", topo)
+		reservedCPUs, ok := nodeAllocatableReservation[v1.ResourceCPU]
+		if !ok {
+			return nil, fmt.Errorf("[cpumanager] unable to determine reserved CPU resources for static-priority policy")
+		}
+		if reservedCPUs.IsZero() {
+			return nil, fmt.Errorf("[cpumanager] the static-priority policy requires systemreserved.cpu + kubereserved.cpu to be greater than zero")
+		}
+		reservedCPUsFloat := float64(reservedCPUs.MilliValue()) / 1000
+		numReservedCPUs := int(math.Ceil(reservedCPUsFloat))
+		policy = NewStaticPriorityPolicy(topo, numReservedCPUs)
+
 	default:
 		klog.Errorf("[cpumanager] Unknown policy \"%s\", falling back to default policy \"%s\"", cpuPolicyName, PolicyNone)
 		policy = NewNonePolicy()
@@ -0,0 +1,166 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpumanager
+
+import (
+	"k8s.io/api/core/v1"
+	"k8s.io/klog"
+	"k8s.io/kubernetes/pkg/kubelet/cm/cpumanager/state"
+	"k8s.io/kubernetes/pkg/kubelet/cm/cpumanager/topology"
+	"k8s.io/kubernetes/pkg/kubelet/cm/cpuset"
+)
+
+// PolicyStaticPriority is the name of the priority-aware static policy.
+const PolicyStaticPriority policyName = "static-priority"
+
+// highPriorityThreshold is the pod priority above which a Guaranteed container's exclusive CPU
+// request is treated as high priority by the static-priority policy. Default-priority pods carry
+// priority 0, so this reserves preferred sockets for pods that were explicitly given a
+// PriorityClass above default.
+const highPriorityThreshold = 0
+
+// staticPriorityPolicy extends staticPolicy with priority-aware socket selection: a high-priority
+// Guaranteed container's exclusive CPUs are packed onto the sockets already claimed by other
+// high-priority containers (or, failing that, the lowest-numbered free socket), while a
+// default-or-lower-priority container's CPUs are drawn from the remaining sockets whenever they
+// have room. This keeps a wave of low-priority pods from consuming every socket before a
+// high-priority pod arrives. Because staticPolicy never reassigns CPUs away from a running
+// container, a high-priority pod's allocation, once made, can never be displaced by a
+// lower-priority pod arriving afterwards.
+type staticPriorityPolicy struct {
+	*staticPolicy
+	// highPrioritySockets is the set of socket IDs that have hosted at least one high-priority
+	// exclusive allocation. New high-priority allocations prefer these sockets first, and new
+	// low-priority allocations avoid them while other sockets have room.
+	highPrioritySockets cpuset.CPUSet
+}
+
+var _ Policy = &staticPriorityPolicy{}
+
+// NewStaticPriorityPolicy returns a CPU manager policy that behaves like the static policy but
+// additionally biases exclusive core allocation towards preferred sockets based on pod priority.
+func NewStaticPriorityPolicy(topology *topology.CPUTopology, numReservedCPUs int) Policy {
+	return &staticPriorityPolicy{
+		staticPolicy:        NewStaticPolicy(topology, numReservedCPUs).(*staticPolicy),
+		highPrioritySockets: cpuset.NewCPUSet(),
+	}
+}
+
+func (p *staticPriorityPolicy) Name() string {
+	return string(PolicyStaticPriority)
+}
+
+func (p *staticPriorityPolicy) AddContainer(s state.State, pod *v1.Pod, container *v1.Container, containerID string) error {
+	numCPUs := guaranteedCPUs(pod, container)
+	if numCPUs == 0 {
+		return nil
+	}
+	klog.Infof("[cpumanager] static-priority policy: AddContainer (pod: %s, container: %s, container id: %s)", pod.Name, container.Name, containerID)
+
+	if _, ok := s.GetCPUSet(containerID); ok {
+		klog.Infof("[cpumanager] static-priority policy: container already present in state, skipping (container: %s, container id: %s)", container.Name, containerID)
+		return nil
+	}
+
+	result, err := p.allocateCPUs(s, numCPUs, podPriority(pod))
+	if err != nil {
+		klog.Errorf("[cpumanager] unable to allocate %d CPUs (container id: %s, error: %v)", numCPUs, containerID, err)
+		return err
+	}
+	s.SetCPUSet(containerID, result)
+	return nil
+}
+
+// allocateCPUs picks numCPUs exclusive CPUs for a container at the given priority, preferring
+// sockets appropriate to that priority (see staticPriorityPolicy), and falls back to a
+// topology-aware selection across every assignable CPU if the preferred sockets alone can't
+// satisfy the request.
+func (p *staticPriorityPolicy) allocateCPUs(s state.State, numCPUs int, priority int32) (cpuset.CPUSet, error) {
+	assignable := p.assignableCPUs(s)
+	preferred := p.preferredCPUs(assignable, priority)
+
+	result, err := takeByTopology(p.topology, preferred, numCPUs)
+	if err != nil {
+		// The preferred sockets don't have room; fall back to the full assignable pool rather
+		// than fail the allocation outright.
+		result, err = takeByTopology(p.topology, assignable, numCPUs)
+		if err != nil {
+			return cpuset.NewCPUSet(), err
+		}
+	}
+
+	if priority > highPriorityThreshold {
+		p.highPrioritySockets = p.highPrioritySockets.Union(p.topology.CPUDetails.KeepOnly(result).Sockets())
+	}
+
+	s.SetDefaultCPUSet(s.GetDefaultCPUSet().Difference(result))
+	klog.Infof("[cpumanager] static-priority policy: allocateCPUs: returning \"%v\" for priority %d", result, priority)
+	return result, nil
+}
+
+// preferredCPUs narrows assignable to the sockets appropriate for priority: a high-priority
+// container prefers sockets already claimed by other high-priority containers, or, if none are
+// claimed yet, the lowest-numbered socket present in assignable. A default-or-lower-priority
+// container prefers every socket except those already claimed for high priority. Either way, if
+// the preference would leave no CPUs at all, the full assignable set is returned so a narrow
+// preference never turns into an outright allocation failure.
+func (p *staticPriorityPolicy) preferredCPUs(assignable cpuset.CPUSet, priority int32) cpuset.CPUSet {
+	details := p.topology.CPUDetails.KeepOnly(assignable)
+
+	if priority > highPriorityThreshold {
+		reserved := cpuset.NewCPUSet()
+		for _, socket := range details.Sockets().ToSlice() {
+			if p.highPrioritySockets.Contains(socket) {
+				reserved = reserved.Union(details.CPUsInSocket(socket))
+			}
+		}
+		if !reserved.IsEmpty() {
+			return reserved
+		}
+		return lowestNumberedSocket(details, assignable)
+	}
+
+	unreserved := cpuset.NewCPUSet()
+	for _, socket := range details.Sockets().ToSlice() {
+		if !p.highPrioritySockets.Contains(socket) {
+			unreserved = unreserved.Union(details.CPUsInSocket(socket))
+		}
+	}
+	if unreserved.IsEmpty() {
+		return assignable
+	}
+	return unreserved
+}
+
+// lowestNumberedSocket returns the CPUs of the lowest-numbered socket present in details, or
+// assignable itself if details has no sockets at all.
+func lowestNumberedSocket(details topology.CPUDetails, assignable cpuset.CPUSet) cpuset.CPUSet {
+	sockets := details.Sockets().ToSlice() // already sorted ascending
+	if len(sockets) == 0 {
+		return assignable
+	}
+	return details.CPUsInSocket(sockets[0])
+}
+
+// podPriority returns pod's effective scheduling priority, or 0 (the default priority class's
+// value) if unset.
+func podPriority(pod *v1.Pod) int32 {
+	if pod.Spec.Priority == nil {
+		return 0
+	}
+	return *pod.Spec.Priority
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpumanager
+
+import (
+	"testing"
+
+	"k8s.io/kubernetes/pkg/kubelet/cm/cpumanager/state"
+)
+
+func TestStaticPriorityPolicyName(t *testing.T) {
+	policy := NewStaticPriorityPolicy(topoDualSocketHT, 0)
+	if got := policy.Name(); got != "static-priority" {
+		t.Errorf("StaticPriorityPolicy Name() error. expected: static-priority, returned: %v", got)
+	}
+}
+
+func TestStaticPriorityPolicyReservesSocketForHighPriority(t *testing.T) {
+	policy := NewStaticPriorityPolicy(topoDualSocketHT, 0)
+	st := &mockState{
+		assignments:   state.ContainerCPUAssignments{},
+		defaultCPUSet: topoDualSocketHT.CPUDetails.CPUs(),
+	}
+	socket0 := topoDualSocketHT.CPUDetails.CPUsInSocket(0)
+	socket1 := topoDualSocketHT.CPUDetails.CPUsInSocket(1)
+
+	highPod := makePod("2", "2")
+	highPriority := int32(1000)
+	highPod.Spec.Priority = &highPriority
+	if err := policy.AddContainer(st, highPod, &highPod.Spec.Containers[0], "high1"); err != nil {
+		t.Fatalf("AddContainer for high priority pod failed: %v", err)
+	}
+	highAlloc, _ := st.GetCPUSet("high1")
+	if !highAlloc.IsSubsetOf(socket0) {
+		t.Errorf("expected the first high-priority allocation to land on socket 0 (%v), got %v", socket0, highAlloc)
+	}
+
+	lowPod := makePod("4", "4")
+	if err := policy.AddContainer(st, lowPod, &lowPod.Spec.Containers[0], "low1"); err != nil {
+		t.Fatalf("AddContainer for low priority pod failed: %v", err)
+	}
+	lowAlloc, _ := st.GetCPUSet("low1")
+	if !lowAlloc.IsSubsetOf(socket1) {
+		t.Errorf("expected the low-priority allocation to avoid the high-priority socket 0 (%v), got %v", socket1, lowAlloc)
+	}
+
+	highPod2 := makePod("2", "2")
+	highPod2.Spec.Priority = &highPriority
+	if err := policy.AddContainer(st, highPod2, &highPod2.Spec.Containers[0], "high2"); err != nil {
+		t.Fatalf("AddContainer for second high priority pod failed: %v", err)
+	}
+	highAlloc2, _ := st.GetCPUSet("high2")
+	if !highAlloc2.IsSubsetOf(socket0) {
+		t.Errorf("expected the second high-priority allocation to keep packing onto socket 0 (%v), got %v", socket0, highAlloc2)
+	}
+	if !highAlloc2.Intersection(lowAlloc).IsEmpty() {
+		t.Errorf("high-priority allocation %v must not overlap the already-running low-priority allocation %v", highAlloc2, lowAlloc)
+	}
+}
+
+func TestStaticPriorityPolicyFallsBackWhenPreferredSocketIsFull(t *testing.T) {
+	policy := NewStaticPriorityPolicy(topoDualSocketHT, 0)
+	st := &mockState{
+		assignments:   state.ContainerCPUAssignments{},
+		defaultCPUSet: topoDualSocketHT.CPUDetails.CPUs(),
+	}
+
+	highPriority := int32(1000)
+	fillSocket0 := makePod("6", "6")
+	fillSocket0.Spec.Priority = &highPriority
+	if err := policy.AddContainer(st, fillSocket0, &fillSocket0.Spec.Containers[0], "fill0"); err != nil {
+		t.Fatalf("AddContainer failed: %v", err)
+	}
+
+	overflow := makePod("2", "2")
+	overflow.Spec.Priority = &highPriority
+	if err := policy.AddContainer(st, overflow, &overflow.Spec.Containers[0], "overflow"); err != nil {
+		t.Fatalf("expected the allocation to fall back to the other socket once socket 0 is full, got error: %v", err)
+	}
+	overflowAlloc, _ := st.GetCPUSet("overflow")
+	if overflowAlloc.IsEmpty() || !overflowAlloc.IsSubsetOf(topoDualSocketHT.CPUDetails.CPUsInSocket(1)) {
+		t.Errorf("expected the overflow allocation to land on socket 1, got %v", overflowAlloc)
+	}
+}
@@ -26,6 +26,7 @@ const (
 	PreemptContainer        = "Preempting"
 	BackOffStartContainer   = "BackOff"
 	ExceededGracePeriod     = "ExceededGracePeriod"
+	DeferredContainerStart  = "RestartDeferred"
 
 	// Pod event reason list
 	FailedToKillPod                = "FailedKillPod"
@@ -681,6 +681,10 @@ func NewMainKubelet(kubeCfg *kubeletconfiginternal.KubeletConfiguration,
 		kubeDeps.ContainerManager.InternalContainerLifecycle(),
 		legacyLogProvider,
 		klet.runtimeClassManager,
+		kubeCfg.PriorityBandwidthShaping,
+		klet,
+		kubeCfg.NonCriticalRestartPriorityThreshold,
+		kubeCfg.NonCriticalRestartBackOffUnderPressure,
 	)
 	if err != nil {
 		return nil, err
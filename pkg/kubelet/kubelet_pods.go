@@ -895,6 +895,21 @@ func (kl *Kubelet) IsPodDeleted(uid types.UID) bool {
 	return eviction.PodIsEvicted(status) || (pod.DeletionTimestamp != nil && notRunning(status.ContainerStatuses))
 }
 
+// IsUnderMemoryPressure returns true if the node is currently under memory pressure.
+func (kl *Kubelet) IsUnderMemoryPressure() bool {
+	return kl.evictionManager.IsUnderMemoryPressure()
+}
+
+// IsUnderDiskPressure returns true if the node is currently under disk pressure.
+func (kl *Kubelet) IsUnderDiskPressure() bool {
+	return kl.evictionManager.IsUnderDiskPressure()
+}
+
+// IsUnderPIDPressure returns true if the node is currently under PID pressure.
+func (kl *Kubelet) IsUnderPIDPressure() bool {
+	return kl.evictionManager.IsUnderPIDPressure()
+}
+
 // PodResourcesAreReclaimed returns true if all required node-level resources that a pod was consuming have
 // been reclaimed by the kubelet.  Reclaiming resources is a prerequisite to deleting a pod from the API server.
 func (kl *Kubelet) PodResourcesAreReclaimed(pod *v1.Pod, status v1.PodStatus) bool {
@@ -37,6 +37,7 @@ import (
 	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1alpha2"
 	"k8s.io/kubernetes/pkg/api/legacyscheme"
 	"k8s.io/kubernetes/pkg/credentialprovider"
+	kubeletconfig "k8s.io/kubernetes/pkg/kubelet/apis/config"
 	"k8s.io/kubernetes/pkg/kubelet/cm"
 	kubecontainer "k8s.io/kubernetes/pkg/kubelet/container"
 	"k8s.io/kubernetes/pkg/kubelet/events"
@@ -75,6 +76,14 @@ type podStateProvider interface {
 	IsPodTerminated(kubetypes.UID) bool
 }
 
+// NodePressureProvider reports the node conditions consulted to defer non-critical pod restarts;
+// eviction.Manager satisfies it.
+type NodePressureProvider interface {
+	IsUnderMemoryPressure() bool
+	IsUnderDiskPressure() bool
+	IsUnderPIDPressure() bool
+}
+
 type kubeGenericRuntimeManager struct {
 	runtimeName         string
 	recorder            record.EventRecorder
@@ -129,6 +138,24 @@ type kubeGenericRuntimeManager struct {
 
 	// Cache last per-container error message to reduce log spam
 	logReduction *logreduction.LogReduction
+
+	// Priority-based network bandwidth shaping classes, consulted when a pod does not already
+	// request explicit bandwidth shaping via annotations.
+	priorityBandwidthShaping []kubeletconfig.PriorityBandwidthClass
+
+	// nodePressureProvider reports whether the node is currently under memory, disk, or PID
+	// pressure, consulted by doBackOff to decide whether a non-critical pod's restart should be
+	// deferred. Nil if NonCriticalRestartPriorityThreshold is unset.
+	nodePressureProvider NodePressureProvider
+
+	// nonCriticalRestartPriorityThreshold, and pods with a lower priority than it, have their
+	// CrashLoopBackOff restarts deferred to pressureRestartBackOff while nodePressureProvider
+	// reports pressure. Nil disables this behavior.
+	nonCriticalRestartPriorityThreshold *int32
+
+	// pressureRestartBackOff is the backoff applied, in place of the passed-in backOff, to a
+	// non-critical pod's restarts while the node is under pressure.
+	pressureRestartBackOff *flowcontrol.Backoff
 }
 
 // KubeGenericRuntime is a interface contains interfaces for container runtime and command.
@@ -166,24 +193,37 @@ func NewKubeGenericRuntimeManager(
 	internalLifecycle cm.InternalContainerLifecycle,
 	legacyLogProvider LegacyLogProvider,
 	runtimeClassManager *runtimeclass.Manager,
+	priorityBandwidthShaping []kubeletconfig.PriorityBandwidthClass,
+	nodePressureProvider NodePressureProvider,
+	nonCriticalRestartPriorityThreshold *int32,
+	nonCriticalRestartBackOffUnderPressure metav1.Duration,
 ) (KubeGenericRuntime, error) {
 	kubeRuntimeManager := &kubeGenericRuntimeManager{
-		recorder:            recorder,
-		cpuCFSQuota:         cpuCFSQuota,
-		cpuCFSQuotaPeriod:   cpuCFSQuotaPeriod,
-		seccompProfileRoot:  seccompProfileRoot,
-		livenessManager:     livenessManager,
-		containerRefManager: containerRefManager,
-		machineInfo:         machineInfo,
-		osInterface:         osInterface,
-		runtimeHelper:       runtimeHelper,
-		runtimeService:      newInstrumentedRuntimeService(runtimeService),
-		imageService:        newInstrumentedImageManagerService(imageService),
-		keyring:             credentialprovider.NewDockerKeyring(),
-		internalLifecycle:   internalLifecycle,
-		legacyLogProvider:   legacyLogProvider,
-		runtimeClassManager: runtimeClassManager,
-		logReduction:        logreduction.NewLogReduction(identicalErrorDelay),
+		recorder:                            recorder,
+		cpuCFSQuota:                         cpuCFSQuota,
+		cpuCFSQuotaPeriod:                   cpuCFSQuotaPeriod,
+		seccompProfileRoot:                  seccompProfileRoot,
+		livenessManager:                     livenessManager,
+		containerRefManager:                 containerRefManager,
+		machineInfo:                         machineInfo,
+		osInterface:                         osInterface,
+		runtimeHelper:                       runtimeHelper,
+		runtimeService:                      newInstrumentedRuntimeService(runtimeService),
+		imageService:                        newInstrumentedImageManagerService(imageService),
+		keyring:                             credentialprovider.NewDockerKeyring(),
+		internalLifecycle:                   internalLifecycle,
+		legacyLogProvider:                   legacyLogProvider,
+		runtimeClassManager:                 runtimeClassManager,
+		logReduction:                        logreduction.NewLogReduction(identicalErrorDelay),
+		priorityBandwidthShaping:            priorityBandwidthShaping,
+		nodePressureProvider:                nodePressureProvider,
+		nonCriticalRestartPriorityThreshold: nonCriticalRestartPriorityThreshold,
+	}
+
+	if nonCriticalRestartPriorityThreshold != nil && nonCriticalRestartBackOffUnderPressure.Duration > 0 {
+		kubeRuntimeManager.pressureRestartBackOff = flowcontrol.NewBackOff(
+			nonCriticalRestartBackOffUnderPressure.Duration,
+			nonCriticalRestartBackOffUnderPressure.Duration)
 	}
 
 	typedVersion, err := kubeRuntimeManager.runtimeService.Version(kubeRuntimeAPIVersion)
@@ -781,6 +821,22 @@ func (m *kubeGenericRuntimeManager) SyncPod(pod *v1.Pod, podStatus *kubecontaine
 	return
 }
 
+// nonCriticalRestartBackOff returns the backoff to use for a container restart of a pod whose
+// priority is below nonCriticalRestartPriorityThreshold while the node is under memory, disk, or
+// PID pressure, or nil if the pod does not qualify for deferral right now.
+func (m *kubeGenericRuntimeManager) nonCriticalRestartBackOff(pod *v1.Pod) *flowcontrol.Backoff {
+	if m.pressureRestartBackOff == nil || m.nodePressureProvider == nil {
+		return nil
+	}
+	if podPriorityOrZero(pod) >= *m.nonCriticalRestartPriorityThreshold {
+		return nil
+	}
+	if !m.nodePressureProvider.IsUnderMemoryPressure() && !m.nodePressureProvider.IsUnderDiskPressure() && !m.nodePressureProvider.IsUnderPIDPressure() {
+		return nil
+	}
+	return m.pressureRestartBackOff
+}
+
 // If a container is still in backoff, the function will return a brief backoff error and
 // a detailed error message.
 func (m *kubeGenericRuntimeManager) doBackOff(pod *v1.Pod, container *v1.Container, podStatus *kubecontainer.PodStatus, backOff *flowcontrol.Backoff) (bool, string, error) {
@@ -801,6 +857,12 @@ func (m *kubeGenericRuntimeManager) doBackOff(pod *v1.Pod, container *v1.Contain
 	ts := cStatus.FinishedAt
 	// backOff requires a unique key to identify the container.
 	key := getStableKey(pod, container)
+	if deferBackOff := m.nonCriticalRestartBackOff(pod); deferBackOff != nil {
+		backOff = deferBackOff
+		if ref, err := kubecontainer.GenerateContainerRef(pod, container); err == nil {
+			m.recorder.Eventf(ref, v1.EventTypeNormal, events.DeferredContainerStart, "Deferring restart of non-critical container while node is under pressure")
+		}
+	}
 	if backOff.IsInBackOffSince(key, ts) {
 		if ref, err := kubecontainer.GenerateContainerRef(pod, container); err == nil {
 			m.recorder.Eventf(ref, v1.EventTypeWarning, events.BackOffStartContainer, "Back-off restarting failed container")
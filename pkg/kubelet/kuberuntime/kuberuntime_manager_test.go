@@ -259,6 +259,59 @@ func TestNewKubeRuntimeManager(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+type fakeNodePressureProvider struct {
+	memory, disk, pid bool
+}
+
+func (f *fakeNodePressureProvider) IsUnderMemoryPressure() bool { return f.memory }
+func (f *fakeNodePressureProvider) IsUnderDiskPressure() bool   { return f.disk }
+func (f *fakeNodePressureProvider) IsUnderPIDPressure() bool    { return f.pid }
+
+func TestNonCriticalRestartBackOff(t *testing.T) {
+	lowPriority := int32(10)
+	highPriority := int32(100)
+	threshold := int32(50)
+
+	for desc, test := range map[string]struct {
+		podPriority  *int32
+		underPressure bool
+		expectDefer  bool
+	}{
+		"low priority pod while under pressure is deferred": {
+			podPriority:   &lowPriority,
+			underPressure: true,
+			expectDefer:   true,
+		},
+		"low priority pod while not under pressure is not deferred": {
+			podPriority:   &lowPriority,
+			underPressure: false,
+			expectDefer:   false,
+		},
+		"high priority pod while under pressure is not deferred": {
+			podPriority:   &highPriority,
+			underPressure: true,
+			expectDefer:   false,
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			_, _, m, err := createTestRuntimeManager()
+			require.NoError(t, err)
+
+			m.nonCriticalRestartPriorityThreshold = &threshold
+			m.pressureRestartBackOff = flowcontrol.NewBackOff(time.Minute, time.Minute)
+			m.nodePressureProvider = &fakeNodePressureProvider{memory: test.underPressure}
+
+			pod := &v1.Pod{Spec: v1.PodSpec{Priority: test.podPriority}}
+			backOff := m.nonCriticalRestartBackOff(pod)
+			if test.expectDefer {
+				assert.Equal(t, m.pressureRestartBackOff, backOff)
+			} else {
+				assert.Nil(t, backOff)
+			}
+		})
+	}
+}
+
 func TestVersion(t *testing.T) {
 	_, _, m, err := createTestRuntimeManager()
 	assert.NoError(t, err)
@@ -85,7 +85,7 @@ func (m *kubeGenericRuntimeManager) generatePodSandboxConfig(pod *v1.Pod, attemp
 			Attempt:   attempt,
 		},
 		Labels:      newPodLabels(pod),
-		Annotations: newPodAnnotations(pod),
+		Annotations: m.newPodAnnotations(pod),
 	}
 
 	dnsConfig, err := m.runtimeHelper.GetPodDNS(pod)
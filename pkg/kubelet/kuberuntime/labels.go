@@ -23,6 +23,7 @@ import (
 	"k8s.io/api/core/v1"
 	kubetypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog"
+	kubeletconfig "k8s.io/kubernetes/pkg/kubelet/apis/config"
 	kubecontainer "k8s.io/kubernetes/pkg/kubelet/container"
 	"k8s.io/kubernetes/pkg/kubelet/types"
 	"k8s.io/kubernetes/pkg/kubelet/util/format"
@@ -88,9 +89,54 @@ func newPodLabels(pod *v1.Pod) map[string]string {
 	return labels
 }
 
-// newPodAnnotations creates pod annotations from v1.Pod.
-func newPodAnnotations(pod *v1.Pod) map[string]string {
-	return pod.Annotations
+// egressBandwidthAnnotationKey is the pod annotation kubenet reads to shape a pod's egress
+// traffic. It is kept in sync with pkg/util/bandwidth's understanding of the same key.
+const egressBandwidthAnnotationKey = "kubernetes.io/egress-bandwidth"
+
+// newPodAnnotations creates pod annotations from v1.Pod. If the pod does not already request
+// explicit bandwidth shaping and the kubelet is configured with PriorityBandwidthShaping, the
+// highest-priority matching class is synthesized as an egress-bandwidth annotation so that
+// network plugins which already understand that annotation (currently kubenet) shape the pod
+// without needing to know anything about pod priority themselves.
+func (m *kubeGenericRuntimeManager) newPodAnnotations(pod *v1.Pod) map[string]string {
+	annotations := pod.Annotations
+	if _, ok := annotations[egressBandwidthAnnotationKey]; ok {
+		return annotations
+	}
+	class := priorityBandwidthClassFor(m.priorityBandwidthShaping, podPriorityOrZero(pod))
+	if class == nil {
+		return annotations
+	}
+	shaped := make(map[string]string, len(annotations)+1)
+	for k, v := range annotations {
+		shaped[k] = v
+	}
+	shaped[egressBandwidthAnnotationKey] = class.EgressBandwidth
+	return shaped
+}
+
+// priorityBandwidthClassFor returns the class with the highest Threshold that is still >= priority,
+// or nil if priority exceeds every configured Threshold.
+func priorityBandwidthClassFor(classes []kubeletconfig.PriorityBandwidthClass, priority int32) *kubeletconfig.PriorityBandwidthClass {
+	var best *kubeletconfig.PriorityBandwidthClass
+	for i := range classes {
+		class := &classes[i]
+		if priority > class.Threshold {
+			continue
+		}
+		if best == nil || class.Threshold > best.Threshold {
+			best = class
+		}
+	}
+	return best
+}
+
+// podPriorityOrZero returns the pod's priority, or 0 if it has not been resolved yet.
+func podPriorityOrZero(pod *v1.Pod) int32 {
+	if pod.Spec.Priority == nil {
+		return 0
+	}
+	return *pod.Spec.Priority
 }
 
 // newContainerLabels creates container labels from v1.Container and v1.Pod.
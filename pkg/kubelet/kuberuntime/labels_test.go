@@ -20,9 +20,11 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/require"
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	kubeletconfig "k8s.io/kubernetes/pkg/kubelet/apis/config"
 	kubecontainer "k8s.io/kubernetes/pkg/kubelet/container"
 )
 
@@ -215,6 +217,9 @@ func TestPodLabels(t *testing.T) {
 }
 
 func TestPodAnnotations(t *testing.T) {
+	_, _, m, err := createTestRuntimeManager()
+	require.NoError(t, err)
+
 	pod := &v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        "test_pod",
@@ -231,9 +236,35 @@ func TestPodAnnotations(t *testing.T) {
 	}
 
 	// Test whether we can get right information from annotations
-	annotations := newPodAnnotations(pod)
+	annotations := m.newPodAnnotations(pod)
 	podSandboxInfo := getPodSandboxInfoFromAnnotations(annotations)
 	if !reflect.DeepEqual(podSandboxInfo, expected) {
 		t.Errorf("expected %v, got %v", expected, podSandboxInfo)
 	}
 }
+
+func TestPodAnnotationsPriorityBandwidthShaping(t *testing.T) {
+	_, _, m, err := createTestRuntimeManager()
+	require.NoError(t, err)
+	m.priorityBandwidthShaping = []kubeletconfig.PriorityBandwidthClass{
+		{Threshold: 0, EgressBandwidth: "1M"},
+		{Threshold: 100, EgressBandwidth: "10M"},
+	}
+
+	lowPriority := int32(0)
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test_pod", Namespace: "test_pod_namespace", UID: "test_pod_uid"},
+		Spec:       v1.PodSpec{Priority: &lowPriority},
+	}
+	annotations := m.newPodAnnotations(pod)
+	if annotations[egressBandwidthAnnotationKey] != "1M" {
+		t.Errorf("expected synthesized egress-bandwidth annotation %q, got %q", "1M", annotations[egressBandwidthAnnotationKey])
+	}
+
+	// An explicit annotation is never overridden.
+	pod.Annotations = map[string]string{egressBandwidthAnnotationKey: "5M"}
+	annotations = m.newPodAnnotations(pod)
+	if annotations[egressBandwidthAnnotationKey] != "5M" {
+		t.Errorf("expected explicit egress-bandwidth annotation to be preserved, got %q", annotations[egressBandwidthAnnotationKey])
+	}
+}
@@ -41,6 +41,19 @@ import (
 // the name used for object count quota
 var podObjectCountName = generic.ObjectCountQuotaResourceNameFor(corev1.SchemeGroupVersion.WithResource("pods").GroupResource())
 
+// podPriorityClassCountResourcePrefix names a per-PriorityClass object count quota, e.g.
+// "count/pods.scheduling.k8s.io/high-priority" limits how many pods using the "high-priority"
+// PriorityClass may exist in a namespace. It complements the PriorityClass ResourceQuotaScope
+// (which gates an entire quota on a set of priority classes) by letting a namespace instead carry
+// one quota per class, at whatever granularity an admin wants to hand out separately.
+const podPriorityClassCountResourcePrefix = "count/pods.scheduling.k8s.io/"
+
+// podPriorityClassCountResourceNameFor returns the object count quota resource name that tracks
+// pods using the given PriorityClass.
+func podPriorityClassCountResourceNameFor(priorityClassName string) corev1.ResourceName {
+	return corev1.ResourceName(podPriorityClassCountResourcePrefix + priorityClassName)
+}
+
 // podResources are the set of resources managed by quota associated with pods.
 var podResources = []corev1.ResourceName{
 	podObjectCountName,
@@ -168,6 +181,10 @@ func (p *podEvaluator) MatchingResources(input []corev1.ResourceName) []corev1.R
 		if isExtendedResourceNameForQuota(resource) {
 			result = append(result, resource)
 		}
+		// for per-PriorityClass object count quotas
+		if strings.HasPrefix(string(resource), podPriorityClassCountResourcePrefix) {
+			result = append(result, resource)
+		}
 	}
 
 	return result
@@ -329,6 +346,9 @@ func PodUsageFunc(obj runtime.Object, clock clock.Clock) (corev1.ResourceList, e
 	result := corev1.ResourceList{
 		podObjectCountName: *(resource.NewQuantity(1, resource.DecimalSI)),
 	}
+	if len(pod.Spec.PriorityClassName) != 0 {
+		result[podPriorityClassCountResourceNameFor(pod.Spec.PriorityClassName)] = *(resource.NewQuantity(1, resource.DecimalSI))
+	}
 
 	// by convention, we do not quota compute resources that have reached end-of life
 	// note: the "pods" resource is considered a compute resource since it is tied to life-cycle.
@@ -415,6 +415,27 @@ func TestPodEvaluatorUsage(t *testing.T) {
 				generic.ObjectCountQuotaResourceNameFor(schema.GroupResource{Resource: "pods"}): resource.MustParse("1"),
 			},
 		},
+		"with priority class": {
+			pod: &api.Pod{
+				Spec: api.PodSpec{
+					PriorityClassName: "high-priority",
+					Containers: []api.Container{{
+						Resources: api.ResourceRequirements{
+							Requests: api.ResourceList{api.ResourceCPU: resource.MustParse("1m")},
+							Limits:   api.ResourceList{api.ResourceCPU: resource.MustParse("2m")},
+						},
+					}},
+				},
+			},
+			usage: corev1.ResourceList{
+				corev1.ResourceRequestsCPU: resource.MustParse("1m"),
+				corev1.ResourceLimitsCPU:   resource.MustParse("2m"),
+				corev1.ResourcePods:        resource.MustParse("1"),
+				corev1.ResourceCPU:         resource.MustParse("1m"),
+				generic.ObjectCountQuotaResourceNameFor(schema.GroupResource{Resource: "pods"}): resource.MustParse("1"),
+				podPriorityClassCountResourceNameFor("high-priority"):                           resource.MustParse("1"),
+			},
+		},
 	}
 	for testName, testCase := range testCases {
 		actual, err := evaluator.Usage(testCase.pod)
@@ -426,3 +447,17 @@ func TestPodEvaluatorUsage(t *testing.T) {
 		}
 	}
 }
+
+func TestPodEvaluatorMatchingResources(t *testing.T) {
+	evaluator := NewPodEvaluator(nil, clock.RealClock{})
+	input := []corev1.ResourceName{
+		corev1.ResourceCPU,
+		podPriorityClassCountResourceNameFor("high-priority"),
+		corev1.ResourceName("count/deployments.apps"),
+	}
+	result := evaluator.MatchingResources(input)
+	expected := quota.ToSet([]corev1.ResourceName{corev1.ResourceCPU, podPriorityClassCountResourceNameFor("high-priority")})
+	if !expected.Equal(quota.ToSet(result)) {
+		t.Errorf("expected %v, actual %v", expected, quota.ToSet(result))
+	}
+}
@@ -49,6 +49,8 @@ var (
 	ErrTaintsTolerationsNotMatch = newPredicateFailureError("PodToleratesNodeTaints", "node(s) had taints that the pod didn't tolerate")
 	// ErrPodNotMatchHostName is used for HostName predicate error.
 	ErrPodNotMatchHostName = newPredicateFailureError("HostName", "node(s) didn't match the requested hostname")
+	// ErrPodPriorityQuotaGated is used for PodFitsPriorityQuotaGate predicate error.
+	ErrPodPriorityQuotaGated = newPredicateFailureError("PodFitsPriorityQuotaGate", "pod is gated pending priority-class quota availability")
 	// ErrPodNotFitsHostPorts is used for PodFitsHostPorts predicate error.
 	ErrPodNotFitsHostPorts = newPredicateFailureError("PodFitsHostPorts", "node(s) didn't have free ports for the requested pod ports")
 	// ErrNodeLabelPresenceViolated is used for CheckNodeLabelPresence predicate error.
@@ -78,6 +80,10 @@ var (
 	// ErrFakePredicate is used for test only. The fake predicates returning false also returns error
 	// as ErrFakePredicate.
 	ErrFakePredicate = newPredicateFailureError("FakePredicateError", "Nodes failed the fake predicate")
+	// ErrNodeNotReadyForNonCriticalPods is used for CriticalDaemonSetPods predicate error.
+	ErrNodeNotReadyForNonCriticalPods = newPredicateFailureError("CriticalDaemonSetPodsNotReady", "node(s) were not yet running a required system-node-critical DaemonSet pod")
+	// ErrExtendedResourcePriorityTooLow is used for the ExtendedResourcePriorityFloor predicate error.
+	ErrExtendedResourcePriorityTooLow = newPredicateFailureError("ExtendedResourcePriorityFloor", "pod's priority is below the minimum required to request this extended resource")
 )
 
 // InsufficientResourceError is an error type that indicates what kind of resource limit is
@@ -0,0 +1,80 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+	schedulernodeinfo "k8s.io/kubernetes/pkg/scheduler/nodeinfo"
+)
+
+func newPriorityResourcePod(priority int32, usage schedulernodeinfo.Resource) *v1.Pod {
+	pod := newResourcePod(usage)
+	pod.Spec.Priority = &priority
+	return pod
+}
+
+func TestNodeOvercommitPredicate(t *testing.T) {
+	tests := []struct {
+		name                 string
+		priorityThreshold    int32
+		overcommitPercentage int32
+		pod                  *v1.Pod
+		fits                 bool
+	}{
+		{
+			name:                 "low priority pod fits within overcommit allowance",
+			priorityThreshold:    10,
+			overcommitPercentage: 120,
+			pod:                  newPriorityResourcePod(0, schedulernodeinfo.Resource{MilliCPU: 11, Memory: 1}),
+			fits:                 true,
+		},
+		{
+			name:                 "low priority pod still fails beyond overcommit allowance",
+			priorityThreshold:    10,
+			overcommitPercentage: 120,
+			pod:                  newPriorityResourcePod(0, schedulernodeinfo.Resource{MilliCPU: 13, Memory: 1}),
+			fits:                 false,
+		},
+		{
+			name:                 "high priority pod is checked against real allocatable, not overcommit",
+			priorityThreshold:    10,
+			overcommitPercentage: 120,
+			pod:                  newPriorityResourcePod(10, schedulernodeinfo.Resource{MilliCPU: 11, Memory: 1}),
+			fits:                 false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nodeInfo := schedulernodeinfo.NewNodeInfo()
+			node := v1.Node{Status: v1.NodeStatus{Allocatable: makeAllocatableResources(10, 20, 32, 5, 20, 5)}}
+			nodeInfo.SetNode(&node)
+
+			predicate := NewNodeOvercommitPredicate(test.priorityThreshold, test.overcommitPercentage)
+			meta := GetPredicateMetadata(test.pod, nil)
+			fits, _, err := predicate(test.pod, meta, nodeInfo)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fits != test.fits {
+				t.Errorf("expected fits=%v, got %v", test.fits, fits)
+			}
+		})
+	}
+}
@@ -31,6 +31,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/apimachinery/pkg/util/sets"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
@@ -39,10 +40,12 @@ import (
 	volumehelpers "k8s.io/cloud-provider/volume/helpers"
 	v1helper "k8s.io/kubernetes/pkg/apis/core/v1/helper"
 	v1qos "k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
+	"k8s.io/kubernetes/pkg/apis/scheduling"
 	"k8s.io/kubernetes/pkg/features"
 	"k8s.io/kubernetes/pkg/scheduler/algorithm"
 	priorityutil "k8s.io/kubernetes/pkg/scheduler/algorithm/priorities/util"
 	schedulerapi "k8s.io/kubernetes/pkg/scheduler/api"
+	"k8s.io/kubernetes/pkg/scheduler/metrics"
 	schedulernodeinfo "k8s.io/kubernetes/pkg/scheduler/nodeinfo"
 	schedutil "k8s.io/kubernetes/pkg/scheduler/util"
 	"k8s.io/kubernetes/pkg/scheduler/volumebinder"
@@ -60,6 +63,8 @@ const (
 	GeneralPred = "GeneralPredicates"
 	// HostNamePred defines the name of predicate HostName.
 	HostNamePred = "HostName"
+	// PodFitsPriorityQuotaGatePred defines the name of predicate PodFitsPriorityQuotaGate.
+	PodFitsPriorityQuotaGatePred = "PodFitsPriorityQuotaGate"
 	// PodFitsHostPortsPred defines the name of predicate PodFitsHostPorts.
 	PodFitsHostPortsPred = "PodFitsHostPorts"
 	// MatchNodeSelectorPred defines the name of predicate MatchNodeSelector.
@@ -104,6 +109,8 @@ const (
 	CheckNodeDiskPressurePred = "CheckNodeDiskPressure"
 	// CheckNodePIDPressurePred defines the name of predicate CheckNodePIDPressure.
 	CheckNodePIDPressurePred = "CheckNodePIDPressure"
+	// CheckNodeCriticalDaemonSetPodsPred defines the name of predicate CheckNodeCriticalDaemonSetPods.
+	CheckNodeCriticalDaemonSetPodsPred = "CheckNodeCriticalDaemonSetPods"
 
 	// DefaultMaxGCEPDVolumes defines the maximum number of PD Volumes for GCE
 	// GCE instances can have up to 16 PD volumes attached.
@@ -141,7 +148,8 @@ const (
 // Design doc: https://github.com/kubernetes/community/blob/master/contributors/design-proposals/scheduling/predicates-ordering.md
 var (
 	predicatesOrdering = []string{CheckNodeConditionPred, CheckNodeUnschedulablePred,
-		GeneralPred, HostNamePred, PodFitsHostPortsPred,
+		CheckNodeCriticalDaemonSetPodsPred,
+		GeneralPred, PodFitsPriorityQuotaGatePred, HostNamePred, PodFitsHostPortsPred,
 		MatchNodeSelectorPred, PodFitsResourcesPred, NoDiskConflictPred,
 		PodToleratesNodeTaintsPred, PodToleratesNodeNoExecuteTaintsPred, CheckNodeLabelPresencePred,
 		CheckServiceAffinityPred, MaxEBSVolumeCountPred, MaxGCEPDVolumeCountPred, MaxCSIVolumeCountPred,
@@ -833,6 +841,134 @@ func PodFitsResources(pod *v1.Pod, meta PredicateMetadata, nodeInfo *schedulerno
 	return len(predicateFails) == 0, predicateFails, nil
 }
 
+// NodeOvercommitChecker contains information to check node resource fits while allowing nodes to
+// be overcommitted for pods below a configured priority threshold.
+type NodeOvercommitChecker struct {
+	priorityThreshold    int32
+	overcommitPercentage int32
+}
+
+// NewNodeOvercommitPredicate creates a predicate which behaves like PodFitsResources, except that
+// pods with a priority below priorityThreshold are checked against an effective allocatable that
+// is overcommitPercentage percent of the node's real allocatable capacity, rather than the node's
+// real allocatable capacity itself. Pods at or above priorityThreshold are always checked against
+// the node's real allocatable capacity, so overcommitment is only ever extended to lower tiers.
+func NewNodeOvercommitPredicate(priorityThreshold, overcommitPercentage int32) FitPredicate {
+	checker := &NodeOvercommitChecker{
+		priorityThreshold:    priorityThreshold,
+		overcommitPercentage: overcommitPercentage,
+	}
+	return checker.PodFitsResourcesWithOvercommit
+}
+
+// PodFitsResourcesWithOvercommit checks if a node has sufficient resources to run a pod, the same
+// way PodFitsResources does, except that the node's allocatable is scaled by
+// OvercommitPercentage/100 when pod is below the configured PriorityThreshold. The scaled,
+// "effective" allocatable is also published via the scheduler's NodeEffectiveAllocatable metric so
+// operators can see how much headroom the overcommit band is actually offering on each node.
+func (n *NodeOvercommitChecker) PodFitsResourcesWithOvercommit(pod *v1.Pod, meta PredicateMetadata, nodeInfo *schedulernodeinfo.NodeInfo) (bool, []PredicateFailureReason, error) {
+	node := nodeInfo.Node()
+	if node == nil {
+		return false, nil, fmt.Errorf("node not found")
+	}
+
+	allocatable := nodeInfo.AllocatableResource()
+	effective := allocatable
+	band := "standard"
+	if podPriorityOrZero(pod) < n.priorityThreshold {
+		effective.MilliCPU = effective.MilliCPU * int64(n.overcommitPercentage) / 100
+		effective.Memory = effective.Memory * int64(n.overcommitPercentage) / 100
+		effective.EphemeralStorage = effective.EphemeralStorage * int64(n.overcommitPercentage) / 100
+		band = "overcommit"
+	}
+
+	metrics.NodeEffectiveAllocatable.WithLabelValues(node.Name, string(v1.ResourceCPU), band).Set(float64(effective.MilliCPU))
+	metrics.NodeEffectiveAllocatable.WithLabelValues(node.Name, string(v1.ResourceMemory), band).Set(float64(effective.Memory))
+
+	var predicateFails []PredicateFailureReason
+	allowedPodNumber := nodeInfo.AllowedPodNumber()
+	if len(nodeInfo.Pods())+1 > allowedPodNumber {
+		predicateFails = append(predicateFails, NewInsufficientResourceError(v1.ResourcePods, 1, int64(len(nodeInfo.Pods())), int64(allowedPodNumber)))
+	}
+
+	var podRequest *schedulernodeinfo.Resource
+	if predicateMeta, ok := meta.(*predicateMetadata); ok {
+		podRequest = predicateMeta.podRequest
+	} else {
+		podRequest = GetResourceRequest(pod)
+	}
+	if podRequest.MilliCPU == 0 && podRequest.Memory == 0 && podRequest.EphemeralStorage == 0 && len(podRequest.ScalarResources) == 0 {
+		return len(predicateFails) == 0, predicateFails, nil
+	}
+
+	requested := nodeInfo.RequestedResource()
+	if effective.MilliCPU < podRequest.MilliCPU+requested.MilliCPU {
+		predicateFails = append(predicateFails, NewInsufficientResourceError(v1.ResourceCPU, podRequest.MilliCPU, requested.MilliCPU, effective.MilliCPU))
+	}
+	if effective.Memory < podRequest.Memory+requested.Memory {
+		predicateFails = append(predicateFails, NewInsufficientResourceError(v1.ResourceMemory, podRequest.Memory, requested.Memory, effective.Memory))
+	}
+	if effective.EphemeralStorage < podRequest.EphemeralStorage+requested.EphemeralStorage {
+		predicateFails = append(predicateFails, NewInsufficientResourceError(v1.ResourceEphemeralStorage, podRequest.EphemeralStorage, requested.EphemeralStorage, effective.EphemeralStorage))
+	}
+	for rName, rQuant := range podRequest.ScalarResources {
+		if allocatable.ScalarResources[rName] < rQuant+requested.ScalarResources[rName] {
+			predicateFails = append(predicateFails, NewInsufficientResourceError(rName, podRequest.ScalarResources[rName], requested.ScalarResources[rName], allocatable.ScalarResources[rName]))
+		}
+	}
+
+	return len(predicateFails) == 0, predicateFails, nil
+}
+
+// podPriorityOrZero returns the pod's priority, or 0 if it does not have one set.
+func podPriorityOrZero(pod *v1.Pod) int32 {
+	if pod.Spec.Priority == nil {
+		return 0
+	}
+	return *pod.Spec.Priority
+}
+
+// ExtendedResourcePriorityFloorChecker contains information to reject pods that request a scarce
+// extended resource without a high enough priority to be trusted with it.
+type ExtendedResourcePriorityFloorChecker struct {
+	resourceName      v1.ResourceName
+	priorityThreshold int32
+}
+
+// NewExtendedResourcePriorityFloorPredicate creates a predicate which rejects any pod that
+// requests resourceName with a priority below priorityThreshold, regardless of whether the node
+// otherwise has enough of the resource free. Pods that don't request resourceName at all are
+// unaffected.
+func NewExtendedResourcePriorityFloorPredicate(resourceName v1.ResourceName, priorityThreshold int32) FitPredicate {
+	checker := &ExtendedResourcePriorityFloorChecker{
+		resourceName:      resourceName,
+		priorityThreshold: priorityThreshold,
+	}
+	return checker.PodMeetsExtendedResourcePriorityFloor
+}
+
+// PodMeetsExtendedResourcePriorityFloor checks that, if pod requests the checker's configured
+// extended resource, pod's priority is at least priorityThreshold.
+func (c *ExtendedResourcePriorityFloorChecker) PodMeetsExtendedResourcePriorityFloor(pod *v1.Pod, meta PredicateMetadata, nodeInfo *schedulernodeinfo.NodeInfo) (bool, []PredicateFailureReason, error) {
+	var podRequest *schedulernodeinfo.Resource
+	if predicateMeta, ok := meta.(*predicateMetadata); ok {
+		podRequest = predicateMeta.podRequest
+	} else {
+		podRequest = GetResourceRequest(pod)
+	}
+
+	if _, ok := podRequest.ScalarResources[c.resourceName]; !ok {
+		return true, nil, nil
+	}
+
+	if podPriorityOrZero(pod) < c.priorityThreshold {
+		metrics.ExtendedResourcePriorityFloorRejections.WithLabelValues(string(c.resourceName)).Inc()
+		return false, []PredicateFailureReason{ErrExtendedResourcePriorityTooLow}, nil
+	}
+
+	return true, nil, nil
+}
+
 // nodeMatchesNodeSelectorTerms checks if a node's labels satisfy a list of node selector terms,
 // terms are ORed, and an empty list of terms will match nothing.
 func nodeMatchesNodeSelectorTerms(node *v1.Node, nodeSelectorTerms []v1.NodeSelectorTerm) bool {
@@ -917,6 +1053,18 @@ func PodFitsHost(pod *v1.Pod, meta PredicateMetadata, nodeInfo *schedulernodeinf
 	return false, []PredicateFailureReason{ErrPodNotMatchHostName}, nil
 }
 
+// PodFitsPriorityQuotaGate rejects a pod that the priority admission plugin has gated pending
+// per-PriorityClass quota availability (see scheduling.PriorityQuotaGatedAnnotationKey). Unlike
+// most predicates, this is not a property of any particular node, so it can never be made to
+// pass by removing pods from a node; it is cleared out-of-band by a companion controller once
+// quota frees up.
+func PodFitsPriorityQuotaGate(pod *v1.Pod, meta PredicateMetadata, nodeInfo *schedulernodeinfo.NodeInfo) (bool, []PredicateFailureReason, error) {
+	if pod.Annotations[scheduling.PriorityQuotaGatedAnnotationKey] == "true" {
+		return false, []PredicateFailureReason{ErrPodPriorityQuotaGated}, nil
+	}
+	return true, nil, nil
+}
+
 // NodeLabelChecker contains information to check node labels for a predicate.
 type NodeLabelChecker struct {
 	labels   []string
@@ -1152,7 +1300,15 @@ func noncriticalPredicates(pod *v1.Pod, meta PredicateMetadata, nodeInfo *schedu
 // EssentialPredicates are the predicates that all pods, including critical pods, need
 func EssentialPredicates(pod *v1.Pod, meta PredicateMetadata, nodeInfo *schedulernodeinfo.NodeInfo) (bool, []PredicateFailureReason, error) {
 	var predicateFails []PredicateFailureReason
-	fit, reasons, err := PodFitsHost(pod, meta, nodeInfo)
+	fit, reasons, err := PodFitsPriorityQuotaGate(pod, meta, nodeInfo)
+	if err != nil {
+		return false, predicateFails, err
+	}
+	if !fit {
+		predicateFails = append(predicateFails, reasons...)
+	}
+
+	fit, reasons, err = PodFitsHost(pod, meta, nodeInfo)
 	if err != nil {
 		return false, predicateFails, err
 	}
@@ -1637,6 +1793,74 @@ func CheckNodeConditionPredicate(pod *v1.Pod, meta PredicateMetadata, nodeInfo *
 	return len(reasons) == 0, reasons, nil
 }
 
+// CriticalDaemonSetChecker holds the DaemonSetLister used to gate a node's readiness for
+// non-critical pods on the presence of its required system-node-critical DaemonSet pods.
+type CriticalDaemonSetChecker struct {
+	daemonSetLister algorithm.DaemonSetLister
+}
+
+// NewCriticalDaemonSetPredicate evaluates, for a non system-node-critical pod, whether every
+// system-node-critical DaemonSet whose pod template would land on the node already has a
+// Running replacement there. This keeps a node draining for an upgrade from filling back up
+// with ordinary workloads before its critical DaemonSet pods (e.g. kube-proxy, the CNI agent)
+// have been rescheduled and come up, which would otherwise leave those workloads without the
+// node-level services they depend on.
+//
+// Pods that are themselves system-node-critical are never gated by this predicate, since doing
+// so could prevent the very replacement pod the gate is waiting for from ever being scheduled.
+func NewCriticalDaemonSetPredicate(daemonSetLister algorithm.DaemonSetLister) FitPredicate {
+	c := &CriticalDaemonSetChecker{daemonSetLister: daemonSetLister}
+	return c.predicate
+}
+
+func (c *CriticalDaemonSetChecker) predicate(pod *v1.Pod, meta PredicateMetadata, nodeInfo *schedulernodeinfo.NodeInfo) (bool, []PredicateFailureReason, error) {
+	if pod.Spec.PriorityClassName == scheduling.SystemNodeCritical {
+		return true, nil, nil
+	}
+
+	node := nodeInfo.Node()
+	if node == nil {
+		return false, nil, fmt.Errorf("node not found")
+	}
+
+	daemonSets, err := c.daemonSetLister.List(labels.Everything())
+	if err != nil {
+		return false, nil, err
+	}
+
+	for _, ds := range daemonSets {
+		if ds.Spec.Template.Spec.PriorityClassName != scheduling.SystemNodeCritical {
+			continue
+		}
+
+		templatePod := &v1.Pod{Spec: ds.Spec.Template.Spec}
+		if !podMatchesNodeSelectorAndAffinityTerms(templatePod, node) {
+			continue
+		}
+
+		if !nodeHasRunningDaemonSetPod(nodeInfo, ds.UID) {
+			return false, []PredicateFailureReason{ErrNodeNotReadyForNonCriticalPods}, nil
+		}
+	}
+
+	return true, nil, nil
+}
+
+// nodeHasRunningDaemonSetPod returns true if nodeInfo already has a Running pod that is
+// controlled by the DaemonSet with the given UID.
+func nodeHasRunningDaemonSetPod(nodeInfo *schedulernodeinfo.NodeInfo, daemonSetUID types.UID) bool {
+	for _, p := range nodeInfo.Pods() {
+		controllerRef := metav1.GetControllerOf(p)
+		if controllerRef == nil || controllerRef.UID != daemonSetUID {
+			continue
+		}
+		if p.Status.Phase == v1.PodRunning {
+			return true
+		}
+	}
+	return false
+}
+
 // VolumeBindingChecker contains information to check a volume binding.
 type VolumeBindingChecker struct {
 	binder *volumebinder.VolumeBinder
@@ -24,12 +24,15 @@ import (
 	"strings"
 	"testing"
 
+	apps "k8s.io/api/apps/v1"
 	"k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
 	v1helper "k8s.io/kubernetes/pkg/apis/core/v1/helper"
+	"k8s.io/kubernetes/pkg/apis/scheduling"
 	schedulerapi "k8s.io/kubernetes/pkg/scheduler/api"
 	schedulernodeinfo "k8s.io/kubernetes/pkg/scheduler/nodeinfo"
 	schedulertesting "k8s.io/kubernetes/pkg/scheduler/testing"
@@ -5003,3 +5006,84 @@ func TestCheckNodeUnschedulablePredicate(t *testing.T) {
 		}
 	}
 }
+
+type fakeDaemonSetLister []apps.DaemonSet
+
+func (f fakeDaemonSetLister) List(selector labels.Selector) ([]*apps.DaemonSet, error) {
+	var daemonSets []*apps.DaemonSet
+	for i := range f {
+		daemonSets = append(daemonSets, &f[i])
+	}
+	return daemonSets, nil
+}
+
+func TestCriticalDaemonSetPredicate(t *testing.T) {
+	criticalDaemonSet := apps.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "critical-ds", UID: "ds-uid"},
+		Spec: apps.DaemonSetSpec{
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{PriorityClassName: scheduling.SystemNodeCritical},
+			},
+		},
+	}
+	criticalPodOwnedByDaemonSet := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "critical-ds-abcde",
+			OwnerReferences: []metav1.OwnerReference{{Controller: boolPtr(true), UID: "ds-uid"}},
+		},
+		Status: v1.PodStatus{Phase: v1.PodRunning},
+	}
+
+	tests := []struct {
+		name       string
+		pod        *v1.Pod
+		daemonSets fakeDaemonSetLister
+		existing   []*v1.Pod
+		fits       bool
+	}{
+		{
+			name: "no critical DaemonSets, non-critical pod fits",
+			pod:  &v1.Pod{},
+			fits: true,
+		},
+		{
+			name:       "critical DaemonSet has no running pod on the node yet",
+			pod:        &v1.Pod{},
+			daemonSets: fakeDaemonSetLister{criticalDaemonSet},
+			fits:       false,
+		},
+		{
+			name:       "critical DaemonSet already has a running pod on the node",
+			pod:        &v1.Pod{},
+			daemonSets: fakeDaemonSetLister{criticalDaemonSet},
+			existing:   []*v1.Pod{criticalPodOwnedByDaemonSet},
+			fits:       true,
+		},
+		{
+			name:       "a system-node-critical pod is never gated by this predicate",
+			pod:        &v1.Pod{Spec: v1.PodSpec{PriorityClassName: scheduling.SystemNodeCritical}},
+			daemonSets: fakeDaemonSetLister{criticalDaemonSet},
+			fits:       true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nodeInfo := schedulernodeinfo.NewNodeInfo(test.existing...)
+			nodeInfo.SetNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}})
+
+			predicate := NewCriticalDaemonSetPredicate(test.daemonSets)
+			fits, reasons, err := predicate(test.pod, nil, nodeInfo)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fits != test.fits {
+				t.Errorf("expected fits=%v, got fits=%v with reasons %v", test.fits, fits, reasons)
+			}
+		})
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -51,4 +51,7 @@ const (
 	ImageLocalityPriority = "ImageLocalityPriority"
 	// ResourceLimitsPriority defines the nodes of prioritizer function ResourceLimitsPriority.
 	ResourceLimitsPriority = "ResourceLimitsPriority"
+	// ZoneReservedCapacityPriority defines the name of prioritizer function that keeps a
+	// configurable percentage of each zone's capacity reserved for high-priority workloads.
+	ZoneReservedCapacityPriority = "ZoneReservedCapacityPriority"
 )
@@ -0,0 +1,97 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priorities
+
+import (
+	"k8s.io/api/core/v1"
+	schedulerapi "k8s.io/kubernetes/pkg/scheduler/api"
+	schedulernodeinfo "k8s.io/kubernetes/pkg/scheduler/nodeinfo"
+)
+
+// ZoneReservedCapacityPrioritizer keeps a configurable percentage of each zone's aggregate
+// allocatable resources reserved for failover of high-priority workloads. Low-priority pods
+// are steered away from nodes in a zone that is already at or below its configured reserve,
+// so that a zone failure elsewhere does not leave critical workloads without capacity to
+// fail over into because it was consumed by low-priority pods.
+type ZoneReservedCapacityPrioritizer struct {
+	zoneLabel           string
+	highPriorityCutoff  int32
+	reservedPercentages map[string]int32
+}
+
+// NewZoneReservedCapacityPriority creates a ZoneReservedCapacityPrioritizer.
+func NewZoneReservedCapacityPriority(args schedulerapi.ZoneReservedCapacityArguments) PriorityMapFunction {
+	zoneLabel := args.ZoneLabel
+	if zoneLabel == "" {
+		zoneLabel = v1.LabelZoneFailureDomain
+	}
+	p := &ZoneReservedCapacityPrioritizer{
+		zoneLabel:           zoneLabel,
+		highPriorityCutoff:  args.HighPriorityCutoff,
+		reservedPercentages: args.ReservedPercentages,
+	}
+	return p.CalculateZoneReservedCapacityPriorityMap
+}
+
+// CalculateZoneReservedCapacityPriorityMap scores a node lower when the pod being scheduled is
+// below the high-priority cutoff and placing it would push the node's zone below its configured
+// reserve of allocatable resources.
+func (z *ZoneReservedCapacityPrioritizer) CalculateZoneReservedCapacityPriorityMap(pod *v1.Pod, meta interface{}, nodeInfo *schedulernodeinfo.NodeInfo) (schedulerapi.HostPriority, error) {
+	node := nodeInfo.Node()
+	if node == nil {
+		return schedulerapi.HostPriority{}, nil
+	}
+
+	// High-priority pods (or pods with no priority set) are never penalized: they are exactly
+	// the workloads the reserve exists to protect.
+	if pod.Spec.Priority == nil || *pod.Spec.Priority >= z.highPriorityCutoff {
+		return schedulerapi.HostPriority{Host: node.Name, Score: schedulerapi.MaxPriority}, nil
+	}
+
+	zone, ok := node.Labels[z.zoneLabel]
+	if !ok {
+		return schedulerapi.HostPriority{Host: node.Name, Score: schedulerapi.MaxPriority}, nil
+	}
+
+	reservePercent, ok := z.reservedPercentages[zone]
+	if !ok || reservePercent <= 0 {
+		return schedulerapi.HostPriority{Host: node.Name, Score: schedulerapi.MaxPriority}, nil
+	}
+
+	allocatable := nodeInfo.AllocatableResource()
+	requested := nodeInfo.RequestedResource()
+	if allocatable.MilliCPU == 0 {
+		return schedulerapi.HostPriority{Host: node.Name, Score: schedulerapi.MaxPriority}, nil
+	}
+
+	usedFraction := float64(requested.MilliCPU) / float64(allocatable.MilliCPU)
+	reserveFraction := float64(reservePercent) / 100
+
+	if usedFraction+reserveFraction <= 1 {
+		// Scheduling a low-priority pod here still leaves the zone's reserve intact.
+		return schedulerapi.HostPriority{Host: node.Name, Score: schedulerapi.MaxPriority}, nil
+	}
+
+	// The closer the node already is to eating into the reserve, the lower the score for this
+	// low-priority pod.
+	overage := (usedFraction + reserveFraction) - 1
+	score := int((1 - overage) * float64(schedulerapi.MaxPriority))
+	if score < 0 {
+		score = 0
+	}
+	return schedulerapi.HostPriority{Host: node.Name, Score: score}, nil
+}
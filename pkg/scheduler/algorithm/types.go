@@ -118,3 +118,19 @@ type EmptyStatefulSetLister struct{}
 func (f EmptyStatefulSetLister) GetPodStatefulSets(pod *v1.Pod) (sss []*apps.StatefulSet, err error) {
 	return nil, nil
 }
+
+// DaemonSetLister interface represents anything that can produce a list of DaemonSet; the list is consumed by a scheduler.
+type DaemonSetLister interface {
+	// List lists all DaemonSets.
+	List(labels.Selector) ([]*apps.DaemonSet, error)
+}
+
+var _ DaemonSetLister = &EmptyDaemonSetLister{}
+
+// EmptyDaemonSetLister implements DaemonSetLister on []apps.DaemonSet returning empty data.
+type EmptyDaemonSetLister struct{}
+
+// List of EmptyDaemonSetLister returns nil.
+func (f EmptyDaemonSetLister) List(labels.Selector) ([]*apps.DaemonSet, error) {
+	return nil, nil
+}
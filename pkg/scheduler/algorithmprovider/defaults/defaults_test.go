@@ -84,6 +84,7 @@ func TestDefaultPredicates(t *testing.T) {
 		predicates.CheckNodeConditionPred,
 		predicates.PodToleratesNodeTaintsPred,
 		predicates.CheckVolumeBindingPred,
+		predicates.CheckNodeCriticalDaemonSetPodsPred,
 	)
 
 	if expected := defaultPredicates(); !result.Equal(expected) {
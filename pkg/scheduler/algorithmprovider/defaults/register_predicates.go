@@ -47,6 +47,10 @@ func init() {
 	// This predicate is actually a default predicate, because it is invoked from
 	// predicates.GeneralPredicates()
 	factory.RegisterFitPredicate(predicates.HostNamePred, predicates.PodFitsHost)
+	// Fit is false if the priority admission plugin has gated the pod pending priority-class
+	// quota availability. This predicate is actually a default predicate, because it is invoked
+	// from predicates.GeneralPredicates()
+	factory.RegisterFitPredicate(predicates.PodFitsPriorityQuotaGatePred, predicates.PodFitsPriorityQuotaGate)
 	// Fit is determined by node selector query.
 	factory.RegisterFitPredicate(predicates.MatchNodeSelectorPred, predicates.PodMatchNodeSelector)
 
@@ -128,4 +132,13 @@ func init() {
 			return predicates.NewVolumeBindingPredicate(args.VolumeBinder)
 		},
 	)
+
+	// Fit is determined by whether a node's required system-node-critical DaemonSet pods
+	// have already come up, so that non-critical pods don't race them onto the node.
+	factory.RegisterFitPredicateFactory(
+		predicates.CheckNodeCriticalDaemonSetPodsPred,
+		func(args factory.PluginFactoryArgs) predicates.FitPredicate {
+			return predicates.NewCriticalDaemonSetPredicate(args.DaemonSetLister)
+		},
+	)
 }
@@ -1106,6 +1106,7 @@ func TestCompatibility_v1_Scheduler(t *testing.T) {
 			ReplicationControllerInformer:  informerFactory.Core().V1().ReplicationControllers(),
 			ReplicaSetInformer:             informerFactory.Apps().V1().ReplicaSets(),
 			StatefulSetInformer:            informerFactory.Apps().V1().StatefulSets(),
+			DaemonSetInformer:              informerFactory.Apps().V1().DaemonSets(),
 			ServiceInformer:                informerFactory.Core().V1().Services(),
 			PdbInformer:                    informerFactory.Policy().V1beta1().PodDisruptionBudgets(),
 			StorageClassInformer:           informerFactory.Storage().V1().StorageClasses(),
@@ -80,6 +80,10 @@ type PredicateArgument struct {
 	// The predicate that checks whether a particular node has a certain label
 	// defined or not, regardless of value
 	LabelsPresence *LabelsPresence `json:"labelsPresence"`
+	// The predicate that allows nodes to be overcommitted for pods below a priority threshold
+	NodeOvercommit *NodeOvercommitArguments `json:"nodeOvercommit"`
+	// The predicate that requires a minimum priority for pods requesting a scarce extended resource
+	ExtendedResourcePriorityFloor *ExtendedResourcePriorityFloorArguments `json:"extendedResourcePriorityFloor"`
 }
 
 // PriorityArgument represents the arguments to configure priority functions in scheduler policy configuration.
@@ -93,6 +97,8 @@ type PriorityArgument struct {
 	LabelPreference *LabelPreference `json:"labelPreference"`
 	// The RequestedToCapacityRatio priority function is parametrized with function shape.
 	RequestedToCapacityRatioArguments *RequestedToCapacityRatioArguments `json:"requestedToCapacityRatioArguments"`
+	// The ZoneReservedCapacity priority function is parametrized with per-zone reserves.
+	ZoneReservedCapacityArguments *ZoneReservedCapacityArguments `json:"zoneReservedCapacityArguments"`
 }
 
 // ServiceAffinity holds the parameters that are used to configure the corresponding predicate in scheduler policy configuration.
@@ -111,6 +117,34 @@ type LabelsPresence struct {
 	Presence bool `json:"presence"`
 }
 
+// NodeOvercommitArguments holds the parameters that are used to configure the corresponding
+// predicate in scheduler policy configuration. It allows a node's allocatable resources to be
+// treated as larger than they actually are for the purpose of admitting low-priority pods, on
+// the assumption that preemption will reclaim the difference if higher-priority pods need it.
+type NodeOvercommitArguments struct {
+	// PriorityThreshold is the pod priority below which the overcommit allowance applies. Pods
+	// at or above this priority are always checked against the node's real allocatable capacity.
+	PriorityThreshold int32 `json:"priorityThreshold"`
+	// OvercommitPercentage is the percentage of a node's real allocatable capacity that pods
+	// below PriorityThreshold are allowed to schedule against, e.g. 120 allows those pods to
+	// use up to 120% of the node's actual allocatable resources.
+	OvercommitPercentage int32 `json:"overcommitPercentage"`
+}
+
+// ExtendedResourcePriorityFloorArguments holds the parameters that are used to configure the
+// corresponding predicate in scheduler policy configuration. It guards a scarce extended
+// resource (e.g. a GPU type) from being consumed by pods below a configured priority, so the
+// resource isn't exhausted by low-priority or preemptible workloads unless explicitly allowed.
+type ExtendedResourcePriorityFloorArguments struct {
+	// ResourceName is the extended resource this floor applies to, e.g. "nvidia.com/gpu". Pods
+	// that do not request this resource are unaffected by this predicate.
+	ResourceName apiv1.ResourceName `json:"resourceName"`
+	// PriorityThreshold is the minimum priority a pod requesting ResourceName must have to be
+	// considered schedulable. A pod below this priority is rejected regardless of whether the
+	// node otherwise has enough of the resource free.
+	PriorityThreshold int32 `json:"priorityThreshold"`
+}
+
 // ServiceAntiAffinity holds the parameters that are used to configure the corresponding priority function
 type ServiceAntiAffinity struct {
 	// Used to identify node "groups"
@@ -141,6 +175,18 @@ type UtilizationShapePoint struct {
 	Score int `json:"score"`
 }
 
+// ZoneReservedCapacityArguments holds arguments specific to the ZoneReservedCapacity priority function.
+type ZoneReservedCapacityArguments struct {
+	// ZoneLabel is the node label used to determine which failure-domain zone a node belongs to.
+	ZoneLabel string `json:"zoneLabel"`
+	// HighPriorityCutoff is the minimum pod priority that is considered "high-priority" for the
+	// purpose of zone reserves.
+	HighPriorityCutoff int32 `json:"highPriorityCutoff"`
+	// ReservedPercentages maps a zone name to the percentage (0-100) of that zone's aggregate
+	// allocatable resources which should be kept free for failover of high-priority workloads.
+	ReservedPercentages map[string]int32 `json:"reservedPercentages"`
+}
+
 // ExtenderManagedResource describes the arguments of extended resources
 // managed by an extender.
 type ExtenderManagedResource struct {
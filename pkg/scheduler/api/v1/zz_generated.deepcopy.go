@@ -468,6 +468,38 @@ func (in *Policy) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeOvercommitArguments) DeepCopyInto(out *NodeOvercommitArguments) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeOvercommitArguments.
+func (in *NodeOvercommitArguments) DeepCopy() *NodeOvercommitArguments {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeOvercommitArguments)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtendedResourcePriorityFloorArguments) DeepCopyInto(out *ExtendedResourcePriorityFloorArguments) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtendedResourcePriorityFloorArguments.
+func (in *ExtendedResourcePriorityFloorArguments) DeepCopy() *ExtendedResourcePriorityFloorArguments {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtendedResourcePriorityFloorArguments)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PredicateArgument) DeepCopyInto(out *PredicateArgument) {
 	*out = *in
@@ -481,6 +513,16 @@ func (in *PredicateArgument) DeepCopyInto(out *PredicateArgument) {
 		*out = new(LabelsPresence)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NodeOvercommit != nil {
+		in, out := &in.NodeOvercommit, &out.NodeOvercommit
+		*out = new(NodeOvercommitArguments)
+		**out = **in
+	}
+	if in.ExtendedResourcePriorityFloor != nil {
+		in, out := &in.ExtendedResourcePriorityFloor, &out.ExtendedResourcePriorityFloor
+		*out = new(ExtendedResourcePriorityFloorArguments)
+		**out = **in
+	}
 	return
 }
 
@@ -533,6 +575,11 @@ func (in *PriorityArgument) DeepCopyInto(out *PriorityArgument) {
 		*out = new(RequestedToCapacityRatioArguments)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ZoneReservedCapacityArguments != nil {
+		in, out := &in.ZoneReservedCapacityArguments, &out.ZoneReservedCapacityArguments
+		*out = new(ZoneReservedCapacityArguments)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -588,6 +635,29 @@ func (in *RequestedToCapacityRatioArguments) DeepCopy() *RequestedToCapacityRati
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneReservedCapacityArguments) DeepCopyInto(out *ZoneReservedCapacityArguments) {
+	*out = *in
+	if in.ReservedPercentages != nil {
+		in, out := &in.ReservedPercentages, &out.ReservedPercentages
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneReservedCapacityArguments.
+func (in *ZoneReservedCapacityArguments) DeepCopy() *ZoneReservedCapacityArguments {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneReservedCapacityArguments)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceAffinity) DeepCopyInto(out *ServiceAffinity) {
 	*out = *in
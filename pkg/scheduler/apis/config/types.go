@@ -72,6 +72,14 @@ type KubeSchedulerConfiguration struct {
 	// DisablePreemption disables the pod preemption feature.
 	DisablePreemption bool
 
+	// PreemptionSystemOnly restricts preemption so that only pods with a system priority
+	// (system-cluster-critical, system-node-critical, or higher) can trigger it. Pods scheduled
+	// at ordinary priorities are still eligible to be preempted, but never trigger preemption
+	// themselves. This lets a scheduler instance dedicated to a batch workload guarantee that its
+	// pods never evict anything, while a separate default-scheduler instance keeps the normal
+	// behavior. Has no effect when DisablePreemption is true.
+	PreemptionSystemOnly bool
+
 	// PercentageOfNodeToScore is the percentage of all nodes that once found feasible
 	// for running a pod, the scheduler stops its search for more feasible nodes in
 	// the cluster. This helps improve scheduler's performance. Scheduler always tries to find
@@ -86,6 +94,44 @@ type KubeSchedulerConfiguration struct {
 	// Value must be non-negative integer. The value zero indicates no waiting.
 	// If this value is nil, the default value will be used.
 	BindTimeoutSeconds *int64
+
+	// ProvisioningBacklogThreshold is the number of currently pending pods with strictly higher
+	// priority than a given pod, above which dynamic PV provisioning for that pod's unbound PVCs
+	// is delayed for the scheduling cycle rather than triggered immediately. A value of 0 disables
+	// the check.
+	ProvisioningBacklogThreshold int32
+
+	// MinVictimPriorityDelta is the minimum amount by which a preemption victim's priority must
+	// be lower than the preemptor's priority for the victim to be eligible for eviction. This
+	// prevents preemption churn between classes with adjacent priority values. A value of 0
+	// preserves the default behavior of evicting any pod with strictly lower priority.
+	MinVictimPriorityDelta int32
+
+	// PreemptionWaitSecondsByPriorityClass maps a PriorityClassName to the maximum number of
+	// seconds preemption will prefer waiting for a pod of that class to finish on its own, based
+	// on its estimated remaining runtime (from the pod's estimated-completion-seconds annotation
+	// or its ActiveDeadlineSeconds), over evicting it. A PriorityClassName with no entry, or a
+	// pod with no estimate available, is unaffected.
+	PreemptionWaitSecondsByPriorityClass map[string]int32
+
+	// MaxPreemptedPodsByPriorityClass maps a PriorityClassName to the maximum number of pods of
+	// that class preemption may evict on a single node during a single preemption attempt. A
+	// PriorityClassName with no entry is unbounded. This protects a PriorityClass's workload from
+	// having an unbounded amount of it evicted by a single burst of higher-priority pods.
+	MaxPreemptedPodsByPriorityClass map[string]int32
+
+	// PodPriorityAgingWindowSeconds is, when the PodPriorityAging feature gate is enabled, the
+	// number of seconds a pod must wait in the scheduling queue for its effective priority (used
+	// only to order the activeQ, never persisted back to the pod) to increase by one aging step.
+	// Pods at the same PriorityClass still tie-break by wait time as before; aging only changes
+	// how pods of differing priority compare once a lower-priority pod has waited long enough. A
+	// value of 0 disables aging even when the feature gate is enabled.
+	PodPriorityAgingWindowSeconds int64
+
+	// PodPriorityAgingMaxBoost caps the total number of aging steps a pod's effective priority can
+	// accumulate no matter how long it waits, so a sufficiently stale batch pod cannot eventually
+	// outrank system-critical priorities.
+	PodPriorityAgingMaxBoost int32
 }
 
 // SchedulerAlgorithmSource is the source of a scheduler algorithm. One source
@@ -118,8 +118,15 @@ func autoConvert_v1alpha1_KubeSchedulerConfiguration_To_config_KubeSchedulerConf
 		return err
 	}
 	out.DisablePreemption = in.DisablePreemption
+	out.PreemptionSystemOnly = in.PreemptionSystemOnly
 	out.PercentageOfNodesToScore = in.PercentageOfNodesToScore
 	out.BindTimeoutSeconds = (*int64)(unsafe.Pointer(in.BindTimeoutSeconds))
+	out.ProvisioningBacklogThreshold = in.ProvisioningBacklogThreshold
+	out.MinVictimPriorityDelta = in.MinVictimPriorityDelta
+	out.PreemptionWaitSecondsByPriorityClass = *(*map[string]int32)(unsafe.Pointer(&in.PreemptionWaitSecondsByPriorityClass))
+	out.MaxPreemptedPodsByPriorityClass = *(*map[string]int32)(unsafe.Pointer(&in.MaxPreemptedPodsByPriorityClass))
+	out.PodPriorityAgingWindowSeconds = in.PodPriorityAgingWindowSeconds
+	out.PodPriorityAgingMaxBoost = in.PodPriorityAgingMaxBoost
 	return nil
 }
 
@@ -146,8 +153,15 @@ func autoConvert_config_KubeSchedulerConfiguration_To_v1alpha1_KubeSchedulerConf
 		return err
 	}
 	out.DisablePreemption = in.DisablePreemption
+	out.PreemptionSystemOnly = in.PreemptionSystemOnly
 	out.PercentageOfNodesToScore = in.PercentageOfNodesToScore
 	out.BindTimeoutSeconds = (*int64)(unsafe.Pointer(in.BindTimeoutSeconds))
+	out.ProvisioningBacklogThreshold = in.ProvisioningBacklogThreshold
+	out.MinVictimPriorityDelta = in.MinVictimPriorityDelta
+	out.PreemptionWaitSecondsByPriorityClass = *(*map[string]int32)(unsafe.Pointer(&in.PreemptionWaitSecondsByPriorityClass))
+	out.MaxPreemptedPodsByPriorityClass = *(*map[string]int32)(unsafe.Pointer(&in.MaxPreemptedPodsByPriorityClass))
+	out.PodPriorityAgingWindowSeconds = in.PodPriorityAgingWindowSeconds
+	out.PodPriorityAgingMaxBoost = in.PodPriorityAgingMaxBoost
 	return nil
 }
 
@@ -0,0 +1,34 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priorityresolver
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc"
+
+	v1alpha1 "k8s.io/kubernetes/pkg/scheduler/apis/priorityresolver/v1alpha1"
+)
+
+// GetClient returns a client for the PriorityResolver grpc service listening at addr.
+func GetClient(addr string) (v1alpha1.PriorityResolverClient, *grpc.ClientConn, error) {
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	if err != nil {
+		return nil, nil, fmt.Errorf("error dialing %s: %v", addr, err)
+	}
+	return v1alpha1.NewPriorityResolverClient(conn), conn, nil
+}
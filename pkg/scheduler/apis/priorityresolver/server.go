@@ -0,0 +1,149 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package priorityresolver implements a gRPC service that exposes the same
+// PriorityClass resolution and namespace policy checks applied by the
+// priority admission plugin (plugin/pkg/admission/priority), so external
+// schedulers and controllers can apply identical semantics instead of
+// reimplementing them.
+//
+// It intentionally mirrors rather than imports the plugin's private
+// admitPod logic, since that logic is tied to admission.Attributes and to
+// plugin-specific configuration (such as configured PriorityClassName
+// aliases) that has no meaning outside of an admission request. Resolving
+// an alias is therefore out of scope here; callers that rely on aliases
+// will see the alias name echoed back unresolved.
+package priorityresolver
+
+import (
+	"context"
+	"fmt"
+
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	schedulingv1listers "k8s.io/client-go/listers/scheduling/v1"
+	"k8s.io/kubernetes/pkg/apis/scheduling"
+	kubeapiserveradmission "k8s.io/kubernetes/pkg/kubeapiserver/admission"
+	v1alpha1 "k8s.io/kubernetes/pkg/scheduler/apis/priorityresolver/v1alpha1"
+	priorityplugin "k8s.io/kubernetes/plugin/pkg/admission/priority"
+)
+
+// priorityResolverServer implements v1alpha1.PriorityResolverServer.
+type priorityResolverServer struct {
+	lister          schedulingv1listers.PriorityClassLister
+	namespaceParams kubeapiserveradmission.NamespaceParamsGetter
+}
+
+// NewPriorityResolverServer returns a v1alpha1.PriorityResolverServer that resolves
+// PriorityClass names against lister, consulting namespaceParams (which may be nil,
+// in which case namespace-scoped overrides are never consulted) for a namespace's
+// "defaultPriorityClassName" override.
+func NewPriorityResolverServer(lister schedulingv1listers.PriorityClassLister, namespaceParams kubeapiserveradmission.NamespaceParamsGetter) v1alpha1.PriorityResolverServer {
+	return &priorityResolverServer{
+		lister:          lister,
+		namespaceParams: namespaceParams,
+	}
+}
+
+// Resolve implements v1alpha1.PriorityResolverServer.
+func (s *priorityResolverServer) Resolve(ctx context.Context, req *v1alpha1.ResolveRequest) (*v1alpha1.ResolveResponse, error) {
+	pcName := req.PriorityClassName
+	if pcName != "" {
+		if !scheduling.PriorityClassPermittedInNamespace(pcName, req.Namespace) {
+			return nil, fmt.Errorf("PriorityClass %v is not permitted in namespace %v", pcName, req.Namespace)
+		}
+		pc, err := s.lister.Get(pcName)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil, fmt.Errorf("no PriorityClass with name %v was found", pcName)
+			}
+			return nil, err
+		}
+		return &v1alpha1.ResolveResponse{
+			PriorityClassName: pc.Name,
+			Priority:          pc.Value,
+			ResolvedVia:       "explicit",
+		}, nil
+	}
+
+	if override, ok, err := s.namespaceOverride(req.Namespace); err != nil {
+		return nil, err
+	} else if ok {
+		pc, err := s.lister.Get(override)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil, fmt.Errorf("no PriorityClass with name %v was found", override)
+			}
+			return nil, err
+		}
+		return &v1alpha1.ResolveResponse{
+			PriorityClassName: pc.Name,
+			Priority:          pc.Value,
+			ResolvedVia:       "namespace-default",
+		}, nil
+	}
+
+	dpc, err := s.getDefaultPriorityClass()
+	if err != nil {
+		return nil, err
+	}
+	if dpc != nil {
+		return &v1alpha1.ResolveResponse{
+			PriorityClassName: dpc.Name,
+			Priority:          dpc.Value,
+			ResolvedVia:       "cluster-default",
+		}, nil
+	}
+
+	return &v1alpha1.ResolveResponse{
+		PriorityClassName: "",
+		Priority:          scheduling.DefaultPriorityWhenNoDefaultClassExists,
+		ResolvedVia:       "cluster-default",
+	}, nil
+}
+
+// namespaceOverride returns the "defaultPriorityClassName" configured for namespace via
+// the priority plugin's namespace-scoped admission parameters, and whether one was found.
+func (s *priorityResolverServer) namespaceOverride(namespace string) (string, bool, error) {
+	if s.namespaceParams == nil {
+		return "", false, nil
+	}
+	params, ok, err := s.namespaceParams.GetNamespaceParams(priorityplugin.PluginName, namespace)
+	if err != nil || !ok {
+		return "", false, err
+	}
+	override, ok := params["defaultPriorityClassName"]
+	return override, ok, nil
+}
+
+// getDefaultPriorityClass mirrors priorityPlugin.getDefaultPriorityClass: if more than one
+// PriorityClass has GlobalDefault set, the one with the lowest priority value wins.
+func (s *priorityResolverServer) getDefaultPriorityClass() (*schedulingv1.PriorityClass, error) {
+	list, err := s.lister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var defaultPC *schedulingv1.PriorityClass
+	for _, pc := range list {
+		if pc.GlobalDefault {
+			if defaultPC == nil || defaultPC.Value > pc.Value {
+				defaultPC = pc
+			}
+		}
+	}
+	return defaultPC, nil
+}
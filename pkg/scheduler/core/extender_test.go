@@ -551,7 +551,8 @@ func TestGenericSchedulerWithExtenders(t *testing.T) {
 				schedulertesting.FakePDBLister{},
 				false,
 				false,
-				schedulerapi.DefaultPercentageOfNodesToScore)
+				schedulerapi.DefaultPercentageOfNodesToScore,
+				0, nil, nil, nil, nil, nil)
 			podIgnored := &v1.Pod{}
 			result, err := scheduler.Schedule(podIgnored, schedulertesting.FakeNodeLister(makeNodeList(test.nodes)))
 			if test.expectsErr {
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -68,6 +69,9 @@ var unresolvablePredicateFailureErrors = map[predicates.PredicateFailureReason]s
 	predicates.ErrPodNotMatchHostName:       {},
 	predicates.ErrTaintsTolerationsNotMatch: {},
 	predicates.ErrNodeLabelPresenceViolated: {},
+	// A pod gated pending priority-class quota is not a property of any node, so preemption on
+	// any node cannot resolve it; it is cleared out-of-band by a companion controller.
+	predicates.ErrPodPriorityQuotaGated: {},
 	// Node conditions won't change when scheduler simulates removal of preemption victims.
 	// So, it is pointless to try nodes that have not been able to host the pod due to node
 	// conditions. These include ErrNodeNotReady, ErrNodeUnderPIDPressure, ErrNodeUnderMemoryPressure, ....
@@ -81,6 +85,9 @@ var unresolvablePredicateFailureErrors = map[predicates.PredicateFailureReason]s
 	predicates.ErrVolumeZoneConflict:      {},
 	predicates.ErrVolumeNodeConflict:      {},
 	predicates.ErrVolumeBindConflict:      {},
+	// A missing critical DaemonSet pod won't come up any sooner because other pods were
+	// preempted from the node, so it is pointless to try preemption here too.
+	predicates.ErrNodeNotReadyForNonCriticalPods: {},
 }
 
 // FailedPredicateMap declares a map[string][]algorithm.PredicateFailureReason type.
@@ -169,6 +176,116 @@ type genericScheduler struct {
 	disablePreemption        bool
 	lastIndex                int
 	percentageOfNodesToScore int32
+	// minVictimPriorityDelta is the minimum amount by which a victim's priority must be lower
+	// than the preemptor's for the victim to be eligible for preemption. Zero preserves the
+	// historical behavior of evicting any pod with strictly lower priority.
+	minVictimPriorityDelta int32
+	// preemptionWaitSecondsByPriorityClass maps a PriorityClassName to the maximum number of
+	// seconds selectVictimsOnNode will prefer waiting for a pod of that class to finish on its own
+	// over evicting it, when the pod's estimated remaining runtime is within that window. See
+	// estimatedSecondsRemaining.
+	preemptionWaitSecondsByPriorityClass map[string]int32
+	// maxPreemptedPodsByPriorityClass maps a PriorityClassName to the maximum number of pods of
+	// that class selectVictimsOnNode may evict while selecting victims for a single node during
+	// a single preemption attempt. A PriorityClassName with no entry is unbounded. This protects
+	// lower-priority classes from having an unbounded amount of their work evicted by a single
+	// burst of higher-priority pods.
+	maxPreemptedPodsByPriorityClass map[string]int32
+	// preemptionFairness, if set, gates which workload gets to consume freshly freed preemption
+	// capacity next when several workloads are simultaneously preempting. See
+	// PreemptionFairnessConfig.
+	preemptionFairness *PreemptionFairnessConfig
+	// preemptionCircuitBreaker, if set, can block non-system-priority preemption attempts
+	// cluster-wide, e.g. during an incident. See PreemptionCircuitBreaker.
+	preemptionCircuitBreaker *PreemptionCircuitBreaker
+	// podDeletionCost, if set, prefers evicting the equal-priority victim with the lower
+	// pod-deletion-cost annotation. See PodDeletionCostConfig.
+	podDeletionCost *PodDeletionCostConfig
+}
+
+// PreemptionFairnessConfig enables round-robin fairness of freed preemption capacity across
+// workloads, as identified by the value of each pod's LabelKey label. Without it, whichever
+// workload's pods reach the front of the scheduling queue first can keep preempting and
+// nominating nodes for itself indefinitely, starving other workloads that also need to preempt to
+// get scheduled. With it, a preemptor whose workload already holds more nominated (but not yet
+// bound) victims than some other workload defers to that workload this cycle, and is retried on a
+// later scheduling attempt like any other unschedulable pod.
+type PreemptionFairnessConfig struct {
+	// LabelKey identifies the label whose value groups pods into a workload for fairness
+	// accounting. Pods without the label share the empty-string workload.
+	LabelKey string
+}
+
+// PodDeletionCostConfig enables the pod-deletion-cost annotation (see
+// util.PodDeletionCostAnnotationKey) as a preemption victim tie-break, unifying scale-down and
+// preemption victim semantics: among victims of equal priority (and only among those; it never
+// overrides a real priority difference), the pod with the lower deletion cost is preferred as the
+// victim, same as a ReplicaSet scaling down would prefer to remove it.
+type PodDeletionCostConfig struct {
+	// MaxCostInfluence caps the magnitude of a single pod's deletion-cost annotation this
+	// comparison considers, so one outlier annotation value can't dominate every other
+	// equal-priority tie-break it takes part in. A value <= 0 leaves the raw annotation value
+	// uncapped.
+	MaxCostInfluence int32
+}
+
+// cost returns pod's deletion cost, clamped to +/- MaxCostInfluence. A nil config always returns
+// 0, so deletion cost has no effect unless PodDeletionCostConfig is set.
+func (c *PodDeletionCostConfig) cost(pod *v1.Pod) int32 {
+	if c == nil {
+		return 0
+	}
+	cost := util.GetPodDeletionCost(pod)
+	if c.MaxCostInfluence <= 0 {
+		return cost
+	}
+	if cost > c.MaxCostInfluence {
+		return c.MaxCostInfluence
+	}
+	if cost < -c.MaxCostInfluence {
+		return -c.MaxCostInfluence
+	}
+	return cost
+}
+
+// moreImportantVictim ranks pod1 ahead of pod2 the same way util.MoreImportantPod does (higher
+// priority first, then earlier start time), except that when config is set and pod1 and pod2 have
+// equal priority, it tie-breaks on deletion cost before start time, preferring to keep (and
+// therefore not evict) whichever pod has the higher cost.
+func moreImportantVictim(config *PodDeletionCostConfig, pod1, pod2 *v1.Pod) bool {
+	p1 := util.GetPodPriority(pod1)
+	p2 := util.GetPodPriority(pod2)
+	if p1 != p2 {
+		return p1 > p2
+	}
+	if c1, c2 := config.cost(pod1), config.cost(pod2); c1 != c2 {
+		return c1 > c2
+	}
+	return util.GetPodStartTime(pod1).Before(util.GetPodStartTime(pod2))
+}
+
+// workloadFairnessAllows reports whether pod should be allowed to proceed with preemption this
+// cycle under g.preemptionFairness, given how many pods are already nominated for each workload.
+// A workload with no nominations of its own always proceeds; one that already holds nominations
+// defers as soon as some other named workload holds fewer, so nominations spread round-robin
+// across competing workloads instead of accumulating on whichever workload reaches the front of
+// the queue first.
+func (g *genericScheduler) workloadFairnessAllows(pod *v1.Pod) bool {
+	if g.preemptionFairness == nil {
+		return true
+	}
+	workload := pod.Labels[g.preemptionFairness.LabelKey]
+	counts := g.schedulingQueue.NominatedPodsCountByWorkload(g.preemptionFairness.LabelKey)
+	mine := counts[workload]
+	if mine == 0 {
+		return true
+	}
+	for other, count := range counts {
+		if other != workload && count < mine {
+			return false
+		}
+	}
+	return true
 }
 
 // snapshot snapshots scheduler cache and node infos for all fit and priority
@@ -318,6 +435,14 @@ func (g *genericScheduler) Preempt(pod *v1.Pod, nodeLister algorithm.NodeLister,
 		klog.V(5).Infof("Pod %v/%v is not eligible for more preemption.", pod.Namespace, pod.Name)
 		return nil, nil, nil, nil
 	}
+	if !g.workloadFairnessAllows(pod) {
+		klog.V(5).Infof("Pod %v/%v deferred to let another workload's pending preemption make progress first.", pod.Namespace, pod.Name)
+		return nil, nil, nil, nil
+	}
+	if g.preemptionCircuitBreaker != nil && !g.preemptionCircuitBreaker.Allows(pod) {
+		klog.V(3).Infof("Preemption for pod %v/%v blocked by the preemption circuit breaker.", pod.Namespace, pod.Name)
+		return nil, nil, nil, nil
+	}
 	allNodes, err := nodeLister.List()
 	if err != nil {
 		return nil, nil, nil, err
@@ -336,7 +461,8 @@ func (g *genericScheduler) Preempt(pod *v1.Pod, nodeLister algorithm.NodeLister,
 		return nil, nil, nil, err
 	}
 	nodeToVictims, err := selectNodesForPreemption(pod, g.nodeInfoSnapshot.NodeInfoMap, potentialNodes, g.predicates,
-		g.predicateMetaProducer, g.schedulingQueue, pdbs)
+		g.predicateMetaProducer, g.schedulingQueue, pdbs, g.minVictimPriorityDelta, g.preemptionWaitSecondsByPriorityClass, g.maxPreemptedPodsByPriorityClass,
+		g.podDeletionCost, g.framework)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -359,13 +485,54 @@ func (g *genericScheduler) Preempt(pod *v1.Pod, nodeLister algorithm.NodeLister,
 	// nomination updates these pods and moves them to the active queue. It
 	// lets scheduler find another place for them.
 	nominatedPods := g.getLowerPriorityNominatedPods(pod, candidateNode.Name)
-	if nodeInfo, ok := g.nodeInfoSnapshot.NodeInfoMap[candidateNode.Name]; ok {
-		return nodeInfo.Node(), nodeToVictims[candidateNode].Pods, nominatedPods, nil
+	nodeInfo, ok := g.nodeInfoSnapshot.NodeInfoMap[candidateNode.Name]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf(
+			"preemption failed: the target node %s has been deleted from scheduler cache",
+			candidateNode.Name)
+	}
+
+	victims := nodeToVictims[candidateNode].Pods
+	if !g.victimsStillMakeRoom(pod, victims, candidateNode.Name) {
+		klog.V(3).Infof("Preemption of %d pod(s) on node %v would no longer make room for %v/%v by the time of "+
+			"the final check; aborting preemption instead of evicting pods for nothing.",
+			len(victims), candidateNode.Name, pod.Namespace, pod.Name)
+		metrics.PreemptionAttemptsAborted.Inc()
+		return nil, nil, nil, nil
 	}
 
-	return nil, nil, nil, fmt.Errorf(
-		"preemption failed: the target node %s has been deleted from scheduler cache",
-		candidateNode.Name)
+	return nodeInfo.Node(), victims, nominatedPods, nil
+}
+
+// victimsStillMakeRoom re-verifies, against a freshly refreshed snapshot of the candidate node,
+// that evicting victims (already chosen against a possibly-stale snapshot by
+// selectNodesForPreemption) would actually let pod fit. It runs the same FitPredicates used to
+// select the victims in the first place, so volume and topology constraints are covered exactly
+// as they were during selection. This catches the case where cluster state changed between victim
+// selection and eviction, so pods are not evicted for no benefit.
+func (g *genericScheduler) victimsStillMakeRoom(pod *v1.Pod, victims []*v1.Pod, nodeName string) bool {
+	if err := g.snapshot(); err != nil {
+		klog.Warningf("Failed to refresh snapshot before re-checking preemption on node %v: %v", nodeName, err)
+		return false
+	}
+	nodeInfo, ok := g.nodeInfoSnapshot.NodeInfoMap[nodeName]
+	if !ok {
+		return false
+	}
+	nodeInfoCopy := nodeInfo.Clone()
+	meta := g.predicateMetaProducer(pod, g.nodeInfoSnapshot.NodeInfoMap)
+	for _, victim := range victims {
+		nodeInfoCopy.RemovePod(victim)
+		if meta != nil {
+			meta.RemovePod(victim)
+		}
+	}
+	fits, _, err := podFitsOnNode(pod, meta, nodeInfoCopy, g.predicates, g.schedulingQueue, g.alwaysCheckAllPredicates)
+	if err != nil {
+		klog.Warningf("Encountered error while re-checking preemption fit on node %v: %v", nodeName, err)
+		return false
+	}
+	return fits
 }
 
 // processPreemptionWithExtenders processes preemption with extenders
@@ -970,6 +1137,11 @@ func selectNodesForPreemption(pod *v1.Pod,
 	metadataProducer predicates.PredicateMetadataProducer,
 	queue internalqueue.SchedulingQueue,
 	pdbs []*policy.PodDisruptionBudget,
+	minVictimPriorityDelta int32,
+	preemptionWaitSecondsByPriorityClass map[string]int32,
+	maxPreemptedPodsByPriorityClass map[string]int32,
+	podDeletionCost *PodDeletionCostConfig,
+	fwk framework.Framework,
 ) (map[*v1.Node]*schedulerapi.Victims, error) {
 	nodeToVictims := map[*v1.Node]*schedulerapi.Victims{}
 	var resultLock sync.Mutex
@@ -982,7 +1154,10 @@ func selectNodesForPreemption(pod *v1.Pod,
 		if meta != nil {
 			metaCopy = meta.ShallowCopy()
 		}
-		pods, numPDBViolations, fits := selectVictimsOnNode(pod, metaCopy, nodeNameToInfo[nodeName], fitPredicates, queue, pdbs)
+		// Each node gets its own PluginContext: selectVictimsOnNode for different nodes runs
+		// concurrently, and PluginContext is not safe for concurrent use without external locking.
+		pc := framework.NewPluginContext()
+		pods, numPDBViolations, fits := selectVictimsOnNode(pod, metaCopy, nodeNameToInfo[nodeName], fitPredicates, queue, pdbs, minVictimPriorityDelta, preemptionWaitSecondsByPriorityClass, maxPreemptedPodsByPriorityClass, podDeletionCost, fwk, pc)
 		if fits {
 			resultLock.Lock()
 			victims := schedulerapi.Victims{
@@ -1051,6 +1226,14 @@ func filterPodsWithPDBViolation(pods []interface{}, pdbs []*policy.PodDisruption
 // NOTE: This function assumes that it is never called if "pod" cannot be scheduled
 // due to pod affinity, node affinity, or node anti-affinity reasons. None of
 // these predicates can be satisfied by removing more pods from the node.
+// minVictimPriorityDelta, if positive, additionally requires a victim's priority to be at least
+// that much lower than pod's, so that classes with adjacent priority values don't preempt one
+// another; a value of 0 preempts any pod with strictly lower priority.
+// maxPreemptedPodsByPriorityClass, if set for a victim's PriorityClassName, caps how many pods of
+// that class this call may evict; once the cap is reached, further pods of that class are
+// reprieved rather than evicted, even if evicting them would help pod fit.
+// podDeletionCost, if set, prefers evicting the lower-deletion-cost pod among victims of equal
+// priority. See PodDeletionCostConfig.
 func selectVictimsOnNode(
 	pod *v1.Pod,
 	meta predicates.PredicateMetadata,
@@ -1058,11 +1241,19 @@ func selectVictimsOnNode(
 	fitPredicates map[string]predicates.FitPredicate,
 	queue internalqueue.SchedulingQueue,
 	pdbs []*policy.PodDisruptionBudget,
+	minVictimPriorityDelta int32,
+	preemptionWaitSecondsByPriorityClass map[string]int32,
+	maxPreemptedPodsByPriorityClass map[string]int32,
+	podDeletionCost *PodDeletionCostConfig,
+	fwk framework.Framework,
+	pc *framework.PluginContext,
 ) ([]*v1.Pod, int, bool) {
 	if nodeInfo == nil {
 		return nil, 0, false
 	}
-	potentialVictims := util.SortableList{CompFunc: util.MoreImportantPod}
+	potentialVictims := util.SortableList{CompFunc: func(item1, item2 interface{}) bool {
+		return moreImportantVictim(podDeletionCost, item1.(*v1.Pod), item2.(*v1.Pod))
+	}}
 	nodeInfoCopy := nodeInfo.Clone()
 
 	removePod := func(rp *v1.Pod) {
@@ -1081,7 +1272,7 @@ func selectVictimsOnNode(
 	// check if the given pod can be scheduled.
 	podPriority := util.GetPodPriority(pod)
 	for _, p := range nodeInfoCopy.Pods() {
-		if util.GetPodPriority(p) < podPriority {
+		if podPriority-util.GetPodPriority(p) >= minVictimPriorityDelta && util.GetPodPriority(p) < podPriority {
 			potentialVictims.Items = append(potentialVictims.Items, p)
 			removePod(p)
 		}
@@ -1099,21 +1290,35 @@ func selectVictimsOnNode(
 		return nil, 0, false
 	}
 	var victims []*v1.Pod
+	var reprieved []*v1.Pod
 	numViolatingVictim := 0
 	potentialVictims.Sort()
+	if len(preemptionWaitSecondsByPriorityClass) > 0 {
+		potentialVictims.Items = preferPreemptionWait(potentialVictims.Items, preemptionWaitSecondsByPriorityClass, time.Now())
+	}
 	// Try to reprieve as many pods as possible. We first try to reprieve the PDB
 	// violating victims and then other non-violating ones. In both cases, we start
 	// from the highest priority victims.
 	violatingVictims, nonViolatingVictims := filterPodsWithPDBViolation(potentialVictims.Items, pdbs)
+	preemptedCountByPriorityClass := map[string]int32{}
 	reprievePod := func(p *v1.Pod) bool {
 		addPod(p)
 		fits, _, _ := podFitsOnNode(pod, meta, nodeInfoCopy, fitPredicates, queue, false)
+		vetoed := false
 		if !fits {
+			if status := fwk.RunPreemptionFilterPlugins(pc, pod, p, nodeInfo.Node().Name); !status.IsSuccess() {
+				vetoed = true
+			}
+		}
+		if !fits && !vetoed && withinPreemptionBudget(p, maxPreemptedPodsByPriorityClass, preemptedCountByPriorityClass) {
 			removePod(p)
 			victims = append(victims, p)
+			preemptedCountByPriorityClass[p.Spec.PriorityClassName]++
 			klog.V(5).Infof("Pod %v/%v is a potential preemption victim on node %v.", p.Namespace, p.Name, nodeInfo.Node().Name)
+			return false
 		}
-		return fits
+		reprieved = append(reprieved, p)
+		return true
 	}
 	for _, p := range violatingVictims {
 		if !reprievePod(p) {
@@ -1124,9 +1329,161 @@ func selectVictimsOnNode(
 	for _, p := range nonViolatingVictims {
 		reprievePod(p)
 	}
+	// Finally, give victims that other still-scheduled pods have an inter-pod affinity to a
+	// second chance: swap them for an already-reprieved pod of equal or lower priority whose
+	// removal doesn't break anything, if such a substitute exists. This avoids tearing apart an
+	// affinity group when a cheaper substitute victim is available.
+	substituteAffinityAnchors(pod, meta, nodeInfoCopy, fitPredicates, queue, addPod, removePod, &victims, &reprieved)
 	return victims, numViolatingVictim, true
 }
 
+// podEstimatedCompletionAnnotation lets a pod declare, in seconds, how much longer it expects to
+// run. Preemption uses this (together with Spec.ActiveDeadlineSeconds, when set) to prefer waiting
+// briefly for a nearly-finished lower-priority victim to complete on its own over evicting it.
+const podEstimatedCompletionAnnotation = "scheduler.alpha.kubernetes.io/estimated-completion-seconds"
+
+// estimatedSecondsRemaining returns how many more seconds pod is expected to run, using
+// podEstimatedCompletionAnnotation if it is set and parses as a non-negative integer, and
+// otherwise falling back to Spec.ActiveDeadlineSeconds measured from Status.StartTime. It returns
+// ok=false if pod gives no basis for an estimate.
+func estimatedSecondsRemaining(pod *v1.Pod, now time.Time) (remaining int64, ok bool) {
+	if raw, set := pod.Annotations[podEstimatedCompletionAnnotation]; set {
+		if seconds, err := strconv.ParseInt(raw, 10, 64); err == nil && seconds >= 0 {
+			return seconds, true
+		}
+	}
+	if pod.Spec.ActiveDeadlineSeconds == nil || pod.Status.StartTime == nil {
+		return 0, false
+	}
+	elapsed := int64(now.Sub(pod.Status.StartTime.Time).Seconds())
+	remaining = *pod.Spec.ActiveDeadlineSeconds - elapsed
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, true
+}
+
+// withinPreemptionBudget reports whether evicting p would stay within its PriorityClass's
+// configured entry in maxByPriorityClass, given countByPriorityClass so far this node's victim
+// selection. A PriorityClassName with no entry in maxByPriorityClass is unbounded.
+func withinPreemptionBudget(p *v1.Pod, maxByPriorityClass map[string]int32, countByPriorityClass map[string]int32) bool {
+	max, ok := maxByPriorityClass[p.Spec.PriorityClassName]
+	if !ok {
+		return true
+	}
+	return countByPriorityClass[p.Spec.PriorityClassName] < max
+}
+
+// withinPreemptionWaitWindow returns true if pod's PriorityClassName has a configured maximum
+// wait in waitSecondsByPriorityClass and pod's estimated remaining runtime fits within it.
+func withinPreemptionWaitWindow(pod *v1.Pod, waitSecondsByPriorityClass map[string]int32, now time.Time) bool {
+	maxWait, ok := waitSecondsByPriorityClass[pod.Spec.PriorityClassName]
+	if !ok || maxWait <= 0 {
+		return false
+	}
+	remaining, ok := estimatedSecondsRemaining(pod, now)
+	if !ok {
+		return false
+	}
+	return remaining <= int64(maxWait)
+}
+
+// preferPreemptionWait stably moves pods that are within their PriorityClass's configured
+// preemption wait window (see withinPreemptionWaitWindow) to the front of pods, so the reprieve
+// pass in selectVictimsOnNode considers them before other pods of the same priority tier and is
+// more likely to leave them running rather than evict a pod that would have finished on its own
+// shortly anyway.
+func preferPreemptionWait(pods []interface{}, waitSecondsByPriorityClass map[string]int32, now time.Time) []interface{} {
+	waitable := make([]interface{}, 0, len(pods))
+	rest := make([]interface{}, 0, len(pods))
+	for _, obj := range pods {
+		if withinPreemptionWaitWindow(obj.(*v1.Pod), waitSecondsByPriorityClass, now) {
+			waitable = append(waitable, obj)
+		} else {
+			rest = append(rest, obj)
+		}
+	}
+	return append(waitable, rest...)
+}
+
+// substituteAffinityAnchors looks for victims that are the target of another remaining pod's
+// required inter-pod affinity and, where possible, substitutes them with an already-reprieved
+// pod of equal or lower priority so the affinity relationship is preserved.
+func substituteAffinityAnchors(
+	pod *v1.Pod,
+	meta predicates.PredicateMetadata,
+	nodeInfoCopy *schedulernodeinfo.NodeInfo,
+	fitPredicates map[string]predicates.FitPredicate,
+	queue internalqueue.SchedulingQueue,
+	addPod func(*v1.Pod),
+	removePod func(*v1.Pod),
+	victims *[]*v1.Pod,
+	reprieved *[]*v1.Pod,
+) {
+	if len(*reprieved) == 0 {
+		return
+	}
+	remaining := nodeInfoCopy.Pods()
+	for i, victim := range *victims {
+		if !isAffinityAnchor(victim, remaining) {
+			continue
+		}
+		for j, substitute := range *reprieved {
+			if util.GetPodPriority(substitute) > util.GetPodPriority(victim) {
+				continue
+			}
+			removePod(substitute)
+			addPod(victim)
+			fits, _, _ := podFitsOnNode(pod, meta, nodeInfoCopy, fitPredicates, queue, false)
+			if fits {
+				(*victims)[i] = substitute
+				*reprieved = append((*reprieved)[:j], (*reprieved)[j+1:]...)
+				klog.V(5).Infof("Substituted preemption victim %v/%v for %v/%v to preserve inter-pod affinity", substitute.Namespace, substitute.Name, victim.Namespace, victim.Name)
+				break
+			}
+			// Undo: this substitute doesn't work, restore state and try the next one.
+			removePod(victim)
+			addPod(substitute)
+		}
+	}
+}
+
+// isAffinityAnchor returns true if any pod in remaining has a required inter-pod affinity term
+// whose label selector matches candidate, meaning candidate's presence on the node is required
+// to satisfy that affinity.
+func isAffinityAnchor(candidate *v1.Pod, remaining []*v1.Pod) bool {
+	candidateLabels := labels.Set(candidate.Labels)
+	for _, p := range remaining {
+		if p.Spec.Affinity == nil || p.Spec.Affinity.PodAffinity == nil {
+			continue
+		}
+		for _, term := range p.Spec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+			if term.Namespaces != nil && !containsString(term.Namespaces, candidate.Namespace) && len(term.Namespaces) > 0 {
+				continue
+			} else if term.Namespaces == nil && p.Namespace != candidate.Namespace {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(term.LabelSelector)
+			if err != nil {
+				continue
+			}
+			if selector.Matches(candidateLabels) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
 // unresolvablePredicateExists checks whether failedPredicates has unresolvable predicate.
 func unresolvablePredicateExists(failedPredicates []predicates.PredicateFailureReason) bool {
 	for _, failedPredicate := range failedPredicates {
@@ -1220,22 +1577,34 @@ func NewGenericScheduler(
 	alwaysCheckAllPredicates bool,
 	disablePreemption bool,
 	percentageOfNodesToScore int32,
+	minVictimPriorityDelta int32,
+	preemptionWaitSecondsByPriorityClass map[string]int32,
+	maxPreemptedPodsByPriorityClass map[string]int32,
+	preemptionFairness *PreemptionFairnessConfig,
+	preemptionCircuitBreaker *PreemptionCircuitBreaker,
+	podDeletionCost *PodDeletionCostConfig,
 ) ScheduleAlgorithm {
 	return &genericScheduler{
-		cache:                    cache,
-		schedulingQueue:          podQueue,
-		predicates:               predicates,
-		predicateMetaProducer:    predicateMetaProducer,
-		prioritizers:             prioritizers,
-		priorityMetaProducer:     priorityMetaProducer,
-		framework:                framework,
-		extenders:                extenders,
-		nodeInfoSnapshot:         framework.NodeInfoSnapshot(),
-		volumeBinder:             volumeBinder,
-		pvcLister:                pvcLister,
-		pdbLister:                pdbLister,
-		alwaysCheckAllPredicates: alwaysCheckAllPredicates,
-		disablePreemption:        disablePreemption,
-		percentageOfNodesToScore: percentageOfNodesToScore,
+		cache:                                cache,
+		schedulingQueue:                      podQueue,
+		predicates:                           predicates,
+		predicateMetaProducer:                predicateMetaProducer,
+		prioritizers:                         prioritizers,
+		priorityMetaProducer:                 priorityMetaProducer,
+		framework:                            framework,
+		extenders:                            extenders,
+		nodeInfoSnapshot:                     framework.NodeInfoSnapshot(),
+		volumeBinder:                         volumeBinder,
+		pvcLister:                            pvcLister,
+		pdbLister:                            pdbLister,
+		alwaysCheckAllPredicates:             alwaysCheckAllPredicates,
+		disablePreemption:                    disablePreemption,
+		minVictimPriorityDelta:               minVictimPriorityDelta,
+		preemptionWaitSecondsByPriorityClass: preemptionWaitSecondsByPriorityClass,
+		maxPreemptedPodsByPriorityClass:      maxPreemptedPodsByPriorityClass,
+		preemptionFairness:                   preemptionFairness,
+		preemptionCircuitBreaker:             preemptionCircuitBreaker,
+		podDeletionCost:                      podDeletionCost,
+		percentageOfNodesToScore:             percentageOfNodesToScore,
 	}
 }
@@ -29,6 +29,7 @@ import (
 	"k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -464,7 +465,8 @@ func TestGenericScheduler(t *testing.T) {
 				schedulertesting.FakePDBLister{},
 				test.alwaysCheckAllPredicates,
 				false,
-				schedulerapi.DefaultPercentageOfNodesToScore)
+				schedulerapi.DefaultPercentageOfNodesToScore,
+				0, nil, nil, nil, nil, nil)
 			result, err := scheduler.Schedule(test.pod, schedulertesting.FakeNodeLister(makeNodeList(test.nodes)))
 
 			if !reflect.DeepEqual(err, test.wErr) {
@@ -496,7 +498,8 @@ func makeScheduler(predicates map[string]algorithmpredicates.FitPredicate, nodes
 		priorities.EmptyPriorityMetadataProducer,
 		fwk,
 		nil, nil, nil, nil, false, false,
-		schedulerapi.DefaultPercentageOfNodesToScore)
+		schedulerapi.DefaultPercentageOfNodesToScore,
+		0, nil, nil, nil, nil, nil)
 	cache.UpdateNodeInfoSnapshot(s.(*genericScheduler).nodeInfoSnapshot)
 	return s.(*genericScheduler)
 
@@ -992,7 +995,7 @@ func TestSelectNodesForPreemption(t *testing.T) {
 			newnode := makeNode("newnode", 1000*5, priorityutil.DefaultMemoryRequest*5)
 			newnode.ObjectMeta.Labels = map[string]string{"hostname": "newnode"}
 			nodes = append(nodes, newnode)
-			nodeToPods, err := selectNodesForPreemption(test.pod, nodeNameToInfo, nodes, test.predicates, PredicateMetadata, nil, nil)
+			nodeToPods, err := selectNodesForPreemption(test.pod, nodeNameToInfo, nodes, test.predicates, PredicateMetadata, nil, nil, 0, nil, nil, nil, emptyFramework)
 			if err != nil {
 				t.Error(err)
 			}
@@ -1003,6 +1006,205 @@ func TestSelectNodesForPreemption(t *testing.T) {
 	}
 }
 
+// TestSelectNodesForPreemptionMinVictimPriorityDelta verifies that a near-peer victim (whose
+// priority is lower than the preemptor's, but by less than minVictimPriorityDelta) is not
+// preempted, while a victim whose priority is far enough below the preemptor's still is.
+func TestSelectNodesForPreemptionMinVictimPriorityDelta(t *testing.T) {
+	algorithmpredicates.SetPredicatesOrdering(order)
+	nodes := []*v1.Node{makeNode("machine1", 1000*5, priorityutil.DefaultMemoryRequest*5)}
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "preemptor", UID: types.UID("preemptor")}, Spec: v1.PodSpec{Containers: largeContainers, Priority: &highPriority}}
+	pods := []*v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "a", UID: types.UID("a")}, Spec: v1.PodSpec{Containers: largeContainers, Priority: &midPriority, NodeName: "machine1"}},
+	}
+	predicates := map[string]algorithmpredicates.FitPredicate{"matches": algorithmpredicates.PodFitsResources}
+	nodeNameToInfo := schedulernodeinfo.CreateNodeNameToInfoMap(pods, nodes)
+
+	// highPriority - midPriority == 900, so a delta of 500 still permits "a" to be preempted.
+	nodeToPods, err := selectNodesForPreemption(pod, nodeNameToInfo, nodes, predicates, PredicateMetadata, nil, nil, 500, nil, nil, nil, emptyFramework)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := checkPreemptionVictims(map[string]map[string]bool{"machine1": {"a": true}}, nodeToPods); err != nil {
+		t.Errorf("delta 500: %v", err)
+	}
+
+	// A delta of 950 exceeds the 900-point gap, so "a" is not an eligible victim and the
+	// preemptor no longer fits on machine1.
+	nodeToPods, err = selectNodesForPreemption(pod, nodeNameToInfo, nodes, predicates, PredicateMetadata, nil, nil, 950, nil, nil, nil, emptyFramework)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := checkPreemptionVictims(map[string]map[string]bool{}, nodeToPods); err != nil {
+		t.Errorf("delta 950: %v", err)
+	}
+}
+
+func TestSelectNodesForPreemptionWaitSecondsByPriorityClass(t *testing.T) {
+	algorithmpredicates.SetPredicatesOrdering(order)
+	nodes := []*v1.Node{makeNode("machine1", 1000*3, priorityutil.DefaultMemoryRequest*3)}
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "preemptor", UID: types.UID("preemptor")}, Spec: v1.PodSpec{Containers: mediumContainers, Priority: &highPriority}}
+	// "almostDone" is within its priority class's preemption wait window and should be
+	// preferred for reprieve over "freshlyStarted", which has no completion estimate. Evicting
+	// either one alone (but not neither) frees enough room for the preemptor.
+	almostDone := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "almostDone", UID: types.UID("almostDone")},
+		Spec: v1.PodSpec{
+			Containers:        smallContainers,
+			Priority:          &midPriority,
+			PriorityClassName: "waitable",
+			NodeName:          "machine1",
+		},
+		Status: v1.PodStatus{StartTime: &startTime},
+	}
+	almostDone.Annotations = map[string]string{podEstimatedCompletionAnnotation: "5"}
+	freshlyStarted := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "freshlyStarted", UID: types.UID("freshlyStarted")},
+		Spec: v1.PodSpec{
+			Containers:        smallContainers,
+			Priority:          &midPriority,
+			PriorityClassName: "waitable",
+			NodeName:          "machine1",
+		},
+		Status: v1.PodStatus{StartTime: &startTime},
+	}
+	pods := []*v1.Pod{almostDone, freshlyStarted}
+	predicates := map[string]algorithmpredicates.FitPredicate{"matches": algorithmpredicates.PodFitsResources}
+	nodeNameToInfo := schedulernodeinfo.CreateNodeNameToInfoMap(pods, nodes)
+
+	nodeToPods, err := selectNodesForPreemption(pod, nodeNameToInfo, nodes, predicates, PredicateMetadata, nil, nil, 0, map[string]int32{"waitable": 30}, nil, nil, emptyFramework)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := checkPreemptionVictims(map[string]map[string]bool{"machine1": {"freshlyStarted": true}}, nodeToPods); err != nil {
+		t.Errorf("expected only freshlyStarted to be a victim: %v", err)
+	}
+}
+
+func TestSelectNodesForPreemptionMaxPreemptedPodsByPriorityClass(t *testing.T) {
+	algorithmpredicates.SetPredicatesOrdering(order)
+	nodes := []*v1.Node{makeNode("machine1", 1000*2, priorityutil.DefaultMemoryRequest*2)}
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "preemptor", UID: types.UID("preemptor")}, Spec: v1.PodSpec{Containers: mediumContainers, Priority: &highPriority}}
+	// "a" and "b" together use the whole node; both must be evicted for the preemptor to fit.
+	a := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "a", UID: types.UID("a")},
+		Spec: v1.PodSpec{
+			Containers:        smallContainers,
+			Priority:          &midPriority,
+			PriorityClassName: "limited",
+			NodeName:          "machine1",
+		},
+	}
+	b := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "b", UID: types.UID("b")},
+		Spec: v1.PodSpec{
+			Containers:        smallContainers,
+			Priority:          &midPriority,
+			PriorityClassName: "limited",
+			NodeName:          "machine1",
+		},
+	}
+	pods := []*v1.Pod{a, b}
+	predicates := map[string]algorithmpredicates.FitPredicate{"matches": algorithmpredicates.PodFitsResources}
+	nodeNameToInfo := schedulernodeinfo.CreateNodeNameToInfoMap(pods, nodes)
+
+	// With no budget, both "a" and "b" are evicted and the preemptor fits.
+	nodeToPods, err := selectNodesForPreemption(pod, nodeNameToInfo, nodes, predicates, PredicateMetadata, nil, nil, 0, nil, nil, nil, emptyFramework)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := checkPreemptionVictims(map[string]map[string]bool{"machine1": {"a": true, "b": true}}, nodeToPods); err != nil {
+		t.Errorf("expected both a and b to be victims: %v", err)
+	}
+
+	// With a budget of 1 pod for "limited", only one of "a"/"b" can be evicted, which is not
+	// enough room for the preemptor to fit, so "machine1" should not be a candidate at all.
+	nodeToPods, err = selectNodesForPreemption(pod, nodeNameToInfo, nodes, predicates, PredicateMetadata, nil, nil, 0, nil, map[string]int32{"limited": 1}, nil, emptyFramework)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := checkPreemptionVictims(map[string]map[string]bool{}, nodeToPods); err != nil {
+		t.Errorf("expected no candidate nodes: %v", err)
+	}
+}
+
+// TestWorkloadFairnessAllows verifies that a workload already holding more nominated victims
+// than another named workload defers, while a workload with no nominations of its own, or one
+// that already has the fewest, is always allowed to proceed.
+func TestWorkloadFairnessAllows(t *testing.T) {
+	queue := internalqueue.NewSchedulingQueue(nil)
+	heavy1 := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "heavy-victim-1", UID: types.UID("heavy-victim-1"), Labels: map[string]string{"workload": "heavy"}}}
+	heavy2 := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "heavy-victim-2", UID: types.UID("heavy-victim-2"), Labels: map[string]string{"workload": "heavy"}}}
+	queue.UpdateNominatedPodForNode(heavy1, "machine1")
+	queue.UpdateNominatedPodForNode(heavy2, "machine1")
+
+	g := &genericScheduler{
+		schedulingQueue:    queue,
+		preemptionFairness: &PreemptionFairnessConfig{LabelKey: "workload"},
+	}
+
+	lightPreemptor := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "light-preemptor", Labels: map[string]string{"workload": "light"}}}
+	if !g.workloadFairnessAllows(lightPreemptor) {
+		t.Errorf("expected a workload with no prior nominations to be allowed to preempt")
+	}
+
+	heavyPreemptor := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "heavy-preemptor", Labels: map[string]string{"workload": "heavy"}}}
+	light := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "light-victim", UID: types.UID("light-victim"), Labels: map[string]string{"workload": "light"}}}
+	queue.UpdateNominatedPodForNode(light, "machine1")
+	if g.workloadFairnessAllows(heavyPreemptor) {
+		t.Errorf("expected the workload with more nominations than another named workload to defer")
+	}
+}
+
+// vetoingPreemptionFilterPlugin vetoes any victim candidate whose name is in vetoed.
+type vetoingPreemptionFilterPlugin struct {
+	vetoed map[string]bool
+}
+
+func (p *vetoingPreemptionFilterPlugin) Name() string { return "vetoing-preemption-filter" }
+
+func (p *vetoingPreemptionFilterPlugin) FilterPreemption(pc *framework.PluginContext, pod *v1.Pod, victim *v1.Pod, nodeName string) *framework.Status {
+	if p.vetoed[victim.Name] {
+		return framework.NewStatus(framework.Unschedulable, "victim is protected")
+	}
+	return nil
+}
+
+// TestSelectNodesForPreemptionFilterPlugin verifies that a PreemptionFilterPlugin vetoing a
+// candidate keeps it from being evicted, even though evicting it would otherwise let the
+// preemptor fit.
+func TestSelectNodesForPreemptionFilterPlugin(t *testing.T) {
+	algorithmpredicates.SetPredicatesOrdering(order)
+	nodes := []*v1.Node{makeNode("machine1", 1000, priorityutil.DefaultMemoryRequest)}
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "preemptor", UID: types.UID("preemptor")}, Spec: v1.PodSpec{Containers: smallContainers, Priority: &highPriority}}
+	protected := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "protected", UID: types.UID("protected")},
+		Spec:       v1.PodSpec{Containers: smallContainers, Priority: &midPriority, NodeName: "machine1"},
+	}
+	pods := []*v1.Pod{protected}
+	predicates := map[string]algorithmpredicates.FitPredicate{"matches": algorithmpredicates.PodFitsResources}
+	nodeNameToInfo := schedulernodeinfo.CreateNodeNameToInfoMap(pods, nodes)
+
+	registry := framework.Registry{}
+	plugin := &vetoingPreemptionFilterPlugin{vetoed: map[string]bool{"protected": true}}
+	if err := registry.Register(plugin.Name(), func(_ *runtime.Unknown, _ framework.FrameworkHandle) (framework.Plugin, error) {
+		return plugin, nil
+	}); err != nil {
+		t.Fatalf("failed to register plugin: %v", err)
+	}
+	fwk, err := framework.NewFramework(registry, nil)
+	if err != nil {
+		t.Fatalf("failed to create framework: %v", err)
+	}
+
+	nodeToPods, err := selectNodesForPreemption(pod, nodeNameToInfo, nodes, predicates, PredicateMetadata, nil, nil, 0, nil, nil, nil, fwk)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := checkPreemptionVictims(map[string]map[string]bool{}, nodeToPods); err != nil {
+		t.Errorf("expected the vetoed pod to not be evicted, leaving no viable candidate node: %v", err)
+	}
+}
+
 // TestPickOneNodeForPreemption tests pickOneNodeForPreemption.
 func TestPickOneNodeForPreemption(t *testing.T) {
 	algorithmpredicates.SetPredicatesOrdering(order)
@@ -1203,7 +1405,7 @@ func TestPickOneNodeForPreemption(t *testing.T) {
 				nodes = append(nodes, makeNode(n, priorityutil.DefaultMilliCPURequest*5, priorityutil.DefaultMemoryRequest*5))
 			}
 			nodeNameToInfo := schedulernodeinfo.CreateNodeNameToInfoMap(test.pods, nodes)
-			candidateNodes, _ := selectNodesForPreemption(test.pod, nodeNameToInfo, nodes, test.predicates, PredicateMetadata, nil, nil)
+			candidateNodes, _ := selectNodesForPreemption(test.pod, nodeNameToInfo, nodes, test.predicates, PredicateMetadata, nil, nil, 0, nil, nil, nil, emptyFramework)
 			node := pickOneNodeForPreemption(candidateNodes)
 			found := false
 			for _, nodeName := range test.expected {
@@ -1501,7 +1703,8 @@ func TestPreempt(t *testing.T) {
 				schedulertesting.FakePDBLister{},
 				false,
 				false,
-				schedulerapi.DefaultPercentageOfNodesToScore)
+				schedulerapi.DefaultPercentageOfNodesToScore,
+				0, nil, nil, nil, nil, nil)
 			scheduler.(*genericScheduler).snapshot()
 			// Call Preempt and check the expected results.
 			node, victims, _, err := scheduler.Preempt(test.pod, schedulertesting.FakeNodeLister(makeNodeList(nodeNames)), error(&FitError{Pod: test.pod, FailedPredicates: failedPredMap}))
@@ -1546,6 +1749,58 @@ func TestPreempt(t *testing.T) {
 	}
 }
 
+// TestVictimsStillMakeRoom verifies that the final, pre-deletion re-check performed by
+// genericScheduler.victimsStillMakeRoom catches the case where the room freed by evicting the
+// selected victims has, by the time of the check, already been consumed by some other pod that
+// landed on the node in the meantime.
+func TestVictimsStillMakeRoom(t *testing.T) {
+	stop := make(chan struct{})
+	defer close(stop)
+	cache := internalcache.New(time.Duration(0), stop)
+	fwk, _ := framework.NewFramework(EmptyPluginRegistry, nil)
+
+	node := makeNode("machine1", 1000*5, priorityutil.DefaultMemoryRequest*5)
+	cache.AddNode(node)
+
+	victim := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "victim", UID: types.UID("victim")}, Spec: v1.PodSpec{Containers: smallContainers, Priority: &lowPriority, NodeName: "machine1"}, Status: v1.PodStatus{Phase: v1.PodRunning}}
+	if err := cache.AddPod(victim); err != nil {
+		t.Fatalf("unexpected error adding victim pod: %v", err)
+	}
+	preemptor := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "preemptor", UID: types.UID("preemptor")}, Spec: v1.PodSpec{Containers: veryLargeContainers, Priority: &highPriority}}
+
+	scheduler := NewGenericScheduler(
+		cache,
+		internalqueue.NewSchedulingQueue(nil),
+		map[string]algorithmpredicates.FitPredicate{"matches": algorithmpredicates.PodFitsResources},
+		algorithmpredicates.EmptyPredicateMetadataProducer,
+		[]priorities.PriorityConfig{{Function: numericPriority, Weight: 1}},
+		priorities.EmptyPriorityMetadataProducer,
+		fwk,
+		nil,
+		nil,
+		schedulertesting.FakePersistentVolumeClaimLister{},
+		schedulertesting.FakePDBLister{},
+		false,
+		false,
+		schedulerapi.DefaultPercentageOfNodesToScore,
+		0, nil, nil, nil, nil, nil).(*genericScheduler)
+
+	if !scheduler.victimsStillMakeRoom(preemptor, []*v1.Pod{victim}, "machine1") {
+		t.Errorf("expected preemptor to still fit machine1 once victim is evicted")
+	}
+
+	// A pod lands on the node between victim selection and this final check, consuming the room
+	// evicting the victim would otherwise have freed.
+	intruder := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "intruder", UID: types.UID("intruder")}, Spec: v1.PodSpec{Containers: veryLargeContainers, Priority: &highPriority, NodeName: "machine1"}, Status: v1.PodStatus{Phase: v1.PodRunning}}
+	if err := cache.AddPod(intruder); err != nil {
+		t.Fatalf("unexpected error adding intruder pod: %v", err)
+	}
+
+	if scheduler.victimsStillMakeRoom(preemptor, []*v1.Pod{victim}, "machine1") {
+		t.Errorf("expected re-check to fail once the freed room was consumed by another pod")
+	}
+}
+
 func TestNumFeasibleNodesToFind(t *testing.T) {
 	tests := []struct {
 		name                     string
@@ -0,0 +1,154 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync/atomic"
+
+	"k8s.io/api/core/v1"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog"
+
+	"k8s.io/kubernetes/pkg/scheduler/metrics"
+	"k8s.io/kubernetes/pkg/scheduler/util"
+)
+
+// preemptionCircuitBreakerAllowPercentKey is the ConfigMap data key PreemptionCircuitBreaker
+// reads the current allow percentage from.
+const preemptionCircuitBreakerAllowPercentKey = "allowPercent"
+
+// preemptionCircuitBreakerUnset marks a PreemptionCircuitBreaker that has never observed a
+// watched ConfigMap (or has just seen it deleted), in which case it fails open.
+const preemptionCircuitBreakerUnset = -1
+
+// PreemptionCircuitBreaker gates non-system preemption attempts behind a percentage read from a
+// watched ConfigMap, so an operator can immediately disable preemption cluster-wide during an
+// incident (by setting the ConfigMap's "allowPercent" data key to "0") and then ramp it back up
+// gradually (raising allowPercent toward "100") instead of flipping preemption back on all at
+// once. Pods with a system priority (see util.PodHasSystemPriority) always bypass the breaker, so
+// system-critical workloads can never be blocked from making room for themselves. With no
+// ConfigMap observed, the breaker fails open and allows every attempt.
+type PreemptionCircuitBreaker struct {
+	// allowPercent is 0-100, or preemptionCircuitBreakerUnset. Accessed atomically since it's
+	// read on every preemption attempt and written from the informer's event handler goroutine.
+	allowPercent int32
+}
+
+// NewPreemptionCircuitBreaker returns a PreemptionCircuitBreaker that stays synced to
+// namespace/name's "allowPercent" data key via cmInformer, and records an Event against the
+// watched ConfigMap on every change to the effective allow percentage, so the breaker's state is
+// visible via `kubectl describe configmap` as well as through
+// metrics.PreemptionCircuitBreakerAllowPercent. recorder may be nil, in which case state changes
+// are only reflected in the metric.
+func NewPreemptionCircuitBreaker(cmInformer coreinformers.ConfigMapInformer, namespace, name string, recorder record.EventRecorder) *PreemptionCircuitBreaker {
+	b := &PreemptionCircuitBreaker{allowPercent: preemptionCircuitBreakerUnset}
+	watches := func(cm *v1.ConfigMap) bool {
+		return cm.Namespace == namespace && cm.Name == name
+	}
+	cmInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if cm, ok := obj.(*v1.ConfigMap); ok && watches(cm) {
+				b.sync(cm, recorder)
+			}
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			if cm, ok := cur.(*v1.ConfigMap); ok && watches(cm) {
+				b.sync(cm, recorder)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if cm, ok := obj.(*v1.ConfigMap); ok && watches(cm) {
+				b.apply(preemptionCircuitBreakerUnset, cm, recorder)
+			}
+		},
+	})
+	return b
+}
+
+// sync parses cm's allowPercent data key, clamps it to [0, 100], and applies it. A missing key is
+// treated the same as a deleted ConfigMap: fail open.
+func (b *PreemptionCircuitBreaker) sync(cm *v1.ConfigMap, recorder record.EventRecorder) {
+	raw, ok := cm.Data[preemptionCircuitBreakerAllowPercentKey]
+	if !ok {
+		b.apply(preemptionCircuitBreakerUnset, cm, recorder)
+		return
+	}
+	percent, err := strconv.Atoi(raw)
+	if err != nil {
+		klog.Errorf("Preemption circuit breaker ConfigMap %v/%v has invalid %q value %q: %v", cm.Namespace, cm.Name, preemptionCircuitBreakerAllowPercentKey, raw, err)
+		return
+	}
+	if percent < 0 {
+		percent = 0
+	} else if percent > 100 {
+		percent = 100
+	}
+	b.apply(int32(percent), cm, recorder)
+}
+
+// apply installs percent as the current allow percentage, updating
+// metrics.PreemptionCircuitBreakerAllowPercent and recording an Event against cm if the
+// effective percentage actually changed.
+func (b *PreemptionCircuitBreaker) apply(percent int32, cm *v1.ConfigMap, recorder record.EventRecorder) {
+	old := atomic.SwapInt32(&b.allowPercent, percent)
+	if old == percent {
+		return
+	}
+	reportedPercent := percent
+	if reportedPercent == preemptionCircuitBreakerUnset {
+		reportedPercent = 100
+	}
+	metrics.PreemptionCircuitBreakerAllowPercent.Set(float64(reportedPercent))
+	if recorder != nil {
+		recorder.Eventf(cm, v1.EventTypeNormal, "PreemptionAllowPercentChanged",
+			"Non-system preemption allow percent changed from %s to %s", percentString(old), percentString(percent))
+	}
+}
+
+func percentString(percent int32) string {
+	if percent == preemptionCircuitBreakerUnset {
+		return "unset (fully open)"
+	}
+	return fmt.Sprintf("%d", percent)
+}
+
+// Allows reports whether pod should be allowed to proceed with this preemption attempt. Pods
+// with a system priority always proceed. Otherwise pod proceeds with probability
+// allowPercent/100; a breaker that has never observed its ConfigMap always allows.
+func (b *PreemptionCircuitBreaker) Allows(pod *v1.Pod) bool {
+	if util.PodHasSystemPriority(pod) {
+		return true
+	}
+	percent := atomic.LoadInt32(&b.allowPercent)
+	switch {
+	case percent == preemptionCircuitBreakerUnset || percent >= 100:
+		return true
+	case percent <= 0:
+		metrics.PreemptionCircuitBreakerBlockedAttempts.Inc()
+		return false
+	case rand.Int31n(100) < percent:
+		return true
+	default:
+		metrics.PreemptionCircuitBreakerBlockedAttempts.Inc()
+		return false
+	}
+}
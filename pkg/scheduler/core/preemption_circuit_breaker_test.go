@@ -0,0 +1,110 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/apis/scheduling"
+)
+
+// TestPreemptionCircuitBreakerAllows tests PreemptionCircuitBreaker.Allows for the deterministic
+// cases: system-priority pods, an unset breaker, and the 0%/100% extremes.
+func TestPreemptionCircuitBreakerAllows(t *testing.T) {
+	systemPriority := scheduling.SystemCriticalPriority
+	userPriority := int32(1000)
+	systemPod := &v1.Pod{Spec: v1.PodSpec{Priority: &systemPriority}}
+	userPod := &v1.Pod{Spec: v1.PodSpec{Priority: &userPriority}}
+
+	tests := []struct {
+		name         string
+		allowPercent int32
+		pod          *v1.Pod
+		expected     bool
+	}{
+		{
+			name:         "system priority pod always allowed even when fully closed",
+			allowPercent: 0,
+			pod:          systemPod,
+			expected:     true,
+		},
+		{
+			name:         "unset breaker fails open",
+			allowPercent: preemptionCircuitBreakerUnset,
+			pod:          userPod,
+			expected:     true,
+		},
+		{
+			name:         "fully closed blocks a non-system pod",
+			allowPercent: 0,
+			pod:          userPod,
+			expected:     false,
+		},
+		{
+			name:         "fully open allows a non-system pod",
+			allowPercent: 100,
+			pod:          userPod,
+			expected:     true,
+		},
+	}
+	for _, test := range tests {
+		b := &PreemptionCircuitBreaker{allowPercent: test.allowPercent}
+		if got := b.Allows(test.pod); got != test.expected {
+			t.Errorf("%v: expected %v, got %v", test.name, test.expected, got)
+		}
+	}
+}
+
+// TestPreemptionCircuitBreakerSync tests that sync parses, clamps, and applies the ConfigMap's
+// allowPercent data key.
+func TestPreemptionCircuitBreakerSync(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     map[string]string
+		expected int32
+	}{
+		{
+			name:     "missing key fails open",
+			data:     map[string]string{},
+			expected: preemptionCircuitBreakerUnset,
+		},
+		{
+			name:     "in-range value is used as-is",
+			data:     map[string]string{"allowPercent": "42"},
+			expected: 42,
+		},
+		{
+			name:     "negative value is clamped to 0",
+			data:     map[string]string{"allowPercent": "-5"},
+			expected: 0,
+		},
+		{
+			name:     "value over 100 is clamped to 100",
+			data:     map[string]string{"allowPercent": "150"},
+			expected: 100,
+		},
+	}
+	for _, test := range tests {
+		b := &PreemptionCircuitBreaker{allowPercent: preemptionCircuitBreakerUnset}
+		cm := &v1.ConfigMap{Data: test.data}
+		b.sync(cm, nil)
+		if b.allowPercent != test.expected {
+			t.Errorf("%v: expected allowPercent %v, got %v", test.name, test.expected, b.allowPercent)
+		}
+	}
+}
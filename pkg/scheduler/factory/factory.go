@@ -120,8 +120,16 @@ type Config struct {
 	// Disable pod preemption or not.
 	DisablePreemption bool
 
+	// Restrict preemption to pods with a system priority or not. Has no effect if
+	// DisablePreemption is true.
+	PreemptionSystemOnly bool
+
 	// SchedulingQueue holds pods to be scheduled
 	SchedulingQueue internalqueue.SchedulingQueue
+
+	// Client is used for the occasional live read that isn't worth caching for, such as
+	// revalidating a pod's PriorityClass immediately before binding.
+	Client clientset.Interface
 }
 
 // PodPreemptor has methods needed to delete a pod and to update 'NominatedPod'
@@ -178,6 +186,8 @@ type configFactory struct {
 	replicaSetLister appslisters.ReplicaSetLister
 	// a means to list all statefulsets
 	statefulSetLister appslisters.StatefulSetLister
+	// a means to list all daemonsets
+	daemonSetLister appslisters.DaemonSetLister
 	// a means to list all PodDisruptionBudgets
 	pdbLister policylisters.PodDisruptionBudgetLister
 	// a means to list all StorageClasses
@@ -213,7 +223,38 @@ type configFactory struct {
 	// percentageOfNodesToScore specifies percentage of all nodes to score in each scheduling cycle.
 	percentageOfNodesToScore int32
 
+	// minVictimPriorityDelta is the minimum amount by which a preemption victim's priority must
+	// be lower than the preemptor's priority in order to be evicted. See core.selectVictimsOnNode.
+	minVictimPriorityDelta int32
+
+	// preemptionWaitSecondsByPriorityClass maps a PriorityClassName to the maximum number of
+	// seconds preemption will prefer waiting for a pod of that class to finish on its own over
+	// evicting it. See core.selectVictimsOnNode.
+	preemptionWaitSecondsByPriorityClass map[string]int32
+
+	// maxPreemptedPodsByPriorityClass maps a PriorityClassName to the maximum number of pods of
+	// that class preemption may evict per node during a single preemption attempt. See
+	// core.selectVictimsOnNode.
+	maxPreemptedPodsByPriorityClass map[string]int32
+
+	// preemptionFairness, if set, enables round-robin fairness of freed preemption capacity
+	// across workloads. See core.PreemptionFairnessConfig.
+	preemptionFairness *core.PreemptionFairnessConfig
+
+	// preemptionCircuitBreaker, if set, can block non-system-priority preemption attempts
+	// cluster-wide. See core.PreemptionCircuitBreaker.
+	preemptionCircuitBreaker *core.PreemptionCircuitBreaker
+
+	// podDeletionCost, if set, prefers evicting the lower-deletion-cost pod among preemption
+	// victims of equal priority. See core.PodDeletionCostConfig.
+	podDeletionCost *core.PodDeletionCostConfig
+
 	bindTimeoutSeconds int64
+
+	// Number of currently pending pods with strictly higher priority than a given pod, above
+	// which dynamic PV provisioning for that pod is delayed. See VolumeBinder.
+	provisioningBacklogThreshold int32
+
 	// queue for pods that need scheduling
 	podQueue internalqueue.SchedulingQueue
 }
@@ -229,15 +270,50 @@ type ConfigFactoryArgs struct {
 	ReplicationControllerInformer  coreinformers.ReplicationControllerInformer
 	ReplicaSetInformer             appsinformers.ReplicaSetInformer
 	StatefulSetInformer            appsinformers.StatefulSetInformer
+	DaemonSetInformer              appsinformers.DaemonSetInformer
 	ServiceInformer                coreinformers.ServiceInformer
 	PdbInformer                    policyinformers.PodDisruptionBudgetInformer
 	StorageClassInformer           storageinformers.StorageClassInformer
 	HardPodAffinitySymmetricWeight int32
 	DisablePreemption              bool
 	PercentageOfNodesToScore       int32
-	BindTimeoutSeconds             int64
-	StopCh                         <-chan struct{}
-	Registry                       framework.Registry
+	// MinVictimPriorityDelta, if positive, requires a preemption victim's priority to be at
+	// least this much lower than the preemptor's priority; the default of 0 preempts any pod
+	// with strictly lower priority.
+	MinVictimPriorityDelta int32
+	// PreemptionWaitSecondsByPriorityClass, if set, maps a PriorityClassName to the maximum
+	// number of seconds preemption will prefer waiting for a pod of that class to finish on its
+	// own, based on its estimated remaining runtime, over evicting it.
+	PreemptionWaitSecondsByPriorityClass map[string]int32
+	// MaxPreemptedPodsByPriorityClass, if set, maps a PriorityClassName to the maximum number of
+	// pods of that class preemption may evict per node during a single preemption attempt.
+	MaxPreemptedPodsByPriorityClass map[string]int32
+	BindTimeoutSeconds              int64
+	ProvisioningBacklogThreshold         int32
+	StopCh                               <-chan struct{}
+	Registry                             framework.Registry
+	// TenantFairnessConfig, if set, enables weighted fair ordering of equal-priority pods
+	// across tenants in the scheduling queue. See internalqueue.TenantFairnessConfig.
+	TenantFairnessConfig *internalqueue.TenantFairnessConfig
+	// PriorityAgingConfig, if set, enables gradual aging of pods' effective priority in the
+	// scheduling queue. See internalqueue.PriorityAgingConfig.
+	PriorityAgingConfig *internalqueue.PriorityAgingConfig
+	// PreemptionFairnessConfig, if set, enables round-robin fairness of freed preemption
+	// capacity across workloads. See core.PreemptionFairnessConfig.
+	PreemptionFairnessConfig *core.PreemptionFairnessConfig
+	// DRFFairnessConfig, if set, enables dominant-resource-fairness ordering of equal-priority
+	// pods across tenants in the scheduling queue, in place of TenantFairnessConfig. See
+	// internalqueue.DRFFairnessConfig.
+	DRFFairnessConfig *internalqueue.DRFFairnessConfig
+	// PreemptionCircuitBreaker, if set, can block non-system-priority preemption attempts
+	// cluster-wide, e.g. during an incident. See core.PreemptionCircuitBreaker.
+	PreemptionCircuitBreaker *core.PreemptionCircuitBreaker
+	// NamespaceMinShareConfig, if set, guarantees each named namespace a minimum share of
+	// scheduling cycles in the scheduling queue. See internalqueue.NamespaceMinShareConfig.
+	NamespaceMinShareConfig *internalqueue.NamespaceMinShareConfig
+	// PodDeletionCostConfig, if set, prefers evicting the lower-deletion-cost pod among
+	// preemption victims of equal priority. See core.PodDeletionCostConfig.
+	PodDeletionCostConfig *core.PodDeletionCostConfig
 }
 
 // NewConfigFactory initializes the default implementation of a Configurator. To encourage eventual privatization of the struct type, we only
@@ -260,29 +336,50 @@ func NewConfigFactory(args *ConfigFactoryArgs) Configurator {
 		storageClassLister = args.StorageClassInformer.Lister()
 	}
 	c := &configFactory{
-		client:                         args.Client,
-		podLister:                      schedulerCache,
-		podQueue:                       internalqueue.NewSchedulingQueue(stopEverything),
-		nodeLister:                     args.NodeInformer.Lister(),
-		pVLister:                       args.PvInformer.Lister(),
-		pVCLister:                      args.PvcInformer.Lister(),
-		serviceLister:                  args.ServiceInformer.Lister(),
-		controllerLister:               args.ReplicationControllerInformer.Lister(),
-		replicaSetLister:               args.ReplicaSetInformer.Lister(),
-		statefulSetLister:              args.StatefulSetInformer.Lister(),
-		pdbLister:                      args.PdbInformer.Lister(),
-		storageClassLister:             storageClassLister,
-		framework:                      framework,
-		schedulerCache:                 schedulerCache,
-		StopEverything:                 stopEverything,
-		schedulerName:                  args.SchedulerName,
-		hardPodAffinitySymmetricWeight: args.HardPodAffinitySymmetricWeight,
-		disablePreemption:              args.DisablePreemption,
-		percentageOfNodesToScore:       args.PercentageOfNodesToScore,
-		bindTimeoutSeconds:             args.BindTimeoutSeconds,
+		client:                               args.Client,
+		podLister:                            schedulerCache,
+		podQueue:                             internalqueue.NewSchedulingQueue(stopEverything),
+		nodeLister:                           args.NodeInformer.Lister(),
+		pVLister:                             args.PvInformer.Lister(),
+		pVCLister:                            args.PvcInformer.Lister(),
+		serviceLister:                        args.ServiceInformer.Lister(),
+		controllerLister:                     args.ReplicationControllerInformer.Lister(),
+		replicaSetLister:                     args.ReplicaSetInformer.Lister(),
+		statefulSetLister:                    args.StatefulSetInformer.Lister(),
+		daemonSetLister:                      args.DaemonSetInformer.Lister(),
+		pdbLister:                            args.PdbInformer.Lister(),
+		storageClassLister:                   storageClassLister,
+		framework:                            framework,
+		schedulerCache:                       schedulerCache,
+		StopEverything:                       stopEverything,
+		schedulerName:                        args.SchedulerName,
+		hardPodAffinitySymmetricWeight:       args.HardPodAffinitySymmetricWeight,
+		disablePreemption:                    args.DisablePreemption,
+		percentageOfNodesToScore:             args.PercentageOfNodesToScore,
+		minVictimPriorityDelta:               args.MinVictimPriorityDelta,
+		preemptionWaitSecondsByPriorityClass: args.PreemptionWaitSecondsByPriorityClass,
+		maxPreemptedPodsByPriorityClass:      args.MaxPreemptedPodsByPriorityClass,
+		preemptionFairness:                   args.PreemptionFairnessConfig,
+		preemptionCircuitBreaker:             args.PreemptionCircuitBreaker,
+		podDeletionCost:                      args.PodDeletionCostConfig,
+		bindTimeoutSeconds:                   args.BindTimeoutSeconds,
+		provisioningBacklogThreshold:         args.ProvisioningBacklogThreshold,
+	}
+	if args.TenantFairnessConfig != nil {
+		c.podQueue.SetTenantFairnessConfig(args.TenantFairnessConfig)
+	}
+	if args.PriorityAgingConfig != nil {
+		c.podQueue.SetPriorityAgingConfig(args.PriorityAgingConfig)
+	}
+	if args.DRFFairnessConfig != nil {
+		c.podQueue.SetDRFFairnessConfig(args.DRFFairnessConfig)
+	}
+	if args.NamespaceMinShareConfig != nil {
+		c.podQueue.SetNamespaceMinShareConfig(args.NamespaceMinShareConfig)
 	}
 	// Setup volume binder
-	c.volumeBinder = volumebinder.NewVolumeBinder(args.Client, args.NodeInformer, args.PvcInformer, args.PvInformer, args.StorageClassInformer, time.Duration(args.BindTimeoutSeconds)*time.Second)
+	c.volumeBinder = volumebinder.NewVolumeBinder(args.Client, args.NodeInformer, args.PvcInformer, args.PvInformer, args.StorageClassInformer, time.Duration(args.BindTimeoutSeconds)*time.Second,
+		c.podQueue.PendingPods, args.ProvisioningBacklogThreshold)
 	c.scheduledPodsHasSynced = args.PodInformer.Informer().HasSynced
 	// ScheduledPodLister is something we provide to plug-in functions that
 	// they may need to call.
@@ -456,6 +553,12 @@ func (c *configFactory) CreateFromKeys(predicateKeys, priorityKeys sets.String,
 		c.alwaysCheckAllPredicates,
 		c.disablePreemption,
 		c.percentageOfNodesToScore,
+		c.minVictimPriorityDelta,
+		c.preemptionWaitSecondsByPriorityClass,
+		c.maxPreemptedPodsByPriorityClass,
+		c.preemptionFairness,
+		c.preemptionCircuitBreaker,
+		c.podDeletionCost,
 	)
 
 	return &Config{
@@ -475,6 +578,7 @@ func (c *configFactory) CreateFromKeys(predicateKeys, priorityKeys sets.String,
 		StopEverything:  c.StopEverything,
 		VolumeBinder:    c.volumeBinder,
 		SchedulingQueue: c.podQueue,
+		Client:          c.client,
 	}, nil
 }
 
@@ -546,6 +650,7 @@ func (c *configFactory) getPluginArgs() (*PluginFactoryArgs, error) {
 		ControllerLister:               c.controllerLister,
 		ReplicaSetLister:               c.replicaSetLister,
 		StatefulSetLister:              c.statefulSetLister,
+		DaemonSetLister:                c.daemonSetLister,
 		NodeLister:                     &nodeLister{c.nodeLister},
 		PDBLister:                      c.pdbLister,
 		NodeInfo:                       &predicates.CachedNodeInfo{NodeLister: c.nodeLister},
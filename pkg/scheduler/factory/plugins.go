@@ -40,6 +40,7 @@ type PluginFactoryArgs struct {
 	ControllerLister               algorithm.ControllerLister
 	ReplicaSetLister               algorithm.ReplicaSetLister
 	StatefulSetLister              algorithm.StatefulSetLister
+	DaemonSetLister                algorithm.DaemonSetLister
 	NodeLister                     algorithm.NodeLister
 	PDBLister                      algorithm.PDBLister
 	NodeInfo                       predicates.NodeInfo
@@ -229,6 +230,20 @@ func RegisterCustomFitPredicate(policy schedulerapi.PredicatePolicy) string {
 					policy.Argument.LabelsPresence.Presence,
 				)
 			}
+		} else if policy.Argument.NodeOvercommit != nil {
+			predicateFactory = func(args PluginFactoryArgs) predicates.FitPredicate {
+				return predicates.NewNodeOvercommitPredicate(
+					policy.Argument.NodeOvercommit.PriorityThreshold,
+					policy.Argument.NodeOvercommit.OvercommitPercentage,
+				)
+			}
+		} else if policy.Argument.ExtendedResourcePriorityFloor != nil {
+			predicateFactory = func(args PluginFactoryArgs) predicates.FitPredicate {
+				return predicates.NewExtendedResourcePriorityFloorPredicate(
+					policy.Argument.ExtendedResourcePriorityFloor.ResourceName,
+					policy.Argument.ExtendedResourcePriorityFloor.PriorityThreshold,
+				)
+			}
 		}
 	} else if predicateFactory, ok = fitPredicateMap[policy.Name]; ok {
 		// checking to see if a pre-defined predicate is requested
@@ -342,6 +357,13 @@ func RegisterCustomPriorityFunction(policy schedulerapi.PriorityPolicy) string {
 				},
 				Weight: policy.Weight,
 			}
+		} else if policy.Argument.ZoneReservedCapacityArguments != nil {
+			pcf = &PriorityConfigFactory{
+				MapReduceFunction: func(args PluginFactoryArgs) (priorities.PriorityMapFunction, priorities.PriorityReduceFunction) {
+					return priorities.NewZoneReservedCapacityPriority(*policy.Argument.ZoneReservedCapacityArguments), nil
+				},
+				Weight: policy.Weight,
+			}
 		}
 	} else if existingPcf, ok := priorityFunctionMap[policy.Name]; ok {
 		klog.V(2).Infof("Priority type %s already registered, reusing.", policy.Name)
@@ -512,6 +534,12 @@ func validatePredicateOrDie(predicate schedulerapi.PredicatePolicy) {
 		if predicate.Argument.LabelsPresence != nil {
 			numArgs++
 		}
+		if predicate.Argument.NodeOvercommit != nil {
+			numArgs++
+		}
+		if predicate.Argument.ExtendedResourcePriorityFloor != nil {
+			numArgs++
+		}
 		if numArgs != 1 {
 			klog.Fatalf("Exactly 1 predicate argument is required, numArgs: %v, Predicate: %s", numArgs, predicate.Name)
 		}
@@ -530,6 +558,9 @@ func validatePriorityOrDie(priority schedulerapi.PriorityPolicy) {
 		if priority.Argument.RequestedToCapacityRatioArguments != nil {
 			numArgs++
 		}
+		if priority.Argument.ZoneReservedCapacityArguments != nil {
+			numArgs++
+		}
 		if numArgs != 1 {
 			klog.Fatalf("Exactly 1 priority argument is required, numArgs: %v, Priority: %s", numArgs, priority.Name)
 		}
@@ -30,9 +30,10 @@ import (
 type framework struct {
 	registry         Registry
 	nodeInfoSnapshot *cache.NodeInfoSnapshot
-	plugins          map[string]Plugin // a map of initialized plugins. Plugin name:plugin instance.
-	reservePlugins   []ReservePlugin
-	prebindPlugins   []PrebindPlugin
+	plugins                 map[string]Plugin // a map of initialized plugins. Plugin name:plugin instance.
+	reservePlugins          []ReservePlugin
+	prebindPlugins          []PrebindPlugin
+	preemptionFilterPlugins []PreemptionFilterPlugin
 }
 
 var _ = Framework(&framework{})
@@ -64,6 +65,9 @@ func NewFramework(r Registry, _ *runtime.Unknown) (Framework, error) {
 		if pp, ok := p.(PrebindPlugin); ok {
 			f.prebindPlugins = append(f.prebindPlugins, pp)
 		}
+		if fp, ok := p.(PreemptionFilterPlugin); ok {
+			f.preemptionFilterPlugins = append(f.preemptionFilterPlugins, fp)
+		}
 	}
 	return f, nil
 }
@@ -105,6 +109,20 @@ func (f *framework) RunReservePlugins(
 	return nil
 }
 
+// RunPreemptionFilterPlugins runs the set of configured preemption filter plugins against a
+// single victim candidate, stopping at the first plugin that objects.
+func (f *framework) RunPreemptionFilterPlugins(
+	pc *PluginContext, pod *v1.Pod, victim *v1.Pod, nodeName string) *Status {
+	for _, pl := range f.preemptionFilterPlugins {
+		status := pl.FilterPreemption(pc, pod, victim, nodeName)
+		if !status.IsSuccess() {
+			klog.V(5).Infof("victim %v/%v vetoed by preemption filter plugin %v: %v", victim.Namespace, victim.Name, pl.Name(), status.Message())
+			return status
+		}
+	}
+	return nil
+}
+
 // NodeInfoSnapshot returns the latest NodeInfo snapshot. The snapshot
 // is taken at the beginning of a scheduling cycle and remains unchanged until a
 // pod finishes "Reserve". There is no guarantee that the information remains
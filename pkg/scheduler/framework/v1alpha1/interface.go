@@ -113,6 +113,19 @@ type PrebindPlugin interface {
 	Prebind(pc *PluginContext, p *v1.Pod, nodeName string) *Status
 }
 
+// PreemptionFilterPlugin is an interface for plugins that get a chance to veto individual victim
+// candidates while the default preemption algorithm is choosing which pods to evict, e.g. to
+// protect a pod holding in-flight local state that a generic priority/PDB-based selection has no
+// visibility into. Unlike the other extension points, a non-success Status here does not fail the
+// scheduling attempt; it only removes victim from consideration on this node, so preemption keeps
+// looking for a different candidate.
+type PreemptionFilterPlugin interface {
+	Plugin
+	// FilterPreemption returns Success if the plugin does not object to victim being preempted to
+	// make room for pod on nodeName, or Unschedulable to veto that specific candidate.
+	FilterPreemption(pc *PluginContext, pod *v1.Pod, victim *v1.Pod, nodeName string) *Status
+}
+
 // Framework manages the set of plugins in use by the scheduling framework.
 // Configured plugins are called at specified points in a scheduling context.
 type Framework interface {
@@ -128,6 +141,11 @@ type Framework interface {
 	// plugins returns an error, it does not continue running the remaining ones and
 	// returns the error. In such case, pod will not be scheduled.
 	RunReservePlugins(pc *PluginContext, pod *v1.Pod, nodeName string) *Status
+
+	// RunPreemptionFilterPlugins runs the set of configured preemption filter plugins against a
+	// single victim candidate. It returns the first non-success Status returned by a plugin, or a
+	// success Status if none object.
+	RunPreemptionFilterPlugins(pc *PluginContext, pod *v1.Pod, victim *v1.Pod, nodeName string) *Status
 }
 
 // FrameworkHandle provides data and some tools that plugins can use. It is
@@ -0,0 +1,71 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"sync"
+	"time"
+
+	ktypes "k8s.io/apimachinery/pkg/types"
+)
+
+// preemptionOriginWindow is how long a preemption is remembered by
+// preemptionOriginTracker. Replacement pods created after this window has
+// passed are treated as unrelated to the preemption.
+const preemptionOriginWindow = 2 * time.Minute
+
+// preemptionOrigin records where a preempted pod was evicted from.
+type preemptionOrigin struct {
+	node string
+	at   time.Time
+}
+
+// preemptionOriginTracker remembers, per controller UID, the node a pod
+// belonging to that controller was most recently preempted from. It lets the
+// scheduling queue recognize a just-created replacement for a preempted pod
+// (same owning controller) so that pod can be given a shorter backoff and
+// steered away from re-nominating the node it was just evicted from, instead
+// of immediately retrying the node that could not fit it a moment ago.
+type preemptionOriginTracker struct {
+	lock    sync.RWMutex
+	origins map[ktypes.UID]preemptionOrigin
+}
+
+func newPreemptionOriginTracker() *preemptionOriginTracker {
+	return &preemptionOriginTracker{
+		origins: make(map[ktypes.UID]preemptionOrigin),
+	}
+}
+
+// record notes that a pod owned by controllerUID was preempted from node.
+func (t *preemptionOriginTracker) record(controllerUID ktypes.UID, node string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.origins[controllerUID] = preemptionOrigin{node: node, at: time.Now()}
+}
+
+// recentOrigin returns the node controllerUID was last preempted from, if
+// that happened within preemptionOriginWindow.
+func (t *preemptionOriginTracker) recentOrigin(controllerUID ktypes.UID) (string, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	origin, ok := t.origins[controllerUID]
+	if !ok || time.Since(origin.at) > preemptionOriginWindow {
+		return "", false
+	}
+	return origin.node, true
+}
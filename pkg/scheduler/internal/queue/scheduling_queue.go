@@ -26,6 +26,7 @@ package queue
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"sync"
 	"time"
 
@@ -73,7 +74,16 @@ type SchedulingQueue interface {
 	AssignedPodAdded(pod *v1.Pod)
 	AssignedPodUpdated(pod *v1.Pod)
 	NominatedPodsForNode(nodeName string) []*v1.Pod
+	// NominatedPodsCountByWorkload returns, for each value of labelKey found among currently
+	// nominated pods, how many nominated pods carry it. Pods without labelKey set are counted
+	// under the empty-string workload. This backs core.PreemptionFairnessConfig's round-robin
+	// gate between workloads competing for freed preemption capacity.
+	NominatedPodsCountByWorkload(labelKey string) map[string]int32
 	PendingPods() []*v1.Pod
+	// PendingPodsSummary aggregates the pods returned by PendingPods by priority and by
+	// the reason they last failed to schedule, for use by callers that want the queue's
+	// composition (e.g. for introspection) rather than the raw pod list.
+	PendingPodsSummary() *PendingPodsSummary
 	// Close closes the SchedulingQueue so that the goroutine which is
 	// waiting to pop items can exit gracefully.
 	Close()
@@ -84,6 +94,24 @@ type SchedulingQueue interface {
 	DeleteNominatedPodIfExists(pod *v1.Pod)
 	// NumUnschedulablePods returns the number of unschedulable pods exist in the SchedulingQueue.
 	NumUnschedulablePods() int
+	// RecordPreemption notes that victim was preempted from nodeName, so that a
+	// replacement pod from the same controller can be given reduced backoff and
+	// steered away from immediately re-nominating that node.
+	RecordPreemption(victim *v1.Pod, nodeName string)
+	// SetTenantFairnessConfig enables (or, given nil, disables) weighted fair ordering of
+	// equal-priority pods across tenants in the activeQ. See TenantFairnessConfig.
+	SetTenantFairnessConfig(config *TenantFairnessConfig)
+	// SetPriorityAgingConfig enables (or, given nil, disables) gradual aging of pods' effective
+	// priority in the activeQ. See PriorityAgingConfig.
+	SetPriorityAgingConfig(config *PriorityAgingConfig)
+	// SetDRFFairnessConfig enables (or, given nil, disables) dominant-resource-fairness ordering
+	// of equal-priority pods across tenants in the activeQ. See DRFFairnessConfig. Setting a
+	// non-nil config also disables TenantFairnessConfig, since the two are mutually exclusive
+	// ways of tie-breaking within a priority band.
+	SetDRFFairnessConfig(config *DRFFairnessConfig)
+	// SetNamespaceMinShareConfig enables (or, given nil, disables) a minimum guaranteed share of
+	// scheduling cycles per namespace in the activeQ. See NamespaceMinShareConfig.
+	SetNamespaceMinShareConfig(config *NamespaceMinShareConfig)
 }
 
 // NewSchedulingQueue initializes a priority queue as a new scheduling queue.
@@ -123,6 +151,11 @@ type PriorityQueue struct {
 	// nominatedPods is a structures that stores pods which are nominated to run
 	// on nodes.
 	nominatedPods *nominatedPodMap
+	// preemptionOrigins tracks the node a controller's pod was most recently
+	// preempted from, so a freshly created replacement pod for that controller
+	// can be recognized and given reduced backoff and steered away from
+	// re-nominating that node.
+	preemptionOrigins *preemptionOriginTracker
 	// schedulingCycle represents sequence number of scheduling cycle and is incremented
 	// when a pod is popped.
 	schedulingCycle int64
@@ -132,11 +165,200 @@ type PriorityQueue struct {
 	// when we received move request.
 	moveRequestCycle int64
 
+	// tenantFairness, if non-nil, enables weighted fair ordering of equal-priority pods across
+	// tenants in activeQ. See TenantFairnessConfig.
+	tenantFairness *TenantFairnessConfig
+	// tenantSequence counts, per tenant, how many pods that tenant has had enqueued into
+	// activeQ so far. It only ever grows, so it is used purely to compute each pod's
+	// fairnessRank at enqueue time, not as a live measure of queue occupancy.
+	tenantSequence map[string]int64
+
+	// drfFairness, if non-nil, enables dominant-resource-fairness ordering of equal-priority
+	// pods across tenants in activeQ. See DRFFairnessConfig.
+	drfFairness *DRFFairnessConfig
+	// tenantDominantShare accumulates, per tenant, the running total of weighted dominant
+	// resource share that tenant's pods have consumed of ClusterCapacity as they were enqueued.
+	// It only ever grows, so it is used purely to compute each pod's drfRank at enqueue time.
+	tenantDominantShare map[string]float64
+
+	// priorityAging, if non-nil, gradually boosts a pod's effective priority in activeQ the
+	// longer it waits. See PriorityAgingConfig.
+	priorityAging *PriorityAgingConfig
+
+	// namespaceMinShare, if non-nil, guarantees each named namespace a minimum share of
+	// scheduling cycles in activeQ regardless of other namespaces' backlog. See
+	// NamespaceMinShareConfig.
+	namespaceMinShare *NamespaceMinShareConfig
+	// cyclesByNamespace counts, per namespace, how many scheduling cycles have produced a pod
+	// from that namespace since namespaceMinShare was last set. It is compared against
+	// minShareCyclesElapsed to compute each guaranteed namespace's current shortfall.
+	cyclesByNamespace map[string]int64
+	// minShareCyclesElapsed counts scheduling cycles since namespaceMinShare was last set. It is
+	// tracked separately from schedulingCycle so that enabling the guarantee does not credit a
+	// namespace with cycles that were popped before the guarantee existed.
+	minShareCyclesElapsed int64
+
 	// closed indicates that the queue is closed.
 	// It is mainly used to let Pop() exit its control loop while waiting for an item.
 	closed bool
 }
 
+// TenantWeight sets a weighted-fair-queuing weight for pods whose value for
+// TenantFairnessConfig.LabelKey is LabelValue.
+type TenantWeight struct {
+	LabelValue string
+	Weight     int32
+}
+
+// TenantFairnessConfig enables weighted fair ordering of equal-priority pods across tenants, as
+// identified by the value of each pod's LabelKey label. Within a priority band, pods are
+// otherwise served FIFO, so a single tenant enqueuing a large burst of equal-priority pods can
+// monopolize scheduling cycles ahead of everyone else at that priority; this spreads scheduling
+// cycles across tenants roughly in proportion to their configured Weight instead. Pods without
+// LabelKey set, or with a value not listed in Weights, are treated as their own tenant (keyed by
+// the empty string or that literal value) with the default Weight of 1.
+type TenantFairnessConfig struct {
+	LabelKey string
+	Weights  []TenantWeight
+}
+
+// weightFor returns the configured weight for tenant, defaulting to 1 if tenant has no entry.
+func (c *TenantFairnessConfig) weightFor(tenant string) int32 {
+	for _, w := range c.Weights {
+		if w.LabelValue == tenant {
+			if w.Weight > 0 {
+				return w.Weight
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// tenantFor returns the tenant key for pod under this configuration.
+func (c *TenantFairnessConfig) tenantFor(pod *v1.Pod) string {
+	return pod.Labels[c.LabelKey]
+}
+
+// weightsByTenant returns every tenant this configuration names, and its weight, for publishing
+// via metrics.ObserveTenantFairnessWeights.
+func (c *TenantFairnessConfig) weightsByTenant() map[string]int32 {
+	weights := make(map[string]int32, len(c.Weights))
+	for _, w := range c.Weights {
+		weights[w.LabelValue] = c.weightFor(w.LabelValue)
+	}
+	return weights
+}
+
+// DRFFairnessConfig enables an alternative to TenantFairnessConfig that ranks equal-priority
+// pending pods within activeQ by dominant resource fairness (DRF) rather than plain weighted
+// round robin: a tenant whose already-enqueued pods have claimed a larger weighted share of
+// ClusterCapacity's dominant resource sorts behind tenants that have claimed less, so a single
+// tenant submitting a wave of large or high-priority pods cannot monopolize the cluster the way
+// strict priority ordering alone would allow. Well suited to research/batch clusters that need
+// scheduling order to balance fairness against priority instead of following priority strictly.
+// TenantFairnessConfig and DRFFairnessConfig are mutually exclusive; setting one clears the
+// other.
+type DRFFairnessConfig struct {
+	// TenantLabelKey identifies the label whose value groups pods into a tenant for DRF
+	// accounting, in the same sense as TenantFairnessConfig.LabelKey.
+	TenantLabelKey string
+	// ClusterCapacity is the cluster's total allocatable capacity, used to convert a pod's
+	// aggregate container resource requests into a dominant share fraction (the largest of,
+	// e.g., requested-cpu/capacity-cpu and requested-memory/capacity-memory). A resource absent
+	// from, or zero in, ClusterCapacity is excluded from the dominant-share calculation.
+	ClusterCapacity v1.ResourceList
+	// PriorityClassWeights scales the dominant share charged against a pod's tenant by
+	// 1/weight, so operators can let higher-priority pods count for less against their
+	// tenant's running total without disabling DRF fairness for that PriorityClass entirely.
+	// A PriorityClassName absent from this map, or mapped to <= 0, uses a weight of 1.
+	PriorityClassWeights map[string]float64
+}
+
+// tenantFor returns the tenant key for pod under this configuration.
+func (c *DRFFairnessConfig) tenantFor(pod *v1.Pod) string {
+	return pod.Labels[c.TenantLabelKey]
+}
+
+// weightFor returns the configured weight for pod's PriorityClassName, defaulting to 1.
+func (c *DRFFairnessConfig) weightFor(pod *v1.Pod) float64 {
+	if w, ok := c.PriorityClassWeights[pod.Spec.PriorityClassName]; ok && w > 0 {
+		return w
+	}
+	return 1
+}
+
+// dominantShare returns the largest fraction of ClusterCapacity that pod's aggregate container
+// resource requests would consume of any single resource, or 0 if ClusterCapacity has no usable
+// entries.
+func (c *DRFFairnessConfig) dominantShare(pod *v1.Pod) float64 {
+	var share float64
+	for name, capacity := range c.ClusterCapacity {
+		if capacity.MilliValue() <= 0 {
+			continue
+		}
+		var requested int64
+		for _, container := range pod.Spec.Containers {
+			if q, ok := container.Resources.Requests[name]; ok {
+				requested += q.MilliValue()
+			}
+		}
+		if fraction := float64(requested) / float64(capacity.MilliValue()); fraction > share {
+			share = fraction
+		}
+	}
+	return share
+}
+
+// PriorityAgingConfig enables gradual aging of a pod's effective priority in activeQ, the
+// longer it has been waiting to be scheduled, so a low-priority pod cannot starve indefinitely
+// behind a steady stream of fresher, higher-priority arrivals on a busy cluster. Aging only
+// changes how pods of differing priority compare; pods within the same PriorityClass still
+// tie-break the same way as before (fairnessRank if TenantFairnessConfig is set, else FIFO),
+// since aging boosts them identically.
+type PriorityAgingConfig struct {
+	// AgingWindow is how long a pod must wait in activeQ for its effective priority to increase
+	// by one aging step.
+	AgingWindow time.Duration
+	// MaxBoost caps the total number of aging steps a pod's effective priority can accumulate,
+	// so a sufficiently stale pod can never outrank a system-critical priority no matter how
+	// long it waits. A value <= 0 leaves the boost uncapped.
+	MaxBoost int32
+}
+
+// boost returns the number of aging steps a pod that has been waiting for waited should have
+// its effective priority increased by, capped at MaxBoost.
+func (c *PriorityAgingConfig) boost(waited time.Duration) int32 {
+	if c.AgingWindow <= 0 || waited <= 0 {
+		return 0
+	}
+	steps := int32(waited / c.AgingWindow)
+	if c.MaxBoost > 0 && steps > c.MaxBoost {
+		return c.MaxBoost
+	}
+	return steps
+}
+
+// NamespaceMinShareConfig guarantees each namespace named in MinShare a minimum share of
+// scheduling cycles, expressed as a fraction of every cycle Pop has produced since the guarantee
+// was enabled, regardless of how large another namespace's pending backlog is. A namespace that
+// has fallen behind its guaranteed share is ranked ahead of other equal-priority pods until it
+// catches up, and the current shortfall for every guaranteed namespace is published via
+// metrics.ObserveNamespaceMinShareDeficits so operators can see which namespaces, if any, are
+// actively relying on the guarantee to make progress. Namespaces with no entry in MinShare
+// compete for scheduling cycles exactly as they did before.
+type NamespaceMinShareConfig struct {
+	// MinShare maps a namespace name to the fraction (0, 1] of scheduling cycles it is
+	// guaranteed. A namespace absent from MinShare, or mapped to <= 0, is unaffected.
+	MinShare map[string]float64
+}
+
+// shareFor returns the guaranteed fraction of scheduling cycles for namespace, or 0 if it has no
+// entry in MinShare.
+func (c *NamespaceMinShareConfig) shareFor(namespace string) float64 {
+	return c.MinShare[namespace]
+}
+
 // Making sure that PriorityQueue implements SchedulingQueue.
 var _ = SchedulingQueue(&PriorityQueue{})
 
@@ -145,6 +367,12 @@ type podInfo struct {
 	pod *v1.Pod
 	// The time pod added to the scheduling queue.
 	timestamp time.Time
+	// fairnessRank orders pods within the same priority when tenantFairness is configured; it
+	// is meaningless (and left at its zero value) otherwise. Lower ranks are scheduled first.
+	fairnessRank float64
+	// drfRank orders pods within the same priority when drfFairness is configured; it is
+	// meaningless (and left at its zero value) otherwise. Lower ranks are scheduled first.
+	drfRank float64
 }
 
 // newPodInfoNoTimestamp builds a podInfo object without timestamp.
@@ -154,15 +382,56 @@ func newPodInfoNoTimestamp(pod *v1.Pod) *podInfo {
 	}
 }
 
-// activeQComp is the function used by the activeQ heap algorithm to sort pods.
-// It sorts pods based on their priority. When priorities are equal, it uses
-// podInfo.timestamp.
-func activeQComp(podInfo1, podInfo2 interface{}) bool {
+// activeQComp is the function used by the activeQ heap algorithm to sort pods. It sorts pods
+// based on their priority, boosted by however many aging steps they have accumulated if
+// priorityAging is configured. When (boosted) priorities are equal, a pod from a namespace
+// currently short of its namespaceMinShare guarantee, if configured, sorts ahead of one that
+// isn't; otherwise it uses each pod's fairnessRank if tenantFairness is configured, or its
+// drfRank if drfFairness is configured instead, falling back to podInfo.timestamp (plain FIFO) if
+// none apply.
+func (p *PriorityQueue) activeQComp(podInfo1, podInfo2 interface{}) bool {
 	pInfo1 := podInfo1.(*podInfo)
 	pInfo2 := podInfo2.(*podInfo)
 	prio1 := util.GetPodPriority(pInfo1.pod)
 	prio2 := util.GetPodPriority(pInfo2.pod)
-	return (prio1 > prio2) || (prio1 == prio2 && pInfo1.timestamp.Before(pInfo2.timestamp))
+	if p.priorityAging != nil {
+		now := p.clock.Now()
+		prio1 += p.priorityAging.boost(now.Sub(pInfo1.timestamp))
+		prio2 += p.priorityAging.boost(now.Sub(pInfo2.timestamp))
+	}
+	if prio1 != prio2 {
+		return prio1 > prio2
+	}
+	if p.namespaceMinShare != nil {
+		shortfall1 := p.namespaceShortfall(pInfo1.pod.Namespace)
+		shortfall2 := p.namespaceShortfall(pInfo2.pod.Namespace)
+		if shortfall1 != shortfall2 {
+			return shortfall1 > shortfall2
+		}
+	}
+	if p.tenantFairness != nil && pInfo1.fairnessRank != pInfo2.fairnessRank {
+		return pInfo1.fairnessRank < pInfo2.fairnessRank
+	}
+	if p.drfFairness != nil && pInfo1.drfRank != pInfo2.drfRank {
+		return pInfo1.drfRank < pInfo2.drfRank
+	}
+	return pInfo1.timestamp.Before(pInfo2.timestamp)
+}
+
+// namespaceShortfall returns how many scheduling cycles namespace is currently short of its
+// namespaceMinShare guarantee, given every cycle Pop has produced since the guarantee was
+// enabled. A namespace with no configured share, or that is already at or above its guaranteed
+// share, returns 0.
+func (p *PriorityQueue) namespaceShortfall(namespace string) float64 {
+	share := p.namespaceMinShare.shareFor(namespace)
+	if share <= 0 {
+		return 0
+	}
+	shortfall := share*float64(p.minShareCyclesElapsed) - float64(p.cyclesByNamespace[namespace])
+	if shortfall < 0 {
+		return 0
+	}
+	return shortfall
 }
 
 // NewPriorityQueue creates a PriorityQueue object.
@@ -173,15 +442,19 @@ func NewPriorityQueue(stop <-chan struct{}) *PriorityQueue {
 // NewPriorityQueueWithClock creates a PriorityQueue which uses the passed clock for time.
 func NewPriorityQueueWithClock(stop <-chan struct{}, clock util.Clock) *PriorityQueue {
 	pq := &PriorityQueue{
-		clock:            clock,
-		stop:             stop,
-		podBackoff:       NewPodBackoffMap(1*time.Second, 10*time.Second),
-		activeQ:          util.NewHeapWithRecorder(podInfoKeyFunc, activeQComp, metrics.NewActivePodsRecorder()),
-		unschedulableQ:   newUnschedulablePodsMap(metrics.NewUnschedulablePodsRecorder()),
-		nominatedPods:    newNominatedPodMap(),
-		moveRequestCycle: -1,
+		clock:               clock,
+		stop:                stop,
+		podBackoff:          NewPodBackoffMap(1*time.Second, 10*time.Second),
+		unschedulableQ:      newUnschedulablePodsMap(metrics.NewUnschedulablePodsRecorder()),
+		nominatedPods:       newNominatedPodMap(),
+		moveRequestCycle:    -1,
+		preemptionOrigins:   newPreemptionOriginTracker(),
+		tenantSequence:      make(map[string]int64),
+		tenantDominantShare: make(map[string]float64),
+		cyclesByNamespace:   make(map[string]int64),
 	}
 	pq.cond.L = &pq.lock
+	pq.activeQ = util.NewHeapWithRecorder(podInfoKeyFunc, pq.activeQComp, metrics.NewActivePodsRecorder())
 	pq.podBackoffQ = util.NewHeapWithRecorder(podInfoKeyFunc, pq.podsCompareBackoffCompleted, metrics.NewBackoffPodsRecorder())
 
 	pq.run()
@@ -213,7 +486,9 @@ func (p *PriorityQueue) Add(pod *v1.Pod) error {
 	if err := p.podBackoffQ.Delete(pInfo); err == nil {
 		klog.Errorf("Error: pod %v/%v is already in the podBackoff queue.", pod.Namespace, pod.Name)
 	}
-	p.nominatedPods.add(pod, "")
+	if !p.suppressNominationOfPreemptionOrigin(pod) {
+		p.nominatedPods.add(pod, "")
+	}
 	p.cond.Broadcast()
 
 	return nil
@@ -239,7 +514,9 @@ func (p *PriorityQueue) AddIfNotPresent(pod *v1.Pod) error {
 	if err != nil {
 		klog.Errorf("Error adding pod %v/%v to the scheduling queue: %v", pod.Namespace, pod.Name, err)
 	} else {
-		p.nominatedPods.add(pod, "")
+		if !p.suppressNominationOfPreemptionOrigin(pod) {
+			p.nominatedPods.add(pod, "")
+		}
 		p.cond.Broadcast()
 	}
 	return err
@@ -274,12 +551,57 @@ func (p *PriorityQueue) backoffPod(pod *v1.Pod) {
 	p.podBackoff.CleanupPodsCompletesBackingoff()
 
 	podID := nsNameForPod(pod)
+	// A pod that replaces one we just preempted for the same controller starts
+	// its own backoff history: clear any stale entry under this name so it
+	// backs off from the initial duration instead of inheriting a compounded
+	// one, since it is not to blame for its predecessor's earlier failures.
+	if p.isRecentPreemptionReplacement(pod) {
+		p.podBackoff.ClearPodBackoff(podID)
+	}
 	boTime, found := p.podBackoff.GetBackoffTime(podID)
 	if !found || boTime.Before(p.clock.Now()) {
 		p.podBackoff.BackoffPod(podID)
 	}
 }
 
+// isRecentPreemptionReplacement returns true if pod is owned by a controller
+// whose pod was preempted within preemptionOriginWindow.
+func (p *PriorityQueue) isRecentPreemptionReplacement(pod *v1.Pod) bool {
+	controllerRef := metav1.GetControllerOf(pod)
+	if controllerRef == nil {
+		return false
+	}
+	_, ok := p.preemptionOrigins.recentOrigin(controllerRef.UID)
+	return ok
+}
+
+// suppressNominationOfPreemptionOrigin returns true if pod should not be
+// re-nominated to the node it (or its predecessor) was just preempted from,
+// so the scheduler considers other nodes first instead of retrying the one
+// that could not fit it a moment ago.
+func (p *PriorityQueue) suppressNominationOfPreemptionOrigin(pod *v1.Pod) bool {
+	nnn := NominatedNodeName(pod)
+	if len(nnn) == 0 {
+		return false
+	}
+	controllerRef := metav1.GetControllerOf(pod)
+	if controllerRef == nil {
+		return false
+	}
+	origin, ok := p.preemptionOrigins.recentOrigin(controllerRef.UID)
+	return ok && origin == nnn
+}
+
+// RecordPreemption notes that victim, owned by the same controller as any
+// future replacement pod, was preempted from nodeName.
+func (p *PriorityQueue) RecordPreemption(victim *v1.Pod, nodeName string) {
+	controllerRef := metav1.GetControllerOf(victim)
+	if controllerRef == nil {
+		return
+	}
+	p.preemptionOrigins.record(controllerRef.UID, nodeName)
+}
+
 // SchedulingCycle returns current scheduling cycle.
 func (p *PriorityQueue) SchedulingCycle() int64 {
 	p.lock.RLock()
@@ -319,7 +641,9 @@ func (p *PriorityQueue) AddUnschedulableIfNotPresent(pod *v1.Pod, podSchedulingC
 		p.unschedulableQ.addOrUpdate(pInfo)
 	}
 
-	p.nominatedPods.add(pod, "")
+	if !p.suppressNominationOfPreemptionOrigin(pod) {
+		p.nominatedPods.add(pod, "")
+	}
 	return nil
 
 }
@@ -397,6 +721,15 @@ func (p *PriorityQueue) Pop() (*v1.Pod, error) {
 		return nil, err
 	}
 	pInfo := obj.(*podInfo)
+	if p.namespaceMinShare != nil {
+		namespace := pInfo.pod.Namespace
+		if p.namespaceShortfall(namespace) > 0 {
+			metrics.NamespaceMinShareStarvedScheduled.WithLabelValues(namespace).Inc()
+		}
+		p.minShareCyclesElapsed++
+		p.cyclesByNamespace[namespace]++
+		p.observeNamespaceMinShareDeficits()
+	}
 	p.schedulingCycle++
 	return pInfo.pod, err
 }
@@ -584,6 +917,14 @@ func (p *PriorityQueue) NominatedPodsForNode(nodeName string) []*v1.Pod {
 	return p.nominatedPods.podsForNode(nodeName)
 }
 
+// NominatedPodsCountByWorkload returns, for each value of labelKey found among currently
+// nominated pods, how many nominated pods carry it.
+func (p *PriorityQueue) NominatedPodsCountByWorkload(labelKey string) map[string]int32 {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.nominatedPods.countByLabel(labelKey)
+}
+
 // PendingPods returns all the pending pods in the queue. This function is
 // used for debugging purposes in the scheduler cache dumper and comparer.
 func (p *PriorityQueue) PendingPods() []*v1.Pod {
@@ -602,6 +943,120 @@ func (p *PriorityQueue) PendingPods() []*v1.Pod {
 	return result
 }
 
+// unknownUnschedulableReason is used for a pending pod that has not yet had a scheduling
+// attempt recorded against it, so it carries no PodScheduled condition to report a reason from.
+const unknownUnschedulableReason = "Unknown"
+
+// noPriorityClassName is used for a pending pod that was not admitted through a PriorityClass,
+// so it has no PriorityClassName to group it by.
+const noPriorityClassName = "<none>"
+
+// topUnschedulableReasons is the number of distinct unschedulable reasons PendingPodsSummary
+// reports; the rest are folded into the per-priority-band counts but dropped from the ranking.
+const topUnschedulableReasons = 10
+
+// PriorityBandCount is the number of pods pending at a given priority.
+type PriorityBandCount struct {
+	PriorityClassName string
+	Priority          int32
+	Count             int
+}
+
+// UnschedulableReasonCount is the number of pods last found unschedulable for a given reason.
+// Reason is the Message of the pod's PodScheduled condition, so it is free text rather than an
+// enum; pods that have never had a scheduling attempt recorded are counted under
+// unknownUnschedulableReason instead.
+type UnschedulableReasonCount struct {
+	Reason string
+	Count  int
+}
+
+// PendingPodsSummary reports the composition of the scheduling queue: how many pods are
+// pending in total, broken down by priority, and the most common reasons pods are currently
+// failing to schedule. It exists so operators diagnosing a capacity incident can query the
+// queue's shape directly instead of grepping scheduler logs.
+type PendingPodsSummary struct {
+	Total                   int
+	ByPriority              []PriorityBandCount
+	TopUnschedulableReasons []UnschedulableReasonCount
+}
+
+// unschedulableReason returns the best-effort reason pod last failed to schedule, derived from
+// its PodScheduled condition. Because that condition is updated asynchronously from the queue by
+// the scheduler's error handler, it may lag behind the pod's current position in the queue, but
+// it is the only record of "why" kept on the pod.
+func unschedulableReason(pod *v1.Pod) string {
+	for i := range pod.Status.Conditions {
+		condition := &pod.Status.Conditions[i]
+		if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionFalse {
+			if condition.Message != "" {
+				return condition.Message
+			}
+			break
+		}
+	}
+	return unknownUnschedulableReason
+}
+
+// PendingPodsSummary aggregates PendingPods by priority and by unschedulable reason. See
+// PendingPodsSummary (the type) for what is reported.
+func (p *PriorityQueue) PendingPodsSummary() *PendingPodsSummary {
+	pods := p.PendingPods()
+
+	type priorityBand struct {
+		className string
+		priority  int32
+		count     int
+	}
+	byPriority := map[int32]*priorityBand{}
+	byReason := map[string]int{}
+
+	for _, pod := range pods {
+		priority := util.GetPodPriority(pod)
+		band, ok := byPriority[priority]
+		if !ok {
+			className := pod.Spec.PriorityClassName
+			if className == "" {
+				className = noPriorityClassName
+			}
+			band = &priorityBand{className: className, priority: priority}
+			byPriority[priority] = band
+		}
+		band.count++
+		byReason[unschedulableReason(pod)]++
+	}
+
+	summary := &PendingPodsSummary{Total: len(pods)}
+	for _, band := range byPriority {
+		summary.ByPriority = append(summary.ByPriority, PriorityBandCount{
+			PriorityClassName: band.className,
+			Priority:          band.priority,
+			Count:             band.count,
+		})
+	}
+	sort.Slice(summary.ByPriority, func(i, j int) bool {
+		return summary.ByPriority[i].Priority > summary.ByPriority[j].Priority
+	})
+
+	for reason, count := range byReason {
+		summary.TopUnschedulableReasons = append(summary.TopUnschedulableReasons, UnschedulableReasonCount{
+			Reason: reason,
+			Count:  count,
+		})
+	}
+	sort.Slice(summary.TopUnschedulableReasons, func(i, j int) bool {
+		if summary.TopUnschedulableReasons[i].Count != summary.TopUnschedulableReasons[j].Count {
+			return summary.TopUnschedulableReasons[i].Count > summary.TopUnschedulableReasons[j].Count
+		}
+		return summary.TopUnschedulableReasons[i].Reason < summary.TopUnschedulableReasons[j].Reason
+	})
+	if len(summary.TopUnschedulableReasons) > topUnschedulableReasons {
+		summary.TopUnschedulableReasons = summary.TopUnschedulableReasons[:topUnschedulableReasons]
+	}
+
+	return summary
+}
+
 // Close closes the priority queue.
 func (p *PriorityQueue) Close() {
 	p.lock.Lock()
@@ -642,20 +1097,93 @@ func (p *PriorityQueue) NumUnschedulablePods() int {
 	return len(p.unschedulableQ.podInfoMap)
 }
 
-// newPodInfo builds a podInfo object.
+// newPodInfo builds a podInfo object. Must be called with p.lock held, since it may consult and
+// update p.tenantSequence or p.tenantDominantShare.
 func (p *PriorityQueue) newPodInfo(pod *v1.Pod) *podInfo {
-	if p.clock == nil {
-		return &podInfo{
-			pod: pod,
-		}
+	pInfo := &podInfo{pod: pod}
+	if p.clock != nil {
+		pInfo.timestamp = p.clock.Now()
 	}
+	if p.tenantFairness != nil {
+		tenant := p.tenantFairness.tenantFor(pod)
+		p.tenantSequence[tenant]++
+		pInfo.fairnessRank = float64(p.tenantSequence[tenant]) / float64(p.tenantFairness.weightFor(tenant))
+	}
+	if p.drfFairness != nil {
+		tenant := p.drfFairness.tenantFor(pod)
+		p.tenantDominantShare[tenant] += p.drfFairness.dominantShare(pod) / p.drfFairness.weightFor(pod)
+		pInfo.drfRank = p.tenantDominantShare[tenant]
+	}
+	return pInfo
+}
 
-	return &podInfo{
-		pod:       pod,
-		timestamp: p.clock.Now(),
+// SetTenantFairnessConfig enables (config non-nil) or disables (config nil) weighted fair
+// ordering of equal-priority pods across tenants in activeQ, and republishes
+// metrics.TenantFairnessWeight to match. Changing the configuration only affects pods added to
+// activeQ afterward; it does not retroactively re-rank pods already queued. Setting a non-nil
+// config also disables DRFFairnessConfig, since the two are mutually exclusive.
+func (p *PriorityQueue) SetTenantFairnessConfig(config *TenantFairnessConfig) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.tenantFairness = config
+	p.tenantSequence = make(map[string]int64)
+	if config != nil {
+		p.drfFairness = nil
+		metrics.ObserveTenantFairnessWeights(config.weightsByTenant())
+	}
+}
+
+// SetDRFFairnessConfig enables (config non-nil) or disables (config nil) dominant-resource-
+// fairness ordering of equal-priority pods across tenants in activeQ. Changing the configuration
+// only affects pods added to activeQ afterward; it does not retroactively re-rank pods already
+// queued. Setting a non-nil config also disables TenantFairnessConfig, since the two are
+// mutually exclusive.
+func (p *PriorityQueue) SetDRFFairnessConfig(config *DRFFairnessConfig) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.drfFairness = config
+	p.tenantDominantShare = make(map[string]float64)
+	if config != nil {
+		p.tenantFairness = nil
 	}
 }
 
+// SetPriorityAgingConfig enables (config non-nil) or disables (config nil) gradual aging of
+// pods' effective priority in activeQ. Changing the configuration re-sorts pods already in
+// activeQ against the new rules on their next comparison; it is not a one-time re-ranking.
+func (p *PriorityQueue) SetPriorityAgingConfig(config *PriorityAgingConfig) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.priorityAging = config
+}
+
+// SetNamespaceMinShareConfig enables (config non-nil) or disables (config nil) a minimum
+// guaranteed share of scheduling cycles per namespace in activeQ, and resets the cycle counts a
+// namespace's shortfall is measured against, so a namespace does not inherit a shortfall (or a
+// surplus) accrued under a different, or no, guarantee.
+func (p *PriorityQueue) SetNamespaceMinShareConfig(config *NamespaceMinShareConfig) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.namespaceMinShare = config
+	p.cyclesByNamespace = make(map[string]int64)
+	p.minShareCyclesElapsed = 0
+	if config != nil {
+		p.observeNamespaceMinShareDeficits()
+	} else {
+		metrics.ObserveNamespaceMinShareDeficits(nil)
+	}
+}
+
+// observeNamespaceMinShareDeficits republishes every namespace named in namespaceMinShare's
+// current shortfall against its guaranteed share. Must be called with p.lock held.
+func (p *PriorityQueue) observeNamespaceMinShareDeficits() {
+	deficits := make(map[string]float64, len(p.namespaceMinShare.MinShare))
+	for namespace := range p.namespaceMinShare.MinShare {
+		deficits[namespace] = p.namespaceShortfall(namespace)
+	}
+	metrics.ObserveNamespaceMinShareDeficits(deficits)
+}
+
 // UnschedulablePodsMap holds pods that cannot be scheduled. This data structure
 // is used to implement unschedulableQ.
 type UnschedulablePodsMap struct {
@@ -779,6 +1307,18 @@ func (npm *nominatedPodMap) podsForNode(nodeName string) []*v1.Pod {
 	return nil
 }
 
+// countByLabel returns, for each value of labelKey found among nominated pods, how many
+// nominated pods carry it. Pods without labelKey set are counted under the empty string.
+func (npm *nominatedPodMap) countByLabel(labelKey string) map[string]int32 {
+	counts := make(map[string]int32)
+	for _, pods := range npm.nominatedPods {
+		for _, pod := range pods {
+			counts[pod.Labels[labelKey]]++
+		}
+	}
+	return counts
+}
+
 func newNominatedPodMap() *nominatedPodMap {
 	return &nominatedPodMap{
 		nominatedPods:      make(map[string][]*v1.Pod),
@@ -25,6 +25,7 @@ import (
 
 	dto "github.com/prometheus/client_model/go"
 	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/clock"
@@ -463,6 +464,207 @@ func TestPriorityQueue_PendingPods(t *testing.T) {
 	}
 }
 
+func TestPriorityQueue_PendingPodsSummary(t *testing.T) {
+	q := NewPriorityQueue(nil)
+	q.Add(&medPriorityPod)
+	addOrUpdateUnschedulablePod(q, &unschedulablePod)
+	addOrUpdateUnschedulablePod(q, &highPriorityPod)
+
+	summary := q.PendingPodsSummary()
+	if summary.Total != 3 {
+		t.Errorf("expected 3 pending pods, got %d", summary.Total)
+	}
+
+	gotByPriority := map[int32]int{}
+	for _, band := range summary.ByPriority {
+		gotByPriority[band.Priority] = band.Count
+	}
+	wantByPriority := map[int32]int{highPriority: 1, mediumPriority: 1, lowPriority: 1}
+	if !reflect.DeepEqual(gotByPriority, wantByPriority) {
+		t.Errorf("expected per-priority counts %v, got %v", wantByPriority, gotByPriority)
+	}
+
+	// unschedulablePod's PodScheduled condition has no Message, so it should be folded into
+	// unknownUnschedulableReason along with the two pods that have no condition at all.
+	if len(summary.TopUnschedulableReasons) != 1 {
+		t.Fatalf("expected a single unschedulable reason bucket, got %v", summary.TopUnschedulableReasons)
+	}
+	if reason := summary.TopUnschedulableReasons[0]; reason.Reason != unknownUnschedulableReason || reason.Count != 3 {
+		t.Errorf("expected %q with count 3, got %+v", unknownUnschedulableReason, reason)
+	}
+}
+
+// TestPriorityQueue_TenantFairness tests that, once a TenantFairnessConfig is set, a
+// higher-weight tenant's equal-priority pod is preferred over a lower-weight tenant's, even if
+// the lower-weight tenant's pod was enqueued first (which plain FIFO ordering would otherwise
+// prefer).
+func TestPriorityQueue_TenantFairness(t *testing.T) {
+	q := NewPriorityQueue(nil)
+	q.SetTenantFairnessConfig(&TenantFairnessConfig{
+		LabelKey: "tenant",
+		Weights:  []TenantWeight{{LabelValue: "heavy", Weight: 2}},
+	})
+
+	lightPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "light", Namespace: "ns1", UID: "light", Labels: map[string]string{"tenant": "light"}},
+		Spec:       v1.PodSpec{Priority: &midPriority},
+	}
+	heavyPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "heavy", Namespace: "ns1", UID: "heavy", Labels: map[string]string{"tenant": "heavy"}},
+		Spec:       v1.PodSpec{Priority: &midPriority},
+	}
+
+	// lightPod is enqueued first, so plain FIFO would serve it first; weighted fairness should
+	// serve heavyPod first instead, since it has half the fairnessRank at the same enqueue count.
+	if err := q.Add(lightPod); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	if err := q.Add(heavyPod); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+
+	if p, err := q.Pop(); err != nil || p != heavyPod {
+		t.Errorf("expected heavyPod first, got %v (err: %v)", p, err)
+	}
+	if p, err := q.Pop(); err != nil || p != lightPod {
+		t.Errorf("expected lightPod second, got %v (err: %v)", p, err)
+	}
+}
+
+// TestPriorityQueue_DRFFairness tests that, once a DRFFairnessConfig is set, a tenant whose
+// equal-priority pod requests a smaller dominant share of ClusterCapacity is preferred over a
+// tenant whose pod requests a larger share, even if the larger request was enqueued first (which
+// plain FIFO ordering would otherwise prefer).
+func TestPriorityQueue_DRFFairness(t *testing.T) {
+	q := NewPriorityQueue(nil)
+	q.SetDRFFairnessConfig(&DRFFairnessConfig{
+		TenantLabelKey:  "tenant",
+		ClusterCapacity: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100")},
+	})
+
+	bigPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "big", Namespace: "ns1", UID: "big", Labels: map[string]string{"tenant": "big"}},
+		Spec: v1.PodSpec{
+			Priority:   &midPriority,
+			Containers: []v1.Container{{Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("50")}}}},
+		},
+	}
+	smallPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "small", Namespace: "ns1", UID: "small", Labels: map[string]string{"tenant": "small"}},
+		Spec: v1.PodSpec{
+			Priority:   &midPriority,
+			Containers: []v1.Container{{Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}}}},
+		},
+	}
+
+	// bigPod is enqueued first, so plain FIFO would serve it first; DRF fairness should serve
+	// smallPod first instead, since it claims a far smaller share of ClusterCapacity.
+	if err := q.Add(bigPod); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	if err := q.Add(smallPod); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+
+	if p, err := q.Pop(); err != nil || p != smallPod {
+		t.Errorf("expected smallPod first, got %v (err: %v)", p, err)
+	}
+	if p, err := q.Pop(); err != nil || p != bigPod {
+		t.Errorf("expected bigPod second, got %v (err: %v)", p, err)
+	}
+}
+
+// TestPriorityQueue_PriorityAging tests that, once a PriorityAgingConfig is set, a low-priority
+// pod that has waited long enough accumulates enough aging steps to be preferred over a
+// higher-priority pod that was enqueued more recently.
+func TestPriorityQueue_PriorityAging(t *testing.T) {
+	c := clock.NewFakeClock(time.Now())
+	q := NewPriorityQueueWithClock(nil, c)
+	q.SetPriorityAgingConfig(&PriorityAgingConfig{
+		AgingWindow: time.Minute,
+		MaxBoost:    midPriority * 2,
+	})
+
+	oldPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "old", Namespace: "ns1", UID: "old"},
+		Spec:       v1.PodSpec{Priority: &lowPriority},
+	}
+	if err := q.Add(oldPod); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+
+	// Advance the clock well past enough aging windows for oldPod's boosted priority to exceed
+	// midPriority.
+	c.Step(time.Duration(midPriority+1) * time.Minute)
+
+	newPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "ns1", UID: "new"},
+		Spec:       v1.PodSpec{Priority: &midPriority},
+	}
+	if err := q.Add(newPod); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+
+	if p, err := q.Pop(); err != nil || p != oldPod {
+		t.Errorf("expected aged oldPod first, got %v (err: %v)", p, err)
+	}
+	if p, err := q.Pop(); err != nil || p != newPod {
+		t.Errorf("expected newPod second, got %v (err: %v)", p, err)
+	}
+}
+
+// TestPriorityQueue_NamespaceMinShare tests that, once a NamespaceMinShareConfig is set, a
+// namespace that has fallen behind its guaranteed share of scheduling cycles is preferred over an
+// equal-priority pod from a namespace that hasn't, even if the latter was enqueued first (which
+// plain FIFO ordering would otherwise prefer).
+func TestPriorityQueue_NamespaceMinShare(t *testing.T) {
+	q := NewPriorityQueue(nil)
+	q.SetNamespaceMinShareConfig(&NamespaceMinShareConfig{
+		MinShare: map[string]float64{"guaranteed": 0.5},
+	})
+
+	// Drain three pods from the "backlog" namespace, so it consumes every scheduling cycle so
+	// far and "guaranteed" falls behind its 50% guarantee despite never having a pod in the queue
+	// yet.
+	for i := 0; i < 3; i++ {
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("backlog-%d", i), Namespace: "backlog", UID: types.UID(fmt.Sprintf("backlog-%d", i))},
+			Spec:       v1.PodSpec{Priority: &midPriority},
+		}
+		if err := q.Add(pod); err != nil {
+			t.Fatalf("add failed: %v", err)
+		}
+		if _, err := q.Pop(); err != nil {
+			t.Fatalf("pop failed: %v", err)
+		}
+	}
+
+	backlogPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "backlog-pod", Namespace: "backlog", UID: "backlog-pod"},
+		Spec:       v1.PodSpec{Priority: &midPriority},
+	}
+	guaranteedPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "guaranteed-pod", Namespace: "guaranteed", UID: "guaranteed-pod"},
+		Spec:       v1.PodSpec{Priority: &midPriority},
+	}
+
+	// backlogPod is enqueued first, so plain FIFO would serve it first; "guaranteed"'s shortfall
+	// against its 50% minimum share should serve guaranteedPod first instead.
+	if err := q.Add(backlogPod); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	if err := q.Add(guaranteedPod); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+
+	if p, err := q.Pop(); err != nil || p != guaranteedPod {
+		t.Errorf("expected guaranteedPod first, got %v (err: %v)", p, err)
+	}
+	if p, err := q.Pop(); err != nil || p != backlogPod {
+		t.Errorf("expected backlogPod second, got %v (err: %v)", p, err)
+	}
+}
+
 func TestPriorityQueue_UpdateNominatedPodForNode(t *testing.T) {
 	q := NewPriorityQueue(nil)
 	if err := q.Add(&medPriorityPod); err != nil {
@@ -1275,3 +1477,69 @@ func TestPendingPodsMetric(t *testing.T) {
 		})
 	}
 }
+
+func ownedPod(name string, uid types.UID, controllerUID types.UID, nominatedNodeName string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ns1",
+			UID:       uid,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					UID:        controllerUID,
+					Controller: func() *bool { b := true; return &b }(),
+				},
+			},
+		},
+		Status: v1.PodStatus{
+			NominatedNodeName: nominatedNodeName,
+		},
+	}
+}
+
+func TestPriorityQueue_RecordPreemptionReducesReplacementBackoff(t *testing.T) {
+	c := clock.NewFakeClock(time.Now())
+	q := NewPriorityQueueWithClock(nil, c)
+	victim := ownedPod("victim", "victim-uid", "rs-uid", "")
+
+	// Simulate the victim backing off a few times before it gets preempted, so
+	// its own backoff has grown well past the initial duration.
+	q.backoffPod(victim)
+	q.backoffPod(victim)
+	q.backoffPod(victim)
+
+	q.RecordPreemption(victim, "node1")
+
+	replacement := ownedPod("replacement", "replacement-uid", "rs-uid", "")
+	q.backoffPod(replacement)
+
+	boTime, ok := q.podBackoff.GetBackoffTime(nsNameForPod(replacement))
+	if !ok {
+		t.Fatalf("expected replacement pod to have a backoff time")
+	}
+	if got := boTime.Sub(q.podBackoff.podLastUpdateTime[nsNameForPod(replacement)]); got != q.podBackoff.initialDuration {
+		t.Errorf("expected replacement pod to back off for the initial duration %v, got %v", q.podBackoff.initialDuration, got)
+	}
+}
+
+func TestPriorityQueue_RecordPreemptionSuppressesOriginNodeNomination(t *testing.T) {
+	q := NewPriorityQueue(nil)
+	victim := ownedPod("victim", "victim-uid", "rs-uid", "")
+	q.RecordPreemption(victim, "node1")
+
+	replacement := ownedPod("replacement", "replacement-uid", "rs-uid", "node1")
+	if err := q.Add(replacement); err != nil {
+		t.Fatalf("unexpected error adding pod: %v", err)
+	}
+	if pods := q.NominatedPodsForNode("node1"); len(pods) != 0 {
+		t.Errorf("expected replacement pod not to be nominated for the node it was just preempted from, got %v", pods)
+	}
+
+	unrelated := ownedPod("unrelated", "unrelated-uid", "other-rs-uid", "node1")
+	if err := q.Add(unrelated); err != nil {
+		t.Fatalf("unexpected error adding pod: %v", err)
+	}
+	if pods := q.NominatedPodsForNode("node1"); len(pods) != 1 {
+		t.Errorf("expected unrelated pod nominated for node1 to still be tracked, got %v", pods)
+	}
+}
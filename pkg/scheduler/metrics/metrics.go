@@ -202,6 +202,109 @@ var (
 	BackoffPods       = pendingPods.With(prometheus.Labels{"queue": "backoff"})
 	UnschedulablePods = pendingPods.With(prometheus.Labels{"queue": "unschedulable"})
 
+	// PendingPodsByPriority tracks, per PriorityClass, how many pods are currently pending in the
+	// scheduling queue. Unlike pendingPods above (which only breaks down by queue type), this
+	// lets operators see whether pending pods are concentrated in a particular priority band
+	// during a capacity incident. It is republished by ObservePendingPodsSummary rather than
+	// updated inline, since computing it requires walking the whole queue.
+	PendingPodsByPriority = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: SchedulerSubsystem,
+			Name:      "pending_pods_by_priority",
+			Help:      "Number of pending pods, by PriorityClass name.",
+		}, []string{"priority_class"})
+
+	// NodeEffectiveAllocatable tracks, per node and resource, the allocatable quantity the
+	// scheduler actually admits pods against for a given priority band. This is only
+	// interesting (and only set) for nodes configured with the NodeOvercommit predicate, where
+	// the "overcommit" band's effective allocatable differs from the node's real capacity.
+	NodeEffectiveAllocatable = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: SchedulerSubsystem,
+			Name:      "node_effective_allocatable",
+			Help:      "Effective allocatable amount of a resource on a node for a given priority band, as used by the scheduler's fit predicates.",
+		}, []string{"node", "resource", "band"})
+
+	// TenantFairnessWeight publishes the configured weighted-fair-queuing weight for each
+	// tenant label value known to the scheduling queue's TenantFairnessConfig, so operators can
+	// confirm the configuration that's actually in effect.
+	TenantFairnessWeight = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: SchedulerSubsystem,
+			Name:      "tenant_fairness_weight",
+			Help:      "Configured weighted-fair-queuing weight for a tenant label value, used to break priority ties in the scheduling queue.",
+		}, []string{"tenant"})
+
+	// NamespaceMinShareDeficit reports, for each namespace configured in the scheduling queue's
+	// NamespaceMinShareConfig, how many scheduling cycles it is currently short of its guaranteed
+	// minimum share. It reads 0 for a namespace that is meeting or exceeding its guarantee.
+	NamespaceMinShareDeficit = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: SchedulerSubsystem,
+			Name:      "namespace_min_share_deficit",
+			Help:      "Number of scheduling cycles a namespace is currently short of its configured minimum guaranteed share.",
+		}, []string{"namespace"})
+
+	// NamespaceMinShareStarvedScheduled counts, per namespace, how many pods have been scheduled
+	// while that namespace was behind its NamespaceMinShareConfig guarantee, i.e. how often the
+	// guarantee actually had to intervene rather than the namespace being served by ordinary
+	// priority ordering alone.
+	NamespaceMinShareStarvedScheduled = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: SchedulerSubsystem,
+			Name:      "namespace_min_share_starved_scheduled_total",
+			Help:      "Number of pods scheduled from a namespace while it was behind its configured minimum guaranteed share.",
+		}, []string{"namespace"})
+
+	// ExtendedResourcePriorityFloorRejections counts, per extended resource, how many times the
+	// ExtendedResourcePriorityFloor predicate has rejected a pod for requesting that resource
+	// without enough priority.
+	ExtendedResourcePriorityFloorRejections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: SchedulerSubsystem,
+			Name:      "extended_resource_priority_floor_rejections_total",
+			Help:      "Number of pods rejected by the ExtendedResourcePriorityFloor predicate, by resource name.",
+		}, []string{"resource"})
+
+	// PreemptionVictimsByPriorityClass counts, per PriorityClass name, how many pods have been
+	// evicted by preemption, so capacity teams can quantify which classes are causing evictions.
+	PreemptionVictimsByPriorityClass = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: SchedulerSubsystem,
+			Name:      "preemption_victims_total",
+			Help:      "Number of pods preempted, by the PriorityClass name of the preempted pod.",
+		}, []string{"priority_class"})
+
+	// PreemptionAttemptsAborted counts preemptions that selected victims but were then abandoned,
+	// with no pod deleted, because the preemptor no longer fit the candidate node once its fit was
+	// re-checked against a fresh snapshot taken after victim selection.
+	PreemptionAttemptsAborted = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Subsystem: SchedulerSubsystem,
+			Name:      "preemption_attempts_aborted_total",
+			Help:      "Total preemption attempts abandoned after victims stopped making room for the preemptor by the time of the final re-check",
+		})
+
+	// PreemptionCircuitBreakerAllowPercent reports the percentage of non-system preemption
+	// attempts core.PreemptionCircuitBreaker is currently letting through, from 0 (all blocked,
+	// as during an incident) to 100 (fully open). It reads 100 whenever no circuit breaker
+	// ConfigMap has been observed.
+	PreemptionCircuitBreakerAllowPercent = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Subsystem: SchedulerSubsystem,
+			Name:      "preemption_circuit_breaker_allow_percent",
+			Help:      "Percentage of non-system preemption attempts currently allowed through by the preemption circuit breaker.",
+		})
+
+	// PreemptionCircuitBreakerBlockedAttempts counts preemption attempts a
+	// core.PreemptionCircuitBreaker has rejected.
+	PreemptionCircuitBreakerBlockedAttempts = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Subsystem: SchedulerSubsystem,
+			Name:      "preemption_circuit_breaker_blocked_total",
+			Help:      "Total preemption attempts rejected by the preemption circuit breaker.",
+		})
+
 	metricsList = []prometheus.Collector{
 		scheduleAttempts,
 		SchedulingLatency,
@@ -219,8 +322,18 @@ var (
 		SchedulingAlgorithmPremptionEvaluationDuration,
 		DeprecatedSchedulingAlgorithmPremptionEvaluationDuration,
 		PreemptionVictims,
+		PreemptionVictimsByPriorityClass,
 		PreemptionAttempts,
+		PreemptionAttemptsAborted,
+		PreemptionCircuitBreakerAllowPercent,
+		PreemptionCircuitBreakerBlockedAttempts,
 		pendingPods,
+		PendingPodsByPriority,
+		NodeEffectiveAllocatable,
+		TenantFairnessWeight,
+		NamespaceMinShareDeficit,
+		NamespaceMinShareStarvedScheduled,
+		ExtendedResourcePriorityFloorRejections,
 	}
 )
 
@@ -244,6 +357,35 @@ func Reset() {
 	DeprecatedSchedulingLatency.Reset()
 }
 
+// ObservePendingPodsSummary republishes a snapshot of pending pod counts by PriorityClass name to
+// PendingPodsByPriority. It fully replaces the previous snapshot's label values, so a priority
+// band that has drained to zero pending pods is reported as zero rather than left stale.
+func ObservePendingPodsSummary(byPriority map[string]int) {
+	PendingPodsByPriority.Reset()
+	for className, count := range byPriority {
+		PendingPodsByPriority.WithLabelValues(className).Set(float64(count))
+	}
+}
+
+// ObserveTenantFairnessWeights republishes the configured weight for every tenant label value in
+// weights, replacing whatever was previously published.
+func ObserveTenantFairnessWeights(weights map[string]int32) {
+	TenantFairnessWeight.Reset()
+	for tenant, weight := range weights {
+		TenantFairnessWeight.WithLabelValues(tenant).Set(float64(weight))
+	}
+}
+
+// ObserveNamespaceMinShareDeficits republishes the current shortfall for every namespace in
+// deficits, replacing whatever was previously published. A nil or empty deficits clears the
+// metric, e.g. when the guarantee is disabled.
+func ObserveNamespaceMinShareDeficits(deficits map[string]float64) {
+	NamespaceMinShareDeficit.Reset()
+	for namespace, deficit := range deficits {
+		NamespaceMinShareDeficit.WithLabelValues(namespace).Set(deficit)
+	}
+}
+
 // SinceInMicroseconds gets the time since the specified start in microseconds.
 func SinceInMicroseconds(start time.Time) float64 {
 	return float64(time.Since(start).Nanoseconds() / time.Microsecond.Nanoseconds())
@@ -25,6 +25,7 @@ import (
 	"k8s.io/klog"
 
 	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -41,6 +42,7 @@ import (
 	"k8s.io/kubernetes/pkg/scheduler/factory"
 	framework "k8s.io/kubernetes/pkg/scheduler/framework/v1alpha1"
 	internalcache "k8s.io/kubernetes/pkg/scheduler/internal/cache"
+	internalqueue "k8s.io/kubernetes/pkg/scheduler/internal/queue"
 	"k8s.io/kubernetes/pkg/scheduler/metrics"
 	"k8s.io/kubernetes/pkg/scheduler/util"
 )
@@ -64,11 +66,18 @@ func (sched *Scheduler) Cache() internalcache.Cache {
 }
 
 type schedulerOptions struct {
-	schedulerName                  string
-	hardPodAffinitySymmetricWeight int32
-	disablePreemption              bool
-	percentageOfNodesToScore       int32
-	bindTimeoutSeconds             int64
+	schedulerName                        string
+	hardPodAffinitySymmetricWeight       int32
+	disablePreemption                    bool
+	preemptionSystemOnly                 bool
+	percentageOfNodesToScore             int32
+	bindTimeoutSeconds                   int64
+	provisioningBacklogThreshold         int32
+	minVictimPriorityDelta               int32
+	preemptionWaitSecondsByPriorityClass map[string]int32
+	maxPreemptedPodsByPriorityClass      map[string]int32
+	podPriorityAgingWindowSeconds        int64
+	podPriorityAgingMaxBoost             int32
 }
 
 // Option configures a Scheduler
@@ -95,6 +104,15 @@ func WithPreemptionDisabled(disablePreemption bool) Option {
 	}
 }
 
+// WithPreemptionSystemOnly sets preemptionSystemOnly for Scheduler, the default value is false. When
+// true, only pods with a system priority are allowed to trigger preemption; see
+// KubeSchedulerConfiguration.PreemptionSystemOnly.
+func WithPreemptionSystemOnly(preemptionSystemOnly bool) Option {
+	return func(o *schedulerOptions) {
+		o.preemptionSystemOnly = preemptionSystemOnly
+	}
+}
+
 // WithPercentageOfNodesToScore sets percentageOfNodesToScore for Scheduler, the default value is 50
 func WithPercentageOfNodesToScore(percentageOfNodesToScore int32) Option {
 	return func(o *schedulerOptions) {
@@ -109,6 +127,58 @@ func WithBindTimeoutSeconds(bindTimeoutSeconds int64) Option {
 	}
 }
 
+// WithProvisioningBacklogThreshold sets provisioningBacklogThreshold for Scheduler, the default
+// value is 0 (disabled). See KubeSchedulerConfiguration.ProvisioningBacklogThreshold.
+func WithProvisioningBacklogThreshold(provisioningBacklogThreshold int32) Option {
+	return func(o *schedulerOptions) {
+		o.provisioningBacklogThreshold = provisioningBacklogThreshold
+	}
+}
+
+// WithMinVictimPriorityDelta sets minVictimPriorityDelta for Scheduler, the default value is 0
+// (any strictly lower-priority pod is a preemption candidate). See
+// KubeSchedulerConfiguration.MinVictimPriorityDelta.
+func WithMinVictimPriorityDelta(minVictimPriorityDelta int32) Option {
+	return func(o *schedulerOptions) {
+		o.minVictimPriorityDelta = minVictimPriorityDelta
+	}
+}
+
+// WithPreemptionWaitSecondsByPriorityClass sets preemptionWaitSecondsByPriorityClass for
+// Scheduler, the default value is nil (no preference for waiting out a victim). See
+// KubeSchedulerConfiguration.PreemptionWaitSecondsByPriorityClass.
+func WithPreemptionWaitSecondsByPriorityClass(preemptionWaitSecondsByPriorityClass map[string]int32) Option {
+	return func(o *schedulerOptions) {
+		o.preemptionWaitSecondsByPriorityClass = preemptionWaitSecondsByPriorityClass
+	}
+}
+
+// WithMaxPreemptedPodsByPriorityClass sets maxPreemptedPodsByPriorityClass for Scheduler, the
+// default value is nil (no per-PriorityClass preemption budget). See
+// KubeSchedulerConfiguration.MaxPreemptedPodsByPriorityClass.
+func WithMaxPreemptedPodsByPriorityClass(maxPreemptedPodsByPriorityClass map[string]int32) Option {
+	return func(o *schedulerOptions) {
+		o.maxPreemptedPodsByPriorityClass = maxPreemptedPodsByPriorityClass
+	}
+}
+
+// WithPodPriorityAgingWindowSeconds sets podPriorityAgingWindowSeconds for Scheduler, the
+// default value is 0 (aging disabled). Has no effect unless the PodPriorityAging feature gate is
+// also enabled. See KubeSchedulerConfiguration.PodPriorityAgingWindowSeconds.
+func WithPodPriorityAgingWindowSeconds(podPriorityAgingWindowSeconds int64) Option {
+	return func(o *schedulerOptions) {
+		o.podPriorityAgingWindowSeconds = podPriorityAgingWindowSeconds
+	}
+}
+
+// WithPodPriorityAgingMaxBoost sets podPriorityAgingMaxBoost for Scheduler, the default value is
+// 0 (uncapped). See KubeSchedulerConfiguration.PodPriorityAgingMaxBoost.
+func WithPodPriorityAgingMaxBoost(podPriorityAgingMaxBoost int32) Option {
+	return func(o *schedulerOptions) {
+		o.podPriorityAgingMaxBoost = podPriorityAgingMaxBoost
+	}
+}
+
 var defaultSchedulerOptions = schedulerOptions{
 	schedulerName:                  v1.DefaultSchedulerName,
 	hardPodAffinitySymmetricWeight: v1.DefaultHardPodAffinitySymmetricWeight,
@@ -126,6 +196,7 @@ func New(client clientset.Interface,
 	replicationControllerInformer coreinformers.ReplicationControllerInformer,
 	replicaSetInformer appsinformers.ReplicaSetInformer,
 	statefulSetInformer appsinformers.StatefulSetInformer,
+	daemonSetInformer appsinformers.DaemonSetInformer,
 	serviceInformer coreinformers.ServiceInformer,
 	pdbInformer policyinformers.PodDisruptionBudgetInformer,
 	storageClassInformer storageinformers.StorageClassInformer,
@@ -139,25 +210,38 @@ func New(client clientset.Interface,
 	for _, opt := range opts {
 		opt(&options)
 	}
+	var priorityAgingConfig *internalqueue.PriorityAgingConfig
+	if util.PodPriorityAgingEnabled() && options.podPriorityAgingWindowSeconds > 0 {
+		priorityAgingConfig = &internalqueue.PriorityAgingConfig{
+			AgingWindow: time.Duration(options.podPriorityAgingWindowSeconds) * time.Second,
+			MaxBoost:    options.podPriorityAgingMaxBoost,
+		}
+	}
 	// Set up the configurator which can create schedulers from configs.
 	configurator := factory.NewConfigFactory(&factory.ConfigFactoryArgs{
-		SchedulerName:                  options.schedulerName,
-		Client:                         client,
-		NodeInformer:                   nodeInformer,
-		PodInformer:                    podInformer,
-		PvInformer:                     pvInformer,
-		PvcInformer:                    pvcInformer,
-		ReplicationControllerInformer:  replicationControllerInformer,
-		ReplicaSetInformer:             replicaSetInformer,
-		StatefulSetInformer:            statefulSetInformer,
-		ServiceInformer:                serviceInformer,
-		PdbInformer:                    pdbInformer,
-		StorageClassInformer:           storageClassInformer,
-		HardPodAffinitySymmetricWeight: options.hardPodAffinitySymmetricWeight,
-		DisablePreemption:              options.disablePreemption,
-		PercentageOfNodesToScore:       options.percentageOfNodesToScore,
-		BindTimeoutSeconds:             options.bindTimeoutSeconds,
-		Registry:                       registry,
+		SchedulerName:                        options.schedulerName,
+		Client:                               client,
+		NodeInformer:                         nodeInformer,
+		PodInformer:                          podInformer,
+		PvInformer:                           pvInformer,
+		PvcInformer:                          pvcInformer,
+		ReplicationControllerInformer:        replicationControllerInformer,
+		ReplicaSetInformer:                   replicaSetInformer,
+		StatefulSetInformer:                  statefulSetInformer,
+		DaemonSetInformer:                    daemonSetInformer,
+		ServiceInformer:                      serviceInformer,
+		PdbInformer:                          pdbInformer,
+		StorageClassInformer:                 storageClassInformer,
+		HardPodAffinitySymmetricWeight:       options.hardPodAffinitySymmetricWeight,
+		DisablePreemption:                    options.disablePreemption,
+		PercentageOfNodesToScore:             options.percentageOfNodesToScore,
+		BindTimeoutSeconds:                   options.bindTimeoutSeconds,
+		ProvisioningBacklogThreshold:         options.provisioningBacklogThreshold,
+		MinVictimPriorityDelta:               options.minVictimPriorityDelta,
+		PreemptionWaitSecondsByPriorityClass: options.preemptionWaitSecondsByPriorityClass,
+		MaxPreemptedPodsByPriorityClass:      options.maxPreemptedPodsByPriorityClass,
+		PriorityAgingConfig:                  priorityAgingConfig,
+		Registry:                             registry,
 	})
 	var config *factory.Config
 	source := schedulerAlgorithmSource
@@ -193,6 +277,7 @@ func New(client clientset.Interface,
 	// Additional tweaks to the config produced by the configurator.
 	config.Recorder = recorder
 	config.DisablePreemption = options.disablePreemption
+	config.PreemptionSystemOnly = options.preemptionSystemOnly
 	config.StopEverything = stopCh
 
 	// Create the scheduler.
@@ -323,7 +408,10 @@ func (sched *Scheduler) preempt(preemptor *v1.Pod, scheduleErr error) (string, e
 				return "", err
 			}
 			sched.config.Recorder.Eventf(victim, v1.EventTypeNormal, "Preempted", "by %v/%v on node %v", preemptor.Namespace, preemptor.Name, nodeName)
+			sched.config.SchedulingQueue.RecordPreemption(victim, nodeName)
+			metrics.PreemptionVictimsByPriorityClass.WithLabelValues(victim.Spec.PriorityClassName).Inc()
 		}
+		sched.config.Recorder.Eventf(preemptor, v1.EventTypeNormal, "Preempting", "Preempted %v pod(s) on node %v to make room", len(victims), nodeName)
 		metrics.PreemptionVictims.Set(float64(len(victims)))
 	}
 	// Clearing nominated pods should happen outside of "if node != nil". Node could
@@ -406,6 +494,33 @@ func (sched *Scheduler) assume(assumed *v1.Pod, host string) error {
 	return nil
 }
 
+// revalidatePriorityClass re-checks, immediately before binding, that assumed's PriorityClass
+// still exists. A pod can sit in the scheduling queue for a long time, and its PriorityClass may
+// be deleted out from under it in the meantime; binding it with a priority resolved from a class
+// that no longer exists would leave a pod running with a priority nothing can account for.
+//
+// It only re-checks that the class still exists, not that the namespace's admission policy still
+// permits it: that policy lives inside the Priority admission plugin's own, apiserver-internal
+// state, which the scheduler has no access to (and should not gain a dependency on the admission
+// plugin package in order to get). A pod already admitted with a class the current namespace
+// policy would now reject stays scheduled; only outright class deletion is caught here.
+//
+// It fails open (returns nil) on any error other than the class not existing, since a transient
+// API problem should not block otherwise-successful scheduling.
+func (sched *Scheduler) revalidatePriorityClass(assumed *v1.Pod) error {
+	if len(assumed.Spec.PriorityClassName) == 0 || sched.config.Client == nil {
+		return nil
+	}
+	_, err := sched.config.Client.SchedulingV1().PriorityClasses().Get(assumed.Spec.PriorityClassName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return fmt.Errorf("priority class %q no longer exists", assumed.Spec.PriorityClassName)
+	}
+	if err != nil {
+		klog.Warningf("Failed to revalidate priority class %q for pod %v/%v, proceeding with binding: %v", assumed.Spec.PriorityClassName, assumed.Namespace, assumed.Name, err)
+	}
+	return nil
+}
+
 // bind binds a pod to a given node defined in a binding object.  We expect this to run asynchronously, so we
 // handle binding metrics internally.
 func (sched *Scheduler) bind(assumed *v1.Pod, b *v1.Binding) error {
@@ -464,6 +579,9 @@ func (sched *Scheduler) scheduleOne() {
 			if !util.PodPriorityEnabled() || sched.config.DisablePreemption {
 				klog.V(3).Infof("Pod priority feature is not enabled or preemption is disabled by scheduler configuration." +
 					" No preemption is performed.")
+			} else if sched.config.PreemptionSystemOnly && !util.PodHasSystemPriority(pod) {
+				klog.V(3).Infof("Preemption is restricted to system priority pods by scheduler configuration," +
+					" and pod %v/%v does not have a system priority. No preemption is performed.", pod.Namespace, pod.Name)
 			} else {
 				preemptionStartTime := time.Now()
 				sched.preempt(pod, fitError)
@@ -529,6 +647,16 @@ func (sched *Scheduler) scheduleOne() {
 			}
 		}
 
+		// Re-check that the pod's priority is still backed by an existing PriorityClass; it may
+		// have been deleted while the pod sat in the scheduling queue.
+		if err := sched.revalidatePriorityClass(assumedPod); err != nil {
+			if forgetErr := sched.Cache().ForgetPod(assumedPod); forgetErr != nil {
+				klog.Errorf("scheduler cache ForgetPod failed: %v", forgetErr)
+			}
+			sched.recordSchedulingFailure(assumedPod, err, v1.PodReasonUnschedulable, err.Error())
+			return
+		}
+
 		// Run "prebind" plugins.
 		prebindStatus := fwk.RunPrebindPlugins(pluginContext, assumedPod, scheduleResult.SuggestedHost)
 		if !prebindStatus.IsSuccess() {
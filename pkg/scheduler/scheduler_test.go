@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -654,6 +655,12 @@ func setupTestScheduler(queuedPodStore *clientcache.FIFO, scache internalcache.C
 		false,
 		false,
 		schedulerapi.DefaultPercentageOfNodesToScore,
+		0,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	bindingChan := make(chan *v1.Binding, 1)
 	errChan := make(chan error, 1)
@@ -707,6 +714,12 @@ func setupTestSchedulerLongBindingWithRetry(queuedPodStore *clientcache.FIFO, sc
 		false,
 		false,
 		schedulerapi.DefaultPercentageOfNodesToScore,
+		0,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	bindingChan := make(chan *v1.Binding, 2)
 
@@ -1032,3 +1045,36 @@ priorities:
 		}
 	}
 }
+
+func TestRevalidatePriorityClass(t *testing.T) {
+	existingClass := &schedulingv1.PriorityClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing"},
+		Value:      1000,
+	}
+
+	tests := []struct {
+		name              string
+		priorityClassName string
+		expectError       bool
+	}{
+		{"no priority class set", "", false},
+		{"priority class still exists", "existing", false},
+		{"priority class was deleted", "deleted", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := clientsetfake.NewSimpleClientset(existingClass)
+			sched := &Scheduler{config: &factory.Config{Client: client}}
+			pod := &v1.Pod{Spec: v1.PodSpec{PriorityClassName: test.priorityClassName}}
+
+			err := sched.revalidatePriorityClass(pod)
+			if err != nil && !test.expectError {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if err == nil && test.expectError {
+				t.Errorf("expected an error, got nil")
+			}
+		})
+	}
+}
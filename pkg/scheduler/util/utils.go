@@ -18,6 +18,7 @@ package util
 
 import (
 	"sort"
+	"strconv"
 
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -29,6 +30,11 @@ import (
 	"time"
 )
 
+// PodDeletionCostAnnotationKey is the annotation controllers (e.g. ReplicaSet scale-down) and,
+// via GetPodDeletionCost, the scheduler's preemption victim ordering read to learn which of a set
+// of equal-priority pods is cheapest to delete. Higher values mean the pod should be deleted last.
+const PodDeletionCostAnnotationKey = "controller.kubernetes.io/pod-deletion-cost"
+
 // GetContainerPorts returns the used host ports of Pods: if 'port' was used, a 'port:true' pair
 // will be in the result; but it does not resolve port conflict.
 func GetContainerPorts(pods ...*v1.Pod) []*v1.ContainerPort {
@@ -49,6 +55,12 @@ func PodPriorityEnabled() bool {
 	return feature.DefaultFeatureGate.Enabled(features.PodPriority)
 }
 
+// PodPriorityAgingEnabled indicates whether the scheduling queue's priority aging feature is
+// enabled.
+func PodPriorityAgingEnabled() bool {
+	return feature.DefaultFeatureGate.Enabled(features.PodPriorityAging)
+}
+
 // GetPodFullName returns a name that uniquely identifies a pod.
 func GetPodFullName(pod *v1.Pod) string {
 	// Use underscore as the delimiter because it is not allowed in pod name
@@ -67,6 +79,13 @@ func GetPodPriority(pod *v1.Pod) int32 {
 	return scheduling.DefaultPriorityWhenNoDefaultClassExists
 }
 
+// PodHasSystemPriority returns true if the given pod's priority is one of the system priority
+// classes (i.e. system-cluster-critical or system-node-critical), or any other priority at least
+// as high as scheduling.SystemCriticalPriority.
+func PodHasSystemPriority(pod *v1.Pod) bool {
+	return GetPodPriority(pod) >= scheduling.SystemCriticalPriority
+}
+
 // GetPodStartTime returns start time of the given pod.
 func GetPodStartTime(pod *v1.Pod) *metav1.Time {
 	if pod.Status.StartTime != nil {
@@ -80,6 +99,23 @@ func GetPodStartTime(pod *v1.Pod) *metav1.Time {
 	return &metav1.Time{Time: time.Now()}
 }
 
+// GetPodDeletionCost returns the value of pod's PodDeletionCostAnnotationKey annotation, or 0 if
+// the annotation is absent or fails to parse as an int32. It does not itself delete anything; it
+// is a hint of how disruptive deleting pod would be, lower values being cheaper to delete, that
+// controllers and (see MoreImportantPod) preemption victim ordering may use to agree on which of
+// several equivalent pods to remove first.
+func GetPodDeletionCost(pod *v1.Pod) int32 {
+	value, ok := pod.Annotations[PodDeletionCostAnnotationKey]
+	if !ok {
+		return 0
+	}
+	cost, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0
+	}
+	return int32(cost)
+}
+
 // GetEarliestPodStartTime returns the earliest start time of all pods that
 // have the highest priority among all victims.
 func GetEarliestPodStartTime(victims *api.Victims) *metav1.Time {
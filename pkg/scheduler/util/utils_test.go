@@ -61,6 +61,42 @@ func TestGetPodPriority(t *testing.T) {
 	}
 }
 
+// TestPodHasSystemPriority tests PodHasSystemPriority function.
+func TestPodHasSystemPriority(t *testing.T) {
+	systemPriority := scheduling.SystemCriticalPriority
+	userPriority := int32(1000)
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		expected bool
+	}{
+		{
+			name:     "no priority pod resolves to static default priority, not system",
+			pod:      &v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "container", Image: "image"}}}},
+			expected: false,
+		},
+		{
+			name: "pod with a user priority is not system",
+			pod: &v1.Pod{
+				Spec: v1.PodSpec{Containers: []v1.Container{{Name: "container", Image: "image"}}, Priority: &userPriority},
+			},
+			expected: false,
+		},
+		{
+			name: "pod with a system priority is system",
+			pod: &v1.Pod{
+				Spec: v1.PodSpec{Containers: []v1.Container{{Name: "container", Image: "image"}}, Priority: &systemPriority},
+			},
+			expected: true,
+		},
+	}
+	for _, test := range tests {
+		if got := PodHasSystemPriority(test.pod); got != test.expected {
+			t.Errorf("%v: expected %v, got %v", test.name, test.expected, got)
+		}
+	}
+}
+
 // TestSortableList tests SortableList by storing pods in the list and sorting
 // them by their priority.
 func TestSortableList(t *testing.T) {
@@ -31,20 +31,59 @@ type VolumeBinder struct {
 	Binder volumescheduling.SchedulerVolumeBinder
 }
 
-// NewVolumeBinder sets up the volume binding library and binding queue
+// NewVolumeBinder sets up the volume binding library and binding queue.
+//
+// pendingPods and priorityBacklogThreshold together gate dynamic provisioning: if
+// priorityBacklogThreshold or more of the pods returned by pendingPods have strictly higher
+// priority than the pod being scheduled, provisioning for that pod is delayed for this cycle
+// rather than immediately kicking off expensive PV creation for a pod that is likely to sit
+// queued behind that backlog, or be preempted, before it ever runs. A threshold <= 0, or a nil
+// pendingPods, disables the check.
 func NewVolumeBinder(
 	client clientset.Interface,
 	nodeInformer coreinformers.NodeInformer,
 	pvcInformer coreinformers.PersistentVolumeClaimInformer,
 	pvInformer coreinformers.PersistentVolumeInformer,
 	storageClassInformer storageinformers.StorageClassInformer,
-	bindTimeout time.Duration) *VolumeBinder {
+	bindTimeout time.Duration,
+	pendingPods func() []*v1.Pod,
+	priorityBacklogThreshold int32) *VolumeBinder {
 
 	return &VolumeBinder{
-		Binder: volumescheduling.NewVolumeBinder(client, nodeInformer, pvcInformer, pvInformer, storageClassInformer, bindTimeout),
+		Binder: volumescheduling.NewVolumeBinder(client, nodeInformer, pvcInformer, pvInformer, storageClassInformer, bindTimeout,
+			provisioningDelayChecker(pendingPods, priorityBacklogThreshold)),
 	}
 }
 
+// provisioningDelayChecker builds the volumescheduling.ProvisioningDelayChecker described by
+// NewVolumeBinder's pendingPods/priorityBacklogThreshold parameters, or nil if the check is
+// disabled.
+func provisioningDelayChecker(pendingPods func() []*v1.Pod, priorityBacklogThreshold int32) volumescheduling.ProvisioningDelayChecker {
+	if pendingPods == nil || priorityBacklogThreshold <= 0 {
+		return nil
+	}
+	return func(pod *v1.Pod) bool {
+		podPriority := podPriorityOrZero(pod)
+		var higherPriorityBacklog int32
+		for _, pending := range pendingPods() {
+			if podPriorityOrZero(pending) > podPriority {
+				higherPriorityBacklog++
+				if higherPriorityBacklog >= priorityBacklogThreshold {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+func podPriorityOrZero(pod *v1.Pod) int32 {
+	if pod.Spec.Priority == nil {
+		return 0
+	}
+	return *pod.Spec.Priority
+}
+
 // NewFakeVolumeBinder sets up a fake volume binder and binding queue
 func NewFakeVolumeBinder(config *volumescheduling.FakeVolumeBinderConfig) *VolumeBinder {
 	return &VolumeBinder{
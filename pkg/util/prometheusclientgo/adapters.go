@@ -18,7 +18,7 @@ package prometheusclientgo
 
 // Provided metrics needing adapting
 import (
-	_ "k8s.io/kubernetes/pkg/client/metrics/prometheus"              // for client metric registration
-	_ "k8s.io/kubernetes/pkg/util/prometheusclientgo/leaderelection" // for leader election metric registration
-	_ "k8s.io/kubernetes/pkg/util/workqueue/prometheus"              // for workqueue metric registration
+	_ "k8s.io/component-base/metrics/prometheus/leaderelection" // for leader election metric registration
+	_ "k8s.io/kubernetes/pkg/client/metrics/prometheus"         // for client metric registration
+	_ "k8s.io/kubernetes/pkg/util/workqueue/prometheus"         // for workqueue metric registration
 )
@@ -0,0 +1,163 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package podpreemptionpolicy implements an admission plugin enforcing cluster policy on which
+// preemption policies a pod may request, complementing the Priority plugin which only resolves
+// the integer priority value pods are admitted with.
+package podpreemptionpolicy
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/admission"
+	api "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const (
+	// PluginName indicates name of admission plugin.
+	PluginName = "PodPriorityPreemptionPolicy"
+
+	// PreemptLowerPriority is the preemption policy under which a pod may preempt lower-priority
+	// pods to schedule, the historical (and, absent this plugin's interim annotation, the only)
+	// behavior. It matches the value the real PreemptionPolicy API field uses once it lands.
+	PreemptLowerPriority = "PreemptLowerPriority"
+	// PreemptNever is the preemption policy under which a pod is scheduled only once it fits
+	// without evicting any other pod.
+	PreemptNever = "Never"
+
+	// preemptionPolicyAnnotationKey carries a pod's requested preemption policy in this tree,
+	// since the PodSpec/PriorityClass PreemptionPolicy field this plugin ultimately governs was
+	// introduced in a later Kubernetes release than this tree tracks (see the NOTE in
+	// plugin/pkg/admission/priority/admission.go's resolvePod and
+	// pkg/apis/scheduling/validation's ValidatePriorityClassUpdate). Once that field lands, this
+	// plugin should read it directly instead, the same way it should stop needing this annotation
+	// at all.
+	preemptionPolicyAnnotationKey = "scheduling.k8s.io/preemption-policy"
+)
+
+// Register registers a plugin
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName, func(config io.Reader) (admission.Interface, error) {
+		pluginConfig, err := readConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		return NewPlugin(pluginConfig), nil
+	})
+}
+
+// pluginConfig holds the configuration accepted by the PodPriorityPreemptionPolicy admission
+// plugin.
+type pluginConfig struct {
+	// NamespacePreemptionPolicyAllowList, if set, restricts which preemption policies pods in a
+	// given namespace may request via preemptionPolicyAnnotationKey. A namespace with no entry in
+	// this map is unrestricted. A namespace with an entry may only request the policies listed
+	// for it.
+	NamespacePreemptionPolicyAllowList map[string][]string `json:"namespacePreemptionPolicyAllowList,omitempty"`
+}
+
+// readConfig reads the PodPriorityPreemptionPolicy plugin configuration from the file provided
+// with --admission-control-config-file. If the file is not supplied, it defaults to an empty
+// config, under which every preemption policy is permitted in every namespace.
+func readConfig(config io.Reader) (*pluginConfig, error) {
+	parsedConfig := &pluginConfig{}
+	if config == nil || reflect.ValueOf(config).IsNil() {
+		return parsedConfig, nil
+	}
+	raw, err := ioutil.ReadAll(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s configuration: %v", PluginName, err)
+	}
+	if len(raw) == 0 {
+		return parsedConfig, nil
+	}
+	if err := yaml.UnmarshalStrict(raw, parsedConfig); err != nil {
+		return nil, fmt.Errorf("failed to decode %s configuration: %v", PluginName, err)
+	}
+	for namespace, allowed := range parsedConfig.NamespacePreemptionPolicyAllowList {
+		if len(allowed) == 0 {
+			return nil, fmt.Errorf("invalid %s configuration: namespacePreemptionPolicyAllowList[%q] must not be empty; omit the namespace instead of allowing zero preemption policies", PluginName, namespace)
+		}
+	}
+	return parsedConfig, nil
+}
+
+// plugin is an implementation of admission.Interface.
+type plugin struct {
+	*admission.Handler
+	namespacePreemptionPolicyAllowList map[string][]string
+}
+
+var _ admission.ValidationInterface = &plugin{}
+
+// NewPlugin creates a new PodPriorityPreemptionPolicy admission plugin.
+func NewPlugin(config *pluginConfig) *plugin {
+	return &plugin{
+		Handler:                            admission.NewHandler(admission.Create, admission.Update),
+		namespacePreemptionPolicyAllowList: config.NamespacePreemptionPolicyAllowList,
+	}
+}
+
+// Validate rejects a pod whose preemptionPolicyAnnotationKey names a preemption policy not
+// permitted in its namespace. A pod with no preemptionPolicyAnnotationKey set is left alone: it
+// requests no particular preemption policy, so there is nothing here to restrict.
+func (p *plugin) Validate(a admission.Attributes, o admission.ObjectInterfaces) error {
+	if shouldIgnore(a) {
+		return nil
+	}
+	pod, ok := a.GetObject().(*api.Pod)
+	if !ok {
+		return errors.NewBadRequest("resource was marked with kind Pod but was unable to be converted")
+	}
+
+	policy, ok := pod.Annotations[preemptionPolicyAnnotationKey]
+	if !ok {
+		return nil
+	}
+	if policy != PreemptLowerPriority && policy != PreemptNever {
+		return admission.NewForbidden(a, fmt.Errorf("%s must be %q or %q, got %q", preemptionPolicyAnnotationKey, PreemptLowerPriority, PreemptNever, policy))
+	}
+
+	allowed, ok := p.namespacePreemptionPolicyAllowList[a.GetNamespace()]
+	if !ok {
+		return nil
+	}
+	for _, allowedPolicy := range allowed {
+		if allowedPolicy == policy {
+			return nil
+		}
+	}
+	return admission.NewForbidden(a, fmt.Errorf("preemption policy %q is not permitted in namespace %v", policy, a.GetNamespace()))
+}
+
+// shouldIgnore reports whether a is not a top-level pods-resource create/update this plugin should
+// examine. It does not attempt the *api.Pod type assertion itself; a resource that claims to be a
+// pod but fails that assertion falls through to Validate, which rejects it with a BadRequest.
+func shouldIgnore(a admission.Attributes) bool {
+	if len(a.GetSubresource()) != 0 {
+		return true
+	}
+	if a.GetResource().GroupResource() != api.Resource("pods") {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podpreemptionpolicy
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/admission"
+	api "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func admitPod(handler *plugin, namespace string, annotations map[string]string) error {
+	pod := &api.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: namespace, Annotations: annotations},
+	}
+	return handler.Validate(admission.NewAttributesRecord(pod, nil, api.Kind("Pod").WithVersion("version"), namespace, "pod", api.Resource("pods").WithVersion("version"), "", admission.Create, false, nil), nil)
+}
+
+func TestNoAnnotationIsUnrestricted(t *testing.T) {
+	handler := NewPlugin(&pluginConfig{})
+	if err := admitPod(handler, "default", nil); err != nil {
+		t.Errorf("unexpected error for pod with no preemption policy annotation: %v", err)
+	}
+}
+
+func TestInvalidPolicyValueRejected(t *testing.T) {
+	handler := NewPlugin(&pluginConfig{})
+	err := admitPod(handler, "default", map[string]string{preemptionPolicyAnnotationKey: "Sometimes"})
+	if err == nil {
+		t.Fatalf("expected an error for an unrecognized preemption policy value")
+	}
+}
+
+func TestNamespaceAllowListEnforced(t *testing.T) {
+	handler := NewPlugin(&pluginConfig{
+		NamespacePreemptionPolicyAllowList: map[string][]string{
+			"restricted": {PreemptLowerPriority},
+		},
+	})
+
+	if err := admitPod(handler, "restricted", map[string]string{preemptionPolicyAnnotationKey: PreemptLowerPriority}); err != nil {
+		t.Errorf("unexpected error for an allowed policy: %v", err)
+	}
+
+	err := admitPod(handler, "restricted", map[string]string{preemptionPolicyAnnotationKey: PreemptNever})
+	if err == nil {
+		t.Fatalf("expected an error for a policy not in the namespace's allow list")
+	}
+	if !strings.Contains(err.Error(), PreemptNever) {
+		t.Errorf("expected error to name the rejected policy, got: %v", err)
+	}
+
+	if err := admitPod(handler, "unrestricted", map[string]string{preemptionPolicyAnnotationKey: PreemptNever}); err != nil {
+		t.Errorf("unexpected error for a namespace with no configured allow list: %v", err)
+	}
+}
+
+func TestOtherResourcesIgnored(t *testing.T) {
+	handler := NewPlugin(&pluginConfig{})
+	pod := &api.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default"}}
+
+	if err := handler.Validate(admission.NewAttributesRecord(&api.Service{}, nil, api.Kind("Pod").WithVersion("version"), "default", "pod", api.Resource("pods").WithVersion("version"), "", admission.Create, false, nil), nil); err == nil {
+		t.Errorf("expected an error for a non-Pod object claiming to be a Pod")
+	}
+
+	if err := handler.Validate(admission.NewAttributesRecord(pod, nil, api.Kind("Pod").WithVersion("version"), "default", "pod", api.Resource("pods").WithVersion("version"), "eviction", admission.Create, false, nil), nil); err != nil {
+		t.Errorf("expected pod subresources to be ignored, got: %v", err)
+	}
+}
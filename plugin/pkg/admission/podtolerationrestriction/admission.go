@@ -34,6 +34,7 @@ import (
 	api "k8s.io/kubernetes/pkg/apis/core"
 	qoshelper "k8s.io/kubernetes/pkg/apis/core/helper/qos"
 	k8s_api_v1 "k8s.io/kubernetes/pkg/apis/core/v1"
+	kubeapiserveradmission "k8s.io/kubernetes/pkg/kubeapiserver/admission"
 	schedulerapi "k8s.io/kubernetes/pkg/scheduler/api"
 	"k8s.io/kubernetes/pkg/util/tolerations"
 	pluginapi "k8s.io/kubernetes/plugin/pkg/admission/podtolerationrestriction/apis/podtolerationrestriction"
@@ -58,16 +59,25 @@ const (
 	NSWLTolerations      string = "scheduler.alpha.kubernetes.io/tolerationsWhitelist"
 )
 
+// The generic namespace params keys for default and whitelist of tolerations. These are
+// consulted before the legacy annotations above via kubeapiserveradmission.NamespaceParamsGetter.
+const (
+	paramDefaultTolerations string = "defaultTolerations"
+	paramWLTolerations      string = "tolerationsWhitelist"
+)
+
 var _ admission.MutationInterface = &podTolerationsPlugin{}
 var _ admission.ValidationInterface = &podTolerationsPlugin{}
 var _ = genericadmissioninitializer.WantsExternalKubeInformerFactory(&podTolerationsPlugin{})
 var _ = genericadmissioninitializer.WantsExternalKubeClientSet(&podTolerationsPlugin{})
+var _ = kubeapiserveradmission.WantsNamespaceParamsGetter(&podTolerationsPlugin{})
 
 type podTolerationsPlugin struct {
 	*admission.Handler
-	client          kubernetes.Interface
-	namespaceLister corev1listers.NamespaceLister
-	pluginConfig    *pluginapi.Configuration
+	client                kubernetes.Interface
+	namespaceLister       corev1listers.NamespaceLister
+	namespaceParamsGetter kubeapiserveradmission.NamespaceParamsGetter
+	pluginConfig          *pluginapi.Configuration
 }
 
 // This plugin first verifies any conflict between a pod's tolerations and
@@ -208,6 +218,10 @@ func (p *podTolerationsPlugin) SetExternalKubeInformerFactory(f informers.Shared
 
 }
 
+func (p *podTolerationsPlugin) SetNamespaceParamsGetter(getter kubeapiserveradmission.NamespaceParamsGetter) {
+	p.namespaceParamsGetter = getter
+}
+
 func (p *podTolerationsPlugin) ValidateInitialization() error {
 	if p.namespaceLister == nil {
 		return fmt.Errorf("missing namespaceLister")
@@ -238,6 +252,9 @@ func (p *podTolerationsPlugin) getNamespace(nsName string) (*corev1.Namespace, e
 }
 
 func (p *podTolerationsPlugin) getNamespaceDefaultTolerations(nsName string) ([]api.Toleration, error) {
+	if ts, ok, err := p.getNamespaceParamTolerations(nsName, paramDefaultTolerations); err != nil || ok {
+		return ts, err
+	}
 	ns, err := p.getNamespace(nsName)
 	if err != nil {
 		return nil, err
@@ -246,6 +263,9 @@ func (p *podTolerationsPlugin) getNamespaceDefaultTolerations(nsName string) ([]
 }
 
 func (p *podTolerationsPlugin) getNamespaceTolerationsWhitelist(nsName string) ([]api.Toleration, error) {
+	if ts, ok, err := p.getNamespaceParamTolerations(nsName, paramWLTolerations); err != nil || ok {
+		return ts, err
+	}
 	ns, err := p.getNamespace(nsName)
 	if err != nil {
 		return nil, err
@@ -253,6 +273,28 @@ func (p *podTolerationsPlugin) getNamespaceTolerationsWhitelist(nsName string) (
 	return extractNSTolerations(ns, NSWLTolerations)
 }
 
+// getNamespaceParamTolerations looks up param in the namespace's generic admission params, if a
+// NamespaceParamsGetter has been configured. The returned bool reports whether the param was
+// found there at all, so callers can fall back to the legacy namespace annotations when it wasn't.
+func (p *podTolerationsPlugin) getNamespaceParamTolerations(nsName, param string) ([]api.Toleration, bool, error) {
+	if p.namespaceParamsGetter == nil {
+		return nil, false, nil
+	}
+	params, ok, err := p.namespaceParamsGetter.GetNamespaceParams(PluginName, nsName)
+	if err != nil {
+		return nil, false, err
+	}
+	if !ok {
+		return nil, false, nil
+	}
+	raw, ok := params[param]
+	if !ok {
+		return nil, false, nil
+	}
+	ts, err := decodeTolerations(raw)
+	return ts, true, err
+}
+
 // extractNSTolerations extracts default or whitelist of tolerations from
 // following namespace annotations keys: "scheduler.alpha.kubernetes.io/defaultTolerations"
 // and "scheduler.alpha.kubernetes.io/tolerationsWhitelist". If these keys are
@@ -270,13 +312,19 @@ func extractNSTolerations(ns *corev1.Namespace, key string) ([]api.Toleration, e
 		return nil, nil
 	}
 
-	// if value is set to empty
-	if len(ns.Annotations[key]) == 0 {
+	return decodeTolerations(ns.Annotations[key])
+}
+
+// decodeTolerations decodes a JSON-encoded list of v1.Toleration, as found in either the legacy
+// namespace annotations or a generic namespace param value, into internal Tolerations. An empty
+// raw value decodes to an empty (non-nil) list.
+func decodeTolerations(raw string) ([]api.Toleration, error) {
+	if len(raw) == 0 {
 		return []api.Toleration{}, nil
 	}
 
 	var v1Tolerations []corev1.Toleration
-	err := json.Unmarshal([]byte(ns.Annotations[key]), &v1Tolerations)
+	err := json.Unmarshal([]byte(raw), &v1Tolerations)
 	if err != nil {
 		return nil, err
 	}
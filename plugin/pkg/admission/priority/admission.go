@@ -17,42 +17,360 @@ limitations under the License.
 package priority
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
+	"k8s.io/klog"
+	"sigs.k8s.io/yaml"
+
+	corev1 "k8s.io/api/core/v1"
 	schedulingv1 "k8s.io/api/scheduling/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apiserver/pkg/admission"
 	genericadmissioninitializers "k8s.io/apiserver/pkg/admission/initializer"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 	schedulingv1listers "k8s.io/client-go/listers/scheduling/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/kubernetes/pkg/apis/apps"
+	"k8s.io/kubernetes/pkg/apis/batch"
 	api "k8s.io/kubernetes/pkg/apis/core"
 	"k8s.io/kubernetes/pkg/apis/scheduling"
 	"k8s.io/kubernetes/pkg/features"
+	kubeapiserveradmission "k8s.io/kubernetes/pkg/kubeapiserver/admission"
 	kubelettypes "k8s.io/kubernetes/pkg/kubelet/types"
+	priorityapi "k8s.io/kubernetes/plugin/pkg/admission/priority/apis/priority"
+	"k8s.io/kubernetes/plugin/pkg/admission/priority/apis/priority/validation"
 )
 
 const (
 	// PluginName indicates name of admission plugin.
 	PluginName = "Priority"
+
+	// priorityBandLabel is stamped onto pods at admission time with the name of the highest
+	// configured PriorityBand whose Threshold the pod's resolved priority meets or exceeds.
+	// This lets label selectors (NetworkPolicies, ResourceQuota scopes, dashboards) key off a
+	// pod's priority tier without parsing the numeric priority value client-side.
+	priorityBandLabel = "scheduling.k8s.io/priority-band"
+
+	// shadowDivergenceAnnotationPrefix prefixes the audit annotations set on a pod when its
+	// shadow-evaluated candidate configuration would have made a different admission decision
+	// than the active configuration. The divergence kind (e.g. "admission-result",
+	// "priority-class", "priority-value") is appended to form the full annotation key.
+	shadowDivergenceAnnotationPrefix = "priority.k8s.io/shadow-divergence-"
+
+	// defaultedPriorityClassAnnotationKey is the audit annotation set on a pod created without an
+	// explicit PriorityClassName, recording the PriorityClassName this plugin resolved it to. It
+	// lets cluster auditors distinguish user-specified priorities from plugin-injected ones.
+	defaultedPriorityClassAnnotationKey = "priority.admission.k8s.io/defaulted"
+
+	// resolvedAliasAnnotationKey is the audit annotation set on a pod whose PriorityClassName was
+	// rewritten by resolveAlias, recording the alias the pod actually specified. It lets cluster
+	// auditors find pods still using a deprecated name after the PriorityClass that owns it has
+	// been renamed.
+	resolvedAliasAnnotationKey = "priority.admission.k8s.io/resolved-alias"
 )
 
 // Register registers a plugin
 func Register(plugins *admission.Plugins) {
 	plugins.Register(PluginName, func(config io.Reader) (admission.Interface, error) {
-		return newPlugin(), nil
+		loadedConfig, err := LoadConfiguration(config)
+		if err != nil {
+			return nil, err
+		}
+		if errs := validation.ValidateConfiguration(loadedConfig); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid %s configuration: %v", PluginName, errs.ToAggregate())
+		}
+		return newPlugin(toPluginConfig(loadedConfig)), nil
 	})
 }
 
+// toPluginConfig adapts a versioned, validated priorityapi.Configuration into the pluginConfig
+// shape the rest of this file works with, so newPlugin does not need to know about the versioned
+// API on top of it.
+func toPluginConfig(config *priorityapi.Configuration) *pluginConfig {
+	if config == nil {
+		return &pluginConfig{}
+	}
+	c := &pluginConfig{
+		PriorityClassAliases:                config.PriorityClassAliases,
+		DowngradePriorityClassName:          config.DowngradePriorityClassName,
+		MaxGlobalDefaultValue:               config.MaxGlobalDefaultValue,
+		MaxPriorityClasses:                  config.MaxPriorityClasses,
+		NamespacePriorityClassAllowList:     config.NamespacePriorityClassAllowList,
+		RequiredNodeAffinityByPriorityClass: config.RequiredNodeAffinityByPriorityClass,
+		LiveLookupOnCacheMiss:               config.LiveLookupOnCacheMiss,
+		LiveLookupTimeout:                   config.LiveLookupTimeout,
+		PriorityClassPodQuota:               config.PriorityClassPodQuota,
+	}
+	if config.CandidateConfig != nil {
+		c.CandidateConfig = toPluginConfig(config.CandidateConfig)
+	}
+	for _, band := range config.PriorityBands {
+		c.PriorityBands = append(c.PriorityBands, PriorityBand{Name: band.Name, Threshold: band.Threshold})
+	}
+	for _, limit := range config.PriorityClassBandLimits {
+		c.PriorityClassBandLimits = append(c.PriorityClassBandLimits, PriorityClassBandLimit{Threshold: limit.Threshold, Max: limit.Max})
+	}
+	return c
+}
+
+// pluginConfig holds the internal configuration accepted by the Priority admission plugin, as
+// loaded and validated from the versioned priority.admission.k8s.io API by LoadConfiguration and
+// validation.ValidateConfiguration.
+type pluginConfig struct {
+	// PriorityClassAliases maps a legacy or deprecated PriorityClassName to the canonical
+	// PriorityClassName it should be rewritten to at admission time. This eases large-scale
+	// PriorityClass renames without requiring every workload to be updated in lock-step.
+	PriorityClassAliases map[string]string
+	// DowngradePriorityClassName, if set, is the PriorityClassName a pod is admitted with
+	// instead of being rejected when it requests a PriorityClassName it is not permitted to use
+	// (e.g. a system priority requested outside kube-system). Leave empty to reject as before.
+	DowngradePriorityClassName string
+	// PriorityBands, if set, causes every admitted pod to be labeled with the name of the
+	// highest band whose Threshold its resolved priority meets or exceeds. Bands do not need to
+	// be supplied in any particular order.
+	PriorityBands []PriorityBand
+	// MaxGlobalDefaultValue, if non-zero, is the highest Value a PriorityClass may have while
+	// also setting GlobalDefault. This guards against a single mistaken apply of an
+	// otherwise-legitimate high-value PriorityClass silently making every new, unclassed pod in
+	// the cluster priority-critical.
+	MaxGlobalDefaultValue int32
+	// CandidateConfig, if set, holds a proposed configuration that is evaluated against every
+	// pod alongside the configuration above, but never enforced: the candidate's own
+	// PriorityClassAliases, DowngradePriorityClassName, PriorityBands and MaxGlobalDefaultValue
+	// are resolved against a copy of the pod, and the outcome is compared to what was actually
+	// enforced. Any pod for which the candidate would have made a different decision is recorded
+	// via a metric and an audit annotation, so operators can validate that a stricter or
+	// otherwise different policy is safe before promoting it to be the active configuration.
+	CandidateConfig *pluginConfig
+	// MaxPriorityClasses, if non-zero, is the maximum number of PriorityClass objects allowed to
+	// exist cluster-wide. Creating another PriorityClass once the cluster is already at this
+	// limit is rejected, since schedulers and dashboards that enumerate PriorityClasses degrade
+	// once a cluster accumulates thousands of them.
+	MaxPriorityClasses int32
+	// PriorityClassBandLimits, if set, further caps the number of PriorityClass objects whose
+	// Value falls within each configured band, so a single value range cannot alone be used to
+	// exhaust the overall MaxPriorityClasses limit. Bands do not need to be supplied in any
+	// particular order.
+	PriorityClassBandLimits []PriorityClassBandLimit
+	// NamespacePriorityClassAllowList, if set, restricts which PriorityClassNames pods in a given
+	// namespace may use. A namespace with no entry in this map is unrestricted (subject only to
+	// the existing system-priority-class check). A namespace with an entry may only use the
+	// PriorityClassNames listed for it.
+	NamespacePriorityClassAllowList map[string][]string
+	// RequiredNodeAffinityByPriorityClass, if set, causes every pod resolved to a given
+	// PriorityClassName to have the configured NodeSelector merged into its required node
+	// affinity at admission time, guaranteeing placement policy (e.g. onto a dedicated or
+	// control-plane node pool) for critical pods without relying on every manifest author to
+	// remember to set it themselves. See injectRequiredNodeAffinity for the merge semantics.
+	RequiredNodeAffinityByPriorityClass map[string]api.NodeSelector
+	// LiveLookupOnCacheMiss, if true, causes a PriorityClassName that the informer cache reports
+	// as NotFound to be looked up again with a live read against the API server before the pod
+	// is rejected, tolerating the informer cache lag that follows a PriorityClass having just
+	// been created. Leave false to reject on the cache miss alone, as before.
+	LiveLookupOnCacheMiss bool
+	// LiveLookupTimeout bounds the live lookup enabled by LiveLookupOnCacheMiss, so a slow
+	// apiserver cannot add unbounded latency to pod admission. Defaults to 1 second if unset.
+	LiveLookupTimeout metav1.Duration
+	// PriorityClassPodQuota, if set, maps a PriorityClassName to the maximum number of
+	// non-terminal pods a namespace may have using that PriorityClassName at once. A pod that
+	// would exceed its namespace's quota is admitted but gated rather than rejected; see
+	// gatePodForPriorityQuota.
+	PriorityClassPodQuota map[string]int32
+	// NamespacePolicyPrefixesToStrip, if set, rewrites the namespace this plugin uses to evaluate
+	// NamespacePriorityClassAllowList and the defaultPriorityClass/maxPriorityValue
+	// NamespaceParamsAnnotation parameters: the first entry that is a proper prefix of a pod's
+	// actual namespace is stripped before the lookup. This lets a virtual-cluster projection of a
+	// namespace (e.g. a vcluster tenant namespace named "vc-tenant-a-default", projecting the
+	// tenant's own "default" namespace) enforce the same per-tenant priority policy as its
+	// physical namespace, without requiring the policy objects themselves to be duplicated for
+	// every projection. A namespace matching no configured prefix, or one whose stripped result
+	// would be empty, is evaluated under its own name as before.
+	NamespacePolicyPrefixesToStrip []string
+	// CriticalPodAnnotationClassMapping, if set, extends the legacy ExperimentalCriticalPodAnnotation
+	// backwards-compatibility path (see resolvePod) beyond its historical hard-coded restriction to
+	// the "kube-system" namespace and the single CriticalPodAnnotationKey annotation. Each entry is
+	// tried in order against a pod with no PriorityClassName set; the first whose NamespacePattern
+	// matches the pod's namespace and whose Annotation is present with an empty value on the pod
+	// resolves the pod to ClassName. A pod that matches no entry here still falls back to the
+	// original kube-system/CriticalPodAnnotationKey check, resolving to SystemClusterCritical.
+	CriticalPodAnnotationClassMapping []CriticalPodAnnotationMapping
+}
+
+// CriticalPodAnnotationMapping configures one legacy-annotation-to-PriorityClassName rule for
+// CriticalPodAnnotationClassMapping.
+type CriticalPodAnnotationMapping struct {
+	// NamespacePattern is matched against a pod's namespace using shell file-name globbing
+	// (path.Match syntax), e.g. "kube-*" or "*".
+	NamespacePattern string
+	// Annotation is the annotation key that marks a pod in a matching namespace as critical, in
+	// the same "present with an empty value" sense as the legacy CriticalPodAnnotationKey.
+	Annotation string
+	// ClassName is the PriorityClassName a matching pod is resolved to.
+	ClassName string
+}
+
+// PriorityClassBandLimit caps the number of PriorityClass objects whose Value is at or above
+// Threshold, up to (but not including) the next-higher configured Threshold.
+type PriorityClassBandLimit struct {
+	// Threshold is the inclusive lower bound of Value this limit applies to.
+	Threshold int32
+	// Max is the maximum number of PriorityClass objects allowed with Value in this band.
+	Max int32
+}
+
+// PriorityBand names a lower bound on pod priority for the purpose of labeling pods with
+// priorityBandLabel.
+type PriorityBand struct {
+	// Name is the value the priorityBandLabel is set to for pods in this band.
+	Name string
+	// Threshold is the minimum resolved priority a pod must have to be considered part of this
+	// band.
+	Threshold int32
+}
+
+// readConfig reads the Priority plugin configuration from the file provided with
+// --admission-control-config-file. If the file is not supplied, it defaults to an empty config.
+// Decoding is strict: an unrecognized field is an error rather than being silently dropped, since
+// a typo'd field name would otherwise leave the corresponding policy silently disabled.
+func readConfig(config io.Reader) (*pluginConfig, error) {
+	parsedConfig := &pluginConfig{}
+	if config == nil || reflect.ValueOf(config).IsNil() {
+		return parsedConfig, nil
+	}
+	raw, err := ioutil.ReadAll(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s configuration: %v", PluginName, err)
+	}
+	if len(raw) == 0 {
+		return parsedConfig, nil
+	}
+	if err := yaml.UnmarshalStrict(raw, parsedConfig); err != nil {
+		return nil, fmt.Errorf("failed to decode %s configuration: %v", PluginName, err)
+	}
+	if err := parsedConfig.validate(""); err != nil {
+		return nil, fmt.Errorf("invalid %s configuration: %v", PluginName, err)
+	}
+	return parsedConfig, nil
+}
+
+// ValidateConfig parses and validates a Priority plugin configuration exactly as Register would,
+// without constructing a plugin from it, so a --validate-admission-config style dry run can catch
+// a bad configuration before it is ever loaded by a running apiserver.
+func ValidateConfig(config io.Reader) error {
+	_, err := readConfig(config)
+	return err
+}
+
+// validate checks c for structural problems readConfig's strict decoding cannot catch on its
+// own, such as duplicate or negative values, returning a path-based error message identifying
+// which field is at fault. path is the dotted field path of c itself, "" for the top-level
+// config and "candidateConfig" for a nested CandidateConfig.
+func (c *pluginConfig) validate(path string) error {
+	field := func(name string) string {
+		if path == "" {
+			return name
+		}
+		return path + "." + name
+	}
+
+	seenBandNames := map[string]bool{}
+	seenBandThresholds := map[int32]bool{}
+	for i, band := range c.PriorityBands {
+		if band.Name == "" {
+			return fmt.Errorf("%s[%d].name must not be empty", field("priorityBands"), i)
+		}
+		if seenBandNames[band.Name] {
+			return fmt.Errorf("%s[%d].name: duplicate PriorityBand name %q", field("priorityBands"), i, band.Name)
+		}
+		seenBandNames[band.Name] = true
+		if seenBandThresholds[band.Threshold] {
+			return fmt.Errorf("%s[%d].threshold: duplicate PriorityBand threshold %d", field("priorityBands"), i, band.Threshold)
+		}
+		seenBandThresholds[band.Threshold] = true
+	}
+
+	if c.MaxGlobalDefaultValue < 0 {
+		return fmt.Errorf("%s must not be negative", field("maxGlobalDefaultValue"))
+	}
+	if c.MaxPriorityClasses < 0 {
+		return fmt.Errorf("%s must not be negative", field("maxPriorityClasses"))
+	}
+
+	seenLimitThresholds := map[int32]bool{}
+	for i, limit := range c.PriorityClassBandLimits {
+		if limit.Max < 0 {
+			return fmt.Errorf("%s[%d].max must not be negative", field("priorityClassBandLimits"), i)
+		}
+		if seenLimitThresholds[limit.Threshold] {
+			return fmt.Errorf("%s[%d].threshold: duplicate PriorityClassBandLimit threshold %d", field("priorityClassBandLimits"), i, limit.Threshold)
+		}
+		seenLimitThresholds[limit.Threshold] = true
+	}
+
+	for namespace, allowed := range c.NamespacePriorityClassAllowList {
+		if len(allowed) == 0 {
+			return fmt.Errorf("%s[%q] must not be empty; omit the namespace instead of allowing zero PriorityClassNames", field("namespacePriorityClassAllowList"), namespace)
+		}
+	}
+
+	if c.LiveLookupTimeout.Duration < 0 {
+		return fmt.Errorf("%s must not be negative", field("liveLookupTimeout"))
+	}
+
+	if c.CandidateConfig != nil {
+		if err := c.CandidateConfig.validate(field("candidateConfig")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // priorityPlugin is an implementation of admission.Interface.
 type priorityPlugin struct {
 	*admission.Handler
-	client kubernetes.Interface
-	lister schedulingv1listers.PriorityClassLister
+	client                              kubernetes.Interface
+	lister                              schedulingv1listers.PriorityClassLister
+	namespaceLister                     corev1listers.NamespaceLister
+	podLister                           corev1listers.PodLister
+	priorityClassAliases                map[string]string
+	downgradePriorityClassName          string
+	priorityBands                       []PriorityBand
+	maxGlobalDefaultValue               int32
+	maxPriorityClasses                  int32
+	priorityClassBandLimits             []PriorityClassBandLimit
+	namespacePriorityClassAllowList     map[string][]string
+	requiredNodeAffinityByPriorityClass map[string]api.NodeSelector
+	liveLookupOnCacheMiss               bool
+	liveLookupTimeout                   time.Duration
+	priorityClassPodQuota               map[string]int32
+	namespacePolicyPrefixesToStrip      []string
+	criticalPodAnnotationClassMapping   []CriticalPodAnnotationMapping
+
+	// shadow, if non-nil, holds a second priorityPlugin built from a CandidateConfig, whose
+	// decisions are computed for every pod but never enforced. See admitPod and evaluateShadow.
+	shadow *priorityPlugin
+
+	// isShadow is true for a priorityPlugin reachable only via another priorityPlugin's shadow
+	// field. Its resolvePod calls are speculative and never enforced, so they must not be
+	// counted in the admission-decision metrics recorded by recordAdmissionDecision.
+	isShadow bool
 }
 
 var _ admission.MutationInterface = &priorityPlugin{}
@@ -61,10 +379,50 @@ var _ = genericadmissioninitializers.WantsExternalKubeInformerFactory(&priorityP
 var _ = genericadmissioninitializers.WantsExternalKubeClientSet(&priorityPlugin{})
 
 // NewPlugin creates a new priority admission plugin.
-func newPlugin() *priorityPlugin {
-	return &priorityPlugin{
-		Handler: admission.NewHandler(admission.Create, admission.Update, admission.Delete),
+func newPlugin(config *pluginConfig) *priorityPlugin {
+	p := &priorityPlugin{
+		Handler:                             admission.NewHandler(admission.Create, admission.Update, admission.Delete),
+		priorityClassAliases:                config.PriorityClassAliases,
+		downgradePriorityClassName:          config.DowngradePriorityClassName,
+		priorityBands:                       config.PriorityBands,
+		maxGlobalDefaultValue:               config.MaxGlobalDefaultValue,
+		maxPriorityClasses:                  config.MaxPriorityClasses,
+		priorityClassBandLimits:             config.PriorityClassBandLimits,
+		namespacePriorityClassAllowList:     config.NamespacePriorityClassAllowList,
+		requiredNodeAffinityByPriorityClass: config.RequiredNodeAffinityByPriorityClass,
+		liveLookupOnCacheMiss:               config.LiveLookupOnCacheMiss,
+		liveLookupTimeout:                   config.LiveLookupTimeout.Duration,
+		priorityClassPodQuota:               config.PriorityClassPodQuota,
+		namespacePolicyPrefixesToStrip:      config.NamespacePolicyPrefixesToStrip,
+		criticalPodAnnotationClassMapping:   config.CriticalPodAnnotationClassMapping,
+	}
+	if p.liveLookupTimeout == 0 {
+		p.liveLookupTimeout = time.Second
 	}
+	if config.CandidateConfig != nil {
+		p.shadow = newPlugin(config.CandidateConfig)
+		p.shadow.isShadow = true
+	}
+	return p
+}
+
+// priorityBandFor returns the name of the highest configured PriorityBand whose Threshold is at
+// or below priority, or "" if no configured band applies.
+func (p *priorityPlugin) priorityBandFor(priority int32) string {
+	var band string
+	var bandThreshold int32
+	haveBand := false
+	for _, b := range p.priorityBands {
+		if priority < b.Threshold {
+			continue
+		}
+		if !haveBand || b.Threshold > bandThreshold {
+			band = b.Name
+			bandThreshold = b.Threshold
+			haveBand = true
+		}
+	}
+	return band
 }
 
 // ValidateInitialization implements the InitializationValidator interface.
@@ -75,26 +433,102 @@ func (p *priorityPlugin) ValidateInitialization() error {
 	if p.lister == nil {
 		return fmt.Errorf("%s requires a lister", PluginName)
 	}
+	if p.namespaceLister == nil {
+		return fmt.Errorf("%s requires a namespace lister", PluginName)
+	}
+	if p.podLister == nil {
+		return fmt.Errorf("%s requires a pod lister", PluginName)
+	}
+	if p.shadow != nil {
+		return p.shadow.ValidateInitialization()
+	}
 	return nil
 }
 
 // SetInternalKubeClientSet implements the WantsInternalKubeClientSet interface.
 func (p *priorityPlugin) SetExternalKubeClientSet(client kubernetes.Interface) {
 	p.client = client
+	if p.shadow != nil {
+		p.shadow.SetExternalKubeClientSet(client)
+	}
 }
 
 // SetInternalKubeInformerFactory implements the WantsInternalKubeInformerFactory interface.
 func (p *priorityPlugin) SetExternalKubeInformerFactory(f informers.SharedInformerFactory) {
 	priorityInformer := f.Scheduling().V1().PriorityClasses()
 	p.lister = priorityInformer.Lister()
-	p.SetReadyFunc(priorityInformer.Informer().HasSynced)
+
+	namespaceInformer := f.Core().V1().Namespaces()
+	p.namespaceLister = namespaceInformer.Lister()
+
+	podInformer := f.Core().V1().Pods()
+	p.podLister = podInformer.Lister()
+
+	if p.shadow != nil {
+		p.shadow.lister = p.lister
+		p.shadow.namespaceLister = p.namespaceLister
+		p.shadow.podLister = p.podLister
+	}
+
+	// Inventory metrics describe the actual PriorityClass objects in the cluster, not a
+	// particular configuration's admission decisions, so they are only republished from the
+	// active plugin, not from p.shadow.
+	if !p.isShadow {
+		priorityInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { p.observePriorityClassInventory() },
+			UpdateFunc: func(oldObj, newObj interface{}) { p.observePriorityClassInventory() },
+			DeleteFunc: func(obj interface{}) { p.observePriorityClassInventory() },
+		})
+	}
+
+	p.SetReadyFunc(func() bool {
+		return priorityInformer.Informer().HasSynced() && namespaceInformer.Informer().HasSynced() && podInformer.Informer().HasSynced()
+	})
+}
+
+// observePriorityClassInventory relists every PriorityClass known to p.lister and republishes
+// the priority_class_inventory and global-default gauges from it.
+func (p *priorityPlugin) observePriorityClassInventory() {
+	classes, err := p.lister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Priority admission plugin: failed to list PriorityClasses for inventory metrics: %v", err)
+		return
+	}
+	observePriorityClassInventory(classes, p.priorityBandFor)
 }
 
 var (
 	podResource           = api.Resource("pods")
+	podTemplateResource   = api.Resource("podtemplates")
 	priorityClassResource = scheduling.Resource("priorityclasses")
+	namespaceResource     = api.Resource("namespaces")
+
+	// workloadPodTemplateResources are the workload-controller resources whose embedded pod
+	// template PriorityClassName is validated by validateWorkloadPodTemplate, so a user gets
+	// immediate feedback for a bad reference instead of only finding out once the controller
+	// tries and fails to create pods from it.
+	workloadPodTemplateResources = map[schema.GroupResource]bool{
+		apps.Resource("deployments"):  true,
+		apps.Resource("replicasets"):  true,
+		apps.Resource("statefulsets"): true,
+		apps.Resource("daemonsets"):   true,
+		batch.Resource("jobs"):        true,
+		batch.Resource("cronjobs"):    true,
+	}
 )
 
+// namespaceTerminating returns true if namespace is in the process of being deleted. It fails
+// open (returns false) if the namespace cannot be looked up, since the normal namespace-lifecycle
+// admission plugin is responsible for actually blocking pod creation in a terminating namespace;
+// this check only exists to skip optional work for pods that slip through.
+func (p *priorityPlugin) namespaceTerminating(namespace string) bool {
+	ns, err := p.namespaceLister.Get(namespace)
+	if err != nil {
+		return false
+	}
+	return ns.DeletionTimestamp != nil
+}
+
 // Admit checks Pods and admits or rejects them. It also resolves the priority of pods based on their PriorityClass.
 // Note that pod validation mechanism prevents update of a pod priority.
 func (p *priorityPlugin) Admit(a admission.Attributes, o admission.ObjectInterfaces) error {
@@ -115,6 +549,12 @@ func (p *priorityPlugin) Admit(a admission.Attributes, o admission.ObjectInterfa
 		}
 		return nil
 
+	case podTemplateResource:
+		if operation == admission.Create {
+			return p.admitPodTemplate(a)
+		}
+		return nil
+
 	default:
 		return nil
 	}
@@ -133,35 +573,478 @@ func (p *priorityPlugin) Validate(a admission.Attributes, o admission.ObjectInte
 		if operation == admission.Create || operation == admission.Update {
 			return p.validatePriorityClass(a)
 		}
+		if operation == admission.Delete {
+			return p.validatePriorityClassDeletion(a)
+		}
+		return nil
+
+	case namespaceResource:
+		if operation == admission.Update {
+			return p.validateNamespaceUpdate(a)
+		}
 		return nil
 
 	default:
+		if workloadPodTemplateResources[a.GetResource().GroupResource()] {
+			if operation == admission.Create || operation == admission.Update {
+				return p.validateWorkloadPodTemplate(a)
+			}
+		}
 		return nil
 	}
 }
 
-// priorityClassPermittedInNamespace returns true if we allow the given priority class name in the
-// given namespace. It currently checks that system priorities are created only in the system namespace.
-func priorityClassPermittedInNamespace(priorityClassName string, namespace string) bool {
-	// Only allow system priorities in the system namespace. This is to prevent abuse or incorrect
-	// usage of these priorities. Pods created at these priorities could preempt system critical
-	// components.
-	for _, spc := range scheduling.SystemPriorityClasses() {
-		if spc.Name == priorityClassName && namespace != metav1.NamespaceSystem {
-			return false
+// workloadPodTemplateSpec returns a pointer to the embedded api.PodTemplateSpec within obj, or
+// nil if obj is not one of the resources listed in workloadPodTemplateResources. It returns a
+// pointer, rather than a copy, so callers cannot accidentally believe a mutation of the result
+// will be persisted: validateWorkloadPodTemplate only reads from it.
+func workloadPodTemplateSpec(obj runtime.Object) *api.PodTemplateSpec {
+	switch o := obj.(type) {
+	case *apps.Deployment:
+		return &o.Spec.Template
+	case *apps.ReplicaSet:
+		return &o.Spec.Template
+	case *apps.StatefulSet:
+		return &o.Spec.Template
+	case *apps.DaemonSet:
+		return &o.Spec.Template
+	case *batch.Job:
+		return &o.Spec.Template
+	case *batch.CronJob:
+		return &o.Spec.JobTemplate.Spec.Template
+	default:
+		return nil
+	}
+}
+
+// validateWorkloadPodTemplate rejects a Deployment, ReplicaSet, StatefulSet, DaemonSet, Job or
+// CronJob whose embedded pod template references a PriorityClassName that does not exist or is
+// not permitted in the object's namespace, so users find out immediately rather than only once
+// the controller tries and fails to create pods from it. Unlike admitPod and admitPodTemplate,
+// this does not resolve or default the PriorityClassName: the workload's pod template is not
+// itself a pod, and the same defaulting, aliasing and downgrade logic will run again, and can
+// still change the outcome, when a pod is actually created from it.
+func (p *priorityPlugin) validateWorkloadPodTemplate(a admission.Attributes) error {
+	template := workloadPodTemplateSpec(a.GetObject())
+	if template == nil || injectConversionFault() {
+		return errors.NewBadRequest(fmt.Sprintf("resource was marked with kind %v but was unable to be converted", a.GetKind().Kind))
+	}
+
+	pcName := template.Spec.PriorityClassName
+	if len(pcName) == 0 {
+		return nil
+	}
+	pcName = p.resolveAlias(a, pcName)
+
+	if !p.priorityClassPermittedInNamespace(pcName, a.GetNamespace()) && p.downgradePriorityClassName == "" {
+		return admission.NewForbidden(a, fmt.Errorf("pods with %v priorityClass is not permitted in %v namespace", pcName, a.GetNamespace()))
+	}
+
+	if _, err := p.lister.Get(pcName); err != nil {
+		if errors.IsNotFound(err) {
+			return admission.NewForbidden(a, fmt.Errorf("no PriorityClass with name %v was found", pcName))
 		}
+		return fmt.Errorf("failed to get PriorityClass with name %s: %v", pcName, err)
+	}
+	return nil
+}
+
+// validateNamespaceUpdate rejects changes to a terminating namespace's Priority admission policy
+// parameters (see kubeapiserveradmission.NamespaceParamsAnnotation). Changing policy for a
+// namespace that is already being torn down cannot affect anything but adds another mutation for
+// operators to reason about during mass teardown, so it is rejected outright.
+func (p *priorityPlugin) validateNamespaceUpdate(a admission.Attributes) error {
+	oldNs, ok := a.GetOldObject().(*api.Namespace)
+	if !ok {
+		return errors.NewBadRequest("resource was marked with kind Namespace but was unable to be converted")
+	}
+	if oldNs.DeletionTimestamp == nil {
+		return nil
 	}
-	return true
+	newNs, ok := a.GetObject().(*api.Namespace)
+	if !ok {
+		return errors.NewBadRequest("resource was marked with kind Namespace but was unable to be converted")
+	}
+	if namespacePriorityParams(oldNs) != namespacePriorityParams(newNs) {
+		return admission.NewForbidden(a, fmt.Errorf("namespace %v is terminating; its %v admission policy parameters can no longer be changed", oldNs.Name, PluginName))
+	}
+	return nil
+}
+
+// namespacePriorityParams returns a canonical JSON encoding of the Priority plugin's parameters
+// from ns's NamespaceParamsAnnotation, or "" if none are set.
+func namespacePriorityParams(ns *api.Namespace) string {
+	raw, ok := ns.Annotations[kubeapiserveradmission.NamespaceParamsAnnotation]
+	if !ok {
+		return ""
+	}
+	var byPlugin map[string]map[string]string
+	if err := json.Unmarshal([]byte(raw), &byPlugin); err != nil {
+		return raw
+	}
+	encoded, err := json.Marshal(byPlugin[PluginName])
+	if err != nil {
+		return raw
+	}
+	return string(encoded)
+}
+
+// forcePriorityClassDeleteAnnotation, when set to "true" on a PriorityClass, allows that
+// PriorityClass to be deleted even while pods still reference it by name. Without it,
+// validatePriorityClassDeletion rejects the delete so pods aren't silently left with a stale,
+// nonexistent PriorityClassName.
+const forcePriorityClassDeleteAnnotation = "scheduling.k8s.io/force-delete"
+
+// validatePriorityClassDeletion rejects deleting a PriorityClass that is still referenced by any
+// pod's PriorityClassName, unless the PriorityClass carries forcePriorityClassDeleteAnnotation.
+// It fails open (allows the delete) if the PriorityClass itself can no longer be found, since
+// there is nothing left to protect pods from a reference to.
+func (p *priorityPlugin) validatePriorityClassDeletion(a admission.Attributes) error {
+	pc, err := p.lister.Get(a.GetName())
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get PriorityClass with name %s: %v", a.GetName(), err)
+	}
+	if pc.Annotations[forcePriorityClassDeleteAnnotation] == "true" {
+		return nil
+	}
+
+	pods, err := p.podLister.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %v", err)
+	}
+	for _, pod := range pods {
+		if pod.Spec.PriorityClassName == a.GetName() {
+			return admission.NewForbidden(a, fmt.Errorf("cannot delete PriorityClass %v: it is still referenced by pod %v/%v; set the %v annotation to force deletion", a.GetName(), pod.Namespace, pod.Name, forcePriorityClassDeleteAnnotation))
+		}
+	}
+	return nil
+}
+
+// policyNamespace returns the namespace this plugin should use to evaluate namespace-scoped
+// policy for a pod actually created in namespace, applying the first configured
+// NamespacePolicyPrefixesToStrip entry that is a proper prefix of namespace. It leaves namespace
+// unchanged if no configured prefix matches, or if stripping the matched prefix would leave
+// nothing behind.
+func (p *priorityPlugin) policyNamespace(namespace string) string {
+	for _, prefix := range p.namespacePolicyPrefixesToStrip {
+		if stripped := strings.TrimPrefix(namespace, prefix); stripped != namespace && stripped != "" {
+			return stripped
+		}
+	}
+	return namespace
+}
+
+// namespaceDefaultPriorityClassParam is the key, within the Priority plugin's section of
+// NamespaceParamsAnnotation, of a PriorityClassName that overrides the cluster-wide GlobalDefault
+// for pods created in that namespace without an explicit PriorityClassName. This lets a
+// multi-tenant cluster give different tenants different default priorities without requiring a
+// mutating webhook per tenant.
+const namespaceDefaultPriorityClassParam = "defaultPriorityClass"
+
+// namespaceDefaultPriorityClass returns the PriorityClassName configured as namespace's (or, per
+// policyNamespace, its physical namespace's) default via NamespaceParamsAnnotation, or "" if the
+// namespace cannot be looked up or does not configure one. It fails open for the same reason
+// namespaceTerminating does: a missing or unparsable namespace should not by itself block pod
+// admission.
+func (p *priorityPlugin) namespaceDefaultPriorityClass(namespace string) string {
+	ns, err := p.namespaceLister.Get(p.policyNamespace(namespace))
+	if err != nil {
+		return ""
+	}
+	raw, ok := ns.Annotations[kubeapiserveradmission.NamespaceParamsAnnotation]
+	if !ok {
+		return ""
+	}
+	var byPlugin map[string]map[string]string
+	if err := json.Unmarshal([]byte(raw), &byPlugin); err != nil {
+		return ""
+	}
+	return byPlugin[PluginName][namespaceDefaultPriorityClassParam]
+}
+
+// namespacePriorityCeilingParam is the key, within the Priority plugin's section of
+// NamespaceParamsAnnotation, of the highest resolved priority value pods in that namespace may
+// use. It works like a ResourceQuota scoped to priority: application teams can be handed a
+// namespace without being able to self-assign near-system priorities, without a cluster operator
+// having to maintain a single cluster-wide map of every namespace's ceiling.
+const namespacePriorityCeilingParam = "maxPriorityValue"
+
+// namespacePriorityCeiling returns the priority ceiling configured for namespace (or, per
+// policyNamespace, its physical namespace) via NamespaceParamsAnnotation, and whether one is
+// configured at all. It fails open (returns false) if the namespace cannot be looked up or the
+// configured value isn't parseable, for the same reason namespaceTerminating does: a missing or
+// unparsable namespace should not by itself block pod admission.
+func (p *priorityPlugin) namespacePriorityCeiling(namespace string) (int32, bool) {
+	ns, err := p.namespaceLister.Get(p.policyNamespace(namespace))
+	if err != nil {
+		return 0, false
+	}
+	raw, ok := ns.Annotations[kubeapiserveradmission.NamespaceParamsAnnotation]
+	if !ok {
+		return 0, false
+	}
+	var byPlugin map[string]map[string]string
+	if err := json.Unmarshal([]byte(raw), &byPlugin); err != nil {
+		return 0, false
+	}
+	value, ok := byPlugin[PluginName][namespacePriorityCeilingParam]
+	if !ok {
+		return 0, false
+	}
+	ceiling, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return int32(ceiling), true
+}
+
+// resolveAlias rewrites pcName to its canonical PriorityClassName if pcName is a configured
+// alias or is listed in some PriorityClass's own DeprecatedAliases, logging a deprecation
+// warning and recording resolvedAliasAnnotationKey on a so operators can track down and update
+// remaining users of the alias.
+func (p *priorityPlugin) resolveAlias(a admission.Attributes, pcName string) string {
+	canonical, ok := p.priorityClassAliases[pcName]
+	if !ok {
+		canonical, ok = p.resolveObjectAlias(pcName)
+		if !ok {
+			return pcName
+		}
+	}
+	klog.Warningf("PriorityClassName %q is a deprecated alias for %q; update the pod spec to use the canonical name", pcName, canonical)
+	p.recordResolvedAlias(a, pcName)
+	return canonical
+}
+
+// resolveObjectAlias looks for a PriorityClass whose own DeprecatedAliases field lists pcName,
+// returning its canonical name. This is the object-driven counterpart to the admin-configured
+// priorityClassAliases map: it lets a PriorityClass declare its own old names instead of
+// requiring every consumer of this plugin to keep a separate alias map in sync.
+func (p *priorityPlugin) resolveObjectAlias(pcName string) (string, bool) {
+	list, err := p.lister.List(labels.Everything())
+	if err != nil {
+		return "", false
+	}
+	for _, pc := range list {
+		for _, alias := range pc.DeprecatedAliases {
+			if alias == pcName {
+				return pc.Name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// recordResolvedAlias sets resolvedAliasAnnotationKey on the admission request, recording the
+// deprecated alias a pod specified before resolveAlias rewrote it to its canonical name.
+func (p *priorityPlugin) recordResolvedAlias(a admission.Attributes, pcName string) {
+	if err := a.AddAnnotation(resolvedAliasAnnotationKey, pcName); err != nil {
+		klog.Warningf("failed to set admission audit annotation %s to %s: %v", resolvedAliasAnnotationKey, pcName, err)
+	}
+}
+
+// priorityClassPermittedInNamespace returns true if pcName may be used by objects created in
+// namespace. It first applies the built-in system-priority-class restriction shared with
+// everything else that reproduces this plugin's namespace policy, and then, if namespace's
+// policyNamespace has a configured NamespacePriorityClassAllowList entry, further restricts it to
+// that list.
+func (p *priorityPlugin) priorityClassPermittedInNamespace(pcName, namespace string) bool {
+	if !scheduling.PriorityClassPermittedInNamespace(pcName, namespace) {
+		return false
+	}
+	allowList, ok := p.namespacePriorityClassAllowList[p.policyNamespace(namespace)]
+	if !ok {
+		return true
+	}
+	for _, allowed := range allowList {
+		if allowed == pcName {
+			return true
+		}
+	}
+	return false
 }
 
 // admitPod makes sure a new pod does not set spec.Priority field. It also makes sure that the PriorityClassName exists if it is provided and resolves the pod priority from the PriorityClassName.
 func (p *priorityPlugin) admitPod(a admission.Attributes) error {
-	operation := a.GetOperation()
 	pod, ok := a.GetObject().(*api.Pod)
-	if !ok {
+	if !ok || injectConversionFault() {
 		return errors.NewBadRequest("resource was marked with kind Pod but was unable to be converted")
 	}
 
+	// Bound every lookup resolvePod performs (currently just getPriorityClass's live-client
+	// fallback, but also the natural place to hang future work like recording an Event against
+	// the PriorityClass) to liveLookupTimeout, so a stuck apiserver or etcd cannot hold this pod's
+	// admission open past the apiserver's own request timeout. ctx is not derived from a.GetContext,
+	// since admission.Attributes in this version carries no request context of its own.
+	ctx, cancel := context.WithTimeout(context.Background(), p.liveLookupTimeout)
+	defer cancel()
+
+	var shadowPod *api.Pod
+	if p.shadow != nil {
+		shadowPod = pod.DeepCopy()
+	}
+	hadPriorityClassName := len(pod.Spec.PriorityClassName) != 0
+
+	start := time.Now()
+	activeErr := p.resolvePod(ctx, a, pod)
+	if a.GetOperation() == admission.Create {
+		elapsed := time.Since(start)
+		p.recordClassDecision(ctx, pod.Spec.PriorityClassName, elapsed)
+		p.recordAdmissionDecision(pod.Spec.PriorityClassName, activeErr, elapsed)
+		if activeErr == nil && !hadPriorityClassName && pod.Spec.PriorityClassName != "" {
+			p.recordDefaultedPriorityClass(a, pod.Spec.PriorityClassName)
+		}
+	}
+
+	if p.shadow != nil {
+		p.evaluateShadow(ctx, a, shadowPod, activeErr, pod)
+	}
+
+	return activeErr
+}
+
+// recordClassDecision instruments how long resolving pcName took (unconditionally, since it is
+// always a cheap informer cache read) and, for a sampled subset of decisions, how many
+// resourceVersions behind that cache was relative to a live read of the same object. Sampling
+// keeps the cost of the latter off the hot path, since it costs an extra apiserver round trip.
+func (p *priorityPlugin) recordClassDecision(ctx context.Context, pcName string, latency time.Duration) {
+	if pcName == "" {
+		return
+	}
+	priorityClassDecisionLatency.WithLabelValues(pcName).Observe(latency.Seconds())
+
+	if p.client == nil || !shouldSampleStaleness() {
+		return
+	}
+	if ctx.Err() != nil {
+		// The request's own deadline (see admitPod) has already passed; don't spend any more of
+		// the apiserver's time on a live read that only feeds a sampled metric.
+		return
+	}
+	cached, err := p.lister.Get(pcName)
+	if err != nil {
+		return
+	}
+	live, err := p.client.SchedulingV1().PriorityClasses().Get(pcName, metav1.GetOptions{})
+	if err != nil {
+		return
+	}
+	if diff, ok := resourceVersionDiff(live.ResourceVersion, cached.ResourceVersion); ok {
+		priorityClassListerStaleness.Observe(diff)
+	}
+}
+
+// recordAdmissionDecision instruments podAdmissionDecisions and podAdmissionLatency for a Create
+// admission decision. It is a no-op for a shadow-evaluated candidate configuration, which never
+// makes a real admission decision (see evaluateShadow).
+func (p *priorityPlugin) recordAdmissionDecision(pcName string, err error, latency time.Duration) {
+	if p.isShadow {
+		return
+	}
+	podAdmissionLatency.Observe(latency.Seconds())
+	decision := "admitted"
+	if err != nil {
+		decision = "rejected"
+	}
+	podAdmissionDecisions.WithLabelValues(pcName, decision).Inc()
+}
+
+// recordDefaultedPriorityClass sets defaultedPriorityClassAnnotationKey on the admission request,
+// recording that pcName was resolved by this plugin rather than specified on the pod.
+func (p *priorityPlugin) recordDefaultedPriorityClass(a admission.Attributes, pcName string) {
+	if err := a.AddAnnotation(defaultedPriorityClassAnnotationKey, pcName); err != nil {
+		klog.Warningf("failed to set admission audit annotation %s to %s: %v", defaultedPriorityClassAnnotationKey, pcName, err)
+	}
+}
+
+// admitPodTemplate defaults and validates the PriorityClassName carried by a PodTemplate's pod
+// spec, so that Jobs and other controllers instantiated from a template fail fast at
+// template-creation time instead of at pod-instantiation time. It reuses resolvePod's Create-path
+// logic by wrapping the template's embedded pod spec in a synthetic Pod, then copies the results
+// back onto the template; PodTemplate specs are immutable after creation, so only Create needs
+// handling here.
+func (p *priorityPlugin) admitPodTemplate(a admission.Attributes) error {
+	podTemplate, ok := a.GetObject().(*api.PodTemplate)
+	if !ok || injectConversionFault() {
+		return errors.NewBadRequest("resource was marked with kind PodTemplate but was unable to be converted")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.liveLookupTimeout)
+	defer cancel()
+
+	pod := &api.Pod{
+		ObjectMeta: podTemplate.Template.ObjectMeta,
+		Spec:       podTemplate.Template.Spec,
+	}
+	if err := p.resolvePod(ctx, a, pod); err != nil {
+		return err
+	}
+	podTemplate.Template.ObjectMeta = pod.ObjectMeta
+	podTemplate.Template.Spec = pod.Spec
+	return nil
+}
+
+// getPriorityClass looks up name in the informer cache and, if the cache reports NotFound and
+// liveLookupOnCacheMiss is enabled, retries once with a live read against the API server bounded
+// by liveLookupTimeout. This tolerates the informer cache lag that follows a PriorityClass having
+// just been created, without letting a slow or unreachable apiserver add unbounded latency to pod
+// admission.
+func (p *priorityPlugin) getPriorityClass(ctx context.Context, name string) (*schedulingv1.PriorityClass, error) {
+	pc, err := p.lister.Get(name)
+	if err == nil || !errors.IsNotFound(err) || !p.liveLookupOnCacheMiss || p.client == nil {
+		return pc, err
+	}
+
+	type result struct {
+		pc  *schedulingv1.PriorityClass
+		err error
+	}
+	resultCh := make(chan result, 1)
+	go func() {
+		pc, err := p.client.SchedulingV1().PriorityClasses().Get(name, metav1.GetOptions{})
+		resultCh <- result{pc, err}
+	}()
+
+	select {
+	case r := <-resultCh:
+		return r.pc, r.err
+	case <-ctx.Done():
+		klog.Warningf("live lookup of PriorityClass %v timed out after %v: %v; falling back to cache miss", name, p.liveLookupTimeout, ctx.Err())
+		return nil, err
+	}
+}
+
+// resolvePod resolves pod's priority in place, enforcing this plugin's configuration. It is
+// shared, unmodified, by both the active configuration and any shadow-evaluated candidate
+// configuration (see evaluateShadow); it must not depend on or mutate anything outside of pod.
+// ctx bounds any lookup resolvePod performs against a live client (currently just
+// getPriorityClass's cache-miss fallback) to the deadline admitPod/admitPodTemplate set up.
+// criticalPodClassFor returns the PriorityClassName that a pod with no PriorityClassName set,
+// carrying annotations in namespace, should be resolved to under the legacy
+// ExperimentalCriticalPodAnnotation backwards-compatibility path, and whether any rule matched at
+// all. It first tries p.criticalPodAnnotationClassMapping in order, then falls back to the
+// original kube-system/CriticalPodAnnotationKey check.
+func (p *priorityPlugin) criticalPodClassFor(namespace string, annotations map[string]string) (string, bool) {
+	for _, m := range p.criticalPodAnnotationClassMapping {
+		matched, err := path.Match(m.NamespacePattern, namespace)
+		if err != nil || !matched {
+			continue
+		}
+		if val, ok := annotations[m.Annotation]; ok && val == "" {
+			return m.ClassName, true
+		}
+	}
+	if kubelettypes.IsCritical(namespace, annotations) {
+		return scheduling.SystemClusterCritical, true
+	}
+	return "", false
+}
+
+func (p *priorityPlugin) resolvePod(ctx context.Context, a admission.Attributes, pod *api.Pod) error {
+	operation := a.GetOperation()
+
 	if operation == admission.Update {
 		oldPod, ok := a.GetOldObject().(*api.Pod)
 		if !ok {
@@ -174,6 +1057,10 @@ func (p *priorityPlugin) admitPod(a admission.Attributes) error {
 		if pod.Spec.Priority == nil && oldPod.Spec.Priority != nil {
 			pod.Spec.Priority = oldPod.Spec.Priority
 		}
+		// NOTE: PodSpec and PriorityClass in this API version have no PreemptionPolicy field to
+		// default here (that field, and the "preemptionPolicy: Never" PriorityClass option, were
+		// introduced in a later Kubernetes release than this tree tracks). Once that API lands,
+		// its value should be copied forward from oldPod the same way Priority is above.
 		return nil
 	}
 
@@ -182,28 +1069,63 @@ func (p *priorityPlugin) admitPod(a admission.Attributes) error {
 		// TODO: @ravig - This is for backwards compatibility to ensure that critical pods with annotations just work fine.
 		// Remove when no longer needed.
 		if len(pod.Spec.PriorityClassName) == 0 &&
-			utilfeature.DefaultFeatureGate.Enabled(features.ExperimentalCriticalPodAnnotation) &&
-			kubelettypes.IsCritical(a.GetNamespace(), pod.Annotations) {
-			pod.Spec.PriorityClassName = scheduling.SystemClusterCritical
+			utilfeature.DefaultFeatureGate.Enabled(features.ExperimentalCriticalPodAnnotation) {
+			if className, ok := p.criticalPodClassFor(a.GetNamespace(), pod.Annotations); ok {
+				pod.Spec.PriorityClassName = className
+			}
 		}
 		if len(pod.Spec.PriorityClassName) == 0 {
-			var err error
-			var pcName string
-			pcName, priority, err = p.getDefaultPriority()
-			if err != nil {
-				return fmt.Errorf("failed to get default priority class: %v", err)
+			if p.namespaceTerminating(a.GetNamespace()) {
+				// The namespace is being torn down, so the pod being force-created into it
+				// (e.g. by a controller finalizing cleanup) will not run long enough for its
+				// resolved priority to matter. Skip the default-priority-class lookup, which
+				// lists every PriorityClass in the cluster, rather than doing that work for
+				// every pod created during a mass namespace teardown.
+				priority = scheduling.DefaultPriorityWhenNoDefaultClassExists
+			} else if nsDefault := p.namespaceDefaultPriorityClass(a.GetNamespace()); nsDefault != "" {
+				pc, err := p.lister.Get(nsDefault)
+				if err != nil {
+					if errors.IsNotFound(err) {
+						if !p.isShadow {
+							missingPriorityClassRejections.Inc()
+						}
+						return admission.NewForbidden(a, fmt.Errorf("namespace %v configures %v as its default PriorityClass, but no PriorityClass with that name was found", a.GetNamespace(), nsDefault))
+					}
+					return fmt.Errorf("failed to get PriorityClass with name %s: %v", nsDefault, err)
+				}
+				pod.Spec.PriorityClassName = nsDefault
+				priority = pc.Value
+			} else {
+				var err error
+				var pcName string
+				pcName, priority, err = p.getDefaultPriority()
+				if err != nil {
+					return fmt.Errorf("failed to get default priority class: %v", err)
+				}
+				pod.Spec.PriorityClassName = pcName
 			}
-			pod.Spec.PriorityClassName = pcName
 		} else {
-			pcName := pod.Spec.PriorityClassName
-			if !priorityClassPermittedInNamespace(pcName, a.GetNamespace()) {
-				return admission.NewForbidden(a, fmt.Errorf("pods with %v priorityClass is not permitted in %v namespace", pcName, a.GetNamespace()))
+			pcName := p.resolveAlias(a, pod.Spec.PriorityClassName)
+			if !p.priorityClassPermittedInNamespace(pcName, a.GetNamespace()) {
+				if p.downgradePriorityClassName == "" {
+					return admission.NewForbidden(a, fmt.Errorf("pods with %v priorityClass is not permitted in %v namespace", pcName, a.GetNamespace()))
+				}
+				klog.Warningf("pods with %v priorityClass are not permitted in %v namespace; downgrading pod %v/%v to %v", pcName, a.GetNamespace(), a.GetNamespace(), pod.Name, p.downgradePriorityClassName)
+				pcName = p.downgradePriorityClassName
+			}
+			pod.Spec.PriorityClassName = pcName
+
+			if err := injectListerFault(); err != nil {
+				return err
 			}
 
 			// Try resolving the priority class name.
-			pc, err := p.lister.Get(pod.Spec.PriorityClassName)
+			pc, err := p.getPriorityClass(ctx, pod.Spec.PriorityClassName)
 			if err != nil {
 				if errors.IsNotFound(err) {
+					if !p.isShadow {
+						missingPriorityClassRejections.Inc()
+					}
 					return admission.NewForbidden(a, fmt.Errorf("no PriorityClass with name %v was found", pod.Spec.PriorityClassName))
 				}
 
@@ -211,25 +1133,172 @@ func (p *priorityPlugin) admitPod(a admission.Attributes) error {
 			}
 
 			priority = pc.Value
+			// NOTE: pc has no PreemptionPolicy to default onto pod here; see the update-path
+			// comment in resolvePod above for why.
 		}
+
+		if ceiling, ok := p.namespacePriorityCeiling(a.GetNamespace()); ok && priority > ceiling {
+			if !p.isShadow {
+				priorityCeilingRejections.Inc()
+			}
+			return admission.NewForbidden(a, fmt.Errorf("pod priority %d exceeds the maximum of %d permitted in namespace %v", priority, ceiling, a.GetNamespace()))
+		}
+
 		// if the pod contained a priority that differs from the one computed from the priority class, error
 		if pod.Spec.Priority != nil && *pod.Spec.Priority != priority {
 			return admission.NewForbidden(a, fmt.Errorf("the integer value of priority (%d) must not be provided in pod spec; priority admission controller computed %d from the given PriorityClass name", *pod.Spec.Priority, priority))
 		}
 		pod.Spec.Priority = &priority
+
+		if band := p.priorityBandFor(priority); band != "" {
+			if pod.Labels == nil {
+				pod.Labels = map[string]string{}
+			}
+			pod.Labels[priorityBandLabel] = band
+		}
+
+		if selector, ok := p.requiredNodeAffinityByPriorityClass[pod.Spec.PriorityClassName]; ok {
+			injectRequiredNodeAffinity(pod, selector)
+		}
+
+		if err := p.gatePodForPriorityClassQuota(a, pod); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gatePodForPriorityClassQuota checks pod's resolved PriorityClassName against
+// priorityClassPodQuota. If the namespace already has at least quota non-terminal pods using
+// that PriorityClassName, pod is admitted but gated: it is annotated with
+// scheduling.PriorityQuotaGatedAnnotationKey and given a
+// scheduling.PriorityQuotaExceededConditionType condition, so the PodFitsPriorityQuotaGate
+// scheduler predicate keeps it from competing for a node until a companion controller (not part
+// of this plugin) clears the gate once quota frees up.
+func (p *priorityPlugin) gatePodForPriorityClassQuota(a admission.Attributes, pod *api.Pod) error {
+	quota, ok := p.priorityClassPodQuota[pod.Spec.PriorityClassName]
+	if !ok {
+		return nil
+	}
+	pods, err := p.podLister.Pods(a.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to list pods in namespace %s: %v", a.GetNamespace(), err)
 	}
+	var used int32
+	for _, existing := range pods {
+		if existing.Spec.PriorityClassName != pod.Spec.PriorityClassName {
+			continue
+		}
+		if existing.Status.Phase == corev1.PodSucceeded || existing.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		used++
+	}
+	if used < quota {
+		return nil
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[scheduling.PriorityQuotaGatedAnnotationKey] = "true"
+	pod.Status.Conditions = append(pod.Status.Conditions, api.PodCondition{
+		Type:    scheduling.PriorityQuotaExceededConditionType,
+		Status:  api.ConditionFalse,
+		Reason:  "PriorityClassPodQuotaExceeded",
+		Message: fmt.Sprintf("namespace %v has reached its quota of %d non-terminal pods for PriorityClass %v", a.GetNamespace(), quota, pod.Spec.PriorityClassName),
+	})
 	return nil
 }
 
-// validatePriorityClass ensures that the value field is not larger than the highest user definable priority. If the GlobalDefault is set, it ensures that there is no other PriorityClass whose GlobalDefault is set.
+// injectRequiredNodeAffinity merges selector into pod's required node affinity, so that pod can
+// only be scheduled to a node selector would also match. If pod does not yet require any node
+// affinity, selector becomes pod's requirement outright. If pod already requires node affinity,
+// selector's MatchExpressions and MatchFields are AND'd onto every existing NodeSelectorTerm,
+// since NodeSelectorTerms are OR'd together and each must independently continue to guarantee the
+// configured policy. selector is expected to have exactly one NodeSelectorTerm; if it has more,
+// only the first is applied, since a NodeSelectorTerm can only be AND'd onto another term, not
+// OR'd in without weakening the existing requirement.
+func injectRequiredNodeAffinity(pod *api.Pod, selector api.NodeSelector) {
+	if len(selector.NodeSelectorTerms) == 0 {
+		return
+	}
+	required := selector.NodeSelectorTerms[0]
+
+	if pod.Spec.Affinity == nil {
+		pod.Spec.Affinity = &api.Affinity{}
+	}
+	if pod.Spec.Affinity.NodeAffinity == nil {
+		pod.Spec.Affinity.NodeAffinity = &api.NodeAffinity{}
+	}
+	existing := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if existing == nil || len(existing.NodeSelectorTerms) == 0 {
+		pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &api.NodeSelector{
+			NodeSelectorTerms: []api.NodeSelectorTerm{required},
+		}
+		return
+	}
+	for i := range existing.NodeSelectorTerms {
+		existing.NodeSelectorTerms[i].MatchExpressions = append(existing.NodeSelectorTerms[i].MatchExpressions, required.MatchExpressions...)
+		existing.NodeSelectorTerms[i].MatchFields = append(existing.NodeSelectorTerms[i].MatchFields, required.MatchFields...)
+	}
+}
+
+// evaluateShadow resolves shadowPod using the candidate configuration in p.shadow and compares
+// the outcome to the active configuration's already-computed result (activeErr, activePod),
+// without enforcing or otherwise letting the candidate's decision affect this admission request.
+// Any divergence is recorded via the shadowDivergences metric and a shadowDivergence audit
+// annotation on the request.
+func (p *priorityPlugin) evaluateShadow(ctx context.Context, a admission.Attributes, shadowPod *api.Pod, activeErr error, activePod *api.Pod) {
+	shadowErr := p.shadow.resolvePod(ctx, a, shadowPod)
+
+	switch {
+	case (activeErr == nil) != (shadowErr == nil):
+		p.recordShadowDivergence(a, "admission-result", fmt.Sprintf("active=%v candidate=%v", activeErr, shadowErr))
+	case activeErr != nil:
+		// Both the active and candidate configurations rejected the pod; there is nothing
+		// further to compare since neither resolved a priority for it.
+	case activePod.Spec.PriorityClassName != shadowPod.Spec.PriorityClassName:
+		p.recordShadowDivergence(a, "priority-class", fmt.Sprintf("active=%s candidate=%s", activePod.Spec.PriorityClassName, shadowPod.Spec.PriorityClassName))
+	case podPriority(activePod) != podPriority(shadowPod):
+		p.recordShadowDivergence(a, "priority-value", fmt.Sprintf("active=%d candidate=%d", podPriority(activePod), podPriority(shadowPod)))
+	}
+}
+
+// recordShadowDivergence records that the shadow-evaluated candidate configuration diverged from
+// the active configuration's decision for the pod being admitted, by the given kind.
+func (p *priorityPlugin) recordShadowDivergence(a admission.Attributes, kind, detail string) {
+	shadowDivergences.WithLabelValues(kind).Inc()
+	key := shadowDivergenceAnnotationPrefix + kind
+	if err := a.AddAnnotation(key, detail); err != nil {
+		klog.Warningf("failed to set admission audit annotation %s to %s: %v", key, detail, err)
+	}
+}
+
+// podPriority returns the resolved priority of pod, or 0 if it has not been resolved.
+func podPriority(pod *api.Pod) int32 {
+	if pod.Spec.Priority == nil {
+		return 0
+	}
+	return *pod.Spec.Priority
+}
+
+// validatePriorityClass ensures that the value field is not larger than the highest user definable priority. If the GlobalDefault is set, it ensures that there is no other PriorityClass whose GlobalDefault is set, and that the class's Value does not exceed the configured MaxGlobalDefaultValue.
 func (p *priorityPlugin) validatePriorityClass(a admission.Attributes) error {
 	operation := a.GetOperation()
 	pc, ok := a.GetObject().(*scheduling.PriorityClass)
 	if !ok {
 		return errors.NewBadRequest("resource was marked with kind PriorityClass but was unable to be converted")
 	}
+	if operation == admission.Create {
+		if err := p.checkPriorityClassLimits(a, pc); err != nil {
+			return err
+		}
+	}
 	// If the new PriorityClass tries to be the default priority, make sure that no other priority class is marked as default.
 	if pc.GlobalDefault {
+		if p.maxGlobalDefaultValue != 0 && pc.Value > p.maxGlobalDefaultValue {
+			return admission.NewForbidden(a, fmt.Errorf("PriorityClass %v has value %v, which is above the configured maximum of %v for a GlobalDefault PriorityClass", pc.Name, pc.Value, p.maxGlobalDefaultValue))
+		}
 		dpc, err := p.getDefaultPriorityClass()
 		if err != nil {
 			return fmt.Errorf("failed to get default priority class: %v", err)
@@ -244,6 +1313,54 @@ func (p *priorityPlugin) validatePriorityClass(a admission.Attributes) error {
 	return nil
 }
 
+// checkPriorityClassLimits enforces MaxPriorityClasses and PriorityClassBandLimits against the
+// PriorityClass objects that already exist, rejecting the creation of pc if it would exceed
+// either. It is a no-op if neither limit is configured, so it never pays for a List call on a
+// cluster that has not opted in.
+func (p *priorityPlugin) checkPriorityClassLimits(a admission.Attributes, pc *scheduling.PriorityClass) error {
+	if p.maxPriorityClasses == 0 && len(p.priorityClassBandLimits) == 0 {
+		return nil
+	}
+	list, err := p.lister.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to list priority classes: %v", err)
+	}
+	if p.maxPriorityClasses != 0 && int32(len(list)) >= p.maxPriorityClasses {
+		return admission.NewForbidden(a, fmt.Errorf("cluster already has %d PriorityClasses, which is at the configured maximum of %d; delete an unused PriorityClass before creating another", len(list), p.maxPriorityClasses))
+	}
+	band, ok := p.priorityClassBandFor(pc.Value)
+	if !ok {
+		return nil
+	}
+	var count int32
+	for _, existing := range list {
+		if existingBand, ok := p.priorityClassBandFor(existing.Value); ok && existingBand.Threshold == band.Threshold {
+			count++
+		}
+	}
+	if count >= band.Max {
+		return admission.NewForbidden(a, fmt.Errorf("%d PriorityClasses already have a value of %d or above, which is at the configured maximum of %d for that band; delete an unused PriorityClass in that range before creating another", count, band.Threshold, band.Max))
+	}
+	return nil
+}
+
+// priorityClassBandFor returns the configured PriorityClassBandLimit with the highest Threshold
+// that is still at or below value, or false if no configured band applies.
+func (p *priorityPlugin) priorityClassBandFor(value int32) (PriorityClassBandLimit, bool) {
+	var band PriorityClassBandLimit
+	haveBand := false
+	for _, b := range p.priorityClassBandLimits {
+		if value < b.Threshold {
+			continue
+		}
+		if !haveBand || b.Threshold > band.Threshold {
+			band = b
+			haveBand = true
+		}
+	}
+	return band, haveBand
+}
+
 func (p *priorityPlugin) getDefaultPriorityClass() (*schedulingv1.PriorityClass, error) {
 	list, err := p.lister.List(labels.Everything())
 	if err != nil {
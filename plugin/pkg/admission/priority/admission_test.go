@@ -17,22 +17,39 @@ limitations under the License.
 package priority
 
 import (
+	"context"
+	"strings"
 	"testing"
+	"time"
 
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"k8s.io/klog"
 
+	corev1 "k8s.io/api/core/v1"
 	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apiserver/pkg/admission"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
 	"k8s.io/apiserver/pkg/authentication/user"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
 	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"k8s.io/kubernetes/pkg/apis/apps"
+	"k8s.io/kubernetes/pkg/apis/batch"
 	api "k8s.io/kubernetes/pkg/apis/core"
 	"k8s.io/kubernetes/pkg/apis/scheduling"
 	"k8s.io/kubernetes/pkg/apis/scheduling/v1"
 	"k8s.io/kubernetes/pkg/controller"
 	"k8s.io/kubernetes/pkg/features"
+	kubeapiserveradmission "k8s.io/kubernetes/pkg/kubeapiserver/admission"
+	kubelettypes "k8s.io/kubernetes/pkg/kubelet/types"
 )
 
 func addPriorityClasses(ctrl *priorityPlugin, priorityClasses []*scheduling.PriorityClass) error {
@@ -49,6 +66,38 @@ func addPriorityClasses(ctrl *priorityPlugin, priorityClasses []*scheduling.Prio
 	return nil
 }
 
+func addPriorityClassesAndNamespaces(ctrl *priorityPlugin, priorityClasses []*scheduling.PriorityClass, namespaces []*corev1.Namespace) error {
+	informerFactory := informers.NewSharedInformerFactory(nil, controller.NoResyncPeriodFunc())
+	ctrl.SetExternalKubeInformerFactory(informerFactory)
+	for _, c := range priorityClasses {
+		s := &schedulingv1.PriorityClass{}
+		if err := v1.Convert_scheduling_PriorityClass_To_v1_PriorityClass(c, s, nil); err != nil {
+			return err
+		}
+		informerFactory.Scheduling().V1().PriorityClasses().Informer().GetStore().Add(s)
+	}
+	for _, ns := range namespaces {
+		informerFactory.Core().V1().Namespaces().Informer().GetStore().Add(ns)
+	}
+	return nil
+}
+
+func addPriorityClassesAndPods(ctrl *priorityPlugin, priorityClasses []*scheduling.PriorityClass, pods []*corev1.Pod) error {
+	informerFactory := informers.NewSharedInformerFactory(nil, controller.NoResyncPeriodFunc())
+	ctrl.SetExternalKubeInformerFactory(informerFactory)
+	for _, c := range priorityClasses {
+		s := &schedulingv1.PriorityClass{}
+		if err := v1.Convert_scheduling_PriorityClass_To_v1_PriorityClass(c, s, nil); err != nil {
+			return err
+		}
+		informerFactory.Scheduling().V1().PriorityClasses().Informer().GetStore().Add(s)
+	}
+	for _, pod := range pods {
+		informerFactory.Core().V1().Pods().Informer().GetStore().Add(pod)
+	}
+	return nil
+}
+
 var defaultClass1 = &scheduling.PriorityClass{
 	TypeMeta: metav1.TypeMeta{
 		Kind: "PriorityClass",
@@ -140,7 +189,7 @@ func TestPriorityClassAdmission(t *testing.T) {
 	for _, test := range tests {
 		klog.V(4).Infof("starting test %q", test.name)
 
-		ctrl := newPlugin()
+		ctrl := newPlugin(&pluginConfig{})
 		// Add existing priority classes.
 		if err := addPriorityClasses(ctrl, test.existingClasses); err != nil {
 			t.Errorf("Test %q: unable to add object to informer: %v", test.name, err)
@@ -169,6 +218,58 @@ func TestPriorityClassAdmission(t *testing.T) {
 	}
 }
 
+// TestMaxGlobalDefaultValue tests that a PriorityClass above the configured
+// MaxGlobalDefaultValue is rejected when it tries to set GlobalDefault, but is otherwise fine.
+func TestMaxGlobalDefaultValue(t *testing.T) {
+	highValueDefault := &scheduling.PriorityClass{
+		TypeMeta:      metav1.TypeMeta{Kind: "PriorityClass"},
+		ObjectMeta:    metav1.ObjectMeta{Name: "too-high"},
+		Value:         2000000000,
+		GlobalDefault: true,
+	}
+	highValueNonDefault := &scheduling.PriorityClass{
+		TypeMeta:   metav1.TypeMeta{Kind: "PriorityClass"},
+		ObjectMeta: metav1.ObjectMeta{Name: "too-high-but-not-default"},
+		Value:      2000000000,
+	}
+
+	tests := []struct {
+		name        string
+		newClass    *scheduling.PriorityClass
+		expectError bool
+	}{
+		{"default class over the ceiling is rejected", highValueDefault, true},
+		{"non-default class over the ceiling is allowed", highValueNonDefault, false},
+		{"default class under the ceiling is allowed", defaultClass1, false},
+	}
+
+	for _, test := range tests {
+		ctrl := newPlugin(&pluginConfig{MaxGlobalDefaultValue: 1000000})
+		if err := addPriorityClasses(ctrl, nil); err != nil {
+			t.Errorf("Test %q: unable to add object to informer: %v", test.name, err)
+		}
+		attrs := admission.NewAttributesRecord(
+			test.newClass,
+			nil,
+			scheduling.Kind("PriorityClass").WithVersion("version"),
+			"",
+			"",
+			scheduling.Resource("priorityclasses").WithVersion("version"),
+			"",
+			admission.Create,
+			false,
+			nil,
+		)
+		err := ctrl.Validate(attrs, nil)
+		if err != nil && !test.expectError {
+			t.Errorf("Test %q: unexpected error received: %v", test.name, err)
+		}
+		if err == nil && test.expectError {
+			t.Errorf("Test %q: expected error and no error received", test.name)
+		}
+	}
+}
+
 // TestDefaultPriority tests that default priority is resolved correctly.
 func TestDefaultPriority(t *testing.T) {
 	pcResource := scheduling.Resource("priorityclasses").WithVersion("version")
@@ -240,7 +341,7 @@ func TestDefaultPriority(t *testing.T) {
 
 	for _, test := range tests {
 		klog.V(4).Infof("starting test %q", test.name)
-		ctrl := newPlugin()
+		ctrl := newPlugin(&pluginConfig{})
 		if err := addPriorityClasses(ctrl, test.classesBefore); err != nil {
 			t.Errorf("Test %q: unable to add object to informer: %v", test.name, err)
 		}
@@ -274,6 +375,32 @@ func TestDefaultPriority(t *testing.T) {
 	}
 }
 
+func TestPriorityBandFor(t *testing.T) {
+	ctrl := newPlugin(&pluginConfig{
+		PriorityBands: []PriorityBand{
+			{Name: "critical", Threshold: 1000000000},
+			{Name: "high", Threshold: 1000},
+			{Name: "default", Threshold: 0},
+		},
+	})
+
+	tests := []struct {
+		priority int32
+		want     string
+	}{
+		{priority: -5, want: ""},
+		{priority: 0, want: "default"},
+		{priority: 999, want: "default"},
+		{priority: 1000, want: "high"},
+		{priority: 1000000000, want: "critical"},
+	}
+	for _, test := range tests {
+		if got := ctrl.priorityBandFor(test.priority); got != test.want {
+			t.Errorf("priorityBandFor(%d) = %q, want %q", test.priority, got, test.want)
+		}
+	}
+}
+
 var zeroPriority = int32(0)
 var intPriority = int32(1000)
 
@@ -584,7 +711,7 @@ func TestPodAdmission(t *testing.T) {
 	for _, test := range tests {
 		klog.V(4).Infof("starting test %q", test.name)
 
-		ctrl := newPlugin()
+		ctrl := newPlugin(&pluginConfig{})
 		// Add existing priority classes.
 		if err := addPriorityClasses(ctrl, test.existingClasses); err != nil {
 			t.Errorf("Test %q: unable to add object to informer: %v", test.name, err)
@@ -617,3 +744,1492 @@ func TestPodAdmission(t *testing.T) {
 		}
 	}
 }
+
+// TestPodAdmissionSkipsDefaultLookupInTerminatingNamespace verifies that a pod with no
+// PriorityClassName force-created into a terminating namespace gets the built-in fallback
+// priority instead of paying for (and being subject to) the cluster's GlobalDefault PriorityClass.
+func TestPodAdmissionSkipsDefaultLookupInTerminatingNamespace(t *testing.T) {
+	now := metav1.Now()
+	terminatingNs := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "terminating-ns",
+			DeletionTimestamp: &now,
+		},
+	}
+
+	ctrl := newPlugin(&pluginConfig{})
+	if err := addPriorityClassesAndNamespaces(ctrl, []*scheduling.PriorityClass{defaultClass1}, []*corev1.Namespace{terminatingNs}); err != nil {
+		t.Fatalf("unable to add object to informer: %v", err)
+	}
+
+	pod := &api.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-in-terminating-ns",
+			Namespace: terminatingNs.Name,
+		},
+		Spec: api.PodSpec{
+			Containers: []api.Container{{Name: "container"}},
+		},
+	}
+	attrs := admission.NewAttributesRecord(
+		pod,
+		nil,
+		api.Kind("Pod").WithVersion("version"),
+		pod.Namespace,
+		"",
+		api.Resource("pods").WithVersion("version"),
+		"",
+		admission.Create,
+		false,
+		nil,
+	)
+	if err := ctrl.Admit(attrs, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pod.Spec.PriorityClassName != "" {
+		t.Errorf("expected no PriorityClassName to be assigned, got %q", pod.Spec.PriorityClassName)
+	}
+	if want := int32(scheduling.DefaultPriorityWhenNoDefaultClassExists); *pod.Spec.Priority != want {
+		t.Errorf("expected the built-in fallback priority %d instead of the cluster GlobalDefault, got %d", want, *pod.Spec.Priority)
+	}
+}
+
+// TestValidateNamespaceUpdateRejectsPriorityParamChangeWhileTerminating verifies that changes to
+// the Priority plugin's namespace-scoped admission parameters are rejected once the namespace is
+// terminating, but allowed otherwise.
+func TestValidateNamespaceUpdateRejectsPriorityParamChangeWhileTerminating(t *testing.T) {
+	now := metav1.Now()
+
+	tests := []struct {
+		name        string
+		oldNs       *api.Namespace
+		newNs       *api.Namespace
+		expectError bool
+	}{
+		{
+			name: "priority params changed while terminating is rejected",
+			oldNs: &api.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "ns",
+					DeletionTimestamp: &now,
+					Annotations:       map[string]string{kubeapiserveradmission.NamespaceParamsAnnotation: `{"Priority":{"downgrade":"low"}}`},
+				},
+			},
+			newNs: &api.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "ns",
+					DeletionTimestamp: &now,
+					Annotations:       map[string]string{kubeapiserveradmission.NamespaceParamsAnnotation: `{"Priority":{"downgrade":"high"}}`},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "priority params changed while not terminating is allowed",
+			oldNs: &api.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "ns",
+					Annotations: map[string]string{kubeapiserveradmission.NamespaceParamsAnnotation: `{"Priority":{"downgrade":"low"}}`},
+				},
+			},
+			newNs: &api.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "ns",
+					Annotations: map[string]string{kubeapiserveradmission.NamespaceParamsAnnotation: `{"Priority":{"downgrade":"high"}}`},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "unrelated annotation change while terminating is allowed",
+			oldNs: &api.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "ns",
+					DeletionTimestamp: &now,
+					Annotations:       map[string]string{"other": "one"},
+				},
+			},
+			newNs: &api.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "ns",
+					DeletionTimestamp: &now,
+					Annotations:       map[string]string{"other": "two"},
+				},
+			},
+			expectError: false,
+		},
+	}
+
+	ctrl := newPlugin(&pluginConfig{})
+	for _, test := range tests {
+		attrs := admission.NewAttributesRecord(
+			test.newNs,
+			test.oldNs,
+			api.Kind("Namespace").WithVersion("version"),
+			"",
+			test.oldNs.Name,
+			api.Resource("namespaces").WithVersion("version"),
+			"",
+			admission.Update,
+			false,
+			nil,
+		)
+		err := ctrl.Validate(attrs, nil)
+		if err != nil && !test.expectError {
+			t.Errorf("Test %q: unexpected error received: %v", test.name, err)
+		}
+		if err == nil && test.expectError {
+			t.Errorf("Test %q: expected error and no error received", test.name)
+		}
+	}
+}
+
+// TestShadowEvaluation verifies that a CandidateConfig is evaluated against every pod without
+// ever affecting the enforced admission decision, and that a divergence between the active and
+// candidate configurations is recorded as a metric and an audit annotation.
+func TestShadowEvaluation(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.PodPriority, true)()
+
+	existingClasses := []*scheduling.PriorityClass{systemClusterCritical, nondefaultClass1, defaultClass1}
+
+	newCtrl := func(downgradeTo string) *priorityPlugin {
+		ctrl := newPlugin(&pluginConfig{
+			DowngradePriorityClassName: downgradeTo,
+			CandidateConfig: &pluginConfig{
+				DowngradePriorityClassName: "default1",
+			},
+		})
+		if err := addPriorityClasses(ctrl, existingClasses); err != nil {
+			t.Fatalf("unable to add object to informer: %v", err)
+		}
+		return ctrl
+	}
+
+	newAttrs := func(pod *api.Pod) admission.Attributes {
+		return admission.NewAttributesRecord(
+			pod,
+			nil,
+			api.Kind("Pod").WithVersion("version"),
+			pod.Namespace,
+			"",
+			api.Resource("pods").WithVersion("version"),
+			"",
+			admission.Create,
+			false,
+			nil,
+		)
+	}
+
+	t.Run("candidate agrees with active", func(t *testing.T) {
+		ctrl := newCtrl("default1")
+		before := testutil.ToFloat64(shadowDivergences.WithLabelValues("priority-class"))
+
+		pod := &api.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "non-system-namespace"},
+			Spec: api.PodSpec{
+				Containers:        []api.Container{{Name: "container"}},
+				PriorityClassName: scheduling.SystemClusterCritical,
+			},
+		}
+		ae := &auditinternal.Event{Level: auditinternal.LevelMetadata}
+		if err := admission.WithAudit(ctrl, ae).(admission.MutationInterface).Admit(newAttrs(pod), nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if after := testutil.ToFloat64(shadowDivergences.WithLabelValues("priority-class")); after != before {
+			t.Errorf("expected no new priority-class divergence, counter went from %v to %v", before, after)
+		}
+		if annotation, ok := ae.Annotations[shadowDivergenceAnnotationPrefix+"priority-class"]; ok {
+			t.Errorf("expected no shadow divergence annotation, got %q", annotation)
+		}
+	})
+
+	t.Run("candidate diverges from active", func(t *testing.T) {
+		ctrl := newCtrl("nondefault1")
+		before := testutil.ToFloat64(shadowDivergences.WithLabelValues("priority-class"))
+
+		pod := &api.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "non-system-namespace"},
+			Spec: api.PodSpec{
+				Containers:        []api.Container{{Name: "container"}},
+				PriorityClassName: scheduling.SystemClusterCritical,
+			},
+		}
+		ae := &auditinternal.Event{Level: auditinternal.LevelMetadata}
+		if err := admission.WithAudit(ctrl, ae).(admission.MutationInterface).Admit(newAttrs(pod), nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// The active configuration, not the candidate, must be the one actually enforced.
+		if pod.Spec.PriorityClassName != "nondefault1" {
+			t.Errorf("expected active configuration to downgrade to %q, got %q", "nondefault1", pod.Spec.PriorityClassName)
+		}
+		if want := nondefaultClass1.Value; *pod.Spec.Priority != want {
+			t.Errorf("expected active configuration's priority %d, got %d", want, *pod.Spec.Priority)
+		}
+
+		if after := testutil.ToFloat64(shadowDivergences.WithLabelValues("priority-class")); after != before+1 {
+			t.Errorf("expected priority-class divergence counter to increase by 1, went from %v to %v", before, after)
+		}
+		wantDetail := "active=nondefault1 candidate=default1"
+		if annotation := ae.Annotations[shadowDivergenceAnnotationPrefix+"priority-class"]; annotation != wantDetail {
+			t.Errorf("expected shadow divergence annotation %q, got %q", wantDetail, annotation)
+		}
+	})
+}
+
+// TestMaxPriorityClasses tests that PriorityClass creation is rejected once the cluster is at
+// the configured MaxPriorityClasses limit, or once the value band the new class falls into is at
+// its configured PriorityClassBandLimits limit, even if the overall limit has not been reached.
+func TestMaxPriorityClasses(t *testing.T) {
+	lowClass := &scheduling.PriorityClass{
+		TypeMeta:   metav1.TypeMeta{Kind: "PriorityClass"},
+		ObjectMeta: metav1.ObjectMeta{Name: "low"},
+		Value:      100,
+	}
+	anotherLowClass := &scheduling.PriorityClass{
+		TypeMeta:   metav1.TypeMeta{Kind: "PriorityClass"},
+		ObjectMeta: metav1.ObjectMeta{Name: "another-low"},
+		Value:      200,
+	}
+	highClass := &scheduling.PriorityClass{
+		TypeMeta:   metav1.TypeMeta{Kind: "PriorityClass"},
+		ObjectMeta: metav1.ObjectMeta{Name: "high"},
+		Value:      1000000,
+	}
+
+	tests := []struct {
+		name            string
+		config          pluginConfig
+		existingClasses []*scheduling.PriorityClass
+		newClass        *scheduling.PriorityClass
+		expectError     bool
+	}{
+		{
+			"under the global maximum is allowed",
+			pluginConfig{MaxPriorityClasses: 2},
+			[]*scheduling.PriorityClass{lowClass},
+			anotherLowClass,
+			false,
+		},
+		{
+			"at the global maximum is rejected",
+			pluginConfig{MaxPriorityClasses: 1},
+			[]*scheduling.PriorityClass{lowClass},
+			anotherLowClass,
+			true,
+		},
+		{
+			"under a band's limit is allowed",
+			pluginConfig{PriorityClassBandLimits: []PriorityClassBandLimit{{Threshold: 1000000, Max: 1}}},
+			[]*scheduling.PriorityClass{lowClass},
+			highClass,
+			false,
+		},
+		{
+			"at a band's limit is rejected even though the global maximum is not reached",
+			pluginConfig{PriorityClassBandLimits: []PriorityClassBandLimit{{Threshold: 1000000, Max: 1}}},
+			[]*scheduling.PriorityClass{highClass},
+			anotherLowClass,
+			false,
+		},
+		{
+			"at a band's limit rejects another class in that band",
+			pluginConfig{PriorityClassBandLimits: []PriorityClassBandLimit{{Threshold: 1000000, Max: 1}}},
+			[]*scheduling.PriorityClass{highClass},
+			&scheduling.PriorityClass{
+				TypeMeta:   metav1.TypeMeta{Kind: "PriorityClass"},
+				ObjectMeta: metav1.ObjectMeta{Name: "another-high"},
+				Value:      2000000,
+			},
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		ctrl := newPlugin(&test.config)
+		if err := addPriorityClasses(ctrl, test.existingClasses); err != nil {
+			t.Errorf("Test %q: unable to add object to informer: %v", test.name, err)
+		}
+		attrs := admission.NewAttributesRecord(
+			test.newClass,
+			nil,
+			scheduling.Kind("PriorityClass").WithVersion("version"),
+			"",
+			"",
+			scheduling.Resource("priorityclasses").WithVersion("version"),
+			"",
+			admission.Create,
+			false,
+			nil,
+		)
+		err := ctrl.Validate(attrs, nil)
+		if err != nil && !test.expectError {
+			t.Errorf("Test %q: unexpected error received: %v", test.name, err)
+		}
+		if err == nil && test.expectError {
+			t.Errorf("Test %q: expected error and no error received", test.name)
+		}
+	}
+}
+
+func TestResourceVersionDiff(t *testing.T) {
+	tests := []struct {
+		name         string
+		live, cached string
+		want         float64
+		wantOK       bool
+	}{
+		{"live ahead of cached", "105", "100", 5, true},
+		{"live equal to cached", "100", "100", 0, true},
+		{"live behind cached is clamped to zero", "100", "105", 0, true},
+		{"live not numeric", "abc", "100", 0, false},
+		{"cached not numeric", "100", "abc", 0, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := resourceVersionDiff(test.live, test.cached)
+			if ok != test.wantOK || (ok && got != test.want) {
+				t.Errorf("resourceVersionDiff(%q, %q) = (%v, %v), want (%v, %v)", test.live, test.cached, got, ok, test.want, test.wantOK)
+			}
+		})
+	}
+}
+
+// TestRecordClassDecision tests that resolving a PriorityClass records its latency, regardless
+// of whether that decision happens to be sampled for staleness.
+func TestRecordClassDecision(t *testing.T) {
+	pcName := "test-decision-latency-class"
+	ctrl := newPlugin(&pluginConfig{})
+
+	before := &dto.Metric{}
+	if err := priorityClassDecisionLatency.WithLabelValues(pcName).(prometheus.Histogram).Write(before); err != nil {
+		t.Fatalf("failed to read priorityClassDecisionLatency: %v", err)
+	}
+
+	ctrl.recordClassDecision(context.Background(), pcName, 5*time.Millisecond)
+
+	after := &dto.Metric{}
+	if err := priorityClassDecisionLatency.WithLabelValues(pcName).(prometheus.Histogram).Write(after); err != nil {
+		t.Fatalf("failed to read priorityClassDecisionLatency: %v", err)
+	}
+	if got, want := after.GetHistogram().GetSampleCount(), before.GetHistogram().GetSampleCount()+1; got != want {
+		t.Errorf("priorityClassDecisionLatency sample count = %d, want %d", got, want)
+	}
+
+	// A pod with no resolved PriorityClassName (e.g. an Update) shouldn't record anything.
+	ctrl.recordClassDecision(context.Background(), "", 5*time.Millisecond)
+}
+
+// TestRecordClassDecisionSamplesStaleness tests that, once sampled, recordClassDecision compares
+// the informer-cached PriorityClass against a live read and records the difference. Since
+// shouldSampleStaleness samples deterministically (every staleSampleRate-th call), staleSampleRate
+// consecutive calls are guaranteed to include exactly one sample.
+func TestRecordClassDecisionSamplesStaleness(t *testing.T) {
+	pcName := "test-staleness-class"
+	cached := &scheduling.PriorityClass{
+		ObjectMeta: metav1.ObjectMeta{Name: pcName, ResourceVersion: "100"},
+		Value:      100,
+	}
+	ctrl := newPlugin(&pluginConfig{})
+	if err := addPriorityClasses(ctrl, []*scheduling.PriorityClass{cached}); err != nil {
+		t.Fatalf("unable to add object to informer: %v", err)
+	}
+	live := &schedulingv1.PriorityClass{
+		ObjectMeta: metav1.ObjectMeta{Name: pcName, ResourceVersion: "105"},
+		Value:      100,
+	}
+	ctrl.SetExternalKubeClientSet(clientsetfake.NewSimpleClientset(live))
+
+	before := &dto.Metric{}
+	if err := priorityClassListerStaleness.Write(before); err != nil {
+		t.Fatalf("failed to read priorityClassListerStaleness: %v", err)
+	}
+
+	for i := 0; i < staleSampleRate; i++ {
+		ctrl.recordClassDecision(context.Background(), pcName, time.Millisecond)
+	}
+
+	after := &dto.Metric{}
+	if err := priorityClassListerStaleness.Write(after); err != nil {
+		t.Fatalf("failed to read priorityClassListerStaleness: %v", err)
+	}
+	if got, want := after.GetHistogram().GetSampleCount(), before.GetHistogram().GetSampleCount()+1; got != want {
+		t.Errorf("priorityClassListerStaleness sample count = %d, want %d", got, want)
+	}
+}
+
+// TestGetPriorityClassLiveLookupFallback verifies that a cache miss falls back to a live client
+// read when liveLookupOnCacheMiss is enabled, and is rejected outright otherwise.
+func TestGetPriorityClassLiveLookupFallback(t *testing.T) {
+	pcName := "test-live-lookup-class"
+	live := &schedulingv1.PriorityClass{
+		ObjectMeta: metav1.ObjectMeta{Name: pcName},
+		Value:      100,
+	}
+
+	t.Run("disabled falls through to NotFound", func(t *testing.T) {
+		ctrl := newPlugin(&pluginConfig{})
+		if err := addPriorityClasses(ctrl, nil); err != nil {
+			t.Fatalf("unable to initialize informer: %v", err)
+		}
+		ctrl.SetExternalKubeClientSet(clientsetfake.NewSimpleClientset(live))
+
+		if _, err := ctrl.getPriorityClass(context.Background(), pcName); !errors.IsNotFound(err) {
+			t.Errorf("getPriorityClass() error = %v, want NotFound", err)
+		}
+	})
+
+	t.Run("enabled falls back to live read", func(t *testing.T) {
+		ctrl := newPlugin(&pluginConfig{LiveLookupOnCacheMiss: true})
+		if err := addPriorityClasses(ctrl, nil); err != nil {
+			t.Fatalf("unable to initialize informer: %v", err)
+		}
+		ctrl.SetExternalKubeClientSet(clientsetfake.NewSimpleClientset(live))
+
+		pc, err := ctrl.getPriorityClass(context.Background(), pcName)
+		if err != nil {
+			t.Fatalf("getPriorityClass() error = %v, want nil", err)
+		}
+		if pc.Value != live.Value {
+			t.Errorf("getPriorityClass() Value = %d, want %d", pc.Value, live.Value)
+		}
+	})
+}
+
+// TestPodTemplateAdmission verifies that PriorityClassName on a PodTemplate's embedded pod spec
+// is defaulted and validated the same way it would be for a bare Pod.
+func TestPodTemplateAdmission(t *testing.T) {
+	containerName := "container"
+
+	tests := []struct {
+		name             string
+		existingClasses  []*scheduling.PriorityClass
+		podTemplate      api.PodTemplate
+		expectedPriority int32
+		expectError      bool
+	}{
+		{
+			"pod template with a proper priority class",
+			[]*scheduling.PriorityClass{{ObjectMeta: metav1.ObjectMeta{Name: "default1"}, Value: 1000}},
+			api.PodTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "template-w-priorityclass", Namespace: "namespace"},
+				Template: api.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Name: "pod-w-priorityclass"},
+					Spec: api.PodSpec{
+						Containers:        []api.Container{{Name: containerName}},
+						PriorityClassName: "default1",
+					},
+				},
+			},
+			1000,
+			false,
+		},
+		{
+			"pod template with non-existing priority class",
+			[]*scheduling.PriorityClass{},
+			api.PodTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "template-w-bad-priorityclass", Namespace: "namespace"},
+				Template: api.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Name: "pod-w-bad-priorityclass"},
+					Spec: api.PodSpec{
+						Containers:        []api.Container{{Name: containerName}},
+						PriorityClassName: "non-existing",
+					},
+				},
+			},
+			0,
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		ctrl := newPlugin(&pluginConfig{})
+		if err := addPriorityClasses(ctrl, test.existingClasses); err != nil {
+			t.Errorf("Test %q: unable to add object to informer: %v", test.name, err)
+		}
+
+		attrs := admission.NewAttributesRecord(
+			&test.podTemplate,
+			nil,
+			api.Kind("PodTemplate").WithVersion("version"),
+			test.podTemplate.ObjectMeta.Namespace,
+			"",
+			api.Resource("podtemplates").WithVersion("version"),
+			"",
+			admission.Create,
+			false,
+			nil,
+		)
+		err := ctrl.Admit(attrs, nil)
+		if !test.expectError {
+			if err != nil {
+				t.Errorf("Test %q: unexpected error received: %v", test.name, err)
+			} else if *test.podTemplate.Template.Spec.Priority != test.expectedPriority {
+				t.Errorf("Test %q: expected priority is %d, but got %d.", test.name, test.expectedPriority, *test.podTemplate.Template.Spec.Priority)
+			}
+		}
+		if err == nil && test.expectError {
+			t.Errorf("Test %q: expected error and no error received", test.name)
+		}
+	}
+}
+
+// TestWorkloadPodTemplateValidation verifies that Deployment, ReplicaSet, StatefulSet, DaemonSet,
+// Job and CronJob objects are rejected at admission time if their embedded pod template
+// references a PriorityClassName that does not exist, rather than only failing once the
+// controller tries and fails to create pods from it.
+func TestWorkloadPodTemplateValidation(t *testing.T) {
+	containerName := "container"
+	goodTemplate := api.PodTemplateSpec{
+		Spec: api.PodSpec{
+			Containers:        []api.Container{{Name: containerName}},
+			PriorityClassName: defaultClass1.Name,
+		},
+	}
+	badTemplate := api.PodTemplateSpec{
+		Spec: api.PodSpec{
+			Containers:        []api.Container{{Name: containerName}},
+			PriorityClassName: "non-existing",
+		},
+	}
+	noPriorityClassTemplate := api.PodTemplateSpec{
+		Spec: api.PodSpec{
+			Containers: []api.Container{{Name: containerName}},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		obj         runtime.Object
+		kind        schema.GroupVersionKind
+		resource    schema.GroupVersionResource
+		expectError bool
+	}{
+		{
+			"Deployment with a valid priority class",
+			&apps.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "d", Namespace: "ns"}, Spec: apps.DeploymentSpec{Template: goodTemplate}},
+			apps.Kind("Deployment").WithVersion("version"), apps.Resource("deployments").WithVersion("version"),
+			false,
+		},
+		{
+			"Deployment with a non-existing priority class",
+			&apps.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "d", Namespace: "ns"}, Spec: apps.DeploymentSpec{Template: badTemplate}},
+			apps.Kind("Deployment").WithVersion("version"), apps.Resource("deployments").WithVersion("version"),
+			true,
+		},
+		{
+			"ReplicaSet with a non-existing priority class",
+			&apps.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "ns"}, Spec: apps.ReplicaSetSpec{Template: badTemplate}},
+			apps.Kind("ReplicaSet").WithVersion("version"), apps.Resource("replicasets").WithVersion("version"),
+			true,
+		},
+		{
+			"StatefulSet with a non-existing priority class",
+			&apps.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "ss", Namespace: "ns"}, Spec: apps.StatefulSetSpec{Template: badTemplate}},
+			apps.Kind("StatefulSet").WithVersion("version"), apps.Resource("statefulsets").WithVersion("version"),
+			true,
+		},
+		{
+			"DaemonSet with a non-existing priority class",
+			&apps.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "ds", Namespace: "ns"}, Spec: apps.DaemonSetSpec{Template: badTemplate}},
+			apps.Kind("DaemonSet").WithVersion("version"), apps.Resource("daemonsets").WithVersion("version"),
+			true,
+		},
+		{
+			"Job with a non-existing priority class",
+			&batch.Job{ObjectMeta: metav1.ObjectMeta{Name: "j", Namespace: "ns"}, Spec: batch.JobSpec{Template: badTemplate}},
+			batch.Kind("Job").WithVersion("version"), batch.Resource("jobs").WithVersion("version"),
+			true,
+		},
+		{
+			"CronJob with a non-existing priority class",
+			&batch.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "cj", Namespace: "ns"}, Spec: batch.CronJobSpec{JobTemplate: batch.JobTemplateSpec{Spec: batch.JobSpec{Template: badTemplate}}}},
+			batch.Kind("CronJob").WithVersion("version"), batch.Resource("cronjobs").WithVersion("version"),
+			true,
+		},
+		{
+			"CronJob with no priority class set",
+			&batch.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "cj", Namespace: "ns"}, Spec: batch.CronJobSpec{JobTemplate: batch.JobTemplateSpec{Spec: batch.JobSpec{Template: noPriorityClassTemplate}}}},
+			batch.Kind("CronJob").WithVersion("version"), batch.Resource("cronjobs").WithVersion("version"),
+			false,
+		},
+	}
+
+	for _, test := range tests {
+		ctrl := newPlugin(&pluginConfig{})
+		if err := addPriorityClasses(ctrl, []*scheduling.PriorityClass{defaultClass1}); err != nil {
+			t.Fatalf("Test %q: unable to add object to informer: %v", test.name, err)
+		}
+
+		attrs := admission.NewAttributesRecord(
+			test.obj, nil, test.kind, "ns", test.name, test.resource, "", admission.Create, false, nil,
+		)
+		err := ctrl.Validate(attrs, nil)
+		if test.expectError && err == nil {
+			t.Errorf("Test %q: expected error and no error received", test.name)
+		}
+		if !test.expectError && err != nil {
+			t.Errorf("Test %q: unexpected error received: %v", test.name, err)
+		}
+	}
+}
+
+// TestChaosHooksNoopInReleaseBuilds verifies that the fault-injection hooks used to test
+// resilience of the admission chain (see chaos.go) are no-ops when this package is built without
+// the "debug" build tag, so they never affect production behavior.
+func TestChaosHooksNoopInReleaseBuilds(t *testing.T) {
+	if err := injectListerFault(); err != nil {
+		t.Errorf("expected injectListerFault to be a no-op outside of debug builds, got: %v", err)
+	}
+	if injectConversionFault() {
+		t.Errorf("expected injectConversionFault to be a no-op outside of debug builds")
+	}
+}
+
+// TestPodAdmissionNamespaceDefaultPriorityClass verifies that a namespace configuring a
+// defaultPriorityClass via NamespaceParamsAnnotation overrides the cluster-wide GlobalDefault for
+// pods created in that namespace without an explicit PriorityClassName, that an unresolvable
+// namespace default is rejected rather than silently falling back, and that namespaces without the
+// annotation are unaffected.
+func TestPodAdmissionNamespaceDefaultPriorityClass(t *testing.T) {
+	tenantNs := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "tenant-ns",
+			Annotations: map[string]string{kubeapiserveradmission.NamespaceParamsAnnotation: `{"Priority":{"defaultPriorityClass":"nondefault1"}}`},
+		},
+	}
+	brokenNs := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "broken-ns",
+			Annotations: map[string]string{kubeapiserveradmission.NamespaceParamsAnnotation: `{"Priority":{"defaultPriorityClass":"does-not-exist"}}`},
+		},
+	}
+	plainNs := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "plain-ns",
+		},
+	}
+
+	newPod := func(namespace string) *api.Pod {
+		return &api.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod",
+				Namespace: namespace,
+			},
+			Spec: api.PodSpec{
+				Containers: []api.Container{{Name: "container"}},
+			},
+		}
+	}
+	admit := func(ctrl *priorityPlugin, pod *api.Pod) error {
+		attrs := admission.NewAttributesRecord(
+			pod,
+			nil,
+			api.Kind("Pod").WithVersion("version"),
+			pod.Namespace,
+			"",
+			api.Resource("pods").WithVersion("version"),
+			"",
+			admission.Create,
+			false,
+			nil,
+		)
+		return ctrl.Admit(attrs, nil)
+	}
+
+	ctrl := newPlugin(&pluginConfig{})
+	if err := addPriorityClassesAndNamespaces(
+		ctrl,
+		[]*scheduling.PriorityClass{defaultClass1, nondefaultClass1},
+		[]*corev1.Namespace{tenantNs, brokenNs, plainNs},
+	); err != nil {
+		t.Fatalf("unable to add object to informer: %v", err)
+	}
+
+	tenantPod := newPod(tenantNs.Name)
+	if err := admit(ctrl, tenantPod); err != nil {
+		t.Fatalf("unexpected error admitting pod in %v: %v", tenantNs.Name, err)
+	}
+	if tenantPod.Spec.PriorityClassName != nondefaultClass1.Name {
+		t.Errorf("expected PriorityClassName %q, got %q", nondefaultClass1.Name, tenantPod.Spec.PriorityClassName)
+	}
+	if *tenantPod.Spec.Priority != nondefaultClass1.Value {
+		t.Errorf("expected priority %d from the namespace's default PriorityClass instead of the cluster GlobalDefault, got %d", nondefaultClass1.Value, *tenantPod.Spec.Priority)
+	}
+
+	if err := admit(ctrl, newPod(brokenNs.Name)); err == nil {
+		t.Errorf("expected an error admitting a pod in %v, whose configured default PriorityClass does not exist", brokenNs.Name)
+	}
+
+	plainPod := newPod(plainNs.Name)
+	if err := admit(ctrl, plainPod); err != nil {
+		t.Fatalf("unexpected error admitting pod in %v: %v", plainNs.Name, err)
+	}
+	if plainPod.Spec.PriorityClassName != defaultClass1.Name {
+		t.Errorf("expected PriorityClassName %q, got %q", defaultClass1.Name, plainPod.Spec.PriorityClassName)
+	}
+	if *plainPod.Spec.Priority != defaultClass1.Value {
+		t.Errorf("expected the cluster GlobalDefault priority %d, got %d", defaultClass1.Value, *plainPod.Spec.Priority)
+	}
+}
+
+// TestPodAdmissionNamespacePriorityClassAllowList verifies that a namespace listed in
+// NamespacePriorityClassAllowList may only admit pods using one of its allowed PriorityClassNames,
+// that a namespace with no entry in the allow-list remains unrestricted, and that the existing
+// system-priority-class restriction still applies regardless of the allow-list.
+func TestPodAdmissionNamespacePriorityClassAllowList(t *testing.T) {
+	ctrl := newPlugin(&pluginConfig{
+		NamespacePriorityClassAllowList: map[string][]string{
+			"restricted-ns": {nondefaultClass1.Name},
+		},
+	})
+	if err := addPriorityClasses(ctrl, []*scheduling.PriorityClass{defaultClass1, nondefaultClass1, systemClusterCritical}); err != nil {
+		t.Fatalf("unable to add object to informer: %v", err)
+	}
+
+	newPod := func(namespace, priorityClassName string) *api.Pod {
+		return &api.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod",
+				Namespace: namespace,
+			},
+			Spec: api.PodSpec{
+				Containers:        []api.Container{{Name: "container"}},
+				PriorityClassName: priorityClassName,
+			},
+		}
+	}
+	admit := func(pod *api.Pod) error {
+		attrs := admission.NewAttributesRecord(
+			pod,
+			nil,
+			api.Kind("Pod").WithVersion("version"),
+			pod.Namespace,
+			"",
+			api.Resource("pods").WithVersion("version"),
+			"",
+			admission.Create,
+			false,
+			nil,
+		)
+		return ctrl.Admit(attrs, nil)
+	}
+
+	if err := admit(newPod("restricted-ns", nondefaultClass1.Name)); err != nil {
+		t.Errorf("expected the allow-listed PriorityClassName to be permitted, got: %v", err)
+	}
+	if err := admit(newPod("restricted-ns", defaultClass1.Name)); err == nil {
+		t.Errorf("expected a PriorityClassName not on the namespace's allow-list to be rejected")
+	}
+	if err := admit(newPod("unrestricted-ns", defaultClass1.Name)); err != nil {
+		t.Errorf("expected a namespace with no allow-list entry to remain unrestricted, got: %v", err)
+	}
+	if err := admit(newPod("restricted-ns", scheduling.SystemClusterCritical)); err == nil {
+		t.Errorf("expected the system-priority-class restriction to still apply outside the system namespace")
+	}
+}
+
+// TestNamespacePolicyPrefixesToStrip verifies that a virtual-cluster projection of a namespace
+// (identified by a configured prefix) is evaluated under its physical namespace's
+// NamespacePriorityClassAllowList and defaultPriorityClass NamespaceParamsAnnotation, that a
+// namespace matching no configured prefix is unaffected, and that a namespace equal to a
+// configured prefix (stripping to "") is left alone rather than resolving to an empty namespace.
+func TestNamespacePolicyPrefixesToStrip(t *testing.T) {
+	physicalNs := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "tenant-a",
+			Annotations: map[string]string{kubeapiserveradmission.NamespaceParamsAnnotation: `{"Priority":{"defaultPriorityClass":"nondefault1"}}`},
+		},
+	}
+	ctrl := newPlugin(&pluginConfig{
+		NamespacePolicyPrefixesToStrip: []string{"vc-"},
+		NamespacePriorityClassAllowList: map[string][]string{
+			physicalNs.Name: {nondefaultClass1.Name},
+		},
+	})
+	if err := addPriorityClassesAndNamespaces(
+		ctrl,
+		[]*scheduling.PriorityClass{defaultClass1, nondefaultClass1},
+		[]*corev1.Namespace{physicalNs},
+	); err != nil {
+		t.Fatalf("unable to add object to informer: %v", err)
+	}
+
+	newPod := func(namespace, priorityClassName string) *api.Pod {
+		return &api.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod",
+				Namespace: namespace,
+			},
+			Spec: api.PodSpec{
+				Containers:        []api.Container{{Name: "container"}},
+				PriorityClassName: priorityClassName,
+			},
+		}
+	}
+	admit := func(pod *api.Pod) error {
+		attrs := admission.NewAttributesRecord(
+			pod,
+			nil,
+			api.Kind("Pod").WithVersion("version"),
+			pod.Namespace,
+			"",
+			api.Resource("pods").WithVersion("version"),
+			"",
+			admission.Create,
+			false,
+			nil,
+		)
+		return ctrl.Admit(attrs, nil)
+	}
+
+	projected := newPod("vc-tenant-a", "")
+	if err := admit(projected); err != nil {
+		t.Fatalf("unexpected error admitting pod in projected namespace: %v", err)
+	}
+	if projected.Spec.PriorityClassName != nondefaultClass1.Name {
+		t.Errorf("expected the projected namespace's default PriorityClass %q from its physical namespace, got %q", nondefaultClass1.Name, projected.Spec.PriorityClassName)
+	}
+
+	if err := admit(newPod("vc-tenant-a", defaultClass1.Name)); err == nil {
+		t.Errorf("expected a PriorityClassName not on the physical namespace's allow-list to be rejected for the projected namespace")
+	}
+
+	if err := admit(newPod("tenant-a", defaultClass1.Name)); err == nil {
+		t.Errorf("expected the physical namespace itself to still be restricted by its own allow-list")
+	}
+
+	unprefixed := newPod("other-ns", "")
+	if err := admit(unprefixed); err != nil {
+		t.Fatalf("unexpected error admitting pod in unprefixed namespace: %v", err)
+	}
+	if unprefixed.Spec.PriorityClassName != defaultClass1.Name {
+		t.Errorf("expected a namespace matching no configured prefix to keep using the cluster GlobalDefault, got %q", unprefixed.Spec.PriorityClassName)
+	}
+
+	stripsToEmpty := newPod("vc-", "")
+	if err := admit(stripsToEmpty); err != nil {
+		t.Fatalf("unexpected error admitting pod in namespace equal to the configured prefix: %v", err)
+	}
+	if stripsToEmpty.Spec.PriorityClassName != defaultClass1.Name {
+		t.Errorf("expected a namespace equal to the configured prefix to be left alone rather than resolved to an empty namespace, got %q", stripsToEmpty.Spec.PriorityClassName)
+	}
+}
+
+func TestPodAdmissionRequiredNodeAffinityByPriorityClass(t *testing.T) {
+	requiredSelector := api.NodeSelector{
+		NodeSelectorTerms: []api.NodeSelectorTerm{
+			{
+				MatchExpressions: []api.NodeSelectorRequirement{
+					{Key: "node-role.kubernetes.io/control-plane", Operator: api.NodeSelectorOpExists},
+				},
+			},
+		},
+	}
+	ctrl := newPlugin(&pluginConfig{
+		RequiredNodeAffinityByPriorityClass: map[string]api.NodeSelector{
+			nondefaultClass1.Name: requiredSelector,
+		},
+	})
+	if err := addPriorityClasses(ctrl, []*scheduling.PriorityClass{defaultClass1, nondefaultClass1}); err != nil {
+		t.Fatalf("unable to add object to informer: %v", err)
+	}
+
+	admit := func(pod *api.Pod) *api.Pod {
+		attrs := admission.NewAttributesRecord(
+			pod,
+			nil,
+			api.Kind("Pod").WithVersion("version"),
+			"ns",
+			"",
+			api.Resource("pods").WithVersion("version"),
+			"",
+			admission.Create,
+			false,
+			nil,
+		)
+		if err := ctrl.Admit(attrs, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return pod
+	}
+
+	withoutOwnAffinity := admit(&api.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "no-affinity"},
+		Spec: api.PodSpec{
+			Containers:        []api.Container{{Name: "container"}},
+			PriorityClassName: nondefaultClass1.Name,
+		},
+	})
+	if withoutOwnAffinity.Spec.Affinity == nil || withoutOwnAffinity.Spec.Affinity.NodeAffinity == nil {
+		t.Fatalf("expected required node affinity to be injected")
+	}
+	terms := withoutOwnAffinity.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) != 1 || len(terms[0].MatchExpressions) != 1 || terms[0].MatchExpressions[0].Key != "node-role.kubernetes.io/control-plane" {
+		t.Errorf("expected the configured requirement to be injected as-is, got: %+v", terms)
+	}
+
+	withOwnAffinity := admit(&api.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "own-affinity"},
+		Spec: api.PodSpec{
+			Containers:        []api.Container{{Name: "container"}},
+			PriorityClassName: nondefaultClass1.Name,
+			Affinity: &api.Affinity{
+				NodeAffinity: &api.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &api.NodeSelector{
+						NodeSelectorTerms: []api.NodeSelectorTerm{
+							{MatchExpressions: []api.NodeSelectorRequirement{{Key: "disktype", Operator: api.NodeSelectorOpIn, Values: []string{"ssd"}}}},
+						},
+					},
+				},
+			},
+		},
+	})
+	terms = withOwnAffinity.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) != 1 || len(terms[0].MatchExpressions) != 2 {
+		t.Errorf("expected the configured requirement to be AND'd onto the pod's own term, got: %+v", terms)
+	}
+
+	unaffected := admit(&api.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "unaffected"},
+		Spec: api.PodSpec{
+			Containers:        []api.Container{{Name: "container"}},
+			PriorityClassName: defaultClass1.Name,
+		},
+	})
+	if unaffected.Spec.Affinity != nil {
+		t.Errorf("expected a pod with no configured PriorityClassName entry to be left alone")
+	}
+}
+
+// TestPodAdmissionPriorityClassPodQuota verifies that a pod which would exceed its namespace's
+// configured PriorityClassPodQuota is admitted rather than rejected, but is gated: it is
+// annotated with scheduling.PriorityQuotaGatedAnnotationKey and given a
+// scheduling.PriorityQuotaExceededConditionType condition. A pod that keeps the namespace within
+// quota is admitted ungated, and the quota is scoped per namespace.
+func TestPodAdmissionPriorityClassPodQuota(t *testing.T) {
+	ctrl := newPlugin(&pluginConfig{
+		PriorityClassPodQuota: map[string]int32{nondefaultClass1.Name: 1},
+	})
+	existing := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "ns"},
+		Spec:       corev1.PodSpec{PriorityClassName: nondefaultClass1.Name},
+	}
+	if err := addPriorityClassesAndPods(ctrl, []*scheduling.PriorityClass{defaultClass1, nondefaultClass1}, []*corev1.Pod{existing}); err != nil {
+		t.Fatalf("unable to add object to informer: %v", err)
+	}
+
+	admit := func(pod *api.Pod, namespace string) *api.Pod {
+		attrs := admission.NewAttributesRecord(
+			pod,
+			nil,
+			api.Kind("Pod").WithVersion("version"),
+			namespace,
+			"",
+			api.Resource("pods").WithVersion("version"),
+			"",
+			admission.Create,
+			false,
+			nil,
+		)
+		if err := ctrl.Admit(attrs, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return pod
+	}
+
+	gated := admit(&api.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "over-quota"},
+		Spec: api.PodSpec{
+			Containers:        []api.Container{{Name: "container"}},
+			PriorityClassName: nondefaultClass1.Name,
+		},
+	}, "ns")
+	if gated.Annotations[scheduling.PriorityQuotaGatedAnnotationKey] != "true" {
+		t.Errorf("expected pod exceeding its namespace's PriorityClassPodQuota to be gated, got annotations: %+v", gated.Annotations)
+	}
+	found := false
+	for _, c := range gated.Status.Conditions {
+		if c.Type == scheduling.PriorityQuotaExceededConditionType {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a %v condition on the gated pod, got: %+v", scheduling.PriorityQuotaExceededConditionType, gated.Status.Conditions)
+	}
+
+	underQuota := admit(&api.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "under-quota"},
+		Spec: api.PodSpec{
+			Containers:        []api.Container{{Name: "container"}},
+			PriorityClassName: defaultClass1.Name,
+		},
+	}, "ns")
+	if underQuota.Annotations[scheduling.PriorityQuotaGatedAnnotationKey] == "true" {
+		t.Errorf("expected a pod using an unquota'd PriorityClass to be left ungated")
+	}
+
+	otherNamespace := admit(&api.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "other-namespace"},
+		Spec: api.PodSpec{
+			Containers:        []api.Container{{Name: "container"}},
+			PriorityClassName: nondefaultClass1.Name,
+		},
+	}, "other-ns")
+	if otherNamespace.Annotations[scheduling.PriorityQuotaGatedAnnotationKey] == "true" {
+		t.Errorf("expected the quota to be scoped per namespace, but pod in a different namespace was gated")
+	}
+}
+
+// TestCriticalPodAnnotationClassMapping verifies that a configured CriticalPodAnnotationClassMapping
+// entry resolves a legacy annotation-critical pod to its mapped PriorityClassName, that the
+// original kube-system/CriticalPodAnnotationKey behavior still applies when no entry matches, and
+// that a pod matching neither is left to the normal default-priority-class resolution.
+func TestCriticalPodAnnotationClassMapping(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.ExperimentalCriticalPodAnnotation, true)()
+
+	ctrl := newPlugin(&pluginConfig{
+		CriticalPodAnnotationClassMapping: []CriticalPodAnnotationMapping{
+			{NamespacePattern: "team-*", Annotation: "example.com/critical", ClassName: systemClusterCritical.Name},
+		},
+	})
+	if err := addPriorityClassesAndPods(ctrl, []*scheduling.PriorityClass{systemClusterCritical}, nil); err != nil {
+		t.Fatalf("unable to add object to informer: %v", err)
+	}
+
+	admit := func(pod *api.Pod, namespace string) *api.Pod {
+		attrs := admission.NewAttributesRecord(
+			pod,
+			nil,
+			api.Kind("Pod").WithVersion("version"),
+			namespace,
+			"",
+			api.Resource("pods").WithVersion("version"),
+			"",
+			admission.Create,
+			false,
+			nil,
+		)
+		if err := ctrl.Admit(attrs, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return pod
+	}
+
+	mapped := admit(&api.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "mapped",
+			Annotations: map[string]string{"example.com/critical": ""},
+		},
+		Spec: api.PodSpec{Containers: []api.Container{{Name: "container"}}},
+	}, "team-a")
+	if mapped.Spec.PriorityClassName != systemClusterCritical.Name {
+		t.Errorf("expected pod matching a CriticalPodAnnotationClassMapping entry to resolve to %v, got %v", systemClusterCritical.Name, mapped.Spec.PriorityClassName)
+	}
+
+	legacy := admit(&api.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "legacy",
+			Annotations: map[string]string{kubelettypes.CriticalPodAnnotationKey: ""},
+		},
+		Spec: api.PodSpec{Containers: []api.Container{{Name: "container"}}},
+	}, metav1.NamespaceSystem)
+	if legacy.Spec.PriorityClassName != scheduling.SystemClusterCritical {
+		t.Errorf("expected pod matching only the legacy kube-system annotation to resolve to %v, got %v", scheduling.SystemClusterCritical, legacy.Spec.PriorityClassName)
+	}
+
+	unmatched := admit(&api.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "unmatched",
+			Annotations: map[string]string{"example.com/critical": ""},
+		},
+		Spec: api.PodSpec{Containers: []api.Container{{Name: "container"}}},
+	}, "other-ns")
+	if unmatched.Spec.PriorityClassName == systemClusterCritical.Name {
+		t.Errorf("expected pod outside any mapping's NamespacePattern to not be treated as critical")
+	}
+}
+
+// TestDefaultedPriorityClassAnnotation verifies that admitPod records an audit annotation when it
+// fills in a pod's PriorityClassName, but not when the pod already specified one.
+func TestDefaultedPriorityClassAnnotation(t *testing.T) {
+	ctrl := newPlugin(&pluginConfig{})
+	if err := addPriorityClasses(ctrl, []*scheduling.PriorityClass{defaultClass1, nondefaultClass1}); err != nil {
+		t.Fatalf("unable to add object to informer: %v", err)
+	}
+
+	admit := func(pod *api.Pod) *auditinternal.Event {
+		attrs := admission.NewAttributesRecord(
+			pod,
+			nil,
+			api.Kind("Pod").WithVersion("version"),
+			pod.Namespace,
+			"",
+			api.Resource("pods").WithVersion("version"),
+			"",
+			admission.Create,
+			false,
+			nil,
+		)
+		ae := &auditinternal.Event{Level: auditinternal.LevelMetadata}
+		if err := admission.WithAudit(ctrl, ae).(admission.MutationInterface).Admit(attrs, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return ae
+	}
+
+	t.Run("no PriorityClassName specified", func(t *testing.T) {
+		pod := &api.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns"},
+			Spec:       api.PodSpec{Containers: []api.Container{{Name: "container"}}},
+		}
+		ae := admit(pod)
+		if got := ae.Annotations[defaultedPriorityClassAnnotationKey]; got != defaultClass1.Name {
+			t.Errorf("expected defaulted annotation %q, got %q", defaultClass1.Name, got)
+		}
+	})
+
+	t.Run("PriorityClassName explicitly specified", func(t *testing.T) {
+		pod := &api.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns"},
+			Spec: api.PodSpec{
+				Containers:        []api.Container{{Name: "container"}},
+				PriorityClassName: nondefaultClass1.Name,
+			},
+		}
+		ae := admit(pod)
+		if annotation, ok := ae.Annotations[defaultedPriorityClassAnnotationKey]; ok {
+			t.Errorf("expected no defaulted annotation for an explicit PriorityClassName, got %q", annotation)
+		}
+	})
+}
+
+// TestPriorityClassObjectAlias verifies that a pod referencing a name listed in some
+// PriorityClass's own DeprecatedAliases is resolved to that class's canonical name, the same as
+// if the alias had been configured through PriorityClassAliases, and that the resolution is
+// recorded on the pod's admission audit annotations.
+func TestPriorityClassObjectAlias(t *testing.T) {
+	aliasedClass := &scheduling.PriorityClass{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "PriorityClass",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "renamed-class",
+		},
+		Value:             1000,
+		DeprecatedAliases: []string{"old-class-name"},
+	}
+
+	ctrl := newPlugin(&pluginConfig{})
+	if err := addPriorityClasses(ctrl, []*scheduling.PriorityClass{aliasedClass}); err != nil {
+		t.Fatalf("unable to add object to informer: %v", err)
+	}
+
+	pod := &api.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns"},
+		Spec: api.PodSpec{
+			Containers:        []api.Container{{Name: "container"}},
+			PriorityClassName: "old-class-name",
+		},
+	}
+	attrs := admission.NewAttributesRecord(
+		pod,
+		nil,
+		api.Kind("Pod").WithVersion("version"),
+		pod.Namespace,
+		"",
+		api.Resource("pods").WithVersion("version"),
+		"",
+		admission.Create,
+		false,
+		nil,
+	)
+	ae := &auditinternal.Event{Level: auditinternal.LevelMetadata}
+	if err := admission.WithAudit(ctrl, ae).(admission.MutationInterface).Admit(attrs, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pod.Spec.PriorityClassName != aliasedClass.Name {
+		t.Errorf("expected PriorityClassName to be resolved to %q, got %q", aliasedClass.Name, pod.Spec.PriorityClassName)
+	}
+	if got := ae.Annotations[resolvedAliasAnnotationKey]; got != "old-class-name" {
+		t.Errorf("expected resolved-alias annotation %q, got %q", "old-class-name", got)
+	}
+}
+
+// TestMissingPriorityClassRejectionsMetric verifies that missingPriorityClassRejections is
+// incremented when a pod references a PriorityClassName that does not exist, and not for other
+// kinds of admission rejection.
+func TestMissingPriorityClassRejectionsMetric(t *testing.T) {
+	ctrl := newPlugin(&pluginConfig{})
+	if err := addPriorityClasses(ctrl, []*scheduling.PriorityClass{defaultClass1}); err != nil {
+		t.Fatalf("unable to add object to informer: %v", err)
+	}
+
+	admit := func(pod *api.Pod) error {
+		attrs := admission.NewAttributesRecord(
+			pod,
+			nil,
+			api.Kind("Pod").WithVersion("version"),
+			pod.Namespace,
+			"",
+			api.Resource("pods").WithVersion("version"),
+			"",
+			admission.Create,
+			false,
+			nil,
+		)
+		return ctrl.Admit(attrs, nil)
+	}
+
+	before := testutil.ToFloat64(missingPriorityClassRejections)
+	if err := admit(&api.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns"},
+		Spec: api.PodSpec{
+			Containers:        []api.Container{{Name: "container"}},
+			PriorityClassName: "does-not-exist",
+		},
+	}); err == nil {
+		t.Fatalf("expected a nonexistent PriorityClassName to be rejected")
+	}
+	if after := testutil.ToFloat64(missingPriorityClassRejections); after != before+1 {
+		t.Errorf("expected missingPriorityClassRejections to increase by 1, went from %v to %v", before, after)
+	}
+
+	if err := admit(&api.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod2", Namespace: "ns"},
+		Spec: api.PodSpec{
+			Containers:        []api.Container{{Name: "container"}},
+			PriorityClassName: defaultClass1.Name,
+		},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if after := testutil.ToFloat64(missingPriorityClassRejections); after != before+1 {
+		t.Errorf("expected missingPriorityClassRejections to stay unchanged after a valid admission, went from %v to %v", before+1, after)
+	}
+}
+
+// TestPodAdmissionNamespacePriorityCeiling verifies that a namespace configured with a
+// maxPriorityValue via NamespaceParamsAnnotation rejects pods that resolve above it, that pods at
+// or below the ceiling are unaffected, and that namespaces without the annotation are unrestricted.
+func TestPodAdmissionNamespacePriorityCeiling(t *testing.T) {
+	cappedNs := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "capped-ns",
+			Annotations: map[string]string{kubeapiserveradmission.NamespaceParamsAnnotation: `{"Priority":{"maxPriorityValue":"1500"}}`},
+		},
+	}
+	plainNs := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "plain-ns",
+		},
+	}
+
+	ctrl := newPlugin(&pluginConfig{})
+	if err := addPriorityClassesAndNamespaces(
+		ctrl,
+		[]*scheduling.PriorityClass{defaultClass1, nondefaultClass1},
+		[]*corev1.Namespace{cappedNs, plainNs},
+	); err != nil {
+		t.Fatalf("unable to add object to informer: %v", err)
+	}
+
+	admit := func(namespace, priorityClassName string) error {
+		attrs := admission.NewAttributesRecord(
+			&api.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: namespace},
+				Spec: api.PodSpec{
+					Containers:        []api.Container{{Name: "container"}},
+					PriorityClassName: priorityClassName,
+				},
+			},
+			nil,
+			api.Kind("Pod").WithVersion("version"),
+			namespace,
+			"",
+			api.Resource("pods").WithVersion("version"),
+			"",
+			admission.Create,
+			false,
+			nil,
+		)
+		return ctrl.Admit(attrs, nil)
+	}
+
+	if err := admit(cappedNs.Name, nondefaultClass1.Name); err == nil {
+		t.Errorf("expected a pod resolving to priority %d to be rejected in a namespace with a ceiling of 1500", nondefaultClass1.Value)
+	}
+	if err := admit(cappedNs.Name, defaultClass1.Name); err != nil {
+		t.Errorf("unexpected error admitting a pod within the namespace's ceiling: %v", err)
+	}
+	if err := admit(plainNs.Name, nondefaultClass1.Name); err != nil {
+		t.Errorf("unexpected error admitting a pod in a namespace with no configured ceiling: %v", err)
+	}
+}
+
+// TestPriorityCeilingRejectionsMetric verifies that priorityCeilingRejections increases only when
+// a pod is rejected for exceeding its namespace's priority ceiling.
+func TestPriorityCeilingRejectionsMetric(t *testing.T) {
+	cappedNs := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "capped-ns",
+			Annotations: map[string]string{kubeapiserveradmission.NamespaceParamsAnnotation: `{"Priority":{"maxPriorityValue":"1500"}}`},
+		},
+	}
+
+	ctrl := newPlugin(&pluginConfig{})
+	if err := addPriorityClassesAndNamespaces(
+		ctrl,
+		[]*scheduling.PriorityClass{defaultClass1, nondefaultClass1},
+		[]*corev1.Namespace{cappedNs},
+	); err != nil {
+		t.Fatalf("unable to add object to informer: %v", err)
+	}
+
+	admit := func(pod *api.Pod) error {
+		attrs := admission.NewAttributesRecord(
+			pod,
+			nil,
+			api.Kind("Pod").WithVersion("version"),
+			pod.Namespace,
+			"",
+			api.Resource("pods").WithVersion("version"),
+			"",
+			admission.Create,
+			false,
+			nil,
+		)
+		return ctrl.Admit(attrs, nil)
+	}
+
+	before := testutil.ToFloat64(priorityCeilingRejections)
+	if err := admit(&api.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: cappedNs.Name},
+		Spec: api.PodSpec{
+			Containers:        []api.Container{{Name: "container"}},
+			PriorityClassName: nondefaultClass1.Name,
+		},
+	}); err == nil {
+		t.Fatalf("expected a pod above the namespace ceiling to be rejected")
+	}
+	if after := testutil.ToFloat64(priorityCeilingRejections); after != before+1 {
+		t.Errorf("expected priorityCeilingRejections to increase by 1, went from %v to %v", before, after)
+	}
+}
+
+// TestReadConfig verifies that readConfig rejects unknown fields and structurally invalid
+// configuration with a path-based error message, and accepts a valid configuration.
+func TestReadConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name:   "valid config",
+			config: `{"maxGlobalDefaultValue": 1000, "priorityBands": [{"name": "critical", "threshold": 1000}]}`,
+		},
+		{
+			name:    "unknown field is rejected",
+			config:  `{"maxGlobalDefaltValue": 1000}`,
+			wantErr: "maxGlobalDefaltValue",
+		},
+		{
+			name:    "duplicate priority band name is rejected",
+			config:  `{"priorityBands": [{"name": "critical", "threshold": 1000}, {"name": "critical", "threshold": 2000}]}`,
+			wantErr: "priorityBands[1].name",
+		},
+		{
+			name:    "negative maxPriorityClasses is rejected",
+			config:  `{"maxPriorityClasses": -1}`,
+			wantErr: "maxPriorityClasses",
+		},
+		{
+			name:    "invalid candidateConfig is reported with a nested path",
+			config:  `{"candidateConfig": {"maxPriorityClasses": -1}}`,
+			wantErr: "candidateConfig.maxPriorityClasses",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := readConfig(strings.NewReader(test.config))
+			if test.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected an error containing %q, got none", test.wantErr)
+			}
+			if !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("expected error to contain %q, got: %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestPriorityClassDeletion verifies that deleting a PriorityClass still referenced by a pod is
+// rejected, that it is allowed once no pod references it, and that
+// forcePriorityClassDeleteAnnotation overrides the rejection.
+func TestPriorityClassDeletion(t *testing.T) {
+	forcedClass := *nondefaultClass1
+	forcedClass.Name = "forced"
+	forcedClass.Annotations = map[string]string{forcePriorityClassDeleteAnnotation: "true"}
+
+	referencingPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns"},
+		Spec:       corev1.PodSpec{PriorityClassName: nondefaultClass1.Name},
+	}
+
+	del := func(ctrl *priorityPlugin, pcName string) error {
+		attrs := admission.NewAttributesRecord(
+			nil,
+			nil,
+			scheduling.Kind("PriorityClass").WithVersion("version"),
+			"",
+			pcName,
+			scheduling.Resource("priorityclasses").WithVersion("version"),
+			"",
+			admission.Delete,
+			false,
+			nil,
+		)
+		return ctrl.Validate(attrs, nil)
+	}
+
+	t.Run("rejected while a pod still references it", func(t *testing.T) {
+		ctrl := newPlugin(&pluginConfig{})
+		if err := addPriorityClassesAndPods(ctrl, []*scheduling.PriorityClass{nondefaultClass1}, []*corev1.Pod{referencingPod}); err != nil {
+			t.Fatalf("unable to add object to informer: %v", err)
+		}
+		if err := del(ctrl, nondefaultClass1.Name); err == nil {
+			t.Errorf("expected deletion to be rejected while %v/%v still references it", referencingPod.Namespace, referencingPod.Name)
+		}
+	})
+
+	t.Run("allowed once unreferenced", func(t *testing.T) {
+		ctrl := newPlugin(&pluginConfig{})
+		if err := addPriorityClassesAndPods(ctrl, []*scheduling.PriorityClass{nondefaultClass1}, nil); err != nil {
+			t.Fatalf("unable to add object to informer: %v", err)
+		}
+		if err := del(ctrl, nondefaultClass1.Name); err != nil {
+			t.Errorf("unexpected error deleting an unreferenced PriorityClass: %v", err)
+		}
+	})
+
+	t.Run("force annotation overrides the rejection", func(t *testing.T) {
+		ctrl := newPlugin(&pluginConfig{})
+		forcedPod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns"},
+			Spec:       corev1.PodSpec{PriorityClassName: forcedClass.Name},
+		}
+		if err := addPriorityClassesAndPods(ctrl, []*scheduling.PriorityClass{&forcedClass}, []*corev1.Pod{forcedPod}); err != nil {
+			t.Fatalf("unable to add object to informer: %v", err)
+		}
+		if err := del(ctrl, forcedClass.Name); err != nil {
+			t.Errorf("unexpected error deleting a force-annotated PriorityClass: %v", err)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priority
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	api "k8s.io/kubernetes/pkg/apis/core"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Configuration provides configuration for the Priority admission controller.
+type Configuration struct {
+	metav1.TypeMeta
+
+	// PriorityClassAliases maps a legacy or deprecated PriorityClassName to the canonical
+	// PriorityClassName it should be rewritten to at admission time. This eases large-scale
+	// PriorityClass renames without requiring every workload to be updated in lock-step.
+	PriorityClassAliases map[string]string
+	// DowngradePriorityClassName, if set, is the PriorityClassName a pod is admitted with
+	// instead of being rejected when it requests a PriorityClassName it is not permitted to use
+	// (e.g. a system priority requested outside kube-system). Leave empty to reject as before.
+	DowngradePriorityClassName string
+	// PriorityBands, if set, causes every admitted pod to be labeled with the name of the
+	// highest band whose Threshold its resolved priority meets or exceeds. Bands do not need to
+	// be supplied in any particular order.
+	PriorityBands []PriorityBand
+	// MaxGlobalDefaultValue, if non-zero, is the highest Value a PriorityClass may have while
+	// also setting GlobalDefault. This guards against a single mistaken apply of an
+	// otherwise-legitimate high-value PriorityClass silently making every new, unclassed pod in
+	// the cluster priority-critical.
+	MaxGlobalDefaultValue int32
+	// CandidateConfig, if set, holds a proposed configuration that is evaluated against every
+	// pod alongside the configuration above, but never enforced, so operators can validate that
+	// a stricter or otherwise different policy is safe before promoting it to be the active
+	// configuration.
+	CandidateConfig *Configuration
+	// MaxPriorityClasses, if non-zero, is the maximum number of PriorityClass objects allowed to
+	// exist cluster-wide.
+	MaxPriorityClasses int32
+	// PriorityClassBandLimits, if set, further caps the number of PriorityClass objects whose
+	// Value falls within each configured band, so a single value range cannot alone be used to
+	// exhaust the overall MaxPriorityClasses limit. Bands do not need to be supplied in any
+	// particular order.
+	PriorityClassBandLimits []PriorityClassBandLimit
+	// NamespacePriorityClassAllowList, if set, restricts which PriorityClassNames pods in a given
+	// namespace may use. A namespace with no entry in this map is unrestricted (subject only to
+	// the existing system-priority-class check). A namespace with an entry may only use the
+	// PriorityClassNames listed for it.
+	NamespacePriorityClassAllowList map[string][]string
+	// RequiredNodeAffinityByPriorityClass, if set, causes every pod resolved to a given
+	// PriorityClassName to have the configured NodeSelector merged into its required node
+	// affinity at admission time.
+	RequiredNodeAffinityByPriorityClass map[string]api.NodeSelector
+	// LiveLookupOnCacheMiss, if true, causes a PriorityClassName that the informer cache reports
+	// as NotFound to be looked up again with a live read against the API server before the pod
+	// is rejected, tolerating the informer cache lag that follows a PriorityClass having just
+	// been created. Leave false to reject on the cache miss alone, as before.
+	LiveLookupOnCacheMiss bool
+	// LiveLookupTimeout bounds the live lookup enabled by LiveLookupOnCacheMiss, so a slow
+	// apiserver cannot add unbounded latency to pod admission. Defaults to 1 second if unset.
+	LiveLookupTimeout metav1.Duration
+	// PriorityClassPodQuota, if set, maps a PriorityClassName to the maximum number of
+	// non-terminal pods a namespace may have using that PriorityClassName at once. A pod that
+	// would exceed its namespace's quota is admitted rather than rejected, but is gated: it is
+	// annotated with scheduling.PriorityQuotaGatedAnnotationKey and given a
+	// scheduling.PriorityQuotaExceededConditionType condition, so it queues instead of competing
+	// for a node until a companion controller clears the gate once quota frees up.
+	PriorityClassPodQuota map[string]int32
+}
+
+// PriorityClassBandLimit caps the number of PriorityClass objects whose Value is at or above
+// Threshold, up to (but not including) the next-higher configured Threshold.
+type PriorityClassBandLimit struct {
+	// Threshold is the inclusive lower bound of Value this limit applies to.
+	Threshold int32
+	// Max is the maximum number of PriorityClass objects allowed with Value in this band.
+	Max int32
+}
+
+// PriorityBand names a lower bound on pod priority for the purpose of labeling pods with the
+// scheduling.k8s.io/priority-band label.
+type PriorityBand struct {
+	// Name is the value the priority-band label is set to for pods in this band.
+	Name string
+	// Threshold is the minimum resolved priority a pod must have to be considered part of this
+	// band.
+	Threshold int32
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Configuration provides configuration for the Priority admission controller.
+type Configuration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// priorityClassAliases maps a legacy or deprecated priorityClassName to the canonical
+	// priorityClassName it should be rewritten to at admission time.
+	// +optional
+	PriorityClassAliases map[string]string `json:"priorityClassAliases,omitempty"`
+
+	// downgradePriorityClassName, if set, is the priorityClassName a pod is admitted with
+	// instead of being rejected when it requests a priorityClassName it is not permitted to use.
+	// +optional
+	DowngradePriorityClassName string `json:"downgradePriorityClassName,omitempty"`
+
+	// priorityBands, if set, causes every admitted pod to be labeled with the name of the
+	// highest band whose threshold its resolved priority meets or exceeds.
+	// +optional
+	PriorityBands []PriorityBand `json:"priorityBands,omitempty"`
+
+	// maxGlobalDefaultValue, if non-zero, is the highest value a PriorityClass may have while
+	// also setting globalDefault.
+	// +optional
+	MaxGlobalDefaultValue int32 `json:"maxGlobalDefaultValue,omitempty"`
+
+	// candidateConfig, if set, holds a proposed configuration that is shadow-evaluated against
+	// every pod but never enforced.
+	// +optional
+	CandidateConfig *Configuration `json:"candidateConfig,omitempty"`
+
+	// maxPriorityClasses, if non-zero, is the maximum number of PriorityClass objects allowed to
+	// exist cluster-wide.
+	// +optional
+	MaxPriorityClasses int32 `json:"maxPriorityClasses,omitempty"`
+
+	// priorityClassBandLimits, if set, further caps the number of PriorityClass objects whose
+	// value falls within each configured band.
+	// +optional
+	PriorityClassBandLimits []PriorityClassBandLimit `json:"priorityClassBandLimits,omitempty"`
+
+	// namespacePriorityClassAllowList, if set, restricts which priorityClassNames pods in a
+	// given namespace may use.
+	// +optional
+	NamespacePriorityClassAllowList map[string][]string `json:"namespacePriorityClassAllowList,omitempty"`
+
+	// requiredNodeAffinityByPriorityClass, if set, causes every pod resolved to a given
+	// priorityClassName to have the configured nodeSelector merged into its required node
+	// affinity at admission time.
+	// +optional
+	RequiredNodeAffinityByPriorityClass map[string]corev1.NodeSelector `json:"requiredNodeAffinityByPriorityClass,omitempty"`
+
+	// liveLookupOnCacheMiss, if true, causes a priorityClassName that the informer cache
+	// reports as NotFound to be looked up again with a live read against the API server before
+	// the pod is rejected.
+	// +optional
+	LiveLookupOnCacheMiss bool `json:"liveLookupOnCacheMiss,omitempty"`
+
+	// liveLookupTimeout bounds the live lookup enabled by liveLookupOnCacheMiss. Defaults to 1
+	// second if unset.
+	// +optional
+	LiveLookupTimeout metav1.Duration `json:"liveLookupTimeout,omitempty"`
+
+	// priorityClassPodQuota, if set, maps a priorityClassName to the maximum number of
+	// non-terminal pods a namespace may have using that priorityClassName at once. A pod that
+	// would exceed its namespace's quota is admitted but gated, so it queues instead of
+	// competing for a node until a companion controller clears the gate once quota frees up.
+	// +optional
+	PriorityClassPodQuota map[string]int32 `json:"priorityClassPodQuota,omitempty"`
+}
+
+// PriorityClassBandLimit caps the number of PriorityClass objects whose value is at or above
+// threshold, up to (but not including) the next-higher configured threshold.
+type PriorityClassBandLimit struct {
+	// threshold is the inclusive lower bound of value this limit applies to.
+	Threshold int32 `json:"threshold"`
+	// max is the maximum number of PriorityClass objects allowed with value in this band.
+	Max int32 `json:"max"`
+}
+
+// PriorityBand names a lower bound on pod priority for the purpose of labeling pods with the
+// scheduling.k8s.io/priority-band label.
+type PriorityBand struct {
+	// name is the value the priority-band label is set to for pods in this band.
+	Name string `json:"name"`
+	// threshold is the minimum resolved priority a pod must have to be considered part of this
+	// band.
+	Threshold int32 `json:"threshold"`
+}
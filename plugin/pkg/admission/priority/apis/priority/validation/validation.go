@@ -0,0 +1,87 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	priorityapi "k8s.io/kubernetes/plugin/pkg/admission/priority/apis/priority"
+)
+
+// ValidateConfiguration validates the Priority admission plugin configuration, catching
+// structural problems (duplicate or negative values) that strict decoding alone cannot.
+func ValidateConfiguration(config *priorityapi.Configuration) field.ErrorList {
+	return validateConfiguration(config, nil)
+}
+
+func validateConfiguration(config *priorityapi.Configuration, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	bandsPath := fldPath.Child("priorityBands")
+	seenBandNames := map[string]bool{}
+	seenBandThresholds := map[int32]bool{}
+	for i, band := range config.PriorityBands {
+		idxPath := bandsPath.Index(i)
+		if band.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "must not be empty"))
+		} else if seenBandNames[band.Name] {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), band.Name))
+		}
+		seenBandNames[band.Name] = true
+		if seenBandThresholds[band.Threshold] {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("threshold"), band.Threshold))
+		}
+		seenBandThresholds[band.Threshold] = true
+	}
+
+	if config.MaxGlobalDefaultValue < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxGlobalDefaultValue"), config.MaxGlobalDefaultValue, "must not be negative"))
+	}
+	if config.MaxPriorityClasses < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxPriorityClasses"), config.MaxPriorityClasses, "must not be negative"))
+	}
+
+	limitsPath := fldPath.Child("priorityClassBandLimits")
+	seenLimitThresholds := map[int32]bool{}
+	for i, limit := range config.PriorityClassBandLimits {
+		idxPath := limitsPath.Index(i)
+		if limit.Max < 0 {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("max"), limit.Max, "must not be negative"))
+		}
+		if seenLimitThresholds[limit.Threshold] {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("threshold"), limit.Threshold))
+		}
+		seenLimitThresholds[limit.Threshold] = true
+	}
+
+	allowListPath := fldPath.Child("namespacePriorityClassAllowList")
+	for namespace, allowed := range config.NamespacePriorityClassAllowList {
+		if len(allowed) == 0 {
+			allErrs = append(allErrs, field.Invalid(allowListPath.Key(namespace), allowed, "must not be empty; omit the namespace instead of allowing zero PriorityClassNames"))
+		}
+	}
+
+	if config.LiveLookupTimeout.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("liveLookupTimeout"), config.LiveLookupTimeout, "must not be negative"))
+	}
+
+	if config.CandidateConfig != nil {
+		allErrs = append(allErrs, validateConfiguration(config.CandidateConfig, fldPath.Child("candidateConfig"))...)
+	}
+
+	return allErrs
+}
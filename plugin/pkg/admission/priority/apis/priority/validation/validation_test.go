@@ -0,0 +1,145 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	priorityapi "k8s.io/kubernetes/plugin/pkg/admission/priority/apis/priority"
+)
+
+func TestValidateConfiguration(t *testing.T) {
+	cases := []struct {
+		name           string
+		config         priorityapi.Configuration
+		expectedResult bool
+	}{
+		{
+			name:           "empty config",
+			config:         priorityapi.Configuration{},
+			expectedResult: true,
+		},
+		{
+			name: "valid priority bands",
+			config: priorityapi.Configuration{
+				PriorityBands: []priorityapi.PriorityBand{
+					{Name: "low", Threshold: 0},
+					{Name: "high", Threshold: 1000},
+				},
+			},
+			expectedResult: true,
+		},
+		{
+			name: "duplicate band name",
+			config: priorityapi.Configuration{
+				PriorityBands: []priorityapi.PriorityBand{
+					{Name: "low", Threshold: 0},
+					{Name: "low", Threshold: 1000},
+				},
+			},
+			expectedResult: false,
+		},
+		{
+			name: "duplicate band threshold",
+			config: priorityapi.Configuration{
+				PriorityBands: []priorityapi.PriorityBand{
+					{Name: "low", Threshold: 0},
+					{Name: "high", Threshold: 0},
+				},
+			},
+			expectedResult: false,
+		},
+		{
+			name: "empty band name",
+			config: priorityapi.Configuration{
+				PriorityBands: []priorityapi.PriorityBand{
+					{Name: "", Threshold: 0},
+				},
+			},
+			expectedResult: false,
+		},
+		{
+			name: "negative maxGlobalDefaultValue",
+			config: priorityapi.Configuration{
+				MaxGlobalDefaultValue: -1,
+			},
+			expectedResult: false,
+		},
+		{
+			name: "negative maxPriorityClasses",
+			config: priorityapi.Configuration{
+				MaxPriorityClasses: -1,
+			},
+			expectedResult: false,
+		},
+		{
+			name: "negative band limit max",
+			config: priorityapi.Configuration{
+				PriorityClassBandLimits: []priorityapi.PriorityClassBandLimit{
+					{Threshold: 0, Max: -1},
+				},
+			},
+			expectedResult: false,
+		},
+		{
+			name: "duplicate band limit threshold",
+			config: priorityapi.Configuration{
+				PriorityClassBandLimits: []priorityapi.PriorityClassBandLimit{
+					{Threshold: 0, Max: 1},
+					{Threshold: 0, Max: 2},
+				},
+			},
+			expectedResult: false,
+		},
+		{
+			name: "empty namespace allow list",
+			config: priorityapi.Configuration{
+				NamespacePriorityClassAllowList: map[string][]string{
+					"kube-system": {},
+				},
+			},
+			expectedResult: false,
+		},
+		{
+			name: "negative liveLookupTimeout",
+			config: priorityapi.Configuration{
+				LiveLookupTimeout: metav1.Duration{Duration: -1},
+			},
+			expectedResult: false,
+		},
+		{
+			name: "invalid candidate config",
+			config: priorityapi.Configuration{
+				CandidateConfig: &priorityapi.Configuration{
+					MaxPriorityClasses: -1,
+				},
+			},
+			expectedResult: false,
+		},
+	}
+	for _, tc := range cases {
+		errs := ValidateConfiguration(&tc.config)
+		if e, a := tc.expectedResult, len(errs) == 0; e != a {
+			if e {
+				t.Errorf("%v: expected success: %v", tc.name, errs)
+			} else {
+				t.Errorf("%v: expected failure", tc.name)
+			}
+		}
+	}
+}
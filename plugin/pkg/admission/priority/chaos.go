@@ -0,0 +1,56 @@
+// +build debug
+
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priority
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Environment variables consulted by debug builds to inject faults into this plugin's admission
+// path (lister errors, conversion failures, slow lookups), so resilience of the admission chain
+// as a whole can be exercised by integration tests without forking the plugin itself.
+const (
+	chaosListerErrorEnv   = "PRIORITY_ADMISSION_CHAOS_LISTER_ERROR"
+	chaosListerDelayMsEnv = "PRIORITY_ADMISSION_CHAOS_LISTER_DELAY_MS"
+	chaosConversionEnv    = "PRIORITY_ADMISSION_CHAOS_CONVERSION_FAILURE"
+)
+
+// injectListerFault sleeps for chaosListerDelayMsEnv milliseconds if it is set, simulating a slow
+// PriorityClass lookup, and then returns a synthetic error if chaosListerErrorEnv is set,
+// simulating a lister failure. It is compiled in only for binaries built with the "debug" build
+// tag, so it costs nothing in production builds.
+func injectListerFault() error {
+	if delayMs, err := strconv.Atoi(os.Getenv(chaosListerDelayMsEnv)); err == nil && delayMs > 0 {
+		time.Sleep(time.Duration(delayMs) * time.Millisecond)
+	}
+	if os.Getenv(chaosListerErrorEnv) != "" {
+		return fmt.Errorf("injected chaos: PriorityClass lister failure")
+	}
+	return nil
+}
+
+// injectConversionFault reports whether chaosConversionEnv is set, simulating an admission
+// object-conversion failure (a's GetObject() unexpectedly not being the declared kind). It is
+// compiled in only for binaries built with the "debug" build tag.
+func injectConversionFault() bool {
+	return os.Getenv(chaosConversionEnv) != ""
+}
@@ -0,0 +1,67 @@
+// +build debug
+
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priority
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestInjectListerFault(t *testing.T) {
+	defer os.Unsetenv(chaosListerErrorEnv)
+	defer os.Unsetenv(chaosListerDelayMsEnv)
+
+	if err := injectListerFault(); err != nil {
+		t.Errorf("expected no fault by default, got: %v", err)
+	}
+
+	if err := os.Setenv(chaosListerErrorEnv, "1"); err != nil {
+		t.Fatalf("failed to set env: %v", err)
+	}
+	if err := injectListerFault(); err == nil {
+		t.Errorf("expected an injected lister error, got nil")
+	}
+	os.Unsetenv(chaosListerErrorEnv)
+
+	if err := os.Setenv(chaosListerDelayMsEnv, "5"); err != nil {
+		t.Fatalf("failed to set env: %v", err)
+	}
+	start := time.Now()
+	if err := injectListerFault(); err != nil {
+		t.Errorf("expected no fault, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 5*time.Millisecond {
+		t.Errorf("expected injectListerFault to sleep at least 5ms, took %v", elapsed)
+	}
+}
+
+func TestInjectConversionFault(t *testing.T) {
+	defer os.Unsetenv(chaosConversionEnv)
+
+	if injectConversionFault() {
+		t.Errorf("expected no fault by default")
+	}
+	if err := os.Setenv(chaosConversionEnv, "1"); err != nil {
+		t.Fatalf("failed to set env: %v", err)
+	}
+	if !injectConversionFault() {
+		t.Errorf("expected an injected conversion fault")
+	}
+}
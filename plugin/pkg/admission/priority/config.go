@@ -0,0 +1,76 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priority
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	priorityapi "k8s.io/kubernetes/plugin/pkg/admission/priority/apis/priority"
+	"k8s.io/kubernetes/plugin/pkg/admission/priority/apis/priority/install"
+	priorityv1alpha1 "k8s.io/kubernetes/plugin/pkg/admission/priority/apis/priority/v1alpha1"
+)
+
+var (
+	scheme = runtime.NewScheme()
+	codecs = serializer.NewCodecFactory(scheme)
+)
+
+func init() {
+	install.Install(scheme)
+}
+
+// LoadConfiguration loads the versioned Priority admission plugin configuration provided with
+// --admission-control-config-file, returning a defaulted, internal Configuration if none is
+// provided.
+func LoadConfiguration(config io.Reader) (*priorityapi.Configuration, error) {
+	if config == nil || reflect.ValueOf(config).IsNil() {
+		externalConfig := &priorityv1alpha1.Configuration{}
+		scheme.Default(externalConfig)
+		internalConfig := &priorityapi.Configuration{}
+		if err := scheme.Convert(externalConfig, internalConfig, nil); err != nil {
+			return nil, err
+		}
+		return internalConfig, nil
+	}
+	data, err := ioutil.ReadAll(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s configuration: %v", PluginName, err)
+	}
+	if len(data) == 0 {
+		externalConfig := &priorityv1alpha1.Configuration{}
+		scheme.Default(externalConfig)
+		internalConfig := &priorityapi.Configuration{}
+		if err := scheme.Convert(externalConfig, internalConfig, nil); err != nil {
+			return nil, err
+		}
+		return internalConfig, nil
+	}
+	decodedObj, err := runtime.Decode(codecs.UniversalDecoder(), data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s configuration: %v", PluginName, err)
+	}
+	priorityConfiguration, ok := decodedObj.(*priorityapi.Configuration)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type: %T", decodedObj)
+	}
+	return priorityConfiguration, nil
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priority
+
+import (
+	"strconv"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+// shadowDivergences counts pods for which the shadow-evaluated candidate configuration would
+// have made a different admission decision than the active configuration, by the kind of
+// divergence observed. This lets operators judge whether a candidate configuration is safe to
+// promote before it is ever enforced.
+var shadowDivergences = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Subsystem: "admission_priority",
+		Name:      "shadow_divergences_total",
+		Help:      "Number of pods for which the Priority admission plugin's shadow-evaluated candidate configuration would have diverged from the active configuration, by divergence kind.",
+	}, []string{"kind"})
+
+// priorityClassDecisionLatency records how long the PriorityClass lookup and resolution took at
+// admission time, by PriorityClassName. Most of this time is a local informer cache read, so a
+// growing tail here usually points at lock contention or an oversized cluster rather than API
+// latency.
+var priorityClassDecisionLatency = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Subsystem: "admission_priority",
+		Name:      "class_decision_latency_seconds",
+		Help:      "Latency of resolving a pod's PriorityClass at admission time, by PriorityClassName.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"priority_class"})
+
+// priorityClassListerStaleness records, for every successfully resolved PriorityClass, how many
+// resourceVersions behind the informer's cached copy was relative to a live read of the same
+// object at the same moment. A PriorityClass's resourceVersion is not documented to be numeric,
+// but in practice (etcd-backed clusters) it is a monotonically increasing integer, so the
+// difference is a usable proxy for informer lag. This is sampled rather than measured on every
+// admission, since it costs an extra apiserver round trip.
+var priorityClassListerStaleness = prometheus.NewHistogram(
+	prometheus.HistogramOpts{
+		Subsystem: "admission_priority",
+		Name:      "class_lister_staleness",
+		Help:      "Sampled difference between a live-read PriorityClass resourceVersion and the informer-cached resourceVersion used to admit a pod, so spurious NotFound rejections caused by informer lag can be quantified.",
+		Buckets:   []float64{0, 1, 2, 5, 10, 50, 100, 500},
+	})
+
+// podAdmissionDecisions counts pods admitted or rejected by this plugin, by the resolved
+// PriorityClassName (empty if resolution never got that far) and by decision ("admitted" or
+// "rejected"). This is registered via the component-base legacyregistry, unlike the metrics
+// above, so that the counts show up alongside the rest of the control plane's stable metrics.
+var podAdmissionDecisions = metrics.NewCounterVec(
+	&metrics.CounterOpts{
+		Subsystem:      "admission_priority",
+		Name:           "pod_admission_decisions_total",
+		Help:           "Number of pods admitted or rejected by the Priority admission plugin, by PriorityClassName and decision.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"priority_class", "decision"})
+
+// podAdmissionLatency is a raw prometheus.Histogram, rather than a component-base metric, because
+// this package has no Histogram wrapper type (see k8s.io/component-base/metrics); it is
+// registered through metrics.NewLegacyCollector so it is still exposed via the legacyregistry
+// alongside podAdmissionDecisions. It measures the full cost of admitPod, unlike
+// priorityClassDecisionLatency above, which measures only the PriorityClass lookup within it.
+var podAdmissionLatency = prometheus.NewHistogram(
+	prometheus.HistogramOpts{
+		Subsystem: "admission_priority",
+		Name:      "pod_admission_latency_seconds",
+		Help:      "[ALPHA] Latency of the Priority admission plugin's full admission decision for a pod.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+// missingPriorityClassRejections is a raw prometheus.Gauge, for the same reason as
+// podAdmissionLatency, counting pods rejected for referencing a PriorityClassName that does not
+// exist. This value only ever increases over the lifetime of the process.
+var missingPriorityClassRejections = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Subsystem: "admission_priority",
+		Name:      "missing_priority_class_rejections",
+		Help:      "[ALPHA] Number of pods rejected because they referenced a PriorityClassName that does not exist.",
+	})
+
+// priorityCeilingRejections is a raw prometheus.Gauge, for the same reason as
+// podAdmissionLatency, counting pods rejected for resolving to a priority above the ceiling
+// configured for their namespace. This value only ever increases over the lifetime of the
+// process.
+var priorityCeilingRejections = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Subsystem: "admission_priority",
+		Name:      "priority_ceiling_rejections",
+		Help:      "[ALPHA] Number of pods rejected because their resolved priority exceeded the ceiling configured for their namespace.",
+	})
+
+// priorityClassInventory reports, per configured PriorityBand name (empty string for a
+// PriorityClass whose Value falls below every configured band's Threshold), how many
+// PriorityClass objects currently exist in that band. It is republished in full every time a
+// PriorityClass is added, updated, or deleted, so a stale reading never lingers past the next
+// informer event.
+var priorityClassInventory = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Subsystem: "admission_priority",
+		Name:      "priority_class_inventory",
+		Help:      "Number of PriorityClass objects that currently exist in each configured PriorityBand.",
+	}, []string{"band"})
+
+// priorityClassGlobalDefaultConfigured is 1 if exactly one PriorityClass currently has
+// GlobalDefault set, and 0 otherwise, including the misconfigured case of more than one
+// GlobalDefault existing simultaneously (the apiserver's own admission control should prevent
+// that, but this metric is meant to surface exactly this kind of drift if it ever happens).
+var priorityClassGlobalDefaultConfigured = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Subsystem: "admission_priority",
+		Name:      "global_default_priority_class_configured",
+		Help:      "1 if exactly one PriorityClass has GlobalDefault set, 0 if none or more than one does.",
+	})
+
+// priorityClassGlobalDefaultValue is the Value of the cluster's GlobalDefault PriorityClass, or 0
+// if none is configured. Consult priorityClassGlobalDefaultConfigured to distinguish "no default"
+// from "default value happens to be 0".
+var priorityClassGlobalDefaultValue = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Subsystem: "admission_priority",
+		Name:      "global_default_priority_class_value",
+		Help:      "Value of the cluster's GlobalDefault PriorityClass, or 0 if none is configured.",
+	})
+
+func init() {
+	prometheus.MustRegister(shadowDivergences)
+	prometheus.MustRegister(priorityClassDecisionLatency)
+	prometheus.MustRegister(priorityClassListerStaleness)
+	prometheus.MustRegister(priorityClassInventory)
+	prometheus.MustRegister(priorityClassGlobalDefaultConfigured)
+	prometheus.MustRegister(priorityClassGlobalDefaultValue)
+	legacyregistry.MustRegister(podAdmissionDecisions)
+	legacyregistry.MustRegister(metrics.NewLegacyCollector(podAdmissionLatency))
+	legacyregistry.MustRegister(metrics.NewLegacyCollector(missingPriorityClassRejections))
+	legacyregistry.MustRegister(metrics.NewLegacyCollector(priorityCeilingRejections))
+}
+
+// observePriorityClassInventory republishes priorityClassInventory, priorityClassGlobalDefaultConfigured,
+// and priorityClassGlobalDefaultValue from the current set of PriorityClasses, banding each one
+// with bandFor.
+func observePriorityClassInventory(classes []*schedulingv1.PriorityClass, bandFor func(int32) string) {
+	counts := map[string]int{}
+	var globalDefaults int
+	var globalDefaultValue int32
+	for _, pc := range classes {
+		counts[bandFor(pc.Value)]++
+		if pc.GlobalDefault {
+			globalDefaults++
+			globalDefaultValue = pc.Value
+		}
+	}
+	priorityClassInventory.Reset()
+	for band, count := range counts {
+		priorityClassInventory.WithLabelValues(band).Set(float64(count))
+	}
+	if globalDefaults == 1 {
+		priorityClassGlobalDefaultConfigured.Set(1)
+		priorityClassGlobalDefaultValue.Set(float64(globalDefaultValue))
+	} else {
+		priorityClassGlobalDefaultConfigured.Set(0)
+		priorityClassGlobalDefaultValue.Set(0)
+	}
+}
+
+// staleSampleRate is the fraction (1 in staleSampleRate) of admission decisions that pay for the
+// extra live apiserver read needed to measure lister staleness.
+const staleSampleRate = 20
+
+var staleSampleCounter uint64
+
+// shouldSampleStaleness reports whether the current decision should be used to sample
+// priorityClassListerStaleness.
+func shouldSampleStaleness() bool {
+	return atomic.AddUint64(&staleSampleCounter, 1)%staleSampleRate == 0
+}
+
+// resourceVersionDiff returns how far live is ahead of cached, treating both as the
+// monotonically increasing integers Kubernetes' etcd-backed implementations use for
+// resourceVersion. It returns false if either value isn't parseable as one, which is always
+// permitted by the API contract; resourceVersion is documented as an opaque string.
+func resourceVersionDiff(live, cached string) (float64, bool) {
+	liveRV, err := strconv.ParseInt(live, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	cachedRV, err := strconv.ParseInt(cached, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	if liveRV < cachedRV {
+		return 0, true
+	}
+	return float64(liveRV - cachedRV), true
+}
@@ -99,6 +99,14 @@ func (m *PriorityClass) MarshalTo(dAtA []byte) (int, error) {
 	i++
 	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Description)))
 	i += copy(dAtA[i:], m.Description)
+	if len(m.DeprecatedAliases) > 0 {
+		for _, s := range m.DeprecatedAliases {
+			dAtA[i] = 0x2a
+			i++
+			i = encodeVarintGenerated(dAtA, i, uint64(len(s)))
+			i += copy(dAtA[i:], s)
+		}
+	}
 	return i, nil
 }
 
@@ -158,6 +166,12 @@ func (m *PriorityClass) Size() (n int) {
 	n += 2
 	l = len(m.Description)
 	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.DeprecatedAliases) > 0 {
+		for _, s := range m.DeprecatedAliases {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -197,6 +211,7 @@ func (this *PriorityClass) String() string {
 		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
 		`GlobalDefault:` + fmt.Sprintf("%v", this.GlobalDefault) + `,`,
 		`Description:` + fmt.Sprintf("%v", this.Description) + `,`,
+		`DeprecatedAliases:` + fmt.Sprintf("%v", this.DeprecatedAliases) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -347,6 +362,35 @@ func (m *PriorityClass) Unmarshal(dAtA []byte) error {
 			}
 			m.Description = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DeprecatedAliases", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DeprecatedAliases = append(m.DeprecatedAliases, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipGenerated(dAtA[iNdEx:])
@@ -49,6 +49,14 @@ type PriorityClass struct {
 	// when this priority class should be used.
 	// +optional
 	Description string `json:"description,omitempty" protobuf:"bytes,4,opt,name=description"`
+
+	// deprecatedAliases lists PriorityClassNames that pods may still reference in place of this
+	// PriorityClass's own name. The priority admission plugin resolves a pod's PriorityClassName
+	// to this class's name whenever it matches one of these aliases, and records the resolution
+	// on the pod so a rename can be rolled out across a fleet's manifests without breaking any
+	// pod still using an old name.
+	// +optional
+	DeprecatedAliases []string `json:"deprecatedAliases,omitempty" protobuf:"bytes,5,rep,name=deprecatedAliases"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
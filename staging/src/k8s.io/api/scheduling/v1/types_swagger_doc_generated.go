@@ -33,6 +33,7 @@ var map_PriorityClass = map[string]string{
 	"value":         "The value of this priority class. This is the actual priority that pods receive when they have the name of this class in their pod spec.",
 	"globalDefault": "globalDefault specifies whether this PriorityClass should be considered as the default priority for pods that do not have any priority class. Only one PriorityClass can be marked as `globalDefault`. However, if more than one PriorityClasses exists with their `globalDefault` field set to true, the smallest value of such global default PriorityClasses will be used as the default priority.",
 	"description":   "description is an arbitrary string that usually provides guidelines on when this priority class should be used.",
+	"deprecatedAliases": "deprecatedAliases lists PriorityClassNames that pods may still reference in place of this PriorityClass's own name. The priority admission plugin resolves a pod's PriorityClassName to this class's name whenever it matches one of these aliases, and records the resolution on the pod so a rename can be rolled out across a fleet's manifests without breaking any pod still using an old name.",
 }
 
 func (PriorityClass) SwaggerDoc() map[string]string {
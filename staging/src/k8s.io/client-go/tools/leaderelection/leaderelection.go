@@ -291,6 +291,11 @@ func (le *LeaderElector) release() bool {
 // else it tries to renew the lease if it has already been acquired. Returns true
 // on success else returns false.
 func (le *LeaderElector) tryAcquireOrRenew() bool {
+	start := le.clock.Now()
+	defer func() {
+		le.metrics.observeRenewLatency(le.config.Name, le.clock.Since(start))
+	}()
+
 	now := metav1.Now()
 	leaderElectionRecord := rl.LeaderElectionRecord{
 		HolderIdentity:       le.config.Lock.Identity(),
@@ -351,6 +356,7 @@ func (le *LeaderElector) maybeReportTransition() {
 		return
 	}
 	le.reportedLeader = le.observedRecord.HolderIdentity
+	le.metrics.leaderTransition(le.config.Name)
 	if le.config.Callbacks.OnNewLeader != nil {
 		go le.config.Callbacks.OnNewLeader(le.reportedLeader)
 	}
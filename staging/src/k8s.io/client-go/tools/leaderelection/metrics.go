@@ -18,6 +18,7 @@ package leaderelection
 
 import (
 	"sync"
+	"time"
 )
 
 // This file provides abstractions for setting the provider (e.g., prometheus)
@@ -26,6 +27,8 @@ import (
 type leaderMetricsAdapter interface {
 	leaderOn(name string)
 	leaderOff(name string)
+	leaderTransition(name string)
+	observeRenewLatency(name string, latency time.Duration)
 }
 
 // GaugeMetric represents a single numerical value that can arbitrarily go up
@@ -35,15 +38,31 @@ type SwitchMetric interface {
 	Off(name string)
 }
 
+// CounterMetric represents a single numerical value that only ever goes up.
+type CounterMetric interface {
+	Inc(name string)
+}
+
+// SummaryMetric captures individual observations.
+type SummaryMetric interface {
+	Observe(name string, value float64)
+}
+
 type noopMetric struct{}
 
-func (noopMetric) On(name string)  {}
-func (noopMetric) Off(name string) {}
+func (noopMetric) On(name string)                     {}
+func (noopMetric) Off(name string)                    {}
+func (noopMetric) Inc(name string)                    {}
+func (noopMetric) Observe(name string, value float64) {}
 
 // defaultLeaderMetrics expects the caller to lock before setting any metrics.
 type defaultLeaderMetrics struct {
 	// leader's value indicates if the current process is the owner of name lease
 	leader SwitchMetric
+	// transitions counts how many times the observed leader of name has changed
+	transitions CounterMetric
+	// renewLatency observes, in seconds, how long each tryAcquireOrRenew call against name took
+	renewLatency SummaryMetric
 }
 
 func (m *defaultLeaderMetrics) leaderOn(name string) {
@@ -60,14 +79,32 @@ func (m *defaultLeaderMetrics) leaderOff(name string) {
 	m.leader.Off(name)
 }
 
+func (m *defaultLeaderMetrics) leaderTransition(name string) {
+	if m == nil {
+		return
+	}
+	m.transitions.Inc(name)
+}
+
+func (m *defaultLeaderMetrics) observeRenewLatency(name string, latency time.Duration) {
+	if m == nil {
+		return
+	}
+	m.renewLatency.Observe(name, latency.Seconds())
+}
+
 type noMetrics struct{}
 
-func (noMetrics) leaderOn(name string)  {}
-func (noMetrics) leaderOff(name string) {}
+func (noMetrics) leaderOn(name string)                                   {}
+func (noMetrics) leaderOff(name string)                                  {}
+func (noMetrics) leaderTransition(name string)                           {}
+func (noMetrics) observeRenewLatency(name string, latency time.Duration) {}
 
 // MetricsProvider generates various metrics used by the leader election.
 type MetricsProvider interface {
 	NewLeaderMetric() SwitchMetric
+	NewTransitionsMetric() CounterMetric
+	NewRenewLatencyMetric() SummaryMetric
 }
 
 type noopMetricsProvider struct{}
@@ -76,6 +113,14 @@ func (_ noopMetricsProvider) NewLeaderMetric() SwitchMetric {
 	return noopMetric{}
 }
 
+func (_ noopMetricsProvider) NewTransitionsMetric() CounterMetric {
+	return noopMetric{}
+}
+
+func (_ noopMetricsProvider) NewRenewLatencyMetric() SummaryMetric {
+	return noopMetric{}
+}
+
 var globalMetricsFactory = leaderMetricsFactory{
 	metricsProvider: noopMetricsProvider{},
 }
@@ -98,7 +143,9 @@ func (f *leaderMetricsFactory) newLeaderMetrics() leaderMetricsAdapter {
 		return noMetrics{}
 	}
 	return &defaultLeaderMetrics{
-		leader: mp.NewLeaderMetric(),
+		leader:       mp.NewLeaderMetric(),
+		transitions:  mp.NewTransitionsMetric(),
+		renewLatency: mp.NewRenewLatencyMetric(),
 	}
 }
 
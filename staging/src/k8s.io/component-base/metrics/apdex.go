@@ -0,0 +1,84 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "time"
+
+// apdexOutcomeLabel is the label ApdexVec appends to the labels it is given, holding which of the
+// three Apdex buckets an observation fell into.
+const apdexOutcomeLabel = "outcome"
+
+const (
+	// ApdexSatisfied is the outcome for an observation at or below SatisfiedThreshold.
+	ApdexSatisfied = "satisfied"
+	// ApdexTolerating is the outcome for an observation above SatisfiedThreshold but at or below
+	// ToleratingThreshold.
+	ApdexTolerating = "tolerating"
+	// ApdexFrustrated is the outcome for an observation above ToleratingThreshold.
+	ApdexFrustrated = "frustrated"
+)
+
+// ApdexOpts is the configuration for an ApdexVec. See KubeOpts for the embedded fields.
+type ApdexOpts struct {
+	CounterOpts
+	// SatisfiedThreshold is the largest duration an observation can have and still count as
+	// ApdexSatisfied.
+	SatisfiedThreshold time.Duration
+	// ToleratingThreshold is the largest duration an observation can have and still count as
+	// ApdexTolerating rather than ApdexFrustrated. It must be greater than SatisfiedThreshold.
+	ToleratingThreshold time.Duration
+}
+
+// ApdexVec classifies latency observations, labeled like a CounterVec (e.g. by verb), into
+// ApdexSatisfied/ApdexTolerating/ApdexFrustrated buckets against configured thresholds. A
+// RecordingRule with Op: RecordingRuleApdex turns the buckets for a given label combination back
+// into a single 0-1 satisfaction score at gather time, so a component can expose a ready-made
+// Apdex score for a serving path without hand-rolling the classification at every call site.
+type ApdexVec struct {
+	*CounterVec
+	satisfiedThreshold  time.Duration
+	toleratingThreshold time.Duration
+}
+
+// NewApdexVec returns an ApdexVec which satisfies the KubeCollector interface. As with
+// NewCounterVec, the object returned will not measure anything unless it is first registered,
+// since the metric is lazily instantiated.
+func NewApdexVec(opts *ApdexOpts, labels []string) *ApdexVec {
+	return &ApdexVec{
+		CounterVec:          NewCounterVec(&opts.CounterOpts, append(append([]string{}, labels...), apdexOutcomeLabel)),
+		satisfiedThreshold:  opts.SatisfiedThreshold,
+		toleratingThreshold: opts.ToleratingThreshold,
+	}
+}
+
+// Observe classifies elapsed against the configured thresholds and increments the counter for
+// the resulting outcome, for the given label values (in the same order as the labels ApdexVec was
+// created with; do not include a value for the outcome label).
+func (v *ApdexVec) Observe(elapsed time.Duration, lvs ...string) {
+	v.WithLabelValues(append(append([]string{}, lvs...), v.outcomeFor(elapsed))...).Inc()
+}
+
+func (v *ApdexVec) outcomeFor(elapsed time.Duration) string {
+	switch {
+	case elapsed <= v.satisfiedThreshold:
+		return ApdexSatisfied
+	case elapsed <= v.toleratingThreshold:
+		return ApdexTolerating
+	default:
+		return ApdexFrustrated
+	}
+}
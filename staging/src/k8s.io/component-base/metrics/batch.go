@@ -0,0 +1,62 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+// Batch accumulates metric updates for a single logical unit of work (e.g. one request) and
+// applies them together, so a unit of work that only partially completes does not leave a subset
+// of its metrics recorded and skewing the ratios kept between them. Updates are queued with Inc
+// and Add and are only actually applied to their underlying metrics by Do, and only if the work
+// it wraps returns without panicking.
+//
+// A Batch is not safe for concurrent use; each request (or other unit of work) should use its
+// own Batch.
+type Batch struct {
+	ops []func()
+}
+
+// NewBatch returns an empty Batch.
+func NewBatch() *Batch {
+	return &Batch{}
+}
+
+// Inc queues c.Inc() to run when the Batch is committed.
+func (b *Batch) Inc(c CounterMetric) {
+	b.ops = append(b.ops, c.Inc)
+}
+
+// Add queues c.Add(v) to run when the Batch is committed.
+func (b *Batch) Add(c CounterMetric, v float64) {
+	b.ops = append(b.ops, func() { c.Add(v) })
+}
+
+// Do runs fn and then, only if fn returns without panicking, applies every update queued on b
+// (in the order they were queued) by calling commit. If fn panics, the panic propagates out of
+// Do before commit runs, so none of the queued updates are applied.
+func (b *Batch) Do(fn func()) {
+	fn()
+	b.commit()
+}
+
+// commit applies every queued update and empties the queue, so a Batch reused after Do can be
+// filled and committed again without re-applying earlier updates.
+func (b *Batch) commit() {
+	ops := b.ops
+	b.ops = nil
+	for _, op := range ops {
+		op()
+	}
+}
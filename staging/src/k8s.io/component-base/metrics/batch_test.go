@@ -0,0 +1,77 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "testing"
+
+type fakeCounter struct {
+	value float64
+}
+
+func (f *fakeCounter) Inc()          { f.value++ }
+func (f *fakeCounter) Add(v float64) { f.value += v }
+
+func TestBatchCommitsOnSuccess(t *testing.T) {
+	requests := &fakeCounter{}
+	errors := &fakeCounter{}
+
+	b := NewBatch()
+	b.Do(func() {
+		b.Inc(requests)
+		b.Add(errors, 2)
+	})
+
+	if requests.value != 1 {
+		t.Errorf("expected requests to be incremented once, got %v", requests.value)
+	}
+	if errors.value != 2 {
+		t.Errorf("expected errors to be incremented by 2, got %v", errors.value)
+	}
+}
+
+func TestBatchDiscardsOnPanic(t *testing.T) {
+	requests := &fakeCounter{}
+
+	b := NewBatch()
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatalf("expected Do to propagate the panic")
+			}
+		}()
+		b.Do(func() {
+			b.Inc(requests)
+			panic("request handling failed")
+		})
+	}()
+
+	if requests.value != 0 {
+		t.Errorf("expected no queued updates to be applied after a panic, got %v", requests.value)
+	}
+}
+
+func TestBatchReusableAfterCommit(t *testing.T) {
+	requests := &fakeCounter{}
+
+	b := NewBatch()
+	b.Do(func() { b.Inc(requests) })
+	b.Do(func() { b.Inc(requests) })
+
+	if requests.value != 2 {
+		t.Errorf("expected two independent commits to each apply once, got %v", requests.value)
+	}
+}
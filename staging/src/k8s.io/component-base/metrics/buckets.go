@@ -0,0 +1,75 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "fmt"
+
+// The catalog below is the single source of truth for histogram bucket boundaries shared across
+// components. A request duration histogram in the apiserver and one in the kubelet are only
+// comparable if they bucket their observations the same way; letting each component pick its own
+// boundaries quietly defeats that comparison. STABLE histograms are expected to use one of these
+// catalog entries (verified by ValidateHistogramBuckets) rather than defining ad hoc bucket
+// boundaries. Entries are additive-only: an existing catalog's boundaries must not change once a
+// STABLE metric depends on them, since that would silently reshape that metric's exported buckets.
+var (
+	// APILatencyBuckets (v1) is for measuring latencies of requests to an HTTP-style API,
+	// expressed in seconds. It spans single-digit milliseconds through tens of seconds, which
+	// covers everything from a cache-hit read to a slow write against etcd.
+	APILatencyBuckets = []float64{0.001, 0.002, 0.004, 0.008, 0.016, 0.032, 0.064, 0.128, 0.256, 0.512, 1.024, 2.048, 4.096, 8.192, 16.384, 32.768}
+
+	// CacheSizeBuckets (v1) is for measuring the size of an in-memory cache or working set,
+	// expressed as a count of items. It spans single items through the low millions, geometric by
+	// powers of two, which covers everything from a per-namespace informer cache to a
+	// cluster-wide object store.
+	CacheSizeBuckets = []float64{1, 2, 4, 8, 16, 32, 64, 128, 256, 512, 1024, 2048, 4096, 8192, 16384, 32768, 65536, 131072, 262144, 524288, 1048576, 2097152, 4194304}
+)
+
+// histogramBucketCatalog maps each catalog entry above to a name usable in error messages. It is
+// derived from the exported vars rather than duplicated by hand so the two can never drift apart.
+var histogramBucketCatalog = map[string][]float64{
+	"APILatencyBuckets": APILatencyBuckets,
+	"CacheSizeBuckets":  CacheSizeBuckets,
+}
+
+// ValidateHistogramBuckets reports an error if stability is STABLE and buckets does not exactly
+// match one of the named catalogs above. It is intended to be called wherever a component
+// registers a histogram, so that a STABLE latency or size metric cannot silently pick its own
+// bucket boundaries and drift out of comparability with the same metric in other components. ALPHA
+// histograms are unrestricted, since their bucket boundaries are still expected to be tuned.
+func ValidateHistogramBuckets(stability StabilityLevel, buckets []float64) error {
+	if stability != STABLE {
+		return nil
+	}
+	for _, catalog := range histogramBucketCatalog {
+		if bucketsEqual(catalog, buckets) {
+			return nil
+		}
+	}
+	return fmt.Errorf("STABLE histograms must use one of the shared bucket catalogs in component-base/metrics/buckets.go, got %v", buckets)
+}
+
+func bucketsEqual(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "testing"
+
+func TestValidateHistogramBucketsAllowsCatalogEntries(t *testing.T) {
+	for name, buckets := range histogramBucketCatalog {
+		if err := ValidateHistogramBuckets(STABLE, buckets); err != nil {
+			t.Errorf("expected %v to be accepted for STABLE, got: %v", name, err)
+		}
+	}
+}
+
+func TestValidateHistogramBucketsRejectsAdHocBucketsForStable(t *testing.T) {
+	if err := ValidateHistogramBuckets(STABLE, []float64{0.5, 1, 5}); err == nil {
+		t.Errorf("expected an error for a STABLE histogram with ad hoc buckets")
+	}
+}
+
+func TestValidateHistogramBucketsUnrestrictedForAlpha(t *testing.T) {
+	if err := ValidateHistogramBuckets(ALPHA, []float64{0.5, 1, 5}); err != nil {
+		t.Errorf("expected ALPHA histograms to be unrestricted, got: %v", err)
+	}
+}
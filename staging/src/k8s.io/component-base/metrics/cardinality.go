@@ -0,0 +1,109 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sort"
+	"sync"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// registeredMetricSeriesName is the metric name a kubeRegistry exposes its own cardinality
+// accounting under.
+const registeredMetricSeriesName = "registered_metric_series"
+
+// CardinalityOffender is one metric family's series count, as reported by
+// KubeRegistry.TopCardinalityOffenders.
+type CardinalityOffender struct {
+	// Name is the metric family's name.
+	Name string
+	// Series is the number of distinct label combinations the family had at the most recent
+	// Gather.
+	Series int
+}
+
+// cardinalityTracker records, after every Gather, how many series (distinct label combinations)
+// each metric family currently has, so operators can find cardinality hot spots in a running
+// kube-apiserver or kubelet without external tooling. It counts series as observed in the already
+// gathered families rather than instrumenting every WithLabelValues call, since that reuses work
+// the exposition endpoint pays for anyway and needs no cooperation from individual collectors.
+type cardinalityTracker struct {
+	mu     sync.RWMutex
+	series map[string]int
+}
+
+func newCardinalityTracker() *cardinalityTracker {
+	return &cardinalityTracker{series: map[string]int{}}
+}
+
+// update replaces the tracked series counts with those observed in families.
+func (t *cardinalityTracker) update(families []*dto.MetricFamily) {
+	series := make(map[string]int, len(families))
+	for _, family := range families {
+		series[family.GetName()] = len(family.GetMetric())
+	}
+	t.mu.Lock()
+	t.series = series
+	t.mu.Unlock()
+}
+
+// selfMetricFamily returns the registered_metric_series MetricFamily for the series counts
+// tracked as of the most recent update, one series per tracked metric family, labeled by name.
+func (t *cardinalityTracker) selfMetricFamily() *dto.MetricFamily {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	name := registeredMetricSeriesName
+	help := "Number of distinct label combinations (series) each metric family had at the most recent Gather, so operators can find cardinality hot spots without external tooling."
+	familyType := dto.MetricType_GAUGE
+	nameLabel := "name"
+	metrics := make([]*dto.Metric, 0, len(t.series))
+	for family, count := range t.series {
+		family, count := family, count
+		value := float64(count)
+		metrics = append(metrics, &dto.Metric{
+			Label: []*dto.LabelPair{{Name: &nameLabel, Value: &family}},
+			Gauge: &dto.Gauge{Value: &value},
+		})
+	}
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].GetLabel()[0].GetValue() < metrics[j].GetLabel()[0].GetValue()
+	})
+	return &dto.MetricFamily{Name: &name, Help: &help, Type: &familyType, Metric: metrics}
+}
+
+// TopOffenders returns the n metric families with the highest series count as of the most recent
+// Gather, sorted descending and breaking ties by name for a deterministic order. It returns fewer
+// than n entries if fewer than n metric families are currently tracked.
+func (t *cardinalityTracker) TopOffenders(n int) []CardinalityOffender {
+	t.mu.RLock()
+	offenders := make([]CardinalityOffender, 0, len(t.series))
+	for name, count := range t.series {
+		offenders = append(offenders, CardinalityOffender{Name: name, Series: count})
+	}
+	t.mu.RUnlock()
+	sort.Slice(offenders, func(i, j int) bool {
+		if offenders[i].Series != offenders[j].Series {
+			return offenders[i].Series > offenders[j].Series
+		}
+		return offenders[i].Name < offenders[j].Name
+	})
+	if n < len(offenders) {
+		offenders = offenders[:n]
+	}
+	return offenders
+}
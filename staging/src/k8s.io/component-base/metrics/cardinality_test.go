@@ -0,0 +1,90 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+func TestKubeRegistryCardinality(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	wide := NewCounterVec(
+		&CounterOpts{Name: "wide_metric", Help: "help", StabilityLevel: ALPHA},
+		[]string{"label"},
+	)
+	narrow := NewCounter(
+		&CounterOpts{Name: "narrow_metric", Help: "help", StabilityLevel: ALPHA},
+	)
+	registry.MustRegister(wide, narrow)
+	wide.WithLabelValues("a").Inc()
+	wide.WithLabelValues("b").Inc()
+	wide.WithLabelValues("c").Inc()
+	narrow.Inc()
+
+	if _, err := registry.Gather(); err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+
+	offenders := registry.TopCardinalityOffenders(1)
+	if len(offenders) != 1 {
+		t.Fatalf("Got %d offenders, want 1", len(offenders))
+	}
+	if offenders[0].Name != "wide_metric" || offenders[0].Series != 3 {
+		t.Errorf("Got top offender %+v, want {wide_metric 3}", offenders[0])
+	}
+
+	offenders = registry.TopCardinalityOffenders(10)
+	var sawNarrow bool
+	for _, o := range offenders {
+		if o.Name == "narrow_metric" {
+			sawNarrow = true
+			if o.Series != 1 {
+				t.Errorf("Got %d series for narrow_metric, want 1", o.Series)
+			}
+		}
+	}
+	if !sawNarrow {
+		t.Errorf("narrow_metric missing from offenders: %+v", offenders)
+	}
+}
+
+func TestKubeRegistryExposesRegisteredMetricSeries(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	counter := NewCounter(
+		&CounterOpts{Name: "some_counter", Help: "help", StabilityLevel: ALPHA},
+	)
+	registry.MustRegister(counter)
+	counter.Inc()
+
+	mfs, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	for _, mf := range mfs {
+		if mf.GetName() != registeredMetricSeriesName {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			if labelValue(m, "name") == "some_counter" && m.GetGauge().GetValue() == 1 {
+				return
+			}
+		}
+	}
+	t.Errorf("registered_metric_series did not report some_counter with 1 series; got %+v", mfs)
+}
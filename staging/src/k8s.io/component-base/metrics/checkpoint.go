@@ -0,0 +1,119 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// PersistedCounterValue is the on-disk representation of a single counter family's value, as of
+// the last checkpoint.
+type PersistedCounterValue struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// counterCheckpoint is the on-disk representation written to a checkpoint path.
+type counterCheckpoint struct {
+	Counters []PersistedCounterValue `json:"counters"`
+}
+
+// CheckpointCounters gathers the current value of every unlabeled counter family named in names
+// from registry and writes it to path, so a future process can restore it with
+// RestoreCheckpointedCounters. This is meant for long-horizon counters (e.g. total node
+// evictions) where losing the running total on every restart would otherwise destroy trend
+// analysis in environments with no remote, longer-lived time series store to fall back on.
+// Labeled (CounterVec) families are skipped, since there is no way to unambiguously reattach a
+// persisted label combination to a freshly-created vec across a restart.
+//
+// Checkpointing is opt-in and must be driven explicitly by the caller (e.g. from an existing
+// periodic sync loop, or at shutdown); this package does not run a background goroutine of its
+// own.
+func CheckpointCounters(registry KubeRegistry, names []string, path string) error {
+	allowed := make(map[string]bool, len(names))
+	for _, n := range names {
+		allowed[n] = true
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics for checkpoint: %v", err)
+	}
+	var checkpoint counterCheckpoint
+	for _, family := range families {
+		if !allowed[family.GetName()] || family.GetType() != dto.MetricType_COUNTER {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			if len(metric.GetLabel()) != 0 {
+				continue
+			}
+			checkpoint.Counters = append(checkpoint.Counters, PersistedCounterValue{
+				Name:  family.GetName(),
+				Value: metric.GetCounter().GetValue(),
+			})
+		}
+	}
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to marshal counter checkpoint: %v", err)
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write counter checkpoint %s: %v", path, err)
+	}
+	return nil
+}
+
+// RestoreCheckpointedCounters reads the checkpoint written by CheckpointCounters at path, if one
+// exists, and Adds each persisted value back onto the counter registered for it under the same
+// name in counters. If restoredMarker is non-nil and at least one counter was restored,
+// restoredMarker is set to 1, so dashboards and alerts can tell that this process's counters
+// include contributions carried over from before a restart rather than assuming a jump means
+// real, newly observed activity.
+//
+// A missing checkpoint file is not an error, since a process's first-ever run will not have one.
+func RestoreCheckpointedCounters(path string, counters map[string]*Counter, restoredMarker prometheus.Gauge) error {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read counter checkpoint %s: %v", path, err)
+	}
+	var checkpoint counterCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return fmt.Errorf("failed to parse counter checkpoint %s: %v", path, err)
+	}
+	restored := false
+	for _, persisted := range checkpoint.Counters {
+		counter, ok := counters[persisted.Name]
+		if !ok {
+			continue
+		}
+		counter.Add(persisted.Value)
+		restored = true
+	}
+	if restored && restoredMarker != nil {
+		restoredMarker.Set(1)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+func TestCheckpointCountersRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "counters.checkpoint")
+
+	writeRegistry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	evictions := NewCounter(&CounterOpts{
+		Name:           "checkpoint_test_evictions_total",
+		Help:           "counter checkpointed across restarts",
+		StabilityLevel: ALPHA,
+	})
+	labeled := NewCounterVec(&CounterOpts{
+		Name:           "checkpoint_test_labeled_total",
+		Help:           "labeled counter, which is not checkpointable",
+		StabilityLevel: ALPHA,
+	}, []string{"result"})
+	writeRegistry.MustRegister(evictions, labeled)
+	evictions.Add(7)
+	labeled.WithLabelValues("success").Inc()
+
+	if err := CheckpointCounters(writeRegistry, []string{"checkpoint_test_evictions_total", "checkpoint_test_labeled_total"}, path); err != nil {
+		t.Fatalf("CheckpointCounters failed: %v", err)
+	}
+
+	restored := NewCounter(&CounterOpts{
+		Name:           "checkpoint_test_evictions_total",
+		Help:           "counter checkpointed across restarts",
+		StabilityLevel: ALPHA,
+	})
+	readRegistry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	readRegistry.MustRegister(restored)
+	marker := prometheus.NewGauge(prometheus.GaugeOpts{Name: "checkpoint_test_restored"})
+
+	counters := map[string]*Counter{"checkpoint_test_evictions_total": restored}
+	if err := RestoreCheckpointedCounters(path, counters, marker); err != nil {
+		t.Fatalf("RestoreCheckpointedCounters failed: %v", err)
+	}
+
+	families, err := readRegistry.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	var got float64
+	for _, family := range families {
+		if family.GetName() == "checkpoint_test_evictions_total" {
+			got = family.GetMetric()[0].GetCounter().GetValue()
+		}
+	}
+	if got != 7 {
+		t.Errorf("expected restored counter to be 7, got %v", got)
+	}
+
+	var markerValue dto.Metric
+	if err := marker.Write(&markerValue); err != nil {
+		t.Fatalf("failed to read marker gauge: %v", err)
+	}
+	if markerValue.GetGauge().GetValue() != 1 {
+		t.Errorf("expected restoredMarker to be set to 1, got %v", markerValue.GetGauge().GetValue())
+	}
+}
+
+func TestRestoreCheckpointedCountersMissingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "does-not-exist.checkpoint")
+	counter := NewCounter(&CounterOpts{
+		Name:           "checkpoint_test_missing_total",
+		Help:           "counter with no checkpoint on disk yet",
+		StabilityLevel: ALPHA,
+	})
+	if err := RestoreCheckpointedCounters(path, map[string]*Counter{"checkpoint_test_missing_total": counter}, nil); err != nil {
+		t.Errorf("expected a missing checkpoint file to not be an error, got %v", err)
+	}
+}
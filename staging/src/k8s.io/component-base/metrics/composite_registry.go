@@ -0,0 +1,117 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// registeredComponent is one component's KubeRegistry as known to a CompositeRegistry.
+type registeredComponent struct {
+	name     string
+	prefix   string
+	registry KubeRegistry
+	enabled  bool
+}
+
+// CompositeRegistry merges the metrics of several independently owned component KubeRegistries
+// (e.g. one per embedded component of a hyperkube-style combined binary, or one per controller in
+// a controller-manager aggregation) so they can be served from a single /metrics endpoint. Each
+// component's metric names are prefixed with its own registered prefix, and Gather fails loudly
+// if two components still collide on the resulting name, rather than letting one silently
+// overwrite the other's series. A component can be turned on or off at runtime via SetEnabled
+// without needing to be unregistered and re-added.
+type CompositeRegistry struct {
+	mu         sync.Mutex
+	components []*registeredComponent
+}
+
+// NewCompositeRegistry returns an empty CompositeRegistry with no components registered.
+func NewCompositeRegistry() *CompositeRegistry {
+	return &CompositeRegistry{}
+}
+
+// AddComponent registers registry under name, prefixing every metric name it gathers with
+// prefix. The component starts enabled. AddComponent panics if name is already registered, since
+// that is a programmer error at binary-composition time rather than a runtime condition.
+func (r *CompositeRegistry) AddComponent(name, prefix string, registry KubeRegistry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.components {
+		if c.name == name {
+			panic(fmt.Sprintf("component %q is already registered with this CompositeRegistry", name))
+		}
+	}
+	r.components = append(r.components, &registeredComponent{name: name, prefix: prefix, registry: registry, enabled: true})
+}
+
+// SetEnabled enables or disables scraping the named component. Gather silently skips a disabled
+// component. SetEnabled is a no-op if name was never registered via AddComponent.
+func (r *CompositeRegistry) SetEnabled(name string, enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.components {
+		if c.name == name {
+			c.enabled = enabled
+			return
+		}
+	}
+}
+
+// Gather implements the promhttp.Gatherer interface, merging the metric families gathered from
+// every enabled component. It returns an error, without any partial result, if a component fails
+// to gather or if two components' prefixed metric names collide.
+func (r *CompositeRegistry) Gather() ([]*dto.MetricFamily, error) {
+	r.mu.Lock()
+	components := make([]*registeredComponent, len(r.components))
+	copy(components, r.components)
+	r.mu.Unlock()
+
+	seenBy := map[string]string{}
+	var merged []*dto.MetricFamily
+	for _, c := range components {
+		if !c.enabled {
+			continue
+		}
+		families, err := c.registry.Gather()
+		if err != nil {
+			return nil, fmt.Errorf("gathering metrics from component %q: %v", c.name, err)
+		}
+		for _, family := range families {
+			name := c.prefix + family.GetName()
+			if owner, ok := seenBy[name]; ok {
+				return nil, fmt.Errorf("metric name %q from component %q collides with the same name already gathered from component %q; choose a more specific prefix", name, c.name, owner)
+			}
+			seenBy[name] = c.name
+			prefixed := *family
+			prefixed.Name = &name
+			merged = append(merged, &prefixed)
+		}
+	}
+	return merged, nil
+}
+
+// Handler returns an http.Handler serving this CompositeRegistry's merged metrics, negotiating
+// the response format the same way promhttp.HandlerFor does for any other gatherer.
+func (r *CompositeRegistry) Handler(opts promhttp.HandlerOpts) http.Handler {
+	return promhttp.HandlerFor(r, opts)
+}
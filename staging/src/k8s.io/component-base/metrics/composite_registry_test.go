@@ -0,0 +1,86 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+func newComponentRegistry(t *testing.T, metricName string) KubeRegistry {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	c := NewCounter(&CounterOpts{Name: metricName, StabilityLevel: ALPHA, Help: "help"})
+	registry.MustRegister(c)
+	c.Inc()
+	return registry
+}
+
+func TestCompositeRegistryGatherPrefixesAndMerges(t *testing.T) {
+	composite := NewCompositeRegistry()
+	composite.AddComponent("scheduler", "kube_scheduler_", newComponentRegistry(t, "requests"))
+	composite.AddComponent("controller-manager", "kube_controller_manager_", newComponentRegistry(t, "requests"))
+
+	families, err := composite.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	names := sets.NewString()
+	for _, f := range families {
+		names.Insert(f.GetName())
+	}
+	if !names.HasAll("kube_scheduler_requests", "kube_controller_manager_requests") {
+		t.Errorf("expected both prefixed metric names, got %v", names.List())
+	}
+}
+
+func TestCompositeRegistryGatherDetectsCollision(t *testing.T) {
+	composite := NewCompositeRegistry()
+	composite.AddComponent("scheduler", "shared_prefix_", newComponentRegistry(t, "requests"))
+	composite.AddComponent("controller-manager", "shared_prefix_", newComponentRegistry(t, "requests"))
+
+	if _, err := composite.Gather(); err == nil {
+		t.Fatal("expected an error for colliding metric names, got nil")
+	}
+}
+
+func TestCompositeRegistrySetEnabledSkipsComponent(t *testing.T) {
+	composite := NewCompositeRegistry()
+	composite.AddComponent("scheduler", "kube_scheduler_", newComponentRegistry(t, "requests"))
+	composite.SetEnabled("scheduler", false)
+
+	families, err := composite.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(families) != 0 {
+		t.Errorf("expected no metric families from a disabled component, got %v", families)
+	}
+}
+
+func TestCompositeRegistryAddComponentPanicsOnDuplicateName(t *testing.T) {
+	composite := NewCompositeRegistry()
+	composite.AddComponent("scheduler", "kube_scheduler_", newComponentRegistry(t, "requests"))
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected AddComponent to panic on a duplicate component name")
+		}
+	}()
+	composite.AddComponent("scheduler", "kube_scheduler_2_", newComponentRegistry(t, "requests"))
+}
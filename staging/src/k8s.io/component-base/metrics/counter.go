@@ -30,6 +30,26 @@ type Counter struct {
 	selfCollector
 }
 
+// Add increments the counter by v, after checking v against the plausible range for the
+// counter's declared Unit (see KubeOpts.Unit and assertPlausibleValue). The check is compiled out
+// of non-debug builds.
+func (c *Counter) Add(v float64) {
+	assertPlausibleValue(c.CounterOpts.Name, c.CounterOpts.Unit, v)
+	c.CounterMetric.Add(v)
+}
+
+// AddWithExemplar behaves like Add, but additionally attaches exemplar (e.g. a trace ID) to the
+// observation for consumers that scrape this metric as OpenMetrics. See exemplarAdder for why
+// exemplar is silently dropped instead on this repo's currently vendored prometheus client.
+func (c *Counter) AddWithExemplar(v float64, exemplar prometheus.Labels) {
+	assertPlausibleValue(c.CounterOpts.Name, c.CounterOpts.Unit, v)
+	if adder, ok := c.CounterMetric.(exemplarAdder); ok {
+		adder.AddWithExemplar(v, exemplar)
+		return
+	}
+	c.CounterMetric.Add(v)
+}
+
 // NewCounter returns an object which satisfies the KubeCollector and CounterMetric interfaces.
 // However, the object returned will not measure anything unless the collector is first
 // registered, since the metric is lazily instantiated.
@@ -58,6 +78,11 @@ func (c *Counter) DeprecatedVersion() *semver.Version {
 	return c.CounterOpts.DeprecatedVersion
 }
 
+// StabilityLevel returns the metric's declared StabilityLevel.
+func (c *Counter) StabilityLevel() StabilityLevel {
+	return c.CounterOpts.StabilityLevel
+}
+
 // initializeMetric invocation creates the actual underlying Counter. Until this method is called
 // the underlying counter is a no-op.
 func (c *Counter) initializeMetric() {
@@ -80,6 +105,7 @@ type CounterVec struct {
 	*CounterOpts
 	lazyMetric
 	originalLabels []string
+	degradeState
 }
 
 // NewCounterVec returns an object which satisfies the KubeCollector and CounterVecMetric interfaces.
@@ -101,6 +127,11 @@ func (v *CounterVec) DeprecatedVersion() *semver.Version {
 	return v.CounterOpts.DeprecatedVersion
 }
 
+// StabilityLevel returns the metric's declared StabilityLevel.
+func (v *CounterVec) StabilityLevel() StabilityLevel {
+	return v.CounterOpts.StabilityLevel
+}
+
 // initializeMetric invocation creates the actual underlying CounterVec. Until this method is called
 // the underlying counterVec is a no-op.
 func (v *CounterVec) initializeMetric() {
@@ -130,7 +161,9 @@ func (v *CounterVec) WithLabelValues(lvs ...string) CounterMetric {
 	if !v.IsCreated() {
 		return noop // return no-op counter
 	}
-	return v.CounterVec.WithLabelValues(lvs...)
+	lvs = constrainLabelValues(v.originalLabels, lvs, v.CounterOpts.LabelValueAllowLists)
+	lvs = v.degradeLabelValues(v.originalLabels, lvs, v.CounterOpts.DegradeLabel)
+	return &checkedCounterMetric{CounterMetric: v.CounterVec.WithLabelValues(lvs...), name: v.CounterOpts.Name, unit: v.CounterOpts.Unit}
 }
 
 // With returns the Counter for the given Labels map (the label names
@@ -141,5 +174,33 @@ func (v *CounterVec) With(labels prometheus.Labels) CounterMetric {
 	if !v.IsCreated() {
 		return noop // return no-op counter
 	}
-	return v.CounterVec.With(labels)
+	labels = constrainLabelMap(labels, v.CounterOpts.LabelValueAllowLists)
+	labels = v.degradeLabelMap(labels, v.CounterOpts.DegradeLabel)
+	return &checkedCounterMetric{CounterMetric: v.CounterVec.With(labels), name: v.CounterOpts.Name, unit: v.CounterOpts.Unit}
+}
+
+// checkedCounterMetric wraps a CounterMetric to apply assertPlausibleValue to values passed to
+// Add, since the plain prometheus.Counter returned by CounterVec's With/WithLabelValues does not
+// carry the parent CounterVec's declared Unit.
+type checkedCounterMetric struct {
+	CounterMetric
+	name string
+	unit Unit
+}
+
+func (c *checkedCounterMetric) Add(v float64) {
+	assertPlausibleValue(c.name, c.unit, v)
+	c.CounterMetric.Add(v)
+}
+
+// AddWithExemplar behaves like Add, but additionally attaches exemplar (e.g. a trace ID) to the
+// observation for consumers that scrape this metric as OpenMetrics. See exemplarAdder for why
+// exemplar is silently dropped instead on this repo's currently vendored prometheus client.
+func (c *checkedCounterMetric) AddWithExemplar(v float64, exemplar prometheus.Labels) {
+	assertPlausibleValue(c.name, c.unit, v)
+	if adder, ok := c.CounterMetric.(exemplarAdder); ok {
+		adder.AddWithExemplar(v, exemplar)
+		return
+	}
+	c.CounterMetric.Add(v)
 }
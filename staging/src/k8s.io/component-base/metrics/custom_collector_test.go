@@ -0,0 +1,72 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+// fakeCollector is a minimal hand-rolled Collector, standing in for the kind of
+// callback-driven, multi-metric collector components write when a single
+// Counter/Gauge/Histogram/Summary doesn't fit.
+type fakeCollector struct {
+	desc *prometheus.Desc
+}
+
+func newFakeCollector() *fakeCollector {
+	return &fakeCollector{desc: prometheus.NewDesc("test_custom_collector_metric", "help", nil, nil)}
+}
+
+func (c *fakeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *fakeCollector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, 1)
+}
+
+func TestCustomCollectorForwardsOnceRegistered(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	c := NewCustomCollector(&KubeOpts{Name: "test_custom_collector", StabilityLevel: ALPHA}, newFakeCollector())
+	if err := registry.Register(c); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	for _, f := range families {
+		if f.GetName() == "test_custom_collector_metric" {
+			return
+		}
+	}
+	t.Errorf("did not find test_custom_collector_metric in gathered families")
+}
+
+func TestCustomCollectorNoopsUntilRegistered(t *testing.T) {
+	c := NewCustomCollector(&KubeOpts{Name: "test_custom_collector_unregistered", StabilityLevel: ALPHA}, newFakeCollector())
+	ch := make(chan *prometheus.Desc, 1)
+	c.Describe(ch)
+	close(ch)
+	if _, ok := <-ch; ok {
+		t.Errorf("expected Describe to be a no-op before registration")
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/spf13/pflag"
+)
+
+const dumpDashboardsDirFlagName = "dump-metrics-dashboards-dir"
+
+var dumpDashboardsDir = pflag.String(dumpDashboardsDirFlagName, "", "If non-empty, generate a skeleton Grafana dashboard per metrics subsystem into this directory at startup and exit.")
+
+// AddDashboardFlags registers this package's dashboard-generation flags on arbitrary FlagSets,
+// such that they point to the same value as the global flags.
+func AddDashboardFlags(fs *pflag.FlagSet) {
+	fs.AddFlag(pflag.Lookup(dumpDashboardsDirFlagName))
+}
+
+// MaybeDumpDashboards writes one skeleton Grafana dashboard JSON file per metrics subsystem into
+// the directory named by the dump-metrics-dashboards-dir flag, if it was set, and reports whether
+// it did so. Binaries that support the flag should call this after all of their metrics are
+// registered but before serving traffic.
+func MaybeDumpDashboards(registry KubeRegistry) (bool, error) {
+	if *dumpDashboardsDir == "" {
+		return false, nil
+	}
+	dashboards, err := GenerateGrafanaDashboards(registry)
+	if err != nil {
+		return false, err
+	}
+	for subsystem, data := range dashboards {
+		path := filepath.Join(*dumpDashboardsDir, subsystem+".json")
+		if err := ioutil.WriteFile(path, data, 0644); err != nil {
+			return false, fmt.Errorf("failed to write dashboard %s: %v", path, err)
+		}
+	}
+	return true, nil
+}
+
+// dashboard is a minimal skeleton of the Grafana dashboard JSON model
+// (https://grafana.com/docs/grafana/latest/dashboards/json-model/), containing just enough
+// structure for Grafana to import it as a starting point.
+type dashboard struct {
+	Title string         `json:"title"`
+	Rows  []dashboardRow `json:"rows"`
+}
+
+type dashboardRow struct {
+	Title  string           `json:"title"`
+	Panels []dashboardPanel `json:"panels"`
+}
+
+type dashboardPanel struct {
+	Title   string            `json:"title"`
+	Type    string            `json:"type"`
+	Targets []dashboardTarget `json:"targets"`
+	GridPos dashboardGridPos  `json:"gridPos"`
+}
+
+type dashboardTarget struct {
+	Expr string `json:"expr"`
+}
+
+type dashboardGridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+const panelsPerRow = 2
+
+// GenerateGrafanaDashboards gathers registry's currently registered metrics and returns one
+// skeleton Grafana dashboard JSON document per subsystem, keyed by subsystem name, so that
+// operators get a baseline dashboard consistent with the binary's actual metrics instead of
+// hand-authoring one from scratch.
+//
+// Subsystem is not retained on a gathered MetricFamily, so it is approximated as the first
+// "_"-delimited component of the metric's fully qualified name (e.g.
+// "apiserver_request_duration_seconds" is grouped under "apiserver"); names with no "_" are
+// grouped under "other". This is a best-effort grouping intended to seed a dashboard for further
+// hand-editing, not a precise reconstruction of each metric's registered Subsystem.
+func GenerateGrafanaDashboards(registry KubeRegistry) (map[string][]byte, error) {
+	families, err := registry.Gather()
+	if err != nil {
+		return nil, fmt.Errorf("failed to gather metrics for dashboard generation: %v", err)
+	}
+
+	bySubsystem := map[string][]*dto.MetricFamily{}
+	for _, family := range families {
+		subsystem := subsystemOf(family.GetName())
+		bySubsystem[subsystem] = append(bySubsystem[subsystem], family)
+	}
+
+	dashboards := make(map[string][]byte, len(bySubsystem))
+	for subsystem, subsystemFamilies := range bySubsystem {
+		data, err := json.MarshalIndent(newDashboard(subsystem, subsystemFamilies), "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal dashboard for subsystem %s: %v", subsystem, err)
+		}
+		dashboards[subsystem] = data
+	}
+	return dashboards, nil
+}
+
+func newDashboard(subsystem string, families []*dto.MetricFamily) dashboard {
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].GetName() < families[j].GetName()
+	})
+
+	panels := make([]dashboardPanel, 0, len(families))
+	for i, family := range families {
+		panels = append(panels, dashboardPanel{
+			Title:   family.GetName(),
+			Type:    panelTypeFor(family.GetType()),
+			Targets: []dashboardTarget{{Expr: promQLFor(family)}},
+			GridPos: dashboardGridPos{H: 8, W: 24 / panelsPerRow, X: (i % panelsPerRow) * (24 / panelsPerRow), Y: (i / panelsPerRow) * 8},
+		})
+	}
+
+	return dashboard{
+		Title: fmt.Sprintf("%s (generated)", subsystem),
+		Rows:  []dashboardRow{{Title: subsystem, Panels: panels}},
+	}
+}
+
+func subsystemOf(name string) string {
+	if idx := strings.Index(name, "_"); idx > 0 {
+		return name[:idx]
+	}
+	return "other"
+}
+
+func panelTypeFor(t dto.MetricType) string {
+	if t == dto.MetricType_HISTOGRAM || t == dto.MetricType_SUMMARY {
+		return "heatmap"
+	}
+	return "graph"
+}
+
+func promQLFor(family *dto.MetricFamily) string {
+	switch family.GetType() {
+	case dto.MetricType_COUNTER:
+		return fmt.Sprintf("rate(%s[5m])", family.GetName())
+	case dto.MetricType_HISTOGRAM:
+		return fmt.Sprintf("histogram_quantile(0.99, rate(%s_bucket[5m]))", family.GetName())
+	default:
+		return family.GetName()
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"encoding/json"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+func TestGenerateGrafanaDashboards(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	requests := NewCounter(&CounterOpts{
+		Name:           "apiserver_dashboard_test_requests_total",
+		Help:           "total requests handled",
+		StabilityLevel: ALPHA,
+	})
+	errors := NewCounter(&CounterOpts{
+		Name:           "apiserver_dashboard_test_errors_total",
+		Help:           "total errors handled",
+		StabilityLevel: ALPHA,
+	})
+	orphan := NewCounter(&CounterOpts{
+		Name:           "dashboardtestunlabeled",
+		Help:           "a metric with no subsystem-like prefix",
+		StabilityLevel: ALPHA,
+	})
+	registry.MustRegister(requests, errors, orphan)
+	requests.Inc()
+
+	dashboards, err := GenerateGrafanaDashboards(registry)
+	if err != nil {
+		t.Fatalf("GenerateGrafanaDashboards failed: %v", err)
+	}
+
+	apiserverJSON, ok := dashboards["apiserver"]
+	if !ok {
+		t.Fatalf("expected an apiserver dashboard, got dashboards for: %v", keysOf(dashboards))
+	}
+	var d dashboard
+	if err := json.Unmarshal(apiserverJSON, &d); err != nil {
+		t.Fatalf("failed to unmarshal apiserver dashboard: %v", err)
+	}
+	if len(d.Rows) != 1 || len(d.Rows[0].Panels) != 2 {
+		t.Fatalf("expected 1 row with 2 panels, got %+v", d.Rows)
+	}
+	if d.Rows[0].Panels[0].Title != "apiserver_dashboard_test_errors_total" {
+		t.Errorf("expected panels sorted by name, got first panel %q", d.Rows[0].Panels[0].Title)
+	}
+
+	if _, ok := dashboards["other"]; !ok {
+		t.Errorf("expected an 'other' dashboard for the prefix-less metric, got dashboards for: %v", keysOf(dashboards))
+	}
+}
+
+func TestSubsystemOf(t *testing.T) {
+	cases := map[string]string{
+		"apiserver_request_duration_seconds": "apiserver",
+		"unlabeled":                           "other",
+		"":                                    "other",
+	}
+	for name, want := range cases {
+		if got := subsystemOf(name); got != want {
+			t.Errorf("subsystemOf(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestPanelTypeAndPromQLFor(t *testing.T) {
+	counterName, histogramName := "requests_total", "request_duration_seconds"
+	counterType, histogramType := dto.MetricType_COUNTER, dto.MetricType_HISTOGRAM
+	counterFamily := &dto.MetricFamily{Name: &counterName, Type: &counterType}
+	histogramFamily := &dto.MetricFamily{Name: &histogramName, Type: &histogramType}
+
+	if got, want := panelTypeFor(counterFamily.GetType()), "graph"; got != want {
+		t.Errorf("panelTypeFor(counter) = %q, want %q", got, want)
+	}
+	if got, want := panelTypeFor(histogramFamily.GetType()), "heatmap"; got != want {
+		t.Errorf("panelTypeFor(histogram) = %q, want %q", got, want)
+	}
+	if got, want := promQLFor(counterFamily), "rate(requests_total[5m])"; got != want {
+		t.Errorf("promQLFor(counter) = %q, want %q", got, want)
+	}
+	if got, want := promQLFor(histogramFamily), "histogram_quantile(0.99, rate(request_duration_seconds_bucket[5m]))"; got != want {
+		t.Errorf("promQLFor(histogram) = %q, want %q", got, want)
+	}
+}
+
+func keysOf(m map[string][]byte) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+)
+
+// MetricFamilySpec declares one metric family's fixed schema — its name, help text, labels,
+// stability level and (for histograms) buckets — as data, rather than as a Go literal built at
+// each call site. This lets stability-verification and documentation-generation tooling read a
+// component's full metric inventory directly out of its manifest, without having to execute the
+// component's Go code. See ParseDeclarativeMetricManifest.
+type MetricFamilySpec struct {
+	// Name is the metric family's name, without Namespace or Subsystem.
+	Name string `json:"name"`
+	// Namespace and Subsystem are combined with Name the same way KubeOpts combines them.
+	Namespace string `json:"namespace,omitempty"`
+	Subsystem string `json:"subsystem,omitempty"`
+	// Help is the metric family's HELP text.
+	Help string `json:"help"`
+	// Type selects which kind of collector this family is built as: "counter", "gauge",
+	// "histogram", or "summary".
+	Type string `json:"type"`
+	// StabilityLevel is the family's declared StabilityLevel. Left empty, it defaults to ALPHA,
+	// the same default the underlying Opts types apply.
+	StabilityLevel StabilityLevel `json:"stabilityLevel,omitempty"`
+	// Labels lists the family's label names. A family with no labels is built as a plain
+	// (non-Vec) collector.
+	Labels []string `json:"labels,omitempty"`
+	// Buckets is used only when Type is "histogram"; left empty, prometheus.DefBuckets applies.
+	Buckets []float64 `json:"buckets,omitempty"`
+}
+
+// DeclarativeMetricSet is the set of collectors built from a validated manifest of
+// MetricFamilySpecs. A component that declares its metrics this way parses its manifest into a
+// DeclarativeMetricSet once at init, registers it via Registerables, and thereafter refers to its
+// metrics only through the typed accessors below rather than constructing new Opts inline, so the
+// manifest stays the single source of truth for what the component exposes.
+type DeclarativeMetricSet struct {
+	collectors map[string]KubeCollector
+}
+
+// ParseDeclarativeMetricManifest parses manifestYAML (a YAML or JSON list of MetricFamilySpec) into
+// a validated DeclarativeMetricSet, building (but not registering) one collector per entry. It
+// returns an error if the manifest fails to parse, declares the same name twice, or declares an
+// entry ParseDeclarativeMetricManifest cannot build (an unrecognized Type, or Labels on a
+// "summary", which this package's Summary type does not support — use a histogram instead).
+func ParseDeclarativeMetricManifest(manifestYAML []byte) (*DeclarativeMetricSet, error) {
+	var specs []MetricFamilySpec
+	if err := yaml.Unmarshal(manifestYAML, &specs); err != nil {
+		return nil, fmt.Errorf("failed to parse declarative metric manifest: %v", err)
+	}
+	set := &DeclarativeMetricSet{collectors: make(map[string]KubeCollector, len(specs))}
+	for _, spec := range specs {
+		if _, exists := set.collectors[spec.Name]; exists {
+			return nil, fmt.Errorf("declarative metric manifest declares %q more than once", spec.Name)
+		}
+		collector, err := newDeclarativeCollector(spec)
+		if err != nil {
+			return nil, fmt.Errorf("declarative metric manifest entry %q: %v", spec.Name, err)
+		}
+		set.collectors[spec.Name] = collector
+	}
+	return set, nil
+}
+
+// newDeclarativeCollector builds the collector spec describes. Buckets that fail
+// ValidateHistogramBuckets for spec's StabilityLevel panic, the same as calling NewHistogram
+// directly would, since that is a manifest-authoring mistake to catch at init time.
+func newDeclarativeCollector(spec MetricFamilySpec) (KubeCollector, error) {
+	stability := spec.StabilityLevel
+	if stability == "" {
+		stability = ALPHA
+	}
+	switch spec.Type {
+	case "counter":
+		opts := &CounterOpts{Namespace: spec.Namespace, Subsystem: spec.Subsystem, Name: spec.Name, Help: spec.Help, StabilityLevel: stability}
+		if len(spec.Labels) == 0 {
+			return NewCounter(opts), nil
+		}
+		return NewCounterVec(opts, spec.Labels), nil
+	case "gauge":
+		opts := &GaugeOpts{Namespace: spec.Namespace, Subsystem: spec.Subsystem, Name: spec.Name, Help: spec.Help, StabilityLevel: stability}
+		if len(spec.Labels) == 0 {
+			return NewGauge(opts), nil
+		}
+		return NewGaugeVec(opts, spec.Labels), nil
+	case "histogram":
+		opts := &HistogramOpts{Namespace: spec.Namespace, Subsystem: spec.Subsystem, Name: spec.Name, Help: spec.Help, StabilityLevel: stability, Buckets: spec.Buckets}
+		if len(spec.Labels) == 0 {
+			return NewHistogram(opts), nil
+		}
+		return NewHistogramVec(opts, spec.Labels), nil
+	case "summary":
+		if len(spec.Labels) > 0 {
+			return nil, fmt.Errorf("summaries with labels are not supported by this package; declare a histogram instead")
+		}
+		return NewSummary(&SummaryOpts{Namespace: spec.Namespace, Subsystem: spec.Subsystem, Name: spec.Name, Help: spec.Help, StabilityLevel: stability}), nil
+	default:
+		return nil, fmt.Errorf("unrecognized metric type %q", spec.Type)
+	}
+}
+
+// Registerables returns every collector in the set, for one-shot registration via
+// KubeRegistry.MustRegister(set.Registerables()...).
+func (s *DeclarativeMetricSet) Registerables() []KubeCollector {
+	collectors := make([]KubeCollector, 0, len(s.collectors))
+	for _, c := range s.collectors {
+		collectors = append(collectors, c)
+	}
+	return collectors
+}
+
+// handle looks up the collector declared under name, panicking if the manifest never declared it:
+// a mismatch between a manifest and the code reading it is a programmer error to catch at init
+// time, not a runtime condition.
+func (s *DeclarativeMetricSet) handle(name string) KubeCollector {
+	c, ok := s.collectors[name]
+	if !ok {
+		panic(fmt.Sprintf("metrics: %q was not declared in this declarative metric manifest", name))
+	}
+	return c
+}
+
+// Counter returns the unlabeled counter declared under name, panicking if name was not declared
+// with Type "counter" and no Labels.
+func (s *DeclarativeMetricSet) Counter(name string) *Counter {
+	c, ok := s.handle(name).(*Counter)
+	if !ok {
+		panic(fmt.Sprintf("metrics: %q is not a declared unlabeled counter", name))
+	}
+	return c
+}
+
+// CounterVec returns the labeled counter declared under name, panicking if name was not declared
+// with Type "counter" and at least one label.
+func (s *DeclarativeMetricSet) CounterVec(name string) *CounterVec {
+	c, ok := s.handle(name).(*CounterVec)
+	if !ok {
+		panic(fmt.Sprintf("metrics: %q is not a declared labeled counter", name))
+	}
+	return c
+}
+
+// Gauge returns the unlabeled gauge declared under name, panicking if name was not declared with
+// Type "gauge" and no Labels.
+func (s *DeclarativeMetricSet) Gauge(name string) *Gauge {
+	c, ok := s.handle(name).(*Gauge)
+	if !ok {
+		panic(fmt.Sprintf("metrics: %q is not a declared unlabeled gauge", name))
+	}
+	return c
+}
+
+// GaugeVec returns the labeled gauge declared under name, panicking if name was not declared with
+// Type "gauge" and at least one label.
+func (s *DeclarativeMetricSet) GaugeVec(name string) *GaugeVec {
+	c, ok := s.handle(name).(*GaugeVec)
+	if !ok {
+		panic(fmt.Sprintf("metrics: %q is not a declared labeled gauge", name))
+	}
+	return c
+}
+
+// Histogram returns the unlabeled histogram declared under name, panicking if name was not
+// declared with Type "histogram" and no Labels.
+func (s *DeclarativeMetricSet) Histogram(name string) *Histogram {
+	c, ok := s.handle(name).(*Histogram)
+	if !ok {
+		panic(fmt.Sprintf("metrics: %q is not a declared unlabeled histogram", name))
+	}
+	return c
+}
+
+// HistogramVec returns the labeled histogram declared under name, panicking if name was not
+// declared with Type "histogram" and at least one label.
+func (s *DeclarativeMetricSet) HistogramVec(name string) *HistogramVec {
+	c, ok := s.handle(name).(*HistogramVec)
+	if !ok {
+		panic(fmt.Sprintf("metrics: %q is not a declared labeled histogram", name))
+	}
+	return c
+}
+
+// Summary returns the summary declared under name, panicking if name was not declared with Type
+// "summary".
+func (s *DeclarativeMetricSet) Summary(name string) *Summary {
+	c, ok := s.handle(name).(*Summary)
+	if !ok {
+		panic(fmt.Sprintf("metrics: %q is not a declared summary", name))
+	}
+	return c
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+const declarativeTestManifest = `
+- name: requests_total
+  help: total requests handled
+  type: counter
+  labels: ["code"]
+- name: queue_length
+  help: current queue length
+  type: gauge
+- name: request_duration_seconds
+  help: request latency
+  type: histogram
+  buckets: [0.1, 0.5, 1]
+`
+
+func TestParseDeclarativeMetricManifest(t *testing.T) {
+	set, err := ParseDeclarativeMetricManifest([]byte(declarativeTestManifest))
+	if err != nil {
+		t.Fatalf("ParseDeclarativeMetricManifest failed: %v", err)
+	}
+
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	registry.MustRegister(set.Registerables()...)
+
+	set.CounterVec("requests_total").WithLabelValues("200").Inc()
+	set.Gauge("queue_length").Set(3)
+	set.Histogram("request_duration_seconds").Observe(0.2)
+
+	mfs, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	var names []string
+	for _, mf := range mfs {
+		names = append(names, mf.GetName())
+	}
+	for _, want := range []string{"requests_total", "queue_length", "request_duration_seconds"} {
+		var found bool
+		for _, name := range names {
+			if name == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Gather did not include %q; got %v", want, names)
+		}
+	}
+}
+
+func TestParseDeclarativeMetricManifestRejectsDuplicateName(t *testing.T) {
+	_, err := ParseDeclarativeMetricManifest([]byte(`
+- name: dup
+  help: h
+  type: counter
+- name: dup
+  help: h
+  type: gauge
+`))
+	if err == nil || !strings.Contains(err.Error(), "more than once") {
+		t.Errorf("Got err %v, want a duplicate-name error", err)
+	}
+}
+
+func TestParseDeclarativeMetricManifestRejectsUnknownType(t *testing.T) {
+	_, err := ParseDeclarativeMetricManifest([]byte(`
+- name: mystery
+  help: h
+  type: not-a-real-type
+`))
+	if err == nil {
+		t.Errorf("Got nil err, want an unrecognized-type error")
+	}
+}
+
+func TestDeclarativeMetricSetHandlePanicsOnTypeMismatch(t *testing.T) {
+	set, err := ParseDeclarativeMetricManifest([]byte(declarativeTestManifest))
+	if err != nil {
+		t.Fatalf("ParseDeclarativeMetricManifest failed: %v", err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Counter(\"queue_length\") did not panic on type mismatch")
+		}
+	}()
+	set.Counter("queue_length")
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "sync/atomic"
+
+// aggregatedLabelValue replaces every value of a Vec's DegradeLabel once it has been degraded.
+const aggregatedLabelValue = "aggregated"
+
+// Degradable is implemented by every Vec metric type (CounterVec, GaugeVec, HistogramVec,
+// SummaryVec) with a DegradeLabel configured, letting a MemoryWatchdog put it into aggregated
+// mode without needing to know its concrete type.
+type Degradable interface {
+	// Degrade begins substituting aggregatedLabelValue for this Vec's DegradeLabel on every
+	// subsequent WithLabelValues/With call, collapsing all of that label's distinct values into
+	// a single series per combination of the Vec's other labels. It is idempotent.
+	Degrade()
+	// Recover reverses Degrade, so subsequent calls see real label values again. It is
+	// idempotent.
+	Recover()
+	// IsDegraded reports whether Degrade has been called more recently than Recover.
+	IsDegraded() bool
+}
+
+// degradeState is the shared, atomically-flipped on/off switch behind every Degradable Vec's
+// Degrade/Recover/IsDegraded. Embed it (as an unexported field, so it composes without widening
+// the Vec's own exported API) in a Vec struct alongside a DegradeLabel from its Opts to get
+// Degradable for free.
+type degradeState struct {
+	degraded int32
+}
+
+func (d *degradeState) Degrade() {
+	atomic.StoreInt32(&d.degraded, 1)
+}
+
+func (d *degradeState) Recover() {
+	atomic.StoreInt32(&d.degraded, 0)
+}
+
+func (d *degradeState) IsDegraded() bool {
+	return atomic.LoadInt32(&d.degraded) != 0
+}
+
+// degradeLabelValues returns lvs, with the value at degradeLabel's position in labels replaced
+// by aggregatedLabelValue if d is degraded. degradeLabel empty (no DegradeLabel configured) or d
+// not degraded returns lvs unchanged without allocating.
+func (d *degradeState) degradeLabelValues(labels []string, lvs []string, degradeLabel string) []string {
+	if degradeLabel == "" || !d.IsDegraded() {
+		return lvs
+	}
+	for i, l := range labels {
+		if l == degradeLabel && lvs[i] != aggregatedLabelValue {
+			degraded := make([]string, len(lvs))
+			copy(degraded, lvs)
+			degraded[i] = aggregatedLabelValue
+			return degraded
+		}
+	}
+	return lvs
+}
+
+// degradeLabelMap returns labels, with degradeLabel's entry (if present) replaced by
+// aggregatedLabelValue if d is degraded. degradeLabel empty or d not degraded returns labels
+// unchanged without allocating.
+func (d *degradeState) degradeLabelMap(labels map[string]string, degradeLabel string) map[string]string {
+	if degradeLabel == "" || !d.IsDegraded() {
+		return labels
+	}
+	if _, ok := labels[degradeLabel]; !ok {
+		return labels
+	}
+	degraded := make(map[string]string, len(labels))
+	for k, v := range labels {
+		degraded[k] = v
+	}
+	degraded[degradeLabel] = aggregatedLabelValue
+	return degraded
+}
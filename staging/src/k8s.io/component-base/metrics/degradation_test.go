@@ -0,0 +1,107 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+func TestCounterVecDegradeCollapsesLabelValue(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	cv := NewCounterVec(&CounterOpts{
+		Name:         "test_degrade_counter",
+		Help:         "help",
+		DegradeLabel: "pod",
+	}, []string{"pod", "verb"})
+	registry.MustRegister(cv)
+
+	cv.WithLabelValues("pod-a", "get").Inc()
+	cv.WithLabelValues("pod-b", "get").Inc()
+
+	var d Degradable = cv
+	d.Degrade()
+	cv.WithLabelValues("pod-c", "get").Inc()
+	cv.WithLabelValues("pod-d", "list").Inc()
+
+	if !d.IsDegraded() {
+		t.Fatalf("expected IsDegraded() to be true after Degrade()")
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	seen := map[string]bool{}
+	for _, f := range families {
+		if f.GetName() != "test_degrade_counter" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "pod" {
+					seen[l.GetValue()] = true
+				}
+			}
+		}
+	}
+	if !seen["pod-a"] || !seen["pod-b"] {
+		t.Errorf("expected pre-degrade pod label values to remain untouched, got %v", seen)
+	}
+	if !seen[aggregatedLabelValue] {
+		t.Errorf("expected post-degrade series to collapse to %q, got %v", aggregatedLabelValue, seen)
+	}
+	if seen["pod-c"] || seen["pod-d"] {
+		t.Errorf("did not expect post-degrade pod label values to survive, got %v", seen)
+	}
+
+	d.Recover()
+	if d.IsDegraded() {
+		t.Errorf("expected IsDegraded() to be false after Recover()")
+	}
+	cv.WithLabelValues("pod-e", "get").Inc()
+}
+
+func TestDegradeStateNoopWithoutDegradeLabel(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	cv := NewCounterVec(&CounterOpts{
+		Name: "test_no_degrade_label_counter",
+		Help: "help",
+	}, []string{"pod"})
+	registry.MustRegister(cv)
+
+	cv.Degrade()
+	cv.WithLabelValues("pod-a").Inc()
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	for _, f := range families {
+		if f.GetName() != "test_no_degrade_label_counter" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetValue() == aggregatedLabelValue {
+					t.Errorf("expected label value to be left alone with no DegradeLabel configured, got %q", aggregatedLabelValue)
+				}
+			}
+		}
+	}
+}
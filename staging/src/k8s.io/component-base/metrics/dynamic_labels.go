@@ -0,0 +1,86 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sort"
+	"sync"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// dynamicLabelSet holds the current value of zero or more registry-level constant labels
+// configured through KubeRegistry.SetDynamicConstLabel, and stamps them onto every metric family
+// at Gather time. Reading the current value fresh on every Gather, rather than baking it into
+// each collector's Desc at construction time, is what gives a value change correct series
+// transition semantics: the previous value is simply never stamped again after set is called, so
+// there is no point at which two conflicting series for the same underlying metric are both
+// exposed.
+type dynamicLabelSet struct {
+	mu     sync.RWMutex
+	values map[string]string
+}
+
+func newDynamicLabelSet() *dynamicLabelSet {
+	return &dynamicLabelSet{values: map[string]string{}}
+}
+
+// set atomically updates the value of the label named name. A Gather racing with set observes
+// either the old or the new value for every metric it stamps, never a mix of the two.
+func (s *dynamicLabelSet) set(name, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[name] = value
+}
+
+// stamp overwrites (or adds) every configured label onto every metric in families, in place, and
+// re-sorts each metric's labels back into the order Gather's exposition format requires.
+func (s *dynamicLabelSet) stamp(families []*dto.MetricFamily) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.values) == 0 {
+		return
+	}
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			for name, value := range s.values {
+				name, value := name, value
+				stampLabel(m, name, value)
+			}
+			sort.Sort(labelPairsByName(m.Label))
+		}
+	}
+}
+
+// stampLabel overwrites m's label named name with value, adding it if not already present.
+func stampLabel(m *dto.Metric, name, value string) {
+	for _, pair := range m.Label {
+		if pair.GetName() == name {
+			pair.Value = &value
+			return
+		}
+	}
+	m.Label = append(m.Label, &dto.LabelPair{Name: &name, Value: &value})
+}
+
+// labelPairsByName sorts dto.LabelPairs lexicographically by name, as prometheus registries
+// otherwise guarantee for exposition.
+type labelPairsByName []*dto.LabelPair
+
+func (l labelPairsByName) Len() int           { return len(l) }
+func (l labelPairsByName) Swap(i, j int)      { l[i], l[j] = l[j], l[i] }
+func (l labelPairsByName) Less(i, j int) bool { return l[i].GetName() < l[j].GetName() }
@@ -0,0 +1,62 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+func TestDynamicConstLabelStampedOnGather(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	requests := NewCounter(&CounterOpts{Name: "test_requests", StabilityLevel: ALPHA, Help: "requests"})
+	registry.MustRegister(requests)
+	requests.Inc()
+
+	registry.SetDynamicConstLabel("leader", "false")
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := labelValue(families[0].GetMetric()[0], "leader"); got != "false" {
+		t.Errorf("expected leader=false, got %q", got)
+	}
+}
+
+func TestDynamicConstLabelTransitionsCleanly(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	requests := NewCounter(&CounterOpts{Name: "test_requests2", StabilityLevel: ALPHA, Help: "requests"})
+	registry.MustRegister(requests)
+	requests.Inc()
+
+	registry.SetDynamicConstLabel("leader", "false")
+	registry.SetDynamicConstLabel("leader", "true")
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	metric := families[0].GetMetric()[0]
+	if len(metric.GetLabel()) != 1 {
+		t.Fatalf("expected exactly one leader series, got labels %v", metric.GetLabel())
+	}
+	if got := labelValue(metric, "leader"); got != "true" {
+		t.Errorf("expected leader=true, got %q", got)
+	}
+}
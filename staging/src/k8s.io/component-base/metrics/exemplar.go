@@ -0,0 +1,35 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// exemplarAdder is satisfied by an underlying prometheus.Counter that supports attaching an
+// OpenMetrics exemplar to an observation. The client_golang version vendored in this repo does
+// not implement it on any of its concrete counter types, so the type assertion in
+// Counter.AddWithExemplar and checkedCounterMetric.AddWithExemplar never currently succeeds; it is
+// written against this interface, rather than a concrete type, so those call sites start
+// forwarding exemplars the day this repo's vendored client_golang gains exemplar support, with no
+// further change here.
+type exemplarAdder interface {
+	AddWithExemplar(value float64, exemplar prometheus.Labels)
+}
+
+// exemplarObserver is the Histogram/Summary counterpart to exemplarAdder.
+type exemplarObserver interface {
+	ObserveWithExemplar(value float64, exemplar prometheus.Labels)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+// TestCounterAddWithExemplarFallsBackToAdd tests that AddWithExemplar still records the value
+// even though this repo's vendored prometheus client cannot yet attach the exemplar itself.
+func TestCounterAddWithExemplarFallsBackToAdd(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	requests := NewCounter(&CounterOpts{
+		Name:           "test_exemplar_requests",
+		StabilityLevel: ALPHA,
+		Help:           "requests",
+	})
+	registry.MustRegister(requests)
+
+	requests.AddWithExemplar(1, prometheus.Labels{"traceID": "abc123"})
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := families[0].GetMetric()[0].GetCounter().GetValue(); got != 1 {
+		t.Errorf("expected counter value 1, got %v", got)
+	}
+}
+
+// TestHistogramObserveWithExemplarFallsBackToObserve tests that ObserveWithExemplar still
+// records the value even though this repo's vendored prometheus client cannot yet attach the
+// exemplar itself.
+func TestHistogramObserveWithExemplarFallsBackToObserve(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	latency := NewHistogram(&HistogramOpts{
+		Name:           "test_exemplar_latency",
+		StabilityLevel: ALPHA,
+		Help:           "latency",
+		Buckets:        []float64{0.1, 0.5, 1, 5},
+	})
+	registry.MustRegister(latency)
+
+	latency.ObserveWithExemplar(0.5, prometheus.Labels{"traceID": "abc123"})
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := families[0].GetMetric()[0].GetHistogram().GetSampleCount(); got != 1 {
+		t.Errorf("expected sample count 1, got %v", got)
+	}
+}
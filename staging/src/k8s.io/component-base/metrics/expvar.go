@@ -0,0 +1,94 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"expvar"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// PublishExpvar bridges an allow-listed subset of registry's metrics into expvar (and so into
+// /debug/vars), for environments whose existing tooling only consumes expvar rather than
+// Prometheus text exposition. Only metric families named in allowList are bridged; bridging
+// every metric a binary registers would make /debug/vars as unwieldy as the full /metrics output
+// it exists as a lightweight alternative to.
+//
+// name is the key the bridged values are published under in expvar's global map, and, like all
+// expvar variables, must be unique for the lifetime of the process.
+func PublishExpvar(name string, registry KubeRegistry, allowList []string) {
+	allowed := make(map[string]bool, len(allowList))
+	for _, n := range allowList {
+		allowed[n] = true
+	}
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return collectAllowed(registry, allowed)
+	}))
+}
+
+// collectAllowed gathers registry and returns the values of every metric belonging to an
+// allowed family, keyed by the family name plus, for a metric with labels, a suffix identifying
+// which label combination the value belongs to.
+func collectAllowed(registry KubeRegistry, allowed map[string]bool) map[string]float64 {
+	families, err := registry.Gather()
+	if err != nil {
+		return nil
+	}
+	values := map[string]float64{}
+	for _, family := range families {
+		if !allowed[family.GetName()] {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			values[expvarKey(family, metric)] = expvarValue(metric)
+		}
+	}
+	return values
+}
+
+// expvarKey returns the key a metric's value is published under: the bare family name for an
+// unlabeled metric, or the family name plus a "{label=value,...}" suffix for a labeled one, so
+// that e.g. each label value of a CounterVec gets its own entry.
+func expvarKey(family *dto.MetricFamily, metric *dto.Metric) string {
+	labels := metric.GetLabel()
+	if len(labels) == 0 {
+		return family.GetName()
+	}
+	key := family.GetName() + "{"
+	for i, label := range labels {
+		if i > 0 {
+			key += ","
+		}
+		key += label.GetName() + "=" + label.GetValue()
+	}
+	return key + "}"
+}
+
+// expvarValue returns the numeric value of metric, regardless of which of the mutually exclusive
+// value fields the Prometheus wire format happens to have populated for its type.
+func expvarValue(metric *dto.Metric) float64 {
+	switch {
+	case metric.Counter != nil:
+		return metric.Counter.GetValue()
+	case metric.Gauge != nil:
+		return metric.Gauge.GetValue()
+	case metric.Untyped != nil:
+		return metric.Untyped.GetValue()
+	default:
+		return 0
+	}
+}
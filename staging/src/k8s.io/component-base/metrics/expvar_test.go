@@ -0,0 +1,63 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"expvar"
+	"testing"
+
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+func TestPublishExpvar(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+
+	bridged := NewCounter(&CounterOpts{
+		Name:           "expvar_bridge_bridged_total",
+		Help:           "counter that is allow-listed for the expvar bridge",
+		StabilityLevel: ALPHA,
+	})
+	unbridged := NewCounter(&CounterOpts{
+		Name:           "expvar_bridge_unbridged_total",
+		Help:           "counter that is not allow-listed for the expvar bridge",
+		StabilityLevel: ALPHA,
+	})
+	labeled := NewCounterVec(&CounterOpts{
+		Name:           "expvar_bridge_labeled_total",
+		Help:           "labeled counter that is allow-listed for the expvar bridge",
+		StabilityLevel: ALPHA,
+	}, []string{"result"})
+	registry.MustRegister(bridged, unbridged, labeled)
+
+	bridged.Add(2)
+	unbridged.Add(5)
+	labeled.WithLabelValues("success").Inc()
+
+	PublishExpvar(t.Name(), registry, []string{"expvar_bridge_bridged_total", "expvar_bridge_labeled_total"})
+
+	values := expvar.Get(t.Name()).(expvar.Func)().(map[string]float64)
+
+	if got, want := values["expvar_bridge_bridged_total"], 2.0; got != want {
+		t.Errorf("expected expvar_bridge_bridged_total = %v, got %v", want, got)
+	}
+	if got, want := values["expvar_bridge_labeled_total{result=success}"], 1.0; got != want {
+		t.Errorf("expected expvar_bridge_labeled_total{result=success} = %v, got %v", want, got)
+	}
+	if _, ok := values["expvar_bridge_unbridged_total"]; ok {
+		t.Errorf("expvar_bridge_unbridged_total should not have been bridged, got %v", values)
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// dynamicAfterFreeze is implemented only by the collector wrapper AllowDynamicRegistration
+// returns, marking it exempt from a frozen registryFreezer's rejection of further registrations.
+type dynamicAfterFreeze interface {
+	allowedAfterFreeze()
+}
+
+// dynamicCollector wraps a KubeCollector so that it also satisfies dynamicAfterFreeze, without
+// otherwise changing its behavior: every KubeCollector method is promoted straight through to the
+// wrapped collector.
+type dynamicCollector struct {
+	KubeCollector
+}
+
+func (dynamicCollector) allowedAfterFreeze() {}
+
+// AllowDynamicRegistration wraps collector so that it may still be registered with a KubeRegistry
+// after Freeze has been called on it. Use this only for metrics whose full set of instances
+// genuinely cannot be known at component startup, such as one series per admitted CRD or webhook
+// configuration; any other registration after Freeze is exactly the kind of accidental,
+// request-path registration Freeze exists to catch.
+func AllowDynamicRegistration(collector KubeCollector) KubeCollector {
+	return dynamicCollector{KubeCollector: collector}
+}
+
+// registryFreezer holds a KubeRegistry's frozen/unfrozen state and checks new registrations
+// against it. It is embedded in kubeRegistry rather than folded into its other fields since
+// atomic.Value's zero value is not usable and this keeps the zero-initialization out of
+// NewKubeRegistry's struct literal.
+type registryFreezer struct {
+	frozen int32
+}
+
+// freeze marks the registry frozen, so that every subsequent call to check rejects a collector
+// unless it was wrapped with AllowDynamicRegistration.
+func (f *registryFreezer) freeze() {
+	atomic.StoreInt32(&f.frozen, 1)
+}
+
+// check returns an error naming collector if the registry is frozen and collector was not wrapped
+// with AllowDynamicRegistration, and nil otherwise.
+func (f *registryFreezer) check(c KubeCollector) error {
+	if atomic.LoadInt32(&f.frozen) == 0 {
+		return nil
+	}
+	if _, ok := c.(dynamicAfterFreeze); ok {
+		return nil
+	}
+	return fmt.Errorf("metrics registry is frozen: refusing to register a new collector outside of component startup; wrap it with metrics.AllowDynamicRegistration if this metric is intentionally registered dynamically")
+}
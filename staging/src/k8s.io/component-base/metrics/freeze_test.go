@@ -0,0 +1,48 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+func TestFreezeRejectsLateRegistration(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	startupCounter := NewCounter(&CounterOpts{Name: "startup_total", Help: "help", StabilityLevel: ALPHA})
+	if err := registry.Register(startupCounter); err != nil {
+		t.Fatalf("Register before Freeze failed: %v", err)
+	}
+
+	registry.Freeze()
+
+	lateCounter := NewCounter(&CounterOpts{Name: "late_total", Help: "help", StabilityLevel: ALPHA})
+	if err := registry.Register(lateCounter); err == nil {
+		t.Errorf("Register after Freeze succeeded, want an error")
+	}
+}
+
+func TestFreezeAllowsDynamicRegistration(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	registry.Freeze()
+
+	dynamicCounter := NewCounter(&CounterOpts{Name: "per_webhook_total", Help: "help", StabilityLevel: ALPHA})
+	if err := registry.Register(AllowDynamicRegistration(dynamicCounter)); err != nil {
+		t.Errorf("Register(AllowDynamicRegistration(...)) after Freeze failed: %v", err)
+	}
+}
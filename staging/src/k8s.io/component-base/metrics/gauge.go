@@ -0,0 +1,174 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/blang/semver"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Gauge is our internal representation for our wrapping struct around prometheus
+// gauges. Gauge implements both KubeCollector and GaugeMetric.
+type Gauge struct {
+	GaugeMetric
+	*GaugeOpts
+	lazyMetric
+	selfCollector
+}
+
+// Set sets the gauge to v, after checking v against the plausible range for the gauge's declared
+// Unit (see KubeOpts.Unit and assertPlausibleValue). The check is compiled out of non-debug
+// builds.
+func (g *Gauge) Set(v float64) {
+	assertPlausibleValue(g.GaugeOpts.Name, g.GaugeOpts.Unit, v)
+	g.GaugeMetric.Set(v)
+}
+
+// NewGauge returns an object which satisfies the KubeCollector and GaugeMetric interfaces.
+// However, the object returned will not measure anything unless the collector is first
+// registered, since the metric is lazily instantiated.
+func NewGauge(opts *GaugeOpts) *Gauge {
+	// todo: handle defaulting better
+	if opts.StabilityLevel == "" {
+		opts.StabilityLevel = ALPHA
+	}
+	kg := &Gauge{
+		GaugeOpts:  opts,
+		lazyMetric: lazyMetric{},
+	}
+	kg.setPrometheusGauge(noop)
+	kg.lazyInit(kg)
+	return kg
+}
+
+// setPrometheusGauge sets the underlying GaugeMetric object, i.e. the thing that does the measurement.
+func (g *Gauge) setPrometheusGauge(gauge prometheus.Gauge) {
+	g.GaugeMetric = gauge
+	g.initSelfCollection(gauge)
+}
+
+// DeprecatedVersion returns a pointer to the Version or nil
+func (g *Gauge) DeprecatedVersion() *semver.Version {
+	return g.GaugeOpts.DeprecatedVersion
+}
+
+// StabilityLevel returns the metric's declared StabilityLevel.
+func (g *Gauge) StabilityLevel() StabilityLevel {
+	return g.GaugeOpts.StabilityLevel
+}
+
+// initializeMetric invocation creates the actual underlying Gauge. Until this method is called
+// the underlying gauge is a no-op.
+func (g *Gauge) initializeMetric() {
+	g.GaugeOpts.annotateStabilityLevel()
+	// this actually creates the underlying prometheus gauge.
+	g.setPrometheusGauge(prometheus.NewGauge(g.GaugeOpts.toPromGaugeOpts()))
+}
+
+// initializeDeprecatedMetric invocation creates the actual (but deprecated) Gauge. Until this method
+// is called the underlying gauge is a no-op.
+func (g *Gauge) initializeDeprecatedMetric() {
+	g.GaugeOpts.markDeprecated()
+	g.initializeMetric()
+}
+
+// GaugeVec is the internal representation of our wrapping struct around prometheus
+// gaugeVecs. GaugeVec implements both KubeCollector and GaugeVecMetric.
+type GaugeVec struct {
+	*prometheus.GaugeVec
+	*GaugeOpts
+	lazyMetric
+	originalLabels []string
+	degradeState
+}
+
+// NewGaugeVec returns an object which satisfies the KubeCollector and GaugeVecMetric interfaces.
+// However, the object returned will not measure anything unless the collector is first
+// registered, since the metric is lazily instantiated.
+func NewGaugeVec(opts *GaugeOpts, labels []string) *GaugeVec {
+	gv := &GaugeVec{
+		GaugeVec:       noopGaugeVec,
+		GaugeOpts:      opts,
+		originalLabels: labels,
+		lazyMetric:     lazyMetric{},
+	}
+	gv.lazyInit(gv)
+	return gv
+}
+
+// DeprecatedVersion returns a pointer to the Version or nil
+func (v *GaugeVec) DeprecatedVersion() *semver.Version {
+	return v.GaugeOpts.DeprecatedVersion
+}
+
+// StabilityLevel returns the metric's declared StabilityLevel.
+func (v *GaugeVec) StabilityLevel() StabilityLevel {
+	return v.GaugeOpts.StabilityLevel
+}
+
+// initializeMetric invocation creates the actual underlying GaugeVec. Until this method is called
+// the underlying gaugeVec is a no-op.
+func (v *GaugeVec) initializeMetric() {
+	v.GaugeVec = prometheus.NewGaugeVec(v.GaugeOpts.toPromGaugeOpts(), v.originalLabels)
+}
+
+// initializeDeprecatedMetric invocation creates the actual (but deprecated) GaugeVec. Until this method is called
+// the underlying gaugeVec is a no-op.
+func (v *GaugeVec) initializeDeprecatedMetric() {
+	v.GaugeOpts.markDeprecated()
+	v.initializeMetric()
+}
+
+// WithLabelValues returns the Gauge for the given slice of label
+// values (same order as the VariableLabels in Desc). If that combination of
+// label values is accessed for the first time, a new Gauge is created IFF the gaugeVec
+// has been registered to a metrics registry.
+func (v *GaugeVec) WithLabelValues(lvs ...string) GaugeMetric {
+	if !v.IsCreated() {
+		return noop // return no-op gauge
+	}
+	lvs = constrainLabelValues(v.originalLabels, lvs, v.GaugeOpts.LabelValueAllowLists)
+	lvs = v.degradeLabelValues(v.originalLabels, lvs, v.GaugeOpts.DegradeLabel)
+	return &checkedGaugeMetric{GaugeMetric: v.GaugeVec.WithLabelValues(lvs...), name: v.GaugeOpts.Name, unit: v.GaugeOpts.Unit}
+}
+
+// With returns the Gauge for the given Labels map (the label names
+// must match those of the VariableLabels in Desc). If that label map is
+// accessed for the first time, a new Gauge is created IFF the gaugeVec has
+// been registered to a metrics registry.
+func (v *GaugeVec) With(labels prometheus.Labels) GaugeMetric {
+	if !v.IsCreated() {
+		return noop // return no-op gauge
+	}
+	labels = constrainLabelMap(labels, v.GaugeOpts.LabelValueAllowLists)
+	labels = v.degradeLabelMap(labels, v.GaugeOpts.DegradeLabel)
+	return &checkedGaugeMetric{GaugeMetric: v.GaugeVec.With(labels), name: v.GaugeOpts.Name, unit: v.GaugeOpts.Unit}
+}
+
+// checkedGaugeMetric wraps a GaugeMetric to apply assertPlausibleValue to values passed to
+// Set, since the plain prometheus.Gauge returned by GaugeVec's With/WithLabelValues does not
+// carry the parent GaugeVec's declared Unit.
+type checkedGaugeMetric struct {
+	GaugeMetric
+	name string
+	unit Unit
+}
+
+func (g *checkedGaugeMetric) Set(v float64) {
+	assertPlausibleValue(g.name, g.unit, v)
+	g.GaugeMetric.Set(v)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/blang/semver"
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+	"testing"
+)
+
+func TestGauge(t *testing.T) {
+	v114 := semver.MustParse("1.14.0")
+	v115 := semver.MustParse("1.15.0")
+	var tests = []struct {
+		desc string
+		*GaugeOpts
+		expectedMetricCount int
+		expectedHelp        string
+	}{
+		{
+			desc: "Test non deprecated",
+			GaugeOpts: &GaugeOpts{
+				Namespace:      "namespace",
+				Name:           "metric_test_name",
+				Subsystem:      "subsystem",
+				StabilityLevel: ALPHA,
+				Help:           "gauge help",
+			},
+			expectedMetricCount: 1,
+			expectedHelp:        "[ALPHA] gauge help",
+		},
+		{
+			desc: "Test deprecated",
+			GaugeOpts: &GaugeOpts{
+				Namespace:         "namespace",
+				Name:              "metric_test_name",
+				Subsystem:         "subsystem",
+				Help:              "gauge help",
+				StabilityLevel:    ALPHA,
+				DeprecatedVersion: &v115,
+			},
+			expectedMetricCount: 1,
+			expectedHelp:        "[ALPHA] (Deprecated since 1.15.0) gauge help",
+		},
+		{
+			desc: "Test hidden",
+			GaugeOpts: &GaugeOpts{
+				Namespace:         "namespace",
+				Name:              "metric_test_name",
+				Subsystem:         "subsystem",
+				Help:              "gauge help",
+				StabilityLevel:    ALPHA,
+				DeprecatedVersion: &v114,
+			},
+			expectedMetricCount: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			registry := NewKubeRegistry(apimachineryversion.Info{
+				Major:      "1",
+				Minor:      "15",
+				GitVersion: "v1.15.0-alpha-1.12345",
+			})
+			g := NewGauge(test.GaugeOpts)
+			registry.MustRegister(g)
+			g.Set(3)
+
+			ms, err := registry.Gather()
+			if err != nil {
+				t.Fatalf("Gather failed %v", err)
+			}
+			if len(ms) != test.expectedMetricCount {
+				t.Errorf("Got %v metrics, Want: %v metrics", len(ms), test.expectedMetricCount)
+			}
+			for _, mf := range ms {
+				if mf.GetHelp() != test.expectedHelp {
+					t.Errorf("Got %s as help message, want %s", mf.GetHelp(), test.expectedHelp)
+				}
+				for _, m := range mf.GetMetric() {
+					if m.GetGauge().GetValue() != 3 {
+						t.Errorf("Got %v, wanted 3 as the gauge value", m.GetGauge().GetValue())
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGaugeVec(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	v := NewGaugeVec(&GaugeOpts{
+		Name: "metric_test_name",
+		Help: "gauge help",
+	}, []string{"label_a"})
+	registry.MustRegister(v)
+
+	v.WithLabelValues("1").Set(5)
+	v.WithLabelValues("2").Set(7)
+
+	mfs, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed %v", err)
+	}
+	if len(mfs) != 1 {
+		t.Fatalf("Got %v metric families, want 1", len(mfs))
+	}
+	if len(mfs[0].GetMetric()) != 2 {
+		t.Errorf("Got %v metrics, want 2", len(mfs[0].GetMetric()))
+	}
+}
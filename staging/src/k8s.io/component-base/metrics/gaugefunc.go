@@ -0,0 +1,83 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/blang/semver"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// GaugeFunc is our internal representation for our wrapping struct around prometheus
+// GaugeFuncs. Unlike Gauge, its value is read from the function passed to NewGaugeFunc at
+// Collect time rather than Set by the caller, but it still goes through the same lazy
+// registration, deprecation and hiding lifecycle as every other KubeCollector, so callers no
+// longer need to reach past the framework straight to prometheus.NewGaugeFunc to get a
+// callback-based gauge.
+type GaugeFunc struct {
+	*GaugeOpts
+	lazyMetric
+	selfCollector
+	function func() float64
+}
+
+// NewGaugeFunc returns an object which satisfies the KubeCollector interface. However, the
+// object returned will not measure anything unless the collector is first registered, since
+// the value is only read from function when the metric is created.
+func NewGaugeFunc(opts *GaugeOpts, function func() float64) *GaugeFunc {
+	// todo: handle defaulting better
+	if opts.StabilityLevel == "" {
+		opts.StabilityLevel = ALPHA
+	}
+	g := &GaugeFunc{
+		GaugeOpts: opts,
+		function:  function,
+	}
+	g.setPrometheusGaugeFunc(noop)
+	g.lazyInit(g)
+	return g
+}
+
+// setPrometheusGaugeFunc sets the underlying Metric object, i.e. the thing that actually calls
+// function when scraped.
+func (g *GaugeFunc) setPrometheusGaugeFunc(metric prometheus.Metric) {
+	g.initSelfCollection(metric)
+}
+
+// DeprecatedVersion returns a pointer to the Version or nil
+func (g *GaugeFunc) DeprecatedVersion() *semver.Version {
+	return g.GaugeOpts.DeprecatedVersion
+}
+
+// StabilityLevel returns the metric's declared StabilityLevel.
+func (g *GaugeFunc) StabilityLevel() StabilityLevel {
+	return g.GaugeOpts.StabilityLevel
+}
+
+// initializeMetric invocation creates the actual underlying GaugeFunc. Until this method is
+// called the underlying gauge is a no-op.
+func (g *GaugeFunc) initializeMetric() {
+	g.GaugeOpts.annotateStabilityLevel()
+	// this actually creates the underlying prometheus gauge func.
+	g.setPrometheusGaugeFunc(prometheus.NewGaugeFunc(g.GaugeOpts.toPromGaugeOpts(), g.function))
+}
+
+// initializeDeprecatedMetric invocation creates the actual (but deprecated) GaugeFunc. Until
+// this method is called the underlying gauge is a no-op.
+func (g *GaugeFunc) initializeDeprecatedMetric() {
+	g.GaugeOpts.markDeprecated()
+	g.initializeMetric()
+}
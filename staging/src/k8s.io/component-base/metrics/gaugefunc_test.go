@@ -0,0 +1,61 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+func TestGaugeFuncReadsCallbackAtCollectTime(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	value := 1.0
+	g := NewGaugeFunc(&GaugeOpts{
+		Name:           "test_gauge_func",
+		Help:           "help",
+		StabilityLevel: ALPHA,
+	}, func() float64 { return value })
+	registry.MustRegister(g)
+
+	value = 42.0
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+
+	var mf *dto.MetricFamily
+	for _, f := range families {
+		if f.GetName() == "test_gauge_func" {
+			mf = f
+		}
+	}
+	if mf == nil {
+		t.Fatalf("did not find test_gauge_func in gathered families")
+	}
+	if got := mf.GetMetric()[0].GetGauge().GetValue(); got != 42.0 {
+		t.Errorf("got gauge value %v, want 42.0", got)
+	}
+}
+
+func TestGaugeFuncNotCreatedBeforeRegistration(t *testing.T) {
+	g := NewGaugeFunc(&GaugeOpts{Name: "test_gauge_func_unregistered", Help: "help"}, func() float64 { return 1 })
+	if g.IsCreated() {
+		t.Errorf("expected GaugeFunc to not be created before registration")
+	}
+}
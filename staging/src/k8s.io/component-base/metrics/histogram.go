@@ -0,0 +1,212 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/blang/semver"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Histogram is our internal representation for our wrapping struct around prometheus
+// histograms. Histogram implements both KubeCollector and ObserverMetric.
+type Histogram struct {
+	ObserverMetric
+	*HistogramOpts
+	lazyMetric
+	selfCollector
+}
+
+// Observe records v, after checking v against the plausible range for the histogram's declared
+// Unit (see KubeOpts.Unit and assertPlausibleValue). The check is compiled out of non-debug
+// builds.
+func (h *Histogram) Observe(v float64) {
+	assertPlausibleValue(h.HistogramOpts.Name, h.HistogramOpts.Unit, v)
+	h.ObserverMetric.Observe(v)
+}
+
+// ObserveWithExemplar behaves like Observe, but additionally attaches exemplar (e.g. a trace ID)
+// to the observation for consumers that scrape this metric as OpenMetrics. See exemplarObserver
+// for why exemplar is silently dropped instead on this repo's currently vendored prometheus
+// client.
+func (h *Histogram) ObserveWithExemplar(v float64, exemplar prometheus.Labels) {
+	assertPlausibleValue(h.HistogramOpts.Name, h.HistogramOpts.Unit, v)
+	if observer, ok := h.ObserverMetric.(exemplarObserver); ok {
+		observer.ObserveWithExemplar(v, exemplar)
+		return
+	}
+	h.ObserverMetric.Observe(v)
+}
+
+// NewHistogram returns an object which satisfies the KubeCollector and ObserverMetric interfaces.
+// However, the object returned will not measure anything unless the collector is first
+// registered, since the metric is lazily instantiated. It panics if opts.Buckets fails
+// ValidateHistogramBuckets for opts.StabilityLevel, since that is a programmer error caught at
+// construction time rather than a runtime condition.
+func NewHistogram(opts *HistogramOpts) *Histogram {
+	// todo: handle defaulting better
+	if opts.StabilityLevel == "" {
+		opts.StabilityLevel = ALPHA
+	}
+	if err := ValidateHistogramBuckets(opts.StabilityLevel, opts.Buckets); err != nil {
+		panic(err)
+	}
+	kh := &Histogram{
+		HistogramOpts: opts,
+		lazyMetric:    lazyMetric{},
+	}
+	kh.setPrometheusHistogram(noop)
+	kh.lazyInit(kh)
+	return kh
+}
+
+// setPrometheusHistogram sets the underlying ObserverMetric object, i.e. the thing that does the measurement.
+func (h *Histogram) setPrometheusHistogram(histogram prometheus.Histogram) {
+	h.ObserverMetric = histogram
+	h.initSelfCollection(histogram)
+}
+
+// DeprecatedVersion returns a pointer to the Version or nil
+func (h *Histogram) DeprecatedVersion() *semver.Version {
+	return h.HistogramOpts.DeprecatedVersion
+}
+
+// StabilityLevel returns the metric's declared StabilityLevel.
+func (h *Histogram) StabilityLevel() StabilityLevel {
+	return h.HistogramOpts.StabilityLevel
+}
+
+// initializeMetric invocation creates the actual underlying Histogram. Until this method is called
+// the underlying histogram is a no-op.
+func (h *Histogram) initializeMetric() {
+	h.HistogramOpts.annotateStabilityLevel()
+	// this actually creates the underlying prometheus histogram.
+	h.setPrometheusHistogram(prometheus.NewHistogram(h.HistogramOpts.toPromHistogramOpts()))
+}
+
+// initializeDeprecatedMetric invocation creates the actual (but deprecated) Histogram. Until this method
+// is called the underlying histogram is a no-op.
+func (h *Histogram) initializeDeprecatedMetric() {
+	h.HistogramOpts.markDeprecated()
+	h.initializeMetric()
+}
+
+// HistogramVec is the internal representation of our wrapping struct around prometheus
+// histogramVecs. HistogramVec implements both KubeCollector and ObserverVecMetric.
+type HistogramVec struct {
+	*prometheus.HistogramVec
+	*HistogramOpts
+	lazyMetric
+	originalLabels []string
+	degradeState
+}
+
+// NewHistogramVec returns an object which satisfies the KubeCollector and ObserverVecMetric
+// interfaces. However, the object returned will not measure anything unless the collector is
+// first registered, since the metric is lazily instantiated. It panics if opts.Buckets fails
+// ValidateHistogramBuckets for opts.StabilityLevel.
+func NewHistogramVec(opts *HistogramOpts, labels []string) *HistogramVec {
+	if opts.StabilityLevel == "" {
+		opts.StabilityLevel = ALPHA
+	}
+	if err := ValidateHistogramBuckets(opts.StabilityLevel, opts.Buckets); err != nil {
+		panic(err)
+	}
+	hv := &HistogramVec{
+		HistogramVec:   noopHistogramVec,
+		HistogramOpts:  opts,
+		originalLabels: labels,
+		lazyMetric:     lazyMetric{},
+	}
+	hv.lazyInit(hv)
+	return hv
+}
+
+// DeprecatedVersion returns a pointer to the Version or nil
+func (v *HistogramVec) DeprecatedVersion() *semver.Version {
+	return v.HistogramOpts.DeprecatedVersion
+}
+
+// StabilityLevel returns the metric's declared StabilityLevel.
+func (v *HistogramVec) StabilityLevel() StabilityLevel {
+	return v.HistogramOpts.StabilityLevel
+}
+
+// initializeMetric invocation creates the actual underlying HistogramVec. Until this method is called
+// the underlying histogramVec is a no-op.
+func (v *HistogramVec) initializeMetric() {
+	v.HistogramVec = prometheus.NewHistogramVec(v.HistogramOpts.toPromHistogramOpts(), v.originalLabels)
+}
+
+// initializeDeprecatedMetric invocation creates the actual (but deprecated) HistogramVec. Until this method is called
+// the underlying histogramVec is a no-op.
+func (v *HistogramVec) initializeDeprecatedMetric() {
+	v.HistogramOpts.markDeprecated()
+	v.initializeMetric()
+}
+
+// WithLabelValues returns the Observer for the given slice of label
+// values (same order as the VariableLabels in Desc). If that combination of
+// label values is accessed for the first time, a new Histogram is created IFF the histogramVec
+// has been registered to a metrics registry.
+func (v *HistogramVec) WithLabelValues(lvs ...string) ObserverMetric {
+	if !v.IsCreated() {
+		return noop // return no-op observer
+	}
+	lvs = constrainLabelValues(v.originalLabels, lvs, v.HistogramOpts.LabelValueAllowLists)
+	lvs = v.degradeLabelValues(v.originalLabels, lvs, v.HistogramOpts.DegradeLabel)
+	return &checkedObserverMetric{ObserverMetric: v.HistogramVec.WithLabelValues(lvs...), name: v.HistogramOpts.Name, unit: v.HistogramOpts.Unit}
+}
+
+// With returns the Observer for the given Labels map (the label names
+// must match those of the VariableLabels in Desc). If that label map is
+// accessed for the first time, a new Histogram is created IFF the histogramVec has
+// been registered to a metrics registry.
+func (v *HistogramVec) With(labels prometheus.Labels) ObserverMetric {
+	if !v.IsCreated() {
+		return noop // return no-op observer
+	}
+	labels = constrainLabelMap(labels, v.HistogramOpts.LabelValueAllowLists)
+	labels = v.degradeLabelMap(labels, v.HistogramOpts.DegradeLabel)
+	return &checkedObserverMetric{ObserverMetric: v.HistogramVec.With(labels), name: v.HistogramOpts.Name, unit: v.HistogramOpts.Unit}
+}
+
+// checkedObserverMetric wraps an ObserverMetric to apply assertPlausibleValue to values passed
+// to Observe, since the plain prometheus.Observer returned by HistogramVec/SummaryVec's
+// With/WithLabelValues does not carry the parent vec's declared Unit.
+type checkedObserverMetric struct {
+	ObserverMetric
+	name string
+	unit Unit
+}
+
+func (o *checkedObserverMetric) Observe(v float64) {
+	assertPlausibleValue(o.name, o.unit, v)
+	o.ObserverMetric.Observe(v)
+}
+
+// ObserveWithExemplar behaves like Observe, but additionally attaches exemplar (e.g. a trace ID)
+// to the observation for consumers that scrape this metric as OpenMetrics. See exemplarObserver
+// for why exemplar is silently dropped instead on this repo's currently vendored prometheus
+// client.
+func (o *checkedObserverMetric) ObserveWithExemplar(v float64, exemplar prometheus.Labels) {
+	assertPlausibleValue(o.name, o.unit, v)
+	if observer, ok := o.ObserverMetric.(exemplarObserver); ok {
+		observer.ObserveWithExemplar(v, exemplar)
+		return
+	}
+	o.ObserverMetric.Observe(v)
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/klog"
+)
+
+// scrapeTimeoutHeader is the header Prometheus sets on scrape requests to advertise how long it
+// is willing to wait for a response, so components can budget their own collection time.
+const scrapeTimeoutHeader = "X-Prometheus-Scrape-Timeout-Seconds"
+
+// scrapeTimeoutSafetyMargin is subtracted from the advertised scrape timeout so a slow write to
+// the response body doesn't itself cause Prometheus to consider the scrape failed.
+const scrapeTimeoutSafetyMargin = 500 * time.Millisecond
+
+// HandlerWithScrapeTimeout returns an http.Handler which gathers metrics from gatherer, honoring
+// the Prometheus scrape timeout header when present. If gathering does not complete before the
+// deadline, whatever metric families have already been gathered are served, together with an
+// additional incomplete_scrape gauge metric set to 1 so consumers can detect the truncation.
+//
+// The returned handler negotiates the response body's exposition format from the request's
+// Accept header via promhttp.HandlerFor, so a scraper that prefers the protobuf format (for
+// example, to reduce parse overhead at high cardinality) is served protobuf without any extra
+// configuration on this end. The OpenMetrics text format is not offered, since the vendored
+// client_golang predates it; such a request falls back to the classic text format.
+func HandlerWithScrapeTimeout(gatherer KubeRegistry, opts promhttp.HandlerOpts) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timeout := scrapeTimeoutFromHeader(r.Header.Get(scrapeTimeoutHeader))
+
+		mfs, incomplete := gatherWithTimeout(gatherer, timeout)
+		if incomplete {
+			mfs = append(mfs, incompleteScrapeMetricFamily(1))
+		} else {
+			mfs = append(mfs, incompleteScrapeMetricFamily(0))
+		}
+		mfs = append(mfs, estimatedBytesMetricFamily(float64(totalEstimatedBytes(estimateMemoryUsage(mfs)))))
+
+		promhttp.HandlerFor(gathererFunc(func() ([]*dto.MetricFamily, error) {
+			return mfs, nil
+		}), opts).ServeHTTP(w, r)
+	})
+}
+
+// scrapeTimeoutFromHeader parses the Prometheus scrape timeout header, applying a safety margin.
+// A zero duration means no deadline should be enforced.
+func scrapeTimeoutFromHeader(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	seconds, err := strconv.ParseFloat(header, 64)
+	if err != nil {
+		klog.V(5).Infof("failed to parse %s header %q: %v", scrapeTimeoutHeader, header, err)
+		return 0
+	}
+	timeout := time.Duration(seconds*float64(time.Second)) - scrapeTimeoutSafetyMargin
+	if timeout <= 0 {
+		return 0
+	}
+	return timeout
+}
+
+// gatherWithTimeout gathers metrics from gatherer, giving up and returning whatever has already
+// been gathered once timeout elapses. It returns true if the gather was cut short.
+func gatherWithTimeout(gatherer KubeRegistry, timeout time.Duration) ([]*dto.MetricFamily, bool) {
+	if timeout <= 0 {
+		mfs, err := gatherer.Gather()
+		if err != nil {
+			klog.Errorf("error gathering metrics: %v", err)
+		}
+		return mfs, false
+	}
+
+	type result struct {
+		mfs []*dto.MetricFamily
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		mfs, err := gatherer.Gather()
+		done <- result{mfs, err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.err != nil {
+			klog.Errorf("error gathering metrics: %v", res.err)
+		}
+		return res.mfs, false
+	case <-time.After(timeout):
+		klog.Warningf("metrics scrape did not complete within %v, returning partial results", timeout)
+		return nil, true
+	}
+}
+
+// incompleteScrapeMetricFamily builds the incomplete_scrape marker metric family, set to 1 when
+// the scrape was cut short by its deadline and 0 otherwise.
+func incompleteScrapeMetricFamily(value float64) *dto.MetricFamily {
+	name := "incomplete_scrape"
+	help := "1 if this scrape did not complete within the timeout requested by the scraper and results are partial, 0 otherwise."
+	metricType := dto.MetricType_GAUGE
+	return &dto.MetricFamily{
+		Name: &name,
+		Help: &help,
+		Type: &metricType,
+		Metric: []*dto.Metric{
+			{
+				Gauge: &dto.Gauge{Value: &value},
+			},
+		},
+	}
+}
+
+// gathererFunc adapts a plain function to the promhttp.Gatherer interface used by promhttp.HandlerFor.
+type gathererFunc func() ([]*dto.MetricFamily, error)
+
+func (f gathererFunc) Gather() ([]*dto.MetricFamily, error) {
+	return f()
+}
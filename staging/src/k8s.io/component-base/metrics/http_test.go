@@ -0,0 +1,119 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+func TestScrapeTimeoutFromHeader(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected time.Duration
+	}{
+		{"", 0},
+		{"not-a-number", 0},
+		{"0.1", 0},
+		{"5", 5*time.Second - scrapeTimeoutSafetyMargin},
+	}
+	for _, test := range tests {
+		if got := scrapeTimeoutFromHeader(test.header); got != test.expected {
+			t.Errorf("scrapeTimeoutFromHeader(%q) = %v, want %v", test.header, got, test.expected)
+		}
+	}
+}
+
+func TestGatherWithTimeoutNoDeadline(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{Major: "1", Minor: "15"})
+	mfs, incomplete := gatherWithTimeout(registry, 0)
+	if incomplete {
+		t.Errorf("expected gather without a deadline to never be marked incomplete")
+	}
+	if mfs == nil {
+		t.Errorf("expected a (possibly empty) slice of metric families")
+	}
+}
+
+// TestHandlerContentNegotiation exercises the content-type negotiation HandlerWithScrapeTimeout
+// inherits from promhttp.HandlerFor, so a regression in how the Accept header is honored is
+// caught here rather than by a scraper in the field. Note that the vendored client_golang in
+// this tree predates OpenMetrics text format support, so only the plain text and protobuf
+// exposition formats can be negotiated; an Accept header requesting OpenMetrics falls back to
+// plain text like any other unsupported type.
+func TestHandlerContentNegotiation(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{Major: "1", Minor: "15"})
+	counter := NewCounter(&CounterOpts{
+		Name:           "test_content_negotiation_total",
+		Help:           "counter help",
+		StabilityLevel: ALPHA,
+	})
+	registry.MustRegister(counter)
+	counter.Inc()
+
+	handler := HandlerWithScrapeTimeout(registry, promhttp.HandlerOpts{})
+
+	tests := []struct {
+		name                string
+		accept              string
+		wantContentTypeExpr func(contentType string) bool
+	}{
+		{
+			name:                "no accept header defaults to text",
+			accept:              "",
+			wantContentTypeExpr: func(contentType string) bool { return strings.HasPrefix(contentType, "text/plain") },
+		},
+		{
+			name:                "explicit text accept",
+			accept:              "text/plain;version=0.0.4",
+			wantContentTypeExpr: func(contentType string) bool { return strings.HasPrefix(contentType, "text/plain") },
+		},
+		{
+			name:                "protobuf delimited accept",
+			accept:              `application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited`,
+			wantContentTypeExpr: func(contentType string) bool { return strings.HasPrefix(contentType, "application/vnd.google.protobuf") },
+		},
+		{
+			name:                "unsupported openmetrics accept falls back to text",
+			accept:              `application/openmetrics-text;version=1.0.0`,
+			wantContentTypeExpr: func(contentType string) bool { return strings.HasPrefix(contentType, "text/plain") },
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if test.accept != "" {
+				req.Header.Set("Accept", test.accept)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", rec.Code)
+			}
+			contentType := rec.Header().Get("Content-Type")
+			if !test.wantContentTypeExpr(contentType) {
+				t.Errorf("unexpected Content-Type %q for Accept %q", contentType, test.accept)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// unexpectedLabelValue is substituted for any label value a LabelValueAllowList rejects.
+const unexpectedLabelValue = "unexpected"
+
+// LabelAllowListErrorCounter counts, cumulatively since process start, every label value a
+// LabelValueAllowList has rejected and substituted with "unexpected". It is not registered
+// automatically, since not every component uses LabelValueAllowLists; register it (e.g. via
+// legacyregistry.MustRegister(metrics.LabelAllowListErrorCounter)) to make the error budget it
+// tracks visible to monitoring.
+var LabelAllowListErrorCounter = NewCounter(&CounterOpts{
+	Namespace:      "metrics",
+	Subsystem:      "label_allow_list",
+	Name:           "errors_total",
+	Help:           "Cumulative number of label values rejected by a LabelValueAllowList and recorded as \"unexpected\" instead.",
+	StabilityLevel: ALPHA,
+})
+
+// LabelValueNotAllowedError reports a label value a LabelValueAllowList rejected. It is only
+// ever surfaced by way of a panic, and only in binaries built with the "debug" build tag (see
+// reportLabelValueNotAllowed in label_allow_list_debug.go); production builds instead record the
+// rejection in LabelAllowListErrorCounter and substitute unexpectedLabelValue, so a data-driven
+// label bug degrades a metric's cardinality rather than crashing the process.
+type LabelValueNotAllowedError struct {
+	Label string
+	Value string
+}
+
+func (e *LabelValueNotAllowedError) Error() string {
+	return fmt.Sprintf("value %q is not in the allow list for label %q", e.Value, e.Label)
+}
+
+// LabelValueAllowList bounds the set of values a Vec metric's label may take on, guarding
+// against a caller inadvertently blowing up the metric's (and thus prometheus's) cardinality by
+// passing unbounded input, such as a user agent or an object name, straight into a label value.
+type LabelValueAllowList struct {
+	// AllowList is the set of values permitted for the label. Values outside it are recorded as
+	// "unexpected" instead of being passed through to prometheus.
+	AllowList sets.String
+}
+
+// constrain returns value unchanged if l is nil or value is in AllowList. Otherwise it counts
+// the rejection in LabelAllowListErrorCounter, reports a *LabelValueNotAllowedError for label
+// through reportLabelValueNotAllowed, and returns unexpectedLabelValue in value's place.
+func (l *LabelValueAllowList) constrain(label, value string) string {
+	if l == nil || l.AllowList.Has(value) {
+		return value
+	}
+	LabelAllowListErrorCounter.Inc()
+	reportLabelValueNotAllowed(&LabelValueNotAllowedError{Label: label, Value: value})
+	return unexpectedLabelValue
+}
+
+// constrainLabelValues applies allowLists, keyed by label name, to lvs, the positional label
+// values a Vec's WithLabelValues was called with in the same order as labels (the label names
+// the Vec was constructed with), replacing any value an allow list rejects with "unexpected".
+// labels and lvs are always the same length here, per WithLabelValues's contract with
+// prometheus. A nil or empty allowLists returns lvs unchanged without allocating.
+func constrainLabelValues(labels []string, lvs []string, allowLists map[string]*LabelValueAllowList) []string {
+	if len(allowLists) == 0 {
+		return lvs
+	}
+	constrained := make([]string, len(lvs))
+	for i, v := range lvs {
+		constrained[i] = allowLists[labels[i]].constrain(labels[i], v)
+	}
+	return constrained
+}
+
+// constrainLabelMap applies allowLists, keyed by label name, to labels, the named label map a
+// Vec's With was called with, replacing any value an allow list rejects with "unexpected". A nil
+// or empty allowLists returns labels unchanged without allocating.
+func constrainLabelMap(labels prometheus.Labels, allowLists map[string]*LabelValueAllowList) prometheus.Labels {
+	if len(allowLists) == 0 {
+		return labels
+	}
+	constrained := make(prometheus.Labels, len(labels))
+	for k, v := range labels {
+		constrained[k] = allowLists[k].constrain(k, v)
+	}
+	return constrained
+}
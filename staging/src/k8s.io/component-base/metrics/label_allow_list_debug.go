@@ -0,0 +1,27 @@
+// +build debug
+
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+// reportLabelValueNotAllowed panics with err. It is compiled in only for binaries built with the
+// "debug" build tag (see unit_asserts.go for the same convention), so that a data-driven label
+// bug is caught loudly in tests and debug builds while production builds only pay for a counter
+// increment. See LabelValueNotAllowedError.
+func reportLabelValueNotAllowed(err *LabelValueNotAllowedError) {
+	panic(err)
+}
@@ -0,0 +1,38 @@
+// +build debug
+
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "testing"
+
+func TestReportLabelValueNotAllowedPanics(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("expected reportLabelValueNotAllowed to panic in a debug build")
+		}
+		err, ok := r.(*LabelValueNotAllowedError)
+		if !ok {
+			t.Fatalf("expected panic value to be a *LabelValueNotAllowedError, got %T", r)
+		}
+		if err.Label != "code" || err.Value != "999" {
+			t.Errorf("unexpected error contents: %+v", err)
+		}
+	}()
+	reportLabelValueNotAllowed(&LabelValueNotAllowedError{Label: "code", Value: "999"})
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+func TestLabelValueAllowListWithLabelValues(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	requests := NewCounterVec(&CounterOpts{
+		Name:           "test_allowlist_requests",
+		StabilityLevel: ALPHA,
+		Help:           "requests",
+		LabelValueAllowLists: map[string]*LabelValueAllowList{
+			"code": {AllowList: sets.NewString("200", "404")},
+		},
+	}, []string{"code"})
+	registry.MustRegister(requests)
+
+	requests.WithLabelValues("200").Inc()
+	requests.WithLabelValues("999").Inc()
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	metrics := families[0].GetMetric()
+	if len(metrics) != 2 {
+		t.Fatalf("expected 2 series, got %d", len(metrics))
+	}
+	seen := sets.NewString()
+	for _, m := range metrics {
+		seen.Insert(labelValue(m, "code"))
+	}
+	if !seen.HasAll("200", "unexpected") {
+		t.Errorf("expected series for code=200 and code=unexpected, got %v", seen.List())
+	}
+}
+
+func TestLabelValueAllowListWith(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	requests := NewCounterVec(&CounterOpts{
+		Name:           "test_allowlist_requests_with",
+		StabilityLevel: ALPHA,
+		Help:           "requests",
+		LabelValueAllowLists: map[string]*LabelValueAllowList{
+			"code": {AllowList: sets.NewString("200")},
+		},
+	}, []string{"code"})
+	registry.MustRegister(requests)
+
+	requests.With(map[string]string{"code": "500"}).Inc()
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	metric := families[0].GetMetric()[0]
+	if got := labelValue(metric, "code"); got != unexpectedLabelValue {
+		t.Errorf("expected code=unexpected, got %q", got)
+	}
+}
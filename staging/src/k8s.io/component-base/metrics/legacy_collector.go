@@ -0,0 +1,67 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/blang/semver"
+)
+
+// LegacyCollector adapts a metric that was created directly against the raw prometheus client
+// (i.e. via prometheus.NewCounter/NewGauge/etc. rather than this package's constructors) so that
+// it can be registered with a KubeRegistry. This exists purely as a migration aid: components
+// converting call sites away from raw prometheus metrics one at a time can register the not-yet
+// converted ones through the same KubeRegistry as everything else, without having to convert the
+// whole component in one pass. Prefer creating the metric directly with this package's
+// constructors (NewCounter, NewCounterVec, ...) over reaching for this type in new code.
+type LegacyCollector struct {
+	Collector
+}
+
+var _ KubeCollector = &LegacyCollector{}
+
+// NewLegacyCollector wraps an existing raw prometheus collector (for example, one returned by
+// prometheus.NewCounter) so it satisfies KubeCollector and can be registered with a KubeRegistry.
+// The wrapped collector is always created (it already exists), is never considered deprecated,
+// and is treated as ALPHA-stability since this package has no way to inspect its stability level.
+func NewLegacyCollector(c Collector) *LegacyCollector {
+	return &LegacyCollector{Collector: c}
+}
+
+// DeprecatedVersion always returns nil: raw prometheus collectors carry no deprecation metadata,
+// so this migration shim cannot participate in the version-based hiding/deprecation machinery.
+func (c *LegacyCollector) DeprecatedVersion() *semver.Version { return nil }
+
+// StabilityLevel always returns ALPHA: raw prometheus collectors carry no stability metadata, and
+// treating them as ALPHA keeps them out of the BETA deprecation grace period machinery, which this
+// shim has no metadata to participate in correctly anyway.
+func (c *LegacyCollector) StabilityLevel() StabilityLevel { return ALPHA }
+
+// Create always reports the wrapped collector as created, since it was already instantiated by
+// the caller before being wrapped.
+func (c *LegacyCollector) Create(*semver.Version) bool { return true }
+
+// IsCreated always returns true. See Create.
+func (c *LegacyCollector) IsCreated() bool { return true }
+
+// IsHidden always returns false: this shim has no deprecation metadata to hide against.
+func (c *LegacyCollector) IsHidden() bool { return false }
+
+// IsDeprecated always returns false. See DeprecatedVersion.
+func (c *LegacyCollector) IsDeprecated() bool { return false }
+
+func (c *LegacyCollector) initializeMetric()           {}
+func (c *LegacyCollector) initializeDeprecatedMetric() {}
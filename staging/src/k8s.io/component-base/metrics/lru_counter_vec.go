@@ -0,0 +1,122 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+)
+
+// LRUCounterVec wraps a CounterVec whose label value combinations come from a
+// semi-bounded but not statically known domain (e.g. image names or plugin
+// names). Instead of requiring callers to hand-roll a map to avoid unbounded
+// cardinality, it evicts the least recently used label combination once the
+// configured bound is exceeded.
+type LRUCounterVec struct {
+	vec *CounterVec
+
+	maxSize  int
+	mu       sync.Mutex
+	entries  map[string]*list.Element
+	eviction *list.List
+
+	evictionsTotal *Counter
+}
+
+type lruCounterEntry struct {
+	key    string
+	values []string
+}
+
+// NewLRUCounterVec returns a new LRUCounterVec bounded to hold at most
+// maxSize distinct label value combinations for the given CounterVec. When
+// the bound is exceeded, the least recently used combination is evicted and
+// evictionsTotal is incremented.
+func NewLRUCounterVec(opts *CounterOpts, labels []string, maxSize int) *LRUCounterVec {
+	evictionOpts := &CounterOpts{
+		Namespace:         opts.Namespace,
+		Subsystem:         opts.Subsystem,
+		Name:              opts.Name + "_evictions_total",
+		Help:              "Total number of label combinations evicted from the LRU-bounded " + opts.Name + " metric.",
+		ConstLabels:       opts.ConstLabels,
+		DeprecatedVersion: opts.DeprecatedVersion,
+		StabilityLevel:    opts.StabilityLevel,
+		Unit:              opts.Unit,
+	}
+	return &LRUCounterVec{
+		vec:            NewCounterVec(opts, labels),
+		maxSize:        maxSize,
+		entries:        make(map[string]*list.Element),
+		eviction:       list.New(),
+		evictionsTotal: NewCounter(evictionOpts),
+	}
+}
+
+// Registerables returns the collectors that must be registered for this
+// LRUCounterVec to be exposed and tracked: the underlying vector and its
+// eviction counter.
+func (v *LRUCounterVec) Registerables() []KubeCollector {
+	return []KubeCollector{v.vec, v.evictionsTotal}
+}
+
+// GetOrCreateWith returns the Counter for the given label values, recording
+// the combination as most-recently-used. If adding this combination would
+// exceed maxSize, the least recently used combination is evicted first.
+func (v *LRUCounterVec) GetOrCreateWith(lvs ...string) CounterMetric {
+	key := strings.Join(lvs, "\xff")
+
+	v.mu.Lock()
+	if elem, ok := v.entries[key]; ok {
+		v.eviction.MoveToFront(elem)
+		v.mu.Unlock()
+		return v.vec.WithLabelValues(lvs...)
+	}
+
+	if v.maxSize > 0 && len(v.entries) >= v.maxSize {
+		oldest := v.eviction.Back()
+		if oldest != nil {
+			v.eviction.Remove(oldest)
+			evicted := oldest.Value.(*lruCounterEntry)
+			delete(v.entries, evicted.key)
+			v.vec.vec().DeleteLabelValues(evicted.values...)
+			v.evictionsTotal.Inc()
+		}
+	}
+
+	elem := v.eviction.PushFront(&lruCounterEntry{key: key, values: lvs})
+	v.entries[key] = elem
+	v.mu.Unlock()
+
+	return v.vec.WithLabelValues(lvs...)
+}
+
+// vec exposes the underlying prometheus.CounterVec for deletion of evicted
+// entries. It returns a no-op vector until the metric is registered, in which
+// case DeleteLabelValues is a harmless no-op as well.
+func (v *CounterVec) vec() *deletableCounterVec {
+	return (*deletableCounterVec)(v)
+}
+
+type deletableCounterVec CounterVec
+
+func (v *deletableCounterVec) DeleteLabelValues(lvs ...string) bool {
+	if !((*CounterVec)(v)).IsCreated() {
+		return false
+	}
+	return v.CounterVec.DeleteLabelValues(lvs...)
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+func TestLRUCounterVecEviction(t *testing.T) {
+	v := NewLRUCounterVec(&CounterOpts{
+		Name: "test_lru_counter",
+		Help: "help",
+	}, []string{"label"}, 2)
+
+	registry := NewKubeRegistry(apimachineryversion.Info{
+		Major: "1",
+		Minor: "15",
+	})
+	registry.MustRegister(v.Registerables()...)
+
+	v.GetOrCreateWith("a").Inc()
+	v.GetOrCreateWith("b").Inc()
+	// "a" should be evicted since "b" was accessed more recently and the
+	// cache is bounded to two entries.
+	v.GetOrCreateWith("c").Inc()
+
+	if len(v.entries) != 2 {
+		t.Fatalf("expected 2 tracked label combinations, got %d", len(v.entries))
+	}
+	if _, ok := v.entries["a"]; ok {
+		t.Errorf("expected label combination 'a' to have been evicted")
+	}
+
+	metricFamilies, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var evictions float64
+	for _, mf := range metricFamilies {
+		if mf.GetName() == "test_lru_counter_evictions_total" {
+			evictions = mf.GetMetric()[0].GetCounter().GetValue()
+		}
+	}
+	if evictions != 1 {
+		t.Errorf("expected 1 eviction to be recorded, got %v", evictions)
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/spf13/pflag"
+)
+
+const (
+	dumpMetricsManifestFileFlagName     = "dump-metrics-manifest-file"
+	previousMetricsManifestFileFlagName = "previous-metrics-manifest-file"
+)
+
+var (
+	dumpMetricsManifestFile     = pflag.String(dumpMetricsManifestFileFlagName, "", "If non-empty, write a JSON manifest of this binary's currently registered metrics to this file at startup and exit.")
+	previousMetricsManifestFile = pflag.String(previousMetricsManifestFileFlagName, "", "If non-empty and used together with --"+dumpMetricsManifestFileFlagName+", diff the newly generated manifest against the manifest at this path and write the diff alongside it.")
+)
+
+// AddMetricsManifestFlags registers this package's metrics-manifest flags on arbitrary FlagSets,
+// such that they point to the same value as the global flags.
+func AddMetricsManifestFlags(fs *pflag.FlagSet) {
+	fs.AddFlag(pflag.Lookup(dumpMetricsManifestFileFlagName))
+	fs.AddFlag(pflag.Lookup(previousMetricsManifestFileFlagName))
+}
+
+// MetricManifestEntry describes a single registered metric family for the purposes of a metrics
+// manifest, in the form release tooling needs to render release notes and to compute
+// added/removed/deprecated metric lists across versions.
+type MetricManifestEntry struct {
+	Name   string   `json:"name"`
+	Help   string   `json:"help"`
+	Type   string   `json:"type"`
+	Labels []string `json:"labels,omitempty"`
+}
+
+// MetricsManifest is the full, sorted inventory of a binary's currently registered metrics, as
+// produced by GenerateMetricsManifest.
+type MetricsManifest struct {
+	Metrics []MetricManifestEntry `json:"metrics"`
+}
+
+// MetricsManifestDiff is the machine-readable difference between two metrics manifests, as
+// produced by DiffMetricsManifests. Renamed metrics are not detected as such; they appear as one
+// entry in Removed and one in Added.
+type MetricsManifestDiff struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	Changed []string `json:"changed,omitempty"`
+}
+
+// GenerateMetricsManifest gathers registry's currently registered metrics and returns them as a
+// MetricsManifest, sorted by name so that the result is stable across runs and diffs cleanly
+// under source control.
+func GenerateMetricsManifest(registry KubeRegistry) (*MetricsManifest, error) {
+	families, err := registry.Gather()
+	if err != nil {
+		return nil, fmt.Errorf("failed to gather metrics for manifest generation: %v", err)
+	}
+
+	manifest := &MetricsManifest{Metrics: make([]MetricManifestEntry, 0, len(families))}
+	for _, family := range families {
+		manifest.Metrics = append(manifest.Metrics, MetricManifestEntry{
+			Name:   family.GetName(),
+			Help:   family.GetHelp(),
+			Type:   family.GetType().String(),
+			Labels: labelNamesOf(family),
+		})
+	}
+	sort.Slice(manifest.Metrics, func(i, j int) bool {
+		return manifest.Metrics[i].Name < manifest.Metrics[j].Name
+	})
+	return manifest, nil
+}
+
+// labelNamesOf returns the sorted, deduplicated set of label names used across every metric in
+// family, since a MetricFamily does not otherwise carry its label schema separately from its
+// individual metrics.
+func labelNamesOf(family *dto.MetricFamily) []string {
+	seen := map[string]bool{}
+	for _, metric := range family.GetMetric() {
+		for _, label := range metric.GetLabel() {
+			seen[label.GetName()] = true
+		}
+	}
+	if len(seen) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DiffMetricsManifests compares previous against current and returns which metric names were
+// added, removed, or changed (same name, different help text or type). This is what release
+// tooling consumes to render an added/removed/deprecated metrics section without hand-diffing
+// two JSON files.
+func DiffMetricsManifests(previous, current *MetricsManifest) *MetricsManifestDiff {
+	previousByName := make(map[string]MetricManifestEntry, len(previous.Metrics))
+	for _, entry := range previous.Metrics {
+		previousByName[entry.Name] = entry
+	}
+	currentByName := make(map[string]MetricManifestEntry, len(current.Metrics))
+	for _, entry := range current.Metrics {
+		currentByName[entry.Name] = entry
+	}
+
+	diff := &MetricsManifestDiff{}
+	for name, entry := range currentByName {
+		old, ok := previousByName[name]
+		if !ok {
+			diff.Added = append(diff.Added, name)
+			continue
+		}
+		if old.Help != entry.Help || old.Type != entry.Type {
+			diff.Changed = append(diff.Changed, name)
+		}
+	}
+	for name := range previousByName {
+		if _, ok := currentByName[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+	return diff
+}
+
+// MaybeDumpMetricsManifest writes registry's currently registered metrics to the file named by
+// the dump-metrics-manifest-file flag, if it was set, and reports whether it did so. If
+// previous-metrics-manifest-file is also set, it additionally writes a ".diff.json" file
+// alongside the manifest containing the MetricsManifestDiff between the two. Binaries that
+// support the flag should call this after all of their metrics are registered but before serving
+// traffic.
+func MaybeDumpMetricsManifest(registry KubeRegistry) (bool, error) {
+	if *dumpMetricsManifestFile == "" {
+		return false, nil
+	}
+	manifest, err := GenerateMetricsManifest(registry)
+	if err != nil {
+		return false, err
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal metrics manifest: %v", err)
+	}
+	if err := ioutil.WriteFile(*dumpMetricsManifestFile, data, 0644); err != nil {
+		return false, fmt.Errorf("failed to write metrics manifest %s: %v", *dumpMetricsManifestFile, err)
+	}
+
+	if *previousMetricsManifestFile == "" {
+		return true, nil
+	}
+	previousData, err := ioutil.ReadFile(*previousMetricsManifestFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to read previous metrics manifest %s: %v", *previousMetricsManifestFile, err)
+	}
+	var previous MetricsManifest
+	if err := json.Unmarshal(previousData, &previous); err != nil {
+		return false, fmt.Errorf("failed to parse previous metrics manifest %s: %v", *previousMetricsManifestFile, err)
+	}
+	diffData, err := json.MarshalIndent(DiffMetricsManifests(&previous, manifest), "", "  ")
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal metrics manifest diff: %v", err)
+	}
+	if err := ioutil.WriteFile(*dumpMetricsManifestFile+".diff.json", diffData, 0644); err != nil {
+		return false, fmt.Errorf("failed to write metrics manifest diff %s.diff.json: %v", *dumpMetricsManifestFile, err)
+	}
+	return true, nil
+}
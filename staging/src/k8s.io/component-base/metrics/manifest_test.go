@@ -0,0 +1,79 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+func TestGenerateMetricsManifest(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	requests := NewCounterVec(&CounterOpts{
+		Name:           "manifest_test_requests_total",
+		Help:           "total requests handled",
+		StabilityLevel: ALPHA,
+	}, []string{"code"})
+	registry.MustRegister(requests)
+	requests.WithLabelValues("200").Inc()
+
+	manifest, err := GenerateMetricsManifest(registry)
+	if err != nil {
+		t.Fatalf("GenerateMetricsManifest failed: %v", err)
+	}
+
+	var got *MetricManifestEntry
+	for i := range manifest.Metrics {
+		if manifest.Metrics[i].Name == "manifest_test_requests_total" {
+			got = &manifest.Metrics[i]
+		}
+	}
+	if got == nil {
+		t.Fatalf("expected manifest_test_requests_total in manifest, got %+v", manifest.Metrics)
+	}
+	if got.Help != "total requests handled" {
+		t.Errorf("unexpected help text: %q", got.Help)
+	}
+	if len(got.Labels) != 1 || got.Labels[0] != "code" {
+		t.Errorf("expected labels [code], got %v", got.Labels)
+	}
+}
+
+func TestDiffMetricsManifests(t *testing.T) {
+	previous := &MetricsManifest{Metrics: []MetricManifestEntry{
+		{Name: "removed_metric", Help: "gone", Type: "COUNTER"},
+		{Name: "unchanged_metric", Help: "same", Type: "COUNTER"},
+		{Name: "changed_metric", Help: "old help", Type: "COUNTER"},
+	}}
+	current := &MetricsManifest{Metrics: []MetricManifestEntry{
+		{Name: "unchanged_metric", Help: "same", Type: "COUNTER"},
+		{Name: "changed_metric", Help: "new help", Type: "COUNTER"},
+		{Name: "added_metric", Help: "new", Type: "GAUGE"},
+	}}
+
+	diff := DiffMetricsManifests(previous, current)
+	if len(diff.Added) != 1 || diff.Added[0] != "added_metric" {
+		t.Errorf("expected added=[added_metric], got %v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0] != "removed_metric" {
+		t.Errorf("expected removed=[removed_metric], got %v", diff.Removed)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0] != "changed_metric" {
+		t.Errorf("expected changed=[changed_metric], got %v", diff.Changed)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/klog"
+)
+
+// perSeriesOverheadBytes approximates the fixed bookkeeping cost (sample value, timestamp,
+// pointers) the Prometheus client and text exposition format carry for every time series,
+// independent of its labels. It is a rough constant, not a measured one; this estimate is meant
+// to help operators find the metric family responsible for runaway cardinality, not to account
+// for a process's memory to the byte.
+const perSeriesOverheadBytes = 32
+
+// perBucketOrQuantileOverheadBytes approximates the additional cost of each bucket line in a
+// Histogram or quantile line in a Summary, which a plain Counter or Gauge series does not pay.
+const perBucketOrQuantileOverheadBytes = 16
+
+// FamilyMemoryEstimate is the estimated in-memory footprint of a single gathered metric family.
+type FamilyMemoryEstimate struct {
+	// Name is the metric family's name, e.g. "apiserver_request_duration_seconds".
+	Name string `json:"name"`
+	// SeriesCount is the number of distinct label combinations gathered for this family.
+	SeriesCount int `json:"seriesCount"`
+	// EstimatedBytes is this family's share of estimateMemoryUsage's total, computed from
+	// SeriesCount, the byte length of every label name and value across its series, and (for
+	// Histograms and Summaries) the number of bucket or quantile lines each series carries.
+	EstimatedBytes int64 `json:"estimatedBytes"`
+}
+
+// estimateFamilyBytes estimates mf's in-memory footprint as its series count times the average
+// per-series cost: label name and value bytes, a fixed per-series overhead, and, for Histograms
+// and Summaries, a fixed cost per bucket or quantile line, since those carry more than one value
+// per series.
+func estimateFamilyBytes(mf *dto.MetricFamily) int64 {
+	var total int64
+	for _, metric := range mf.GetMetric() {
+		total += perSeriesOverheadBytes
+		for _, label := range metric.GetLabel() {
+			total += int64(len(label.GetName()) + len(label.GetValue()))
+		}
+		if h := metric.GetHistogram(); h != nil {
+			total += int64(len(h.GetBucket())) * perBucketOrQuantileOverheadBytes
+		}
+		if s := metric.GetSummary(); s != nil {
+			total += int64(len(s.GetQuantile())) * perBucketOrQuantileOverheadBytes
+		}
+	}
+	return total
+}
+
+// estimateMemoryUsage estimates the in-memory footprint of every family in mfs, sorted with the
+// largest estimate first so the families most responsible for a registry's memory use sort to
+// the top.
+func estimateMemoryUsage(mfs []*dto.MetricFamily) []FamilyMemoryEstimate {
+	estimates := make([]FamilyMemoryEstimate, 0, len(mfs))
+	for _, mf := range mfs {
+		estimates = append(estimates, FamilyMemoryEstimate{
+			Name:           mf.GetName(),
+			SeriesCount:    len(mf.GetMetric()),
+			EstimatedBytes: estimateFamilyBytes(mf),
+		})
+	}
+	sort.Slice(estimates, func(i, j int) bool {
+		return estimates[i].EstimatedBytes > estimates[j].EstimatedBytes
+	})
+	return estimates
+}
+
+// totalEstimatedBytes sums the EstimatedBytes of every entry in estimates.
+func totalEstimatedBytes(estimates []FamilyMemoryEstimate) int64 {
+	var total int64
+	for _, e := range estimates {
+		total += e.EstimatedBytes
+	}
+	return total
+}
+
+// estimatedBytesMetricFamily builds the metric_registry_estimated_bytes marker metric family,
+// following the same pattern incompleteScrapeMetricFamily uses to inject a derived value into a
+// gathered result without registering a self-referential collector.
+func estimatedBytesMetricFamily(value float64) *dto.MetricFamily {
+	name := "metric_registry_estimated_bytes"
+	help := "Estimated total in-memory footprint, in bytes, of every metric family gathered from this registry. A rough approximation from series count and label sizes, meant to locate runaway cardinality rather than account for memory precisely."
+	metricType := dto.MetricType_GAUGE
+	return &dto.MetricFamily{
+		Name: &name,
+		Help: &help,
+		Type: &metricType,
+		Metric: []*dto.Metric{
+			{
+				Gauge: &dto.Gauge{Value: &value},
+			},
+		},
+	}
+}
+
+// MemoryDebugHandler returns an http.Handler which gathers metrics from gatherer and serves a
+// JSON breakdown of the estimated in-memory footprint per metric family, sorted largest first, so
+// an operator can identify which metric is responsible for a component's memory growth without
+// taking a heap profile.
+func MemoryDebugHandler(gatherer KubeRegistry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mfs, err := gatherer.Gather()
+		if err != nil {
+			klog.Errorf("error gathering metrics: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(estimateMemoryUsage(mfs)); err != nil {
+			klog.Errorf("error encoding metric memory estimate: %v", err)
+		}
+	})
+}
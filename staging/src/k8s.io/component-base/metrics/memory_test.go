@@ -0,0 +1,132 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+func labelPair(name, value string) *dto.LabelPair {
+	return &dto.LabelPair{Name: &name, Value: &value}
+}
+
+func TestEstimateFamilyBytes(t *testing.T) {
+	name := "test_family"
+	metricType := dto.MetricType_COUNTER
+	value := 1.0
+	mf := &dto.MetricFamily{
+		Name: &name,
+		Type: &metricType,
+		Metric: []*dto.Metric{
+			{
+				Label:   []*dto.LabelPair{labelPair("verb", "GET")},
+				Counter: &dto.Counter{Value: &value},
+			},
+			{
+				Label:   []*dto.LabelPair{labelPair("verb", "POST")},
+				Counter: &dto.Counter{Value: &value},
+			},
+		},
+	}
+
+	got := estimateFamilyBytes(mf)
+	want := int64(2) * (perSeriesOverheadBytes + int64(len("verb")+len("GET")))
+	if got != want {
+		t.Errorf("estimateFamilyBytes() = %d, want %d", got, want)
+	}
+}
+
+func TestEstimateFamilyBytesHistogramIncludesBuckets(t *testing.T) {
+	name := "test_histogram"
+	metricType := dto.MetricType_HISTOGRAM
+	count := uint64(1)
+	bound := 1.0
+	mf := &dto.MetricFamily{
+		Name: &name,
+		Type: &metricType,
+		Metric: []*dto.Metric{
+			{
+				Histogram: &dto.Histogram{
+					SampleCount: &count,
+					Bucket: []*dto.Bucket{
+						{CumulativeCount: &count, UpperBound: &bound},
+						{CumulativeCount: &count, UpperBound: &bound},
+					},
+				},
+			},
+		},
+	}
+
+	got := estimateFamilyBytes(mf)
+	want := int64(perSeriesOverheadBytes + 2*perBucketOrQuantileOverheadBytes)
+	if got != want {
+		t.Errorf("estimateFamilyBytes() = %d, want %d", got, want)
+	}
+}
+
+func TestEstimateMemoryUsageSortsLargestFirst(t *testing.T) {
+	small := "small_family"
+	big := "big_family"
+	metricType := dto.MetricType_COUNTER
+	value := 1.0
+	mfs := []*dto.MetricFamily{
+		{
+			Name: &small,
+			Type: &metricType,
+			Metric: []*dto.Metric{
+				{Counter: &dto.Counter{Value: &value}},
+			},
+		},
+		{
+			Name: &big,
+			Type: &metricType,
+			Metric: []*dto.Metric{
+				{Label: []*dto.LabelPair{labelPair("a", "1")}, Counter: &dto.Counter{Value: &value}},
+				{Label: []*dto.LabelPair{labelPair("a", "2")}, Counter: &dto.Counter{Value: &value}},
+			},
+		},
+	}
+
+	estimates := estimateMemoryUsage(mfs)
+	if len(estimates) != 2 || estimates[0].Name != big || estimates[1].Name != small {
+		t.Fatalf("expected %q before %q, got %v", big, small, estimates)
+	}
+	if got, want := totalEstimatedBytes(estimates), estimates[0].EstimatedBytes+estimates[1].EstimatedBytes; got != want {
+		t.Errorf("totalEstimatedBytes() = %d, want %d", got, want)
+	}
+}
+
+func TestMemoryDebugHandler(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{Major: "1", Minor: "15"})
+
+	req := httptest.NewRequest("GET", "/debug/metrics/memory", nil)
+	w := httptest.NewRecorder()
+	MemoryDebugHandler(registry).ServeHTTP(w, req)
+
+	if got, want := w.Header().Get("Content-Type"), "application/json"; got != want {
+		t.Errorf("Content-Type = %q, want %q", got, want)
+	}
+	var estimates []FamilyMemoryEstimate
+	if err := json.Unmarshal(w.Body.Bytes(), &estimates); err != nil {
+		t.Fatalf("failed to decode response as JSON: %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+
+	"k8s.io/klog"
+)
+
+// degradationsTotal counts every time a MemoryWatchdog has degraded or recovered a collector,
+// labeled by the outcome. Like LabelAllowListErrorCounter, it is not registered by default: a
+// binary that wants to observe it must register it with its registry itself.
+var degradationsTotal = NewCounterVec(
+	&CounterOpts{
+		Name: "metrics_memory_watchdog_degradations_total",
+		Help: "Number of times a MemoryWatchdog has toggled a collector's degraded state, by action taken.",
+	},
+	[]string{"action"},
+)
+
+// MemoryWatchdog periodically compares the calling process's resident set size against a
+// configured threshold, and pushes any Degradable collectors it owns into (or back out of)
+// aggregated mode accordingly. It has no opinion on how RSS is measured: readRSS is supplied by
+// the caller, so this package stays free of the platform-specific syscall or /proc parsing that
+// obtaining it would otherwise require, and so it can be exercised in tests with a fake reading.
+type MemoryWatchdog struct {
+	// readRSS returns the process's current resident set size in bytes.
+	readRSS func() (uint64, error)
+	// thresholdBytes is the RSS above which collectors are degraded, and at or below which they
+	// are recovered.
+	thresholdBytes uint64
+	// interval is how often readRSS is polled.
+	interval time.Duration
+	// collectors are put into aggregated mode together, as a single memory budget's worth of
+	// high-cardinality label space.
+	collectors []Degradable
+}
+
+// NewMemoryWatchdog returns a MemoryWatchdog that polls readRSS every interval, degrading
+// collectors once the reading exceeds thresholdBytes and recovering them once it falls back to
+// or below thresholdBytes.
+func NewMemoryWatchdog(readRSS func() (uint64, error), thresholdBytes uint64, interval time.Duration, collectors ...Degradable) *MemoryWatchdog {
+	return &MemoryWatchdog{
+		readRSS:        readRSS,
+		thresholdBytes: thresholdBytes,
+		interval:       interval,
+		collectors:     collectors,
+	}
+}
+
+// Start polls readRSS every interval, degrading or recovering the watchdog's collectors as
+// needed, until stopCh is closed. It is meant to be run in its own goroutine.
+func (w *MemoryWatchdog) Start(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			w.check()
+		}
+	}
+}
+
+// check reads the current RSS once and degrades or recovers the watchdog's collectors to match.
+func (w *MemoryWatchdog) check() {
+	rss, err := w.readRSS()
+	if err != nil {
+		klog.Errorf("memory watchdog: error reading RSS: %v", err)
+		return
+	}
+	if rss > w.thresholdBytes {
+		for _, c := range w.collectors {
+			if !c.IsDegraded() {
+				c.Degrade()
+				degradationsTotal.WithLabelValues("degrade").Inc()
+			}
+		}
+		return
+	}
+	for _, c := range w.collectors {
+		if c.IsDegraded() {
+			c.Recover()
+			degradationsTotal.WithLabelValues("recover").Inc()
+		}
+	}
+}
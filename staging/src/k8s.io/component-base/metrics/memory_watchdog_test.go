@@ -0,0 +1,66 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryWatchdogDegradesAndRecovers(t *testing.T) {
+	cv := NewCounterVec(&CounterOpts{
+		Name:         "test_watchdog_counter",
+		Help:         "help",
+		DegradeLabel: "pod",
+	}, []string{"pod"})
+
+	var rss uint64
+	w := NewMemoryWatchdog(func() (uint64, error) { return rss, nil }, 100, time.Hour, cv)
+
+	rss = 50
+	w.check()
+	if cv.IsDegraded() {
+		t.Fatalf("did not expect degradation below threshold")
+	}
+
+	rss = 150
+	w.check()
+	if !cv.IsDegraded() {
+		t.Fatalf("expected degradation above threshold")
+	}
+
+	rss = 50
+	w.check()
+	if cv.IsDegraded() {
+		t.Fatalf("expected recovery once back below threshold")
+	}
+}
+
+func TestMemoryWatchdogSurvivesReadError(t *testing.T) {
+	cv := NewCounterVec(&CounterOpts{
+		Name:         "test_watchdog_error_counter",
+		Help:         "help",
+		DegradeLabel: "pod",
+	}, []string{"pod"})
+
+	w := NewMemoryWatchdog(func() (uint64, error) { return 0, errors.New("read error") }, 100, time.Hour, cv)
+	w.check()
+	if cv.IsDegraded() {
+		t.Errorf("did not expect a failed RSS read to trigger degradation")
+	}
+}
@@ -34,6 +34,11 @@ type KubeCollector interface {
 	Collector
 	lazyKubeMetric
 	DeprecatedVersion() *semver.Version
+	// StabilityLevel returns the metric's declared StabilityLevel, which
+	// determineDeprecationStatus consults to decide how many minor versions past
+	// DeprecatedVersion the metric stays visible-but-deprecated before being hidden. See
+	// stabilityLevelDeprecationGracePeriod.
+	StabilityLevel() StabilityLevel
 	// Each collector metric should provide an initialization function
 	// for both deprecated and non-deprecated variants of a metric. This
 	// is necessary since metric instantiation will be deferred
@@ -92,13 +97,45 @@ func (r *lazyMetric) determineDeprecationStatus(version semver.Version) {
 		if selfVersion.LTE(version) {
 			r.isDeprecated = true
 		}
-		if selfVersion.LT(version) {
-			klog.Warningf("This metric has been deprecated for more than one release, hiding.")
+		if ShouldShowHidden() {
+			return
+		}
+		gracePeriod := stabilityLevelDeprecationGracePeriod(r.self.StabilityLevel())
+		if minorVersionsSince(*selfVersion, version) > gracePeriod {
+			klog.Warningf("This metric has been deprecated for more than %d minor release(s), hiding.", gracePeriod)
 			r.isHidden = true
 		}
 	})
 }
 
+// minorVersionsSince returns how many minor versions cur is ahead of dep, assuming the same
+// major version. A cur with a different major version is treated as arbitrarily far ahead, since
+// there is no meaningful minor-version distance to compute across a major bump.
+func minorVersionsSince(dep, cur semver.Version) uint64 {
+	switch {
+	case cur.Major > dep.Major:
+		return ^uint64(0)
+	case cur.Major < dep.Major || cur.Minor <= dep.Minor:
+		return 0
+	default:
+		return cur.Minor - dep.Minor
+	}
+}
+
+// stabilityLevelDeprecationGracePeriod returns how many minor versions past DeprecatedVersion a
+// metric at the given StabilityLevel stays visible-but-deprecated before determineDeprecationStatus
+// hides it. ALPHA (and STABLE) metrics carry no guarantee beyond the release in which they are
+// marked deprecated. BETA metrics get a longer, enforced grace period so component owners aren't
+// left to hand-roll the policy themselves.
+func stabilityLevelDeprecationGracePeriod(level StabilityLevel) uint64 {
+	switch level {
+	case BETA:
+		return 3
+	default:
+		return 0
+	}
+}
+
 func (r *lazyMetric) IsHidden() bool {
 	return r.isHidden
 }
@@ -0,0 +1,64 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"github.com/blang/semver"
+	"github.com/spf13/pflag"
+
+	"k8s.io/component-base/metrics"
+)
+
+// MetricsOptions holds the command line flags for the process metrics endpoint that are common
+// across components. It has no fields wired to the metrics endpoint itself (each component sets
+// that up on its own, as it already did before this type existed); it only carries the flags that
+// influence what the metrics endpoint reports.
+type MetricsOptions struct {
+	// ShowHiddenMetricsForVersion is the value of --show-hidden-metrics-for-version. It must be
+	// the empty string or the minor version immediately preceding the binary's own version; see
+	// metrics.ValidateShowHiddenMetricsVersion.
+	ShowHiddenMetricsForVersion string
+}
+
+// NewOptions returns a zero-valued MetricsOptions, i.e. with the escape hatch disabled.
+func NewOptions() *MetricsOptions {
+	return &MetricsOptions{}
+}
+
+// AddFlags registers this options' flags on fs.
+func (o *MetricsOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.ShowHiddenMetricsForVersion, "show-hidden-metrics-for-version", o.ShowHiddenMetricsForVersion,
+		"The previous version for which you want to show hidden metrics. "+
+			"Only the previous minor version is meaningful, other values will not be allowed. "+
+			"The format is <major>.<minor>, e.g.: '1.16'. "+
+			"The purpose of this format is make sure you have the opportunity to notice if the mentioned metrics are deprecated. "+
+			"Do not need to explicitly set this flag be false, unless you want to see the deprecated metrics.")
+}
+
+// Validate checks that ShowHiddenMetricsForVersion, if set, is exactly the minor version
+// immediately preceding currentVersion.
+func (o *MetricsOptions) Validate(currentVersion semver.Version) []error {
+	return metrics.ValidateShowHiddenMetricsVersion(currentVersion, o.ShowHiddenMetricsForVersion)
+}
+
+// Apply enables the process-wide hidden-metrics escape hatch if ShowHiddenMetricsForVersion was
+// set. It should be called only after Validate has returned no errors.
+func (o *MetricsOptions) Apply() {
+	if o.ShowHiddenMetricsForVersion != "" {
+		metrics.SetShowHidden()
+	}
+}
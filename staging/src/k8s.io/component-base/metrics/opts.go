@@ -21,6 +21,7 @@ import (
 	"github.com/blang/semver"
 	"github.com/prometheus/client_golang/prometheus"
 	"sync"
+	"time"
 )
 
 // KubeOpts is superset struct for prometheus.Opts. The prometheus Opts structure
@@ -39,8 +40,41 @@ type KubeOpts struct {
 	deprecateOnce     sync.Once
 	annotateOnce      sync.Once
 	StabilityLevel    StabilityLevel
+	// Unit declares the unit of measurement values recorded for this metric are expected to be
+	// in. It is optional; leave it unset for metrics (e.g. a plain count) with no unit. When set,
+	// debug builds (built with the "debug" build tag) assert at observe time that recorded values
+	// fall within the unit's plausible range, to catch the recurring class of bugs where a value
+	// scaled for the wrong unit (e.g. milliseconds recorded into a seconds metric) is observed.
+	Unit Unit
+	// LabelValueAllowLists bounds, per label name, the set of values the corresponding Vec metric
+	// exposes to prometheus. It is optional; a label with no entry here is left unconstrained.
+	// Any value passed to WithLabelValues/With that isn't in the named label's allow list is
+	// recorded as "unexpected" instead, so a caller that echoes unbounded input (a user agent, a
+	// pod name) straight into a label value cannot blow up the metric's cardinality. See
+	// LabelValueAllowList.
+	LabelValueAllowLists map[string]*LabelValueAllowList
+	// DegradeLabel names the one label, if any, a MemoryWatchdog may collapse to
+	// aggregatedLabelValue on this Vec metric once the process nears its configured RSS
+	// threshold. It is optional; leave it empty for a Vec that should never be degraded, or that
+	// has no single label whose values dominate its cardinality. See Degradable.
+	DegradeLabel string
 }
 
+// Unit is the unit of measurement for the values recorded by a metric, used to sanity-check
+// those values in debug builds. See KubeOpts.Unit.
+type Unit string
+
+const (
+	// Seconds indicates that recorded values are a duration expressed in floating-point seconds,
+	// following Prometheus convention (https://prometheus.io/docs/practices/naming/#base-units).
+	Seconds Unit = "seconds"
+	// Bytes indicates that recorded values are a size expressed in bytes.
+	Bytes Unit = "bytes"
+	// Ratio indicates that recorded values are a dimensionless fraction expected to fall within
+	// [0, 1], as opposed to a percentage expressed on a 0-100 scale.
+	Ratio Unit = "ratio"
+)
+
 // StabilityLevel represents the API guarantees for a given defined metric.
 type StabilityLevel string
 
@@ -48,11 +82,31 @@ const (
 	// ALPHA metrics have no stability guarantees, as such, labels may
 	// be arbitrarily added/removed and the metric may be deleted at any time.
 	ALPHA StabilityLevel = "ALPHA"
+	// BETA metrics have a settled schema, but may still be deleted. Unlike ALPHA, once a BETA
+	// metric is marked deprecated it remains visible for stabilityLevelDeprecationGracePeriod
+	// minor versions before being hidden, giving component owners a fixed, enforced window to
+	// react instead of hand-rolling their own deprecation policy.
+	BETA StabilityLevel = "BETA"
 	// STABLE metrics are guaranteed not be mutated and removal is governed by
 	// the deprecation policy outlined in by the control plane metrics stability KEP.
 	STABLE StabilityLevel = "STABLE"
 )
 
+// Modify help description on the metric description.
+func (o *KubeOpts) markDeprecated() {
+	o.deprecateOnce.Do(func() {
+		o.Help = fmt.Sprintf("(Deprecated since %v) %v", o.DeprecatedVersion, o.Help)
+	})
+}
+
+// annotateStabilityLevel annotates help description on the metric description with the stability level
+// of the metric
+func (o *KubeOpts) annotateStabilityLevel() {
+	o.annotateOnce.Do(func() {
+		o.Help = fmt.Sprintf("[%v] %v", o.StabilityLevel, o.Help)
+	})
+}
+
 // CounterOpts is an alias for Opts. See there for doc comments.
 type CounterOpts KubeOpts
 
@@ -82,3 +136,149 @@ func (o *CounterOpts) toPromCounterOpts() prometheus.CounterOpts {
 		ConstLabels: o.ConstLabels,
 	}
 }
+
+// GaugeOpts is an alias for KubeOpts. See there for doc comments.
+type GaugeOpts KubeOpts
+
+// Modify help description on the metric description.
+func (o *GaugeOpts) markDeprecated() {
+	o.deprecateOnce.Do(func() {
+		o.Help = fmt.Sprintf("(Deprecated since %v) %v", o.DeprecatedVersion, o.Help)
+	})
+}
+
+// annotateStabilityLevel annotates help description on the metric description with the stability level
+// of the metric
+func (o *GaugeOpts) annotateStabilityLevel() {
+	o.annotateOnce.Do(func() {
+		o.Help = fmt.Sprintf("[%v] %v", o.StabilityLevel, o.Help)
+	})
+}
+
+// convenience function to allow easy transformation to the prometheus
+// counterpart. This will do more once we have a proper label abstraction
+func (o *GaugeOpts) toPromGaugeOpts() prometheus.GaugeOpts {
+	return prometheus.GaugeOpts{
+		Namespace:   o.Namespace,
+		Subsystem:   o.Subsystem,
+		Name:        o.Name,
+		Help:        o.Help,
+		ConstLabels: o.ConstLabels,
+	}
+}
+
+// HistogramOpts extends KubeOpts with Buckets, the set of upper bounds observations are
+// counted into. See there for doc comments on the embedded fields.
+type HistogramOpts struct {
+	Namespace         string
+	Subsystem         string
+	Name              string
+	Help              string
+	ConstLabels       prometheus.Labels
+	DeprecatedVersion *semver.Version
+	deprecateOnce     sync.Once
+	annotateOnce      sync.Once
+	StabilityLevel    StabilityLevel
+	Unit              Unit
+	// LabelValueAllowLists bounds, per label name, the set of values HistogramVec exposes to
+	// prometheus. See KubeOpts.LabelValueAllowLists.
+	LabelValueAllowLists map[string]*LabelValueAllowList
+	// DegradeLabel names the one label, if any, a MemoryWatchdog may collapse to
+	// aggregatedLabelValue. See KubeOpts.DegradeLabel.
+	DegradeLabel string
+	// Buckets defines the buckets into which observations are counted. Each element is the
+	// upper inclusive bound of a bucket; there is no need to add a highest +Inf bucket, it is
+	// added implicitly. STABLE histograms must use one of the shared catalogs in buckets.go; see
+	// ValidateHistogramBuckets, which NewHistogram and NewHistogramVec enforce.
+	Buckets []float64
+}
+
+// Modify help description on the metric description.
+func (o *HistogramOpts) markDeprecated() {
+	o.deprecateOnce.Do(func() {
+		o.Help = fmt.Sprintf("(Deprecated since %v) %v", o.DeprecatedVersion, o.Help)
+	})
+}
+
+// annotateStabilityLevel annotates help description on the metric description with the stability level
+// of the metric
+func (o *HistogramOpts) annotateStabilityLevel() {
+	o.annotateOnce.Do(func() {
+		o.Help = fmt.Sprintf("[%v] %v", o.StabilityLevel, o.Help)
+	})
+}
+
+// convenience function to allow easy transformation to the prometheus
+// counterpart. This will do more once we have a proper label abstraction
+func (o *HistogramOpts) toPromHistogramOpts() prometheus.HistogramOpts {
+	return prometheus.HistogramOpts{
+		Namespace:   o.Namespace,
+		Subsystem:   o.Subsystem,
+		Name:        o.Name,
+		Help:        o.Help,
+		ConstLabels: o.ConstLabels,
+		Buckets:     o.Buckets,
+	}
+}
+
+// SummaryOpts extends KubeOpts with the quantile-estimation knobs prometheus.Summary exposes.
+// See there for doc comments on the embedded fields.
+type SummaryOpts struct {
+	Namespace         string
+	Subsystem         string
+	Name              string
+	Help              string
+	ConstLabels       prometheus.Labels
+	DeprecatedVersion *semver.Version
+	deprecateOnce     sync.Once
+	annotateOnce      sync.Once
+	StabilityLevel    StabilityLevel
+	Unit              Unit
+	// LabelValueAllowLists bounds, per label name, the set of values SummaryVec exposes to
+	// prometheus. See KubeOpts.LabelValueAllowLists.
+	LabelValueAllowLists map[string]*LabelValueAllowList
+	// DegradeLabel names the one label, if any, a MemoryWatchdog may collapse to
+	// aggregatedLabelValue. See KubeOpts.DegradeLabel.
+	DegradeLabel string
+	// Objectives defines the quantile rank estimates with their respective absolute error, as
+	// in prometheus.SummaryOpts.Objectives. Left nil, prometheus.DefObjectives applies; pass an
+	// empty map to disable quantile estimation entirely.
+	Objectives map[float64]float64
+	// MaxAge defines the duration for which an observation stays relevant for the summary.
+	MaxAge time.Duration
+	// AgeBuckets is the number of buckets used to exclude observations older than MaxAge.
+	AgeBuckets uint32
+	// BufCap defines the default sample stream buffer size.
+	BufCap uint32
+}
+
+// Modify help description on the metric description.
+func (o *SummaryOpts) markDeprecated() {
+	o.deprecateOnce.Do(func() {
+		o.Help = fmt.Sprintf("(Deprecated since %v) %v", o.DeprecatedVersion, o.Help)
+	})
+}
+
+// annotateStabilityLevel annotates help description on the metric description with the stability level
+// of the metric
+func (o *SummaryOpts) annotateStabilityLevel() {
+	o.annotateOnce.Do(func() {
+		o.Help = fmt.Sprintf("[%v] %v", o.StabilityLevel, o.Help)
+	})
+}
+
+// convenience function to allow easy transformation to the prometheus
+// counterpart. This will do more once we have a proper label abstraction
+func (o *SummaryOpts) toPromSummaryOpts() prometheus.SummaryOpts {
+	return prometheus.SummaryOpts{
+		Namespace:   o.Namespace,
+		Subsystem:   o.Subsystem,
+		Name:        o.Name,
+		Help:        o.Help,
+		ConstLabels: o.ConstLabels,
+		Objectives:  o.Objectives,
+		MaxAge:      o.MaxAge,
+		AgeBuckets:  o.AgeBuckets,
+		BufCap:      o.BufCap,
+	}
+}
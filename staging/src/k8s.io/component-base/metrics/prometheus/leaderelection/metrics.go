@@ -0,0 +1,126 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package leaderelection sets the leaderelection.MetricsProvider to produce metrics compatible
+// with the component-base stability conventions. It supersedes the ad-hoc, per-component
+// leaderelection metrics adapters that used to exist in individual components.
+package leaderelection
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/klog"
+)
+
+// Metrics subsystem and keys used by leader election.
+const (
+	LeaderElectionSubsystem = "leader_election"
+	IsLeaderKey             = "master_status"
+	TransitionsKey          = "leader_transitions_total"
+	RenewLatencyKey         = "renew_latency_seconds"
+
+	// stabilityLevel is prefixed onto the help text of every metric this provider creates, in
+	// keeping with the component-base metrics stability conventions.
+	stabilityLevel = "[ALPHA]"
+)
+
+func init() {
+	leaderelection.SetProvider(prometheusMetricsProvider{})
+}
+
+type noopMetric struct{}
+
+func (noopMetric) On(name string)                     {}
+func (noopMetric) Off(name string)                    {}
+func (noopMetric) Inc(name string)                    {}
+func (noopMetric) Observe(name string, value float64) {}
+
+type prometheusMetricsProvider struct{}
+
+func (prometheusMetricsProvider) NewLeaderMetric() leaderelection.SwitchMetric {
+	leaderGauge := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: LeaderElectionSubsystem,
+			Name:      IsLeaderKey,
+			Help: stabilityLevel + " Gauge of if the reporting system is master of the relevant lease, 0 " +
+				"indicates backup, 1 indicates master. 'name' is the string used to identify the lease. " +
+				"Please make sure to group by name.",
+		},
+		[]string{"name"},
+	)
+	if err := prometheus.Register(leaderGauge); err != nil {
+		klog.Errorf("failed to register leader gauge: %v", err)
+	}
+	return &switchAdapter{gauge: leaderGauge}
+}
+
+func (prometheusMetricsProvider) NewTransitionsMetric() leaderelection.CounterMetric {
+	transitions := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: LeaderElectionSubsystem,
+			Name:      TransitionsKey,
+			Help:      stabilityLevel + " Total number of leader transitions observed for a lease. 'name' is the string used to identify the lease.",
+		},
+		[]string{"name"},
+	)
+	if err := prometheus.Register(transitions); err != nil {
+		klog.Errorf("failed to register leader transitions counter: %v", err)
+	}
+	return &counterAdapter{counter: transitions}
+}
+
+func (prometheusMetricsProvider) NewRenewLatencyMetric() leaderelection.SummaryMetric {
+	renewLatency := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: LeaderElectionSubsystem,
+			Name:      RenewLatencyKey,
+			Help:      stabilityLevel + " How long in seconds it takes to acquire or renew a lease. 'name' is the string used to identify the lease.",
+		},
+		[]string{"name"},
+	)
+	if err := prometheus.Register(renewLatency); err != nil {
+		klog.Errorf("failed to register leader renew latency histogram: %v", err)
+	}
+	return &histogramAdapter{histogram: renewLatency}
+}
+
+type switchAdapter struct {
+	gauge *prometheus.GaugeVec
+}
+
+func (s *switchAdapter) On(name string) {
+	s.gauge.WithLabelValues(name).Set(1.0)
+}
+
+func (s *switchAdapter) Off(name string) {
+	s.gauge.WithLabelValues(name).Set(0.0)
+}
+
+type counterAdapter struct {
+	counter *prometheus.CounterVec
+}
+
+func (c *counterAdapter) Inc(name string) {
+	c.counter.WithLabelValues(name).Inc()
+}
+
+type histogramAdapter struct {
+	histogram *prometheus.HistogramVec
+}
+
+func (h *histogramAdapter) Observe(name string, value float64) {
+	h.histogram.WithLabelValues(name).Observe(value)
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package workqueue sets the workqueue DefaultMetricsFactory to produce metrics compatible with
+// the component-base stability conventions, and additionally allows disabling metrics for
+// individual, by-name workqueues (e.g. because a given controller creates a very large number of
+// dynamically named queues and their metrics are not useful).
+package workqueue
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+)
+
+// Metrics subsystem and keys used by the workqueue.
+const (
+	WorkQueueSubsystem         = "workqueue"
+	DepthKey                   = "depth"
+	AddsKey                    = "adds_total"
+	QueueLatencyKey            = "queue_duration_seconds"
+	WorkDurationKey            = "work_duration_seconds"
+	UnfinishedWorkKey          = "unfinished_work_seconds"
+	LongestRunningProcessorKey = "longest_running_processor_seconds"
+	RetriesKey                 = "retries_total"
+
+	// stabilityLevel is prefixed onto the help text of every metric this provider creates, in
+	// keeping with the component-base metrics stability conventions.
+	stabilityLevel = "[ALPHA]"
+)
+
+func init() {
+	workqueue.SetProvider(prometheusMetricsProvider{})
+}
+
+var (
+	disabledMu       sync.RWMutex
+	disabledForNames = map[string]bool{}
+)
+
+// SetDisabledMetrics configures which by-name workqueues should not have metrics registered for
+// them. This is useful for queues whose names are dynamic and unbounded, where per-queue metrics
+// would otherwise cause unbounded cardinality.
+func SetDisabledMetrics(names ...string) {
+	disabledMu.Lock()
+	defer disabledMu.Unlock()
+	disabledForNames = make(map[string]bool, len(names))
+	for _, name := range names {
+		disabledForNames[name] = true
+	}
+}
+
+func isDisabled(name string) bool {
+	disabledMu.RLock()
+	defer disabledMu.RUnlock()
+	return disabledForNames[name]
+}
+
+type noopMetric struct{}
+
+func (noopMetric) Inc()            {}
+func (noopMetric) Dec()            {}
+func (noopMetric) Set(float64)     {}
+func (noopMetric) Observe(float64) {}
+
+type prometheusMetricsProvider struct{}
+
+func (prometheusMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
+	if isDisabled(name) {
+		return noopMetric{}
+	}
+	depth := prometheus.NewGauge(prometheus.GaugeOpts{
+		Subsystem:   WorkQueueSubsystem,
+		Name:        DepthKey,
+		Help:        stabilityLevel + " Current depth of workqueue",
+		ConstLabels: prometheus.Labels{"name": name},
+	})
+	if err := prometheus.Register(depth); err != nil {
+		klog.Errorf("failed to register depth metric %v: %v", name, err)
+	}
+	return depth
+}
+
+func (prometheusMetricsProvider) NewAddsMetric(name string) workqueue.CounterMetric {
+	if isDisabled(name) {
+		return noopMetric{}
+	}
+	adds := prometheus.NewCounter(prometheus.CounterOpts{
+		Subsystem:   WorkQueueSubsystem,
+		Name:        AddsKey,
+		Help:        stabilityLevel + " Total number of adds handled by workqueue",
+		ConstLabels: prometheus.Labels{"name": name},
+	})
+	if err := prometheus.Register(adds); err != nil {
+		klog.Errorf("failed to register adds metric %v: %v", name, err)
+	}
+	return adds
+}
+
+func (prometheusMetricsProvider) NewLatencyMetric(name string) workqueue.HistogramMetric {
+	if isDisabled(name) {
+		return noopMetric{}
+	}
+	latency := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Subsystem:   WorkQueueSubsystem,
+		Name:        QueueLatencyKey,
+		Help:        stabilityLevel + " How long in seconds an item stays in workqueue before being requested.",
+		ConstLabels: prometheus.Labels{"name": name},
+		Buckets:     prometheus.ExponentialBuckets(10e-9, 10, 10),
+	})
+	if err := prometheus.Register(latency); err != nil {
+		klog.Errorf("failed to register latency metric %v: %v", name, err)
+	}
+	return latency
+}
+
+func (prometheusMetricsProvider) NewWorkDurationMetric(name string) workqueue.HistogramMetric {
+	if isDisabled(name) {
+		return noopMetric{}
+	}
+	workDuration := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Subsystem:   WorkQueueSubsystem,
+		Name:        WorkDurationKey,
+		Help:        stabilityLevel + " How long in seconds processing an item from workqueue takes.",
+		ConstLabels: prometheus.Labels{"name": name},
+		Buckets:     prometheus.ExponentialBuckets(10e-9, 10, 10),
+	})
+	if err := prometheus.Register(workDuration); err != nil {
+		klog.Errorf("failed to register workDuration metric %v: %v", name, err)
+	}
+	return workDuration
+}
+
+func (prometheusMetricsProvider) NewUnfinishedWorkSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	if isDisabled(name) {
+		return noopMetric{}
+	}
+	unfinished := prometheus.NewGauge(prometheus.GaugeOpts{
+		Subsystem: WorkQueueSubsystem,
+		Name:      UnfinishedWorkKey,
+		Help: stabilityLevel + " How many seconds of work has done that " +
+			"is in progress and hasn't been observed by work_duration. Large " +
+			"values indicate stuck threads. One can deduce the number of stuck " +
+			"threads by observing the rate at which this increases.",
+		ConstLabels: prometheus.Labels{"name": name},
+	})
+	if err := prometheus.Register(unfinished); err != nil {
+		klog.Errorf("failed to register unfinished metric %v: %v", name, err)
+	}
+	return unfinished
+}
+
+func (prometheusMetricsProvider) NewLongestRunningProcessorSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	if isDisabled(name) {
+		return noopMetric{}
+	}
+	longestRunningProcessor := prometheus.NewGauge(prometheus.GaugeOpts{
+		Subsystem: WorkQueueSubsystem,
+		Name:      LongestRunningProcessorKey,
+		Help: stabilityLevel + " How many seconds has the longest running " +
+			"processor for workqueue been running.",
+		ConstLabels: prometheus.Labels{"name": name},
+	})
+	if err := prometheus.Register(longestRunningProcessor); err != nil {
+		klog.Errorf("failed to register unfinished metric %v: %v", name, err)
+	}
+	return longestRunningProcessor
+}
+
+func (prometheusMetricsProvider) NewRetriesMetric(name string) workqueue.CounterMetric {
+	if isDisabled(name) {
+		return noopMetric{}
+	}
+	retries := prometheus.NewCounter(prometheus.CounterOpts{
+		Subsystem:   WorkQueueSubsystem,
+		Name:        RetriesKey,
+		Help:        stabilityLevel + " Total number of retries handled by workqueue",
+		ConstLabels: prometheus.Labels{"name": name},
+	})
+	if err := prometheus.Register(retries); err != nil {
+		klog.Errorf("failed to register retries metric %v: %v", name, err)
+	}
+	return retries
+}
+
+// The following legacy, unlabeled metrics are permanently deprecated in favor of the labeled
+// metrics above and are intentionally no-ops.
+
+func (prometheusMetricsProvider) NewDeprecatedDepthMetric(name string) workqueue.GaugeMetric {
+	return noopMetric{}
+}
+
+func (prometheusMetricsProvider) NewDeprecatedAddsMetric(name string) workqueue.CounterMetric {
+	return noopMetric{}
+}
+
+func (prometheusMetricsProvider) NewDeprecatedLatencyMetric(name string) workqueue.SummaryMetric {
+	return noopMetric{}
+}
+
+func (prometheusMetricsProvider) NewDeprecatedWorkDurationMetric(name string) workqueue.SummaryMetric {
+	return noopMetric{}
+}
+
+func (prometheusMetricsProvider) NewDeprecatedUnfinishedWorkSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return noopMetric{}
+}
+
+func (prometheusMetricsProvider) NewDeprecatedLongestRunningProcessorMicrosecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return noopMetric{}
+}
+
+func (prometheusMetricsProvider) NewDeprecatedRetriesMetric(name string) workqueue.CounterMetric {
+	return noopMetric{}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+type fakePushExporter struct {
+	mu       sync.Mutex
+	pushes   int
+	lastAttr map[string]string
+}
+
+func (f *fakePushExporter) Export(families []*dto.MetricFamily, resourceAttributes map[string]string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pushes++
+	f.lastAttr = resourceAttributes
+	return nil
+}
+
+func (f *fakePushExporter) pushCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.pushes
+}
+
+func TestPeriodicPushExporterPushesOnInterval(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	c := NewCounter(&CounterOpts{Name: "test_push_exporter_requests", StabilityLevel: ALPHA, Help: "help"})
+	registry.MustRegister(c)
+	c.Inc()
+
+	exporter := &fakePushExporter{}
+	pushExporter := NewPeriodicPushExporter(registry, exporter, PushExporterOptions{
+		Interval:           10 * time.Millisecond,
+		ResourceAttributes: map[string]string{"service.name": "test-component"},
+	})
+
+	stopCh := make(chan struct{})
+	go pushExporter.Run(stopCh)
+	defer close(stopCh)
+
+	err := wait.Poll(5*time.Millisecond, 200*time.Millisecond, func() (bool, error) {
+		return exporter.pushCount() > 0, nil
+	})
+	if err != nil {
+		t.Fatalf("expected at least one push: %v", err)
+	}
+	if got := exporter.lastAttr["service.name"]; got != "test-component" {
+		t.Errorf("expected resource attributes to be passed through, got %v", exporter.lastAttr)
+	}
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// RecordingRuleOp names the aggregation a RecordingRule applies to its source series.
+type RecordingRuleOp string
+
+const (
+	// RecordingRuleSum exposes the sum, across all label combinations matching MatchLabels (or
+	// all combinations, if MatchLabels is empty), of Metric's samples.
+	RecordingRuleSum RecordingRuleOp = "sum"
+	// RecordingRuleRate exposes the per-second rate of increase of Metric's summed value since
+	// the previous gather. It is 0 on the first gather after the rule is registered.
+	RecordingRuleRate RecordingRuleOp = "rate"
+	// RecordingRuleRatio exposes sum(Numerator) / sum(Denominator). It is 0 if the denominator
+	// sums to 0.
+	RecordingRuleRatio RecordingRuleOp = "ratio"
+	// RecordingRuleApdex exposes an Apdex satisfaction score, in [0, 1], computed from an
+	// ApdexVec's ApdexSatisfied/ApdexTolerating/ApdexFrustrated buckets in Metric:
+	// (satisfied + tolerating/2) / total. It is 0 if no observations matching MatchLabels exist.
+	RecordingRuleApdex RecordingRuleOp = "apdex"
+)
+
+// RecordingRule defines a derived gauge computed from already-registered series at gather time.
+// This lets a component expose consistent SLI ratios (e.g. error rate, cache hit ratio, Apdex
+// score) from JSON-configurable component config, without depending on an external rules engine
+// such as Prometheus recording rules.
+type RecordingRule struct {
+	// Name is the metric name the derived gauge is exposed under.
+	Name string
+	// Help is the derived metric's HELP text.
+	Help string
+	// Op selects the aggregation applied below.
+	Op RecordingRuleOp
+	// Metric is the source series name for RecordingRuleSum, RecordingRuleRate and
+	// RecordingRuleApdex.
+	Metric string
+	// Numerator and Denominator are the source series names for RecordingRuleRatio.
+	Numerator   string
+	Denominator string
+	// MatchLabels, if non-empty, restricts RecordingRuleSum, RecordingRuleRate and
+	// RecordingRuleApdex to samples whose labels contain every key/value pair given here (e.g.
+	// {"verb": "LIST"} to score a single verb out of a per-verb ApdexVec). Ignored by
+	// RecordingRuleRatio.
+	MatchLabels map[string]string
+}
+
+// recordingRuleEvaluator evaluates a fixed set of RecordingRules against the metric families
+// produced by a single Gather call, keeping the state (previous value and time) RecordingRuleRate
+// needs across calls.
+type recordingRuleEvaluator struct {
+	rules []RecordingRule
+	rates map[string]rateSample
+}
+
+type rateSample struct {
+	value float64
+	at    time.Time
+}
+
+func newRecordingRuleEvaluator(rules []RecordingRule) *recordingRuleEvaluator {
+	return &recordingRuleEvaluator{
+		rules: rules,
+		rates: map[string]rateSample{},
+	}
+}
+
+// Evaluate returns one derived MetricFamily per configured rule, computed from families.
+func (e *recordingRuleEvaluator) Evaluate(families []*dto.MetricFamily) []*dto.MetricFamily {
+	if len(e.rules) == 0 {
+		return nil
+	}
+	byName := make(map[string]*dto.MetricFamily, len(families))
+	for _, family := range families {
+		byName[family.GetName()] = family
+	}
+
+	now := time.Now()
+	derived := make([]*dto.MetricFamily, 0, len(e.rules))
+	for _, rule := range e.rules {
+		value, err := e.evaluateRule(rule, byName, now)
+		if err != nil {
+			continue
+		}
+		derived = append(derived, gaugeMetricFamily(rule.Name, rule.Help, value))
+	}
+	return derived
+}
+
+func (e *recordingRuleEvaluator) evaluateRule(rule RecordingRule, byName map[string]*dto.MetricFamily, now time.Time) (float64, error) {
+	switch rule.Op {
+	case RecordingRuleSum:
+		return sumMetricFamilyMatching(byName[rule.Metric], rule.MatchLabels), nil
+	case RecordingRuleRate:
+		current := sumMetricFamilyMatching(byName[rule.Metric], rule.MatchLabels)
+		previous, ok := e.rates[rule.Name]
+		e.rates[rule.Name] = rateSample{value: current, at: now}
+		if !ok {
+			return 0, nil
+		}
+		elapsed := now.Sub(previous.at).Seconds()
+		if elapsed <= 0 {
+			return 0, nil
+		}
+		return (current - previous.value) / elapsed, nil
+	case RecordingRuleRatio:
+		denominator := sumMetricFamily(byName[rule.Denominator])
+		if denominator == 0 {
+			return 0, nil
+		}
+		return sumMetricFamily(byName[rule.Numerator]) / denominator, nil
+	case RecordingRuleApdex:
+		return apdexScore(byName[rule.Metric], rule.MatchLabels), nil
+	default:
+		return 0, fmt.Errorf("unknown recording rule op %q", rule.Op)
+	}
+}
+
+// sumMetricFamily sums every sample in family, regardless of label combination. A nil family
+// (the source series has not been observed yet) sums to 0.
+func sumMetricFamily(family *dto.MetricFamily) float64 {
+	return sumMetricFamilyMatching(family, nil)
+}
+
+// sumMetricFamilyMatching sums the samples in family whose labels contain every key/value pair in
+// match (all samples, if match is empty). A nil family sums to 0.
+func sumMetricFamilyMatching(family *dto.MetricFamily, match map[string]string) float64 {
+	if family == nil {
+		return 0
+	}
+	var sum float64
+	for _, m := range family.GetMetric() {
+		if !labelsMatch(m, match) {
+			continue
+		}
+		switch {
+		case m.GetGauge() != nil:
+			sum += m.GetGauge().GetValue()
+		case m.GetCounter() != nil:
+			sum += m.GetCounter().GetValue()
+		case m.GetUntyped() != nil:
+			sum += m.GetUntyped().GetValue()
+		}
+	}
+	return sum
+}
+
+// labelsMatch returns true if m's labels contain every key/value pair in match.
+func labelsMatch(m *dto.Metric, match map[string]string) bool {
+	for k, v := range match {
+		if labelValue(m, k) != v {
+			return false
+		}
+	}
+	return true
+}
+
+// labelValue returns the value of m's label named name, or "" if it is not present.
+func labelValue(m *dto.Metric, name string) string {
+	for _, pair := range m.GetLabel() {
+		if pair.GetName() == name {
+			return pair.GetValue()
+		}
+	}
+	return ""
+}
+
+// apdexScore computes the Apdex satisfaction score for the samples in family whose labels contain
+// every key/value pair in match: (satisfied + tolerating/2) / total. It is 0 if no matching
+// samples with a recognized apdexOutcomeLabel value exist.
+func apdexScore(family *dto.MetricFamily, match map[string]string) float64 {
+	if family == nil {
+		return 0
+	}
+	var satisfied, tolerating, total float64
+	for _, m := range family.GetMetric() {
+		if !labelsMatch(m, match) {
+			continue
+		}
+		var value float64
+		switch {
+		case m.GetCounter() != nil:
+			value = m.GetCounter().GetValue()
+		case m.GetGauge() != nil:
+			value = m.GetGauge().GetValue()
+		case m.GetUntyped() != nil:
+			value = m.GetUntyped().GetValue()
+		default:
+			continue
+		}
+		total += value
+		switch labelValue(m, apdexOutcomeLabel) {
+		case ApdexSatisfied:
+			satisfied += value
+		case ApdexTolerating:
+			tolerating += value
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return (satisfied + tolerating/2) / total
+}
+
+func gaugeMetricFamily(name, help string, value float64) *dto.MetricFamily {
+	gaugeType := dto.MetricType_GAUGE
+	return &dto.MetricFamily{
+		Name: &name,
+		Help: &help,
+		Type: &gaugeType,
+		Metric: []*dto.Metric{
+			{Gauge: &dto.Gauge{Value: &value}},
+		},
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+func TestRecordingRuleSumAndRatio(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	hits := NewCounter(&CounterOpts{Name: "test_cache_hits", StabilityLevel: ALPHA, Help: "hits"})
+	misses := NewCounter(&CounterOpts{Name: "test_cache_misses", StabilityLevel: ALPHA, Help: "misses"})
+	registry.MustRegister(hits, misses)
+	hits.Add(3)
+	misses.Add(1)
+
+	registry.SetRecordingRules([]RecordingRule{
+		{Name: "test_cache_total", Help: "total", Op: RecordingRuleSum, Metric: "test_cache_hits"},
+		{Name: "test_cache_hit_ratio", Help: "hit ratio", Op: RecordingRuleRatio, Numerator: "test_cache_hits", Denominator: "test_cache_misses"},
+	})
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	values := map[string]float64{}
+	for _, family := range families {
+		values[family.GetName()] = sumMetricFamily(family)
+	}
+
+	if values["test_cache_total"] != 3 {
+		t.Errorf("expected test_cache_total = 3, got %v", values["test_cache_total"])
+	}
+	if values["test_cache_hit_ratio"] != 3 {
+		t.Errorf("expected test_cache_hit_ratio = 3, got %v", values["test_cache_hit_ratio"])
+	}
+}
+
+func TestRecordingRuleRatioWithZeroDenominator(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	hits := NewCounter(&CounterOpts{Name: "test_hits2", StabilityLevel: ALPHA, Help: "hits"})
+	registry.MustRegister(hits)
+	hits.Add(5)
+
+	registry.SetRecordingRules([]RecordingRule{
+		{Name: "test_ratio2", Help: "ratio", Op: RecordingRuleRatio, Numerator: "test_hits2", Denominator: "test_missing_metric"},
+	})
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() == "test_ratio2" && sumMetricFamily(family) != 0 {
+			t.Errorf("expected ratio with zero denominator to be 0, got %v", sumMetricFamily(family))
+		}
+	}
+}
+
+func TestRecordingRuleApdex(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	latency := NewApdexVec(&ApdexOpts{
+		CounterOpts:         CounterOpts{Name: "test_request_latency", StabilityLevel: ALPHA, Help: "latency"},
+		SatisfiedThreshold:  100 * time.Millisecond,
+		ToleratingThreshold: 400 * time.Millisecond,
+	}, []string{"verb"})
+	registry.MustRegister(latency)
+
+	latency.Observe(50*time.Millisecond, "LIST")
+	latency.Observe(50*time.Millisecond, "LIST")
+	latency.Observe(200*time.Millisecond, "LIST")
+	latency.Observe(1*time.Second, "LIST")
+	latency.Observe(50*time.Millisecond, "GET")
+
+	registry.SetRecordingRules([]RecordingRule{
+		{Name: "test_request_apdex_list", Help: "apdex", Op: RecordingRuleApdex, Metric: "test_request_latency", MatchLabels: map[string]string{"verb": "LIST"}},
+		{Name: "test_request_apdex_all", Help: "apdex", Op: RecordingRuleApdex, Metric: "test_request_latency"},
+	})
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	values := map[string]float64{}
+	for _, family := range families {
+		values[family.GetName()] = sumMetricFamily(family)
+	}
+
+	// LIST: 2 satisfied, 1 tolerating, 1 frustrated => (2 + 0.5) / 4 = 0.625
+	if got, want := values["test_request_apdex_list"], 0.625; got != want {
+		t.Errorf("expected test_request_apdex_list = %v, got %v", want, got)
+	}
+	// all verbs: 3 satisfied, 1 tolerating, 1 frustrated => (3 + 0.5) / 5 = 0.7
+	if got, want := values["test_request_apdex_all"], 0.7; got != want {
+		t.Errorf("expected test_request_apdex_all = %v, got %v", want, got)
+	}
+}
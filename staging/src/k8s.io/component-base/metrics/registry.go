@@ -30,6 +30,25 @@ type KubeRegistry interface {
 	MustRegister(...KubeCollector)
 	Unregister(KubeCollector) bool
 	Gather() ([]*dto.MetricFamily, error)
+	// SetRecordingRules configures the derived gauges the registry computes and appends to
+	// every subsequent Gather call. Calling it again replaces the previous set of rules.
+	SetRecordingRules(rules []RecordingRule)
+	// SetDynamicConstLabel atomically sets the value of a registry-level constant label named
+	// name, stamping it onto every metric on every subsequent Gather call. Calling it again with
+	// the same name updates the value in place, so a transition (e.g. "leader" flipping from
+	// "false" to "true" after a leader election) never leaves the previous value's series
+	// lingering alongside the new one: the next Gather reflects only the current value.
+	SetDynamicConstLabel(name, value string)
+	// TopCardinalityOffenders returns the n metric families with the highest series count observed
+	// at the most recent Gather, sorted descending, so operators can find cardinality hot spots
+	// without external tooling.
+	TopCardinalityOffenders(n int) []CardinalityOffender
+	// Freeze causes every subsequent Register or MustRegister call to reject its collector with a
+	// clear error, unless the collector was wrapped with AllowDynamicRegistration. Call it once, at
+	// the end of a component's startup, to catch metrics accidentally registered from a request
+	// path, which would otherwise race with concurrent registrations and Gather calls and produce
+	// inconsistent /metrics output between replicas of the same component.
+	Freeze()
 }
 
 // kubeRegistry is a wrapper around a prometheus registry-type object. Upon initialization
@@ -37,7 +56,11 @@ type KubeRegistry interface {
 // automatic behavior can be configured for metric versioning.
 type kubeRegistry struct {
 	PromRegistry
-	version semver.Version
+	version       semver.Version
+	ruleEvaluator *recordingRuleEvaluator
+	dynamicLabels *dynamicLabelSet
+	cardinality   *cardinalityTracker
+	freezer       registryFreezer
 }
 
 // Register registers a new Collector to be included in metrics
@@ -46,6 +69,9 @@ type kubeRegistry struct {
 // already registered Collectors — do not fulfill the consistency and
 // uniqueness criteria described in the documentation of metric.Desc.
 func (kr *kubeRegistry) Register(c KubeCollector) error {
+	if err := kr.freezer.check(c); err != nil {
+		return err
+	}
 	if c.Create(&kr.version) {
 		return kr.PromRegistry.Register(c)
 	}
@@ -58,6 +84,9 @@ func (kr *kubeRegistry) Register(c KubeCollector) error {
 func (kr *kubeRegistry) MustRegister(cs ...KubeCollector) {
 	metrics := make([]prometheus.Collector, 0, len(cs))
 	for _, c := range cs {
+		if err := kr.freezer.check(c); err != nil {
+			panic(err)
+		}
 		if c.Create(&kr.version) {
 			metrics = append(metrics, c)
 		}
@@ -82,15 +111,54 @@ func (kr *kubeRegistry) Unregister(collector KubeCollector) bool {
 // for valid exposition. As an exception to the strict consistency
 // requirements described for metric.Desc, Gather will tolerate
 // different sets of label names for metrics of the same metric family.
+//
+// Gather also updates this registry's cardinality accounting from the gathered families and
+// appends a registered_metric_series self-metric reporting it, so the accounting is always as
+// fresh as whatever it was most recently asked to expose.
 func (kr *kubeRegistry) Gather() ([]*dto.MetricFamily, error) {
-	return kr.PromRegistry.Gather()
+	families, err := kr.PromRegistry.Gather()
+	if err != nil {
+		return families, err
+	}
+	if kr.ruleEvaluator != nil {
+		families = append(families, kr.ruleEvaluator.Evaluate(families)...)
+	}
+	kr.dynamicLabels.stamp(families)
+	kr.cardinality.update(families)
+	families = append(families, kr.cardinality.selfMetricFamily())
+	return families, nil
+}
+
+// SetRecordingRules configures the derived gauges the registry computes and appends to every
+// subsequent Gather call. Calling it again replaces the previous set of rules.
+func (kr *kubeRegistry) SetRecordingRules(rules []RecordingRule) {
+	kr.ruleEvaluator = newRecordingRuleEvaluator(rules)
+}
+
+// SetDynamicConstLabel atomically sets the value of a registry-level constant label named name.
+// See dynamicLabelSet for how the value is applied at Gather time.
+func (kr *kubeRegistry) SetDynamicConstLabel(name, value string) {
+	kr.dynamicLabels.set(name, value)
+}
+
+// TopCardinalityOffenders returns the n metric families with the highest series count observed at
+// the most recent Gather. See cardinalityTracker.
+func (kr *kubeRegistry) TopCardinalityOffenders(n int) []CardinalityOffender {
+	return kr.cardinality.TopOffenders(n)
+}
+
+// Freeze marks the registry frozen. See the KubeRegistry.Freeze doc comment.
+func (kr *kubeRegistry) Freeze() {
+	kr.freezer.freeze()
 }
 
 // NewKubeRegistry creates a new vanilla Registry without any Collectors
 // pre-registered.
 func NewKubeRegistry(v apimachineryversion.Info) KubeRegistry {
 	return &kubeRegistry{
-		PromRegistry: prometheus.NewRegistry(),
-		version:      parseVersion(v),
+		PromRegistry:  prometheus.NewRegistry(),
+		version:       ParseVersion(v),
+		dynamicLabels: newDynamicLabelSet(),
+		cardinality:   newCardinalityTracker(),
 	}
 }
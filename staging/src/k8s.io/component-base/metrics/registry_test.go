@@ -196,3 +196,43 @@ func TestMustRegister(t *testing.T) {
 		})
 	}
 }
+
+func TestBetaDeprecationGracePeriod(t *testing.T) {
+	var tests = []struct {
+		desc             string
+		registryVersion  apimachineryversion.Info
+		expectedIsHidden bool
+	}{
+		{
+			desc:             "not yet at the release the metric deprecates in",
+			registryVersion:  apimachineryversion.Info{Major: "1", Minor: "14", GitVersion: "v1.14.0"},
+			expectedIsHidden: false,
+		},
+		{
+			desc:             "one minor version past deprecation, still within the BETA grace period",
+			registryVersion:  apimachineryversion.Info{Major: "1", Minor: "16", GitVersion: "v1.16.0"},
+			expectedIsHidden: false,
+		},
+		{
+			desc:             "past the BETA grace period",
+			registryVersion:  apimachineryversion.Info{Major: "1", Minor: "19", GitVersion: "v1.19.0"},
+			expectedIsHidden: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			betaDeprecatedCounter := NewCounter(&CounterOpts{
+				Name:              "test_beta_dep_counter",
+				StabilityLevel:    BETA,
+				Help:              "counter help",
+				DeprecatedVersion: &v115,
+			})
+			registry := NewKubeRegistry(test.registryVersion)
+			registry.MustRegister(betaDeprecatedCounter)
+			if betaDeprecatedCounter.IsHidden() != test.expectedIsHidden {
+				t.Errorf("Got IsHidden() == %v, wanted %v", betaDeprecatedCounter.IsHidden(), test.expectedIsHidden)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+
+	"github.com/blang/semver"
+)
+
+// RenamedCounter emits the same value under both an old and a new metric name, so that a rename
+// can go out with a deprecation window instead of silently breaking dashboards and alerts that
+// still query the old name. The old name is always registered as deprecated as of
+// deprecatedVersion; once consumers have migrated, drop the RenamedCounter in favor of a plain
+// Counter under the new name.
+type RenamedCounter struct {
+	old *Counter
+	new *Counter
+}
+
+// NewRenamedCounter returns a RenamedCounter that increments both oldName (deprecated as of
+// deprecatedVersion) and opts.Name on every call. opts describes the new metric; oldName is
+// otherwise identical (same namespace, subsystem, help text and stability level).
+func NewRenamedCounter(opts *CounterOpts, oldName string, deprecatedVersion *semver.Version) *RenamedCounter {
+	oldOpts := &CounterOpts{
+		Namespace:         opts.Namespace,
+		Subsystem:         opts.Subsystem,
+		Name:              oldName,
+		Help:              opts.Help,
+		ConstLabels:       opts.ConstLabels,
+		DeprecatedVersion: deprecatedVersion,
+		StabilityLevel:    opts.StabilityLevel,
+		Unit:              opts.Unit,
+	}
+
+	rc := &RenamedCounter{
+		old: NewCounter(oldOpts),
+		new: NewCounter(opts),
+	}
+	registerDualEmission(rc)
+	return rc
+}
+
+// Inc increments both the old and new counters by 1.
+func (r *RenamedCounter) Inc() {
+	r.old.Inc()
+	r.new.Inc()
+}
+
+// Add increments both the old and new counters by v.
+func (r *RenamedCounter) Add(v float64) {
+	r.old.Add(v)
+	r.new.Add(v)
+}
+
+// Registerables returns the collectors that must be registered for this RenamedCounter to emit
+// under both names: the deprecated, old-named counter and the new-named counter.
+func (r *RenamedCounter) Registerables() []KubeCollector {
+	return []KubeCollector{r.old, r.new}
+}
+
+// DualEmittedMetric describes a single old-name/new-name pair currently being emitted by a
+// RenamedCounter, for use in migration-tracking reports.
+type DualEmittedMetric struct {
+	OldName string
+	NewName string
+}
+
+var (
+	dualEmissionMu sync.Mutex
+	dualEmissions  []DualEmittedMetric
+)
+
+// registerDualEmission records rc so it shows up in DualEmittedMetrics. It is called once per
+// RenamedCounter, at construction time.
+func registerDualEmission(rc *RenamedCounter) {
+	dualEmissionMu.Lock()
+	defer dualEmissionMu.Unlock()
+	dualEmissions = append(dualEmissions, DualEmittedMetric{
+		OldName: rc.old.Name,
+		NewName: rc.new.Name,
+	})
+}
+
+// DualEmittedMetrics returns every old-name/new-name pair currently being emitted by a
+// RenamedCounter created with NewRenamedCounter, so operators can track down and finish pending
+// metric migrations.
+func DualEmittedMetrics() []DualEmittedMetric {
+	dualEmissionMu.Lock()
+	defer dualEmissionMu.Unlock()
+	out := make([]DualEmittedMetric, len(dualEmissions))
+	copy(out, dualEmissions)
+	return out
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/blang/semver"
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+func TestRenamedCounterDualEmission(t *testing.T) {
+	v115 := semver.MustParse("1.15.0")
+	rc := NewRenamedCounter(&CounterOpts{
+		Name: "test_renamed_counter_new",
+		Help: "help",
+	}, "test_renamed_counter_old", &v115)
+
+	registry := NewKubeRegistry(apimachineryversion.Info{
+		Major: "1",
+		Minor: "15",
+	})
+	registry.MustRegister(rc.Registerables()...)
+
+	rc.Inc()
+	rc.Add(2)
+
+	metricFamilies, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := map[string]float64{}
+	for _, mf := range metricFamilies {
+		values[mf.GetName()] = mf.GetMetric()[0].GetCounter().GetValue()
+	}
+	if values["test_renamed_counter_old"] != 3 {
+		t.Errorf("expected old-named counter to be 3, got %v", values["test_renamed_counter_old"])
+	}
+	if values["test_renamed_counter_new"] != 3 {
+		t.Errorf("expected new-named counter to be 3, got %v", values["test_renamed_counter_new"])
+	}
+
+	var found bool
+	for _, d := range DualEmittedMetrics() {
+		if d.OldName == "test_renamed_counter_old" && d.NewName == "test_renamed_counter_new" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected DualEmittedMetrics to report the old/new name pair")
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "sync"
+
+// ResettableCounter wraps a Counter with a second, internal-only running total that an
+// authorized consumer (e.g. a usage-metering pipeline billing per interval) can atomically
+// read and clear with ReadAndReset, without affecting what Prometheus scrapes see.
+//
+// Prometheus counters are defined to be monotonically non-decreasing, and every scrape of the
+// wrapped Counter continues to see that same cumulative, never-reset value: ReadAndReset only
+// ever drains the separate internal accumulator, it never touches the exported series. Reach
+// for this only when a component genuinely needs a "since I last checked" total alongside the
+// usual cumulative one; most components should just use a plain Counter.
+type ResettableCounter struct {
+	counter *Counter
+
+	mu      sync.Mutex
+	pending float64
+}
+
+// NewResettableCounter returns a ResettableCounter. Like NewCounter, the object returned will
+// not measure anything until its Registerables are registered, since the underlying Counter is
+// lazily instantiated.
+func NewResettableCounter(opts *CounterOpts) *ResettableCounter {
+	return &ResettableCounter{counter: NewCounter(opts)}
+}
+
+// Registerables returns the collectors that must be registered for this ResettableCounter to be
+// exposed to Prometheus: just the underlying cumulative Counter.
+func (c *ResettableCounter) Registerables() []KubeCollector {
+	return []KubeCollector{c.counter}
+}
+
+// Add increments both the cumulative Counter every Prometheus scrape sees and the internal
+// accumulator ReadAndReset drains.
+func (c *ResettableCounter) Add(v float64) {
+	c.counter.Add(v)
+	c.mu.Lock()
+	c.pending += v
+	c.mu.Unlock()
+}
+
+// Inc is a convenience wrapper around Add(1).
+func (c *ResettableCounter) Inc() {
+	c.Add(1)
+}
+
+// ReadAndReset atomically returns the total added since the last call to ReadAndReset (or since
+// creation, for the first call), and clears it back to zero. It has no effect on the value a
+// Prometheus scrape of this ResettableCounter's Counter sees, which keeps accumulating forever.
+func (c *ResettableCounter) ReadAndReset() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v := c.pending
+	c.pending = 0
+	return v
+}
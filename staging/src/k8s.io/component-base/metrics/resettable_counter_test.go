@@ -0,0 +1,69 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+func TestResettableCounterReadAndReset(t *testing.T) {
+	c := NewResettableCounter(&CounterOpts{Name: "test_resettable_counter", Help: "help"})
+
+	c.Add(3)
+	c.Inc()
+	if v := c.ReadAndReset(); v != 4 {
+		t.Errorf("ReadAndReset() = %v, want 4", v)
+	}
+	if v := c.ReadAndReset(); v != 0 {
+		t.Errorf("second ReadAndReset() = %v, want 0", v)
+	}
+
+	c.Add(5)
+	if v := c.ReadAndReset(); v != 5 {
+		t.Errorf("ReadAndReset() after further Add = %v, want 5", v)
+	}
+}
+
+func TestResettableCounterScrapeStaysCumulative(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	c := NewResettableCounter(&CounterOpts{Name: "test_resettable_counter_cumulative", Help: "help"})
+	registry.MustRegister(c.Registerables()...)
+
+	c.Add(3)
+	c.ReadAndReset()
+	c.Add(2)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	var mf *dto.MetricFamily
+	for _, f := range families {
+		if f.GetName() == "test_resettable_counter_cumulative" {
+			mf = f
+		}
+	}
+	if mf == nil {
+		t.Fatalf("did not find test_resettable_counter_cumulative in gathered families")
+	}
+	if got := mf.GetMetric()[0].GetCounter().GetValue(); got != 5 {
+		t.Errorf("scraped counter value = %v, want 5 (cumulative, unaffected by ReadAndReset)", got)
+	}
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RingSample is a single down-sampled observation of a metric family retained by a MetricRing.
+type RingSample struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+// MetricRing retains a bounded, down-sampled history of selected unlabeled metric families in
+// memory, so that dashboards backed by a small cluster's single Prometheus instance still have
+// something to show for the last retention window after a short scrape outage. It is not a
+// substitute for a real time series database: history is lost on process restart, and only the
+// families named at construction time are retained.
+//
+// Like CheckpointCounters, a MetricRing does not run a background goroutine of its own; the
+// caller must invoke Sample on a periodic loop (e.g. every resolution) for the ring to fill in.
+type MetricRing struct {
+	mu         sync.RWMutex
+	names      map[string]bool
+	resolution time.Duration
+	capacity   int
+	series     map[string][]RingSample
+}
+
+// NewMetricRing creates a MetricRing that retains one down-sampled RingSample per resolution
+// interval, for the given retention duration, for each unlabeled metric family named in names.
+// Labeled (Vec) families are skipped for the same reason CheckpointCounters skips them: there is
+// no stable way to reattach a retained label combination across process restarts or cardinality
+// changes. retention is rounded up to the nearest whole multiple of resolution.
+func NewMetricRing(resolution, retention time.Duration, names []string) *MetricRing {
+	allowed := make(map[string]bool, len(names))
+	for _, n := range names {
+		allowed[n] = true
+	}
+	capacity := int(retention / resolution)
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &MetricRing{
+		names:      allowed,
+		resolution: resolution,
+		capacity:   capacity,
+		series:     make(map[string][]RingSample),
+	}
+}
+
+// Sample gathers registry and records one sample per tracked metric family. If a sample already
+// exists for the current resolution bucket, it is overwritten with the latest value rather than
+// appended, so calling Sample more often than resolution does not distort the retained history.
+func (r *MetricRing) Sample(registry KubeRegistry) error {
+	families, err := registry.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics for ring sample: %v", err)
+	}
+	now := time.Now()
+	bucket := now.Truncate(r.resolution)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, family := range families {
+		if !r.names[family.GetName()] {
+			continue
+		}
+		var value float64
+		for _, metric := range family.GetMetric() {
+			if len(metric.GetLabel()) != 0 {
+				continue
+			}
+			switch {
+			case metric.GetGauge() != nil:
+				value = metric.GetGauge().GetValue()
+			case metric.GetCounter() != nil:
+				value = metric.GetCounter().GetValue()
+			case metric.GetUntyped() != nil:
+				value = metric.GetUntyped().GetValue()
+			}
+		}
+		samples := r.series[family.GetName()]
+		if n := len(samples); n > 0 && samples[n-1].Timestamp.Equal(bucket) {
+			samples[n-1].Value = value
+			continue
+		}
+		samples = append(samples, RingSample{Timestamp: bucket, Value: value})
+		if len(samples) > r.capacity {
+			samples = samples[len(samples)-r.capacity:]
+		}
+		r.series[family.GetName()] = samples
+	}
+	return nil
+}
+
+// Range returns the retained samples for name whose timestamp falls within [start, end].
+func (r *MetricRing) Range(name string, start, end time.Time) []RingSample {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []RingSample
+	for _, sample := range r.series[name] {
+		if sample.Timestamp.Before(start) || sample.Timestamp.After(end) {
+			continue
+		}
+		result = append(result, sample)
+	}
+	return result
+}
+
+// RingRangeHandler returns an http.Handler serving JSON-encoded RingSample slices from ring for
+// the metric family named by the "name" query parameter, optionally bounded by "start" and "end"
+// query parameters (Unix seconds). Omitting start and/or end defaults to the full retained
+// history. This is deliberately a minimal range API, meant to cover a short outage's worth of
+// backfill for the same dashboards the live scrape endpoint already feeds, not to replace a
+// general-purpose time series query language.
+func RingRangeHandler(ring *MetricRing) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			http.Error(w, "missing required query parameter: name", http.StatusBadRequest)
+			return
+		}
+		start := time.Unix(0, 0)
+		if v := r.URL.Query().Get("start"); v != "" {
+			seconds, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid start: %v", err), http.StatusBadRequest)
+				return
+			}
+			start = time.Unix(seconds, 0)
+		}
+		end := time.Now()
+		if v := r.URL.Query().Get("end"); v != "" {
+			seconds, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid end: %v", err), http.StatusBadRequest)
+				return
+			}
+			end = time.Unix(seconds, 0)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(ring.Range(name, start, end)); err != nil {
+			http.Error(w, fmt.Sprintf("failed to encode range response: %v", err), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+func TestMetricRingSampleAndRange(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	queueDepth := NewGauge(&GaugeOpts{
+		Name:           "ring_test_queue_depth",
+		Help:           "gauge retained by the ring",
+		StabilityLevel: ALPHA,
+	})
+	registry.MustRegister(queueDepth)
+
+	ring := NewMetricRing(time.Minute, 3*time.Minute, []string{"ring_test_queue_depth"})
+
+	queueDepth.Set(1)
+	if err := ring.Sample(registry); err != nil {
+		t.Fatalf("Sample failed: %v", err)
+	}
+	// A second sample within the same resolution bucket overwrites the first rather than
+	// appending, so the ring reflects the latest value for that bucket.
+	queueDepth.Set(2)
+	if err := ring.Sample(registry); err != nil {
+		t.Fatalf("Sample failed: %v", err)
+	}
+
+	samples := ring.Range("ring_test_queue_depth", time.Unix(0, 0), time.Now().Add(time.Hour))
+	if len(samples) != 1 {
+		t.Fatalf("expected 1 retained sample, got %d", len(samples))
+	}
+	if samples[0].Value != 2 {
+		t.Errorf("expected retained sample to hold the latest value 2, got %v", samples[0].Value)
+	}
+}
+
+func TestMetricRingCapacityEviction(t *testing.T) {
+	ring := NewMetricRing(time.Minute, 2*time.Minute, []string{"ring_test_evicted"})
+	base := time.Unix(0, 0)
+	ring.series["ring_test_evicted"] = []RingSample{
+		{Timestamp: base, Value: 1},
+		{Timestamp: base.Add(time.Minute), Value: 2},
+	}
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	counter := NewCounter(&CounterOpts{
+		Name:           "ring_test_evicted",
+		Help:           "counter retained by the ring",
+		StabilityLevel: ALPHA,
+	})
+	registry.MustRegister(counter)
+	counter.Inc()
+	if err := ring.Sample(registry); err != nil {
+		t.Fatalf("Sample failed: %v", err)
+	}
+	samples := ring.series["ring_test_evicted"]
+	if len(samples) != 2 {
+		t.Fatalf("expected ring to stay capped at 2 samples, got %d", len(samples))
+	}
+	if samples[0].Value != 2 {
+		t.Errorf("expected oldest sample to have been evicted, got samples %+v", samples)
+	}
+}
+
+func TestRingRangeHandler(t *testing.T) {
+	ring := NewMetricRing(time.Minute, time.Hour, []string{"ring_test_handler"})
+	now := time.Now().Truncate(time.Minute)
+	ring.series["ring_test_handler"] = []RingSample{
+		{Timestamp: now, Value: 42},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics/ring?name=ring_test_handler", nil)
+	w := httptest.NewRecorder()
+	RingRangeHandler(ring).ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var got []RingSample
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(got) != 1 || got[0].Value != 42 {
+		t.Errorf("expected one sample with value 42, got %+v", got)
+	}
+}
+
+func TestRingRangeHandlerMissingName(t *testing.T) {
+	ring := NewMetricRing(time.Minute, time.Hour, nil)
+	req := httptest.NewRequest(http.MethodGet, "/metrics/ring", nil)
+	w := httptest.NewRecorder()
+	RingRangeHandler(ring).ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for missing name parameter, got %d", w.Code)
+	}
+}
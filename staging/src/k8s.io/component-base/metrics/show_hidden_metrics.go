@@ -0,0 +1,70 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/blang/semver"
+)
+
+// showHidden tracks whether metrics that would otherwise be hidden for having been deprecated too
+// long ago should be shown instead, as toggled by SetShowHidden. It is process-global rather than
+// per-registry because the component flag it backs (--show-hidden-metrics-for-version) is itself
+// a single, process-wide escape hatch: an operator either wants deprecated metrics back for this
+// release's migration or does not.
+var (
+	showHiddenOnce sync.Once
+	showHidden     bool
+)
+
+// SetShowHidden instructs every metric's determineDeprecationStatus to keep showing metrics that
+// would otherwise be hidden for having passed their StabilityLevel's deprecation grace period.
+// This is the escape hatch backing --show-hidden-metrics-for-version: it lets operators finish
+// migrating dashboards off a metric hidden in the current release before it is removed outright in
+// the next one. Only the first call has an effect, matching the "for one release" contract: a
+// metric un-hidden this way is still slated for removal, and calling this repeatedly across
+// releases is not a supported way to keep it alive indefinitely.
+func SetShowHidden() {
+	showHiddenOnce.Do(func() {
+		showHidden = true
+	})
+}
+
+// ShouldShowHidden returns whether SetShowHidden has been called.
+func ShouldShowHidden() bool {
+	return showHidden
+}
+
+// ValidateShowHiddenMetricsVersion checks that targetVersionStr, as configured via
+// --show-hidden-metrics-for-version, is either empty or exactly the minor version immediately
+// preceding currentVersion. The escape hatch only ever exists for the one release in which a
+// metric is newly hidden; requiring the exact prior version rather than accepting any version
+// keeps an operator from configuring it once and forgetting about it across further upgrades.
+func ValidateShowHiddenMetricsVersion(currentVersion semver.Version, targetVersionStr string) []error {
+	if targetVersionStr == "" {
+		return nil
+	}
+
+	validVersionStr := fmt.Sprintf("%d.%d", currentVersion.Major, currentVersion.Minor-1)
+	if targetVersionStr != validVersionStr {
+		return []error{fmt.Errorf("--show-hidden-metrics-for-version must be omitted or set to '%s'", validVersionStr)}
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/blang/semver"
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+// resetShowHidden restores the process-global SetShowHidden state, since it is otherwise sticky
+// for the lifetime of the test binary.
+func resetShowHidden() {
+	showHiddenOnce = sync.Once{}
+	showHidden = false
+}
+
+func TestSetShowHidden(t *testing.T) {
+	defer resetShowHidden()
+
+	deprecatedCounter := NewCounter(&CounterOpts{
+		Name:              "test_show_hidden_counter",
+		StabilityLevel:    ALPHA,
+		Help:              "counter help",
+		DeprecatedVersion: &v115,
+	})
+	registry := NewKubeRegistry(apimachineryversion.Info{Major: "1", Minor: "19", GitVersion: "v1.19.0"})
+
+	SetShowHidden()
+	registry.MustRegister(deprecatedCounter)
+	if deprecatedCounter.IsHidden() {
+		t.Errorf("expected SetShowHidden to keep a hidden metric visible, but IsHidden() == true")
+	}
+}
+
+func TestValidateShowHiddenMetricsVersion(t *testing.T) {
+	currentVersion := semver.Version{Major: 1, Minor: 19, Patch: 0}
+
+	var tests = []struct {
+		desc        string
+		targetVer   string
+		expectError bool
+	}{
+		{
+			desc:        "empty target version is always valid",
+			targetVer:   "",
+			expectError: false,
+		},
+		{
+			desc:        "the minor version immediately preceding current is valid",
+			targetVer:   "1.18",
+			expectError: false,
+		},
+		{
+			desc:        "current version itself is not valid",
+			targetVer:   "1.19",
+			expectError: true,
+		},
+		{
+			desc:        "a version more than one minor release back is not valid",
+			targetVer:   "1.17",
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			errs := ValidateShowHiddenMetricsVersion(currentVersion, test.targetVer)
+			if test.expectError && len(errs) == 0 {
+				t.Errorf("expected an error for target version %q, got none", test.targetVer)
+			}
+			if !test.expectError && len(errs) != 0 {
+				t.Errorf("expected no error for target version %q, got %v", test.targetVer, errs)
+			}
+		})
+	}
+}
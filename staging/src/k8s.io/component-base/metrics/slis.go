@@ -0,0 +1,62 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/klog"
+)
+
+// SLIHandler returns an http.Handler that serves only the metric families named in names,
+// gathered from gatherer. It is meant to be mounted on a path of its own -- conventionally
+// /metrics/slis -- separate from the component's full /metrics endpoint, so an SLO prober can be
+// authorized to see a small, curated set of health and SLI signals (e.g. a process-up gauge,
+// controller sync loop duration histograms, leader election status) without also being granted,
+// or needing to pay the cost of scraping, whatever high-cardinality metrics the rest of the
+// component exposes.
+//
+// component-base has no opinion on how that separate authorization is enforced: wire this
+// handler up behind whichever authorizer or middleware the component's own HTTP server already
+// uses to gate its other endpoints, the same way the main /metrics handler is wired up.
+//
+// A family named in names that gatherer did not produce (a typo, or a metric that has not yet
+// been observed) is silently absent from the response rather than an error, matching how an
+// ordinary Prometheus scrape treats a metric with no samples.
+func SLIHandler(gatherer KubeRegistry, names []string, opts promhttp.HandlerOpts) http.Handler {
+	allowed := make(map[string]bool, len(names))
+	for _, name := range names {
+		allowed[name] = true
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mfs, err := gatherer.Gather()
+		if err != nil {
+			klog.Errorf("error gathering metrics for SLI endpoint: %v", err)
+		}
+		filtered := make([]*dto.MetricFamily, 0, len(names))
+		for _, mf := range mfs {
+			if allowed[mf.GetName()] {
+				filtered = append(filtered, mf)
+			}
+		}
+		promhttp.HandlerFor(gathererFunc(func() ([]*dto.MetricFamily, error) {
+			return filtered, nil
+		}), opts).ServeHTTP(w, r)
+	})
+}
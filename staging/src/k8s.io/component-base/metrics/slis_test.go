@@ -0,0 +1,63 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+func TestSLIHandlerServesOnlyAllowedFamilies(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	sliCounter := NewCounter(&CounterOpts{Name: "test_process_up", Help: "help", StabilityLevel: ALPHA})
+	otherCounter := NewCounter(&CounterOpts{Name: "test_high_cardinality_total", Help: "help", StabilityLevel: ALPHA})
+	registry.MustRegister(sliCounter, otherCounter)
+	sliCounter.Inc()
+	otherCounter.Inc()
+
+	handler := SLIHandler(registry, []string{"test_process_up"}, promhttp.HandlerOpts{})
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics/slis", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	body := rec.Body.String()
+	if !strings.Contains(body, "test_process_up") {
+		t.Errorf("expected response to contain the allowed family, got: %s", body)
+	}
+	if strings.Contains(body, "test_high_cardinality_total") {
+		t.Errorf("expected response to omit families not in the allow list, got: %s", body)
+	}
+}
+
+func TestSLIHandlerIgnoresUnknownName(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	handler := SLIHandler(registry, []string{"does_not_exist"}, promhttp.HandlerOpts{})
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics/slis", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 even when no named family was gathered, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/blang/semver"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Summary is our internal representation for our wrapping struct around prometheus
+// summaries. Summary implements both KubeCollector and ObserverMetric.
+type Summary struct {
+	ObserverMetric
+	*SummaryOpts
+	lazyMetric
+	selfCollector
+}
+
+// Observe records v, after checking v against the plausible range for the summary's declared
+// Unit (see KubeOpts.Unit and assertPlausibleValue). The check is compiled out of non-debug
+// builds.
+func (s *Summary) Observe(v float64) {
+	assertPlausibleValue(s.SummaryOpts.Name, s.SummaryOpts.Unit, v)
+	s.ObserverMetric.Observe(v)
+}
+
+// NewSummary returns an object which satisfies the KubeCollector and ObserverMetric interfaces.
+// However, the object returned will not measure anything unless the collector is first
+// registered, since the metric is lazily instantiated.
+func NewSummary(opts *SummaryOpts) *Summary {
+	// todo: handle defaulting better
+	if opts.StabilityLevel == "" {
+		opts.StabilityLevel = ALPHA
+	}
+	ks := &Summary{
+		SummaryOpts: opts,
+		lazyMetric:  lazyMetric{},
+	}
+	ks.setPrometheusSummary(noop)
+	ks.lazyInit(ks)
+	return ks
+}
+
+// setPrometheusSummary sets the underlying ObserverMetric object, i.e. the thing that does the measurement.
+func (s *Summary) setPrometheusSummary(summary prometheus.Summary) {
+	s.ObserverMetric = summary
+	s.initSelfCollection(summary)
+}
+
+// DeprecatedVersion returns a pointer to the Version or nil
+func (s *Summary) DeprecatedVersion() *semver.Version {
+	return s.SummaryOpts.DeprecatedVersion
+}
+
+// StabilityLevel returns the metric's declared StabilityLevel.
+func (s *Summary) StabilityLevel() StabilityLevel {
+	return s.SummaryOpts.StabilityLevel
+}
+
+// initializeMetric invocation creates the actual underlying Summary. Until this method is called
+// the underlying summary is a no-op.
+func (s *Summary) initializeMetric() {
+	s.SummaryOpts.annotateStabilityLevel()
+	// this actually creates the underlying prometheus summary.
+	s.setPrometheusSummary(prometheus.NewSummary(s.SummaryOpts.toPromSummaryOpts()))
+}
+
+// initializeDeprecatedMetric invocation creates the actual (but deprecated) Summary. Until this method
+// is called the underlying summary is a no-op.
+func (s *Summary) initializeDeprecatedMetric() {
+	s.SummaryOpts.markDeprecated()
+	s.initializeMetric()
+}
+
+// SummaryVec is the internal representation of our wrapping struct around prometheus
+// summaryVecs. SummaryVec implements both KubeCollector and ObserverVecMetric.
+type SummaryVec struct {
+	*prometheus.SummaryVec
+	*SummaryOpts
+	lazyMetric
+	originalLabels []string
+	degradeState
+}
+
+// NewSummaryVec returns an object which satisfies the KubeCollector and ObserverVecMetric
+// interfaces. However, the object returned will not measure anything unless the collector is
+// first registered, since the metric is lazily instantiated.
+func NewSummaryVec(opts *SummaryOpts, labels []string) *SummaryVec {
+	sv := &SummaryVec{
+		SummaryVec:     noopSummaryVec,
+		SummaryOpts:    opts,
+		originalLabels: labels,
+		lazyMetric:     lazyMetric{},
+	}
+	sv.lazyInit(sv)
+	return sv
+}
+
+// DeprecatedVersion returns a pointer to the Version or nil
+func (v *SummaryVec) DeprecatedVersion() *semver.Version {
+	return v.SummaryOpts.DeprecatedVersion
+}
+
+// StabilityLevel returns the metric's declared StabilityLevel.
+func (v *SummaryVec) StabilityLevel() StabilityLevel {
+	return v.SummaryOpts.StabilityLevel
+}
+
+// initializeMetric invocation creates the actual underlying SummaryVec. Until this method is called
+// the underlying summaryVec is a no-op.
+func (v *SummaryVec) initializeMetric() {
+	v.SummaryVec = prometheus.NewSummaryVec(v.SummaryOpts.toPromSummaryOpts(), v.originalLabels)
+}
+
+// initializeDeprecatedMetric invocation creates the actual (but deprecated) SummaryVec. Until this method is called
+// the underlying summaryVec is a no-op.
+func (v *SummaryVec) initializeDeprecatedMetric() {
+	v.SummaryOpts.markDeprecated()
+	v.initializeMetric()
+}
+
+// WithLabelValues returns the Observer for the given slice of label
+// values (same order as the VariableLabels in Desc). If that combination of
+// label values is accessed for the first time, a new Summary is created IFF the summaryVec
+// has been registered to a metrics registry.
+func (v *SummaryVec) WithLabelValues(lvs ...string) ObserverMetric {
+	if !v.IsCreated() {
+		return noop // return no-op observer
+	}
+	lvs = constrainLabelValues(v.originalLabels, lvs, v.SummaryOpts.LabelValueAllowLists)
+	lvs = v.degradeLabelValues(v.originalLabels, lvs, v.SummaryOpts.DegradeLabel)
+	return &checkedObserverMetric{ObserverMetric: v.SummaryVec.WithLabelValues(lvs...), name: v.SummaryOpts.Name, unit: v.SummaryOpts.Unit}
+}
+
+// With returns the Observer for the given Labels map (the label names
+// must match those of the VariableLabels in Desc). If that label map is
+// accessed for the first time, a new Summary is created IFF the summaryVec has
+// been registered to a metrics registry.
+func (v *SummaryVec) With(labels prometheus.Labels) ObserverMetric {
+	if !v.IsCreated() {
+		return noop // return no-op observer
+	}
+	labels = constrainLabelMap(labels, v.SummaryOpts.LabelValueAllowLists)
+	labels = v.degradeLabelMap(labels, v.SummaryOpts.DegradeLabel)
+	return &checkedObserverMetric{ObserverMetric: v.SummaryVec.With(labels), name: v.SummaryOpts.Name, unit: v.SummaryOpts.Unit}
+}
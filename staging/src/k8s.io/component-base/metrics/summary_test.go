@@ -0,0 +1,71 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+	"testing"
+)
+
+func TestSummary(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	s := NewSummary(&SummaryOpts{
+		Name:           "metric_test_name",
+		Help:           "summary help",
+		StabilityLevel: ALPHA,
+	})
+	registry.MustRegister(s)
+	s.Observe(1)
+	s.Observe(2)
+
+	mfs, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed %v", err)
+	}
+	if len(mfs) != 1 {
+		t.Fatalf("Got %v metric families, want 1", len(mfs))
+	}
+	if mfs[0].GetHelp() != "[ALPHA] summary help" {
+		t.Errorf("Got %s as help message, want [ALPHA] summary help", mfs[0].GetHelp())
+	}
+	if got := mfs[0].GetMetric()[0].GetSummary().GetSampleCount(); got != 2 {
+		t.Errorf("Got %v samples, want 2", got)
+	}
+}
+
+func TestSummaryVec(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	v := NewSummaryVec(&SummaryOpts{
+		Name: "metric_test_name",
+		Help: "summary help",
+	}, []string{"label_a"})
+	registry.MustRegister(v)
+
+	v.WithLabelValues("1").Observe(1)
+	v.WithLabelValues("2").Observe(2)
+
+	mfs, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed %v", err)
+	}
+	if len(mfs) != 1 {
+		t.Fatalf("Got %v metric families, want 1", len(mfs))
+	}
+	if len(mfs[0].GetMetric()) != 2 {
+		t.Errorf("Got %v metrics, want 2", len(mfs[0].GetMetric()))
+	}
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/klog"
+)
+
+// TimingHistogramOpts extends HistogramOpts with Resolution, the unit TimingHistogram quantizes
+// dwell time into. See there for doc comments on the embedded fields.
+type TimingHistogramOpts struct {
+	HistogramOpts
+	// Resolution is the unit TimingHistogram quantizes elapsed dwell time into before
+	// accumulating it into a bucket, since the underlying histogram exposition format only
+	// supports integer bucket counts (see TimingHistogram). Left at its zero value, it defaults
+	// to time.Second. A metric that changes value on the order of seconds is well served by the
+	// default; a burstier one may want a finer Resolution (e.g. time.Millisecond) so a brief
+	// dwell at a value is not rounded away to zero ticks.
+	Resolution time.Duration
+}
+
+// TimingHistogram integrates a value over time, weighted by how long the value was held, instead
+// of sampling it: Set(v) does not record an observation of v, it first credits however many
+// Resolution-sized ticks have elapsed since the previous Set call to the *previous* value, then
+// starts timing v. This measures something like in-flight request concurrency or a queue length
+// far more accurately than a plain Gauge scraped periodically can, since a Gauge misses every
+// spike and dip that happens between two scrapes.
+//
+// Dwell time is quantized to whole multiples of TimingHistogramOpts.Resolution because the
+// histogram exposition format (see client_model.Histogram, whose bucket counts are integers) has
+// no native representation for a fractional, duration-weighted observation; a TimingHistogram
+// therefore undercounts dwell time shorter than Resolution, and the "count" and "sum" it exposes
+// are in units of ticks and value*ticks rather than plain observations. TimingHistogram
+// implements KubeCollector; its Set method plays the same role a GaugeMetric's Set does, but it
+// is not itself a GaugeMetric, since it has no meaningful Inc/Dec/Add.
+type TimingHistogram struct {
+	*TimingHistogramOpts
+	lazyMetric
+
+	lock        sync.Mutex
+	buckets     []float64
+	bucketTicks []uint64
+	totalTicks  uint64
+	sumValue    float64
+	lastValue   float64
+	lastSet     time.Time
+	desc        *prometheus.Desc
+	now         func() time.Time
+}
+
+// NewTimingHistogram returns an object which satisfies the KubeCollector and GaugeMetric
+// interfaces. However, the object returned will not measure anything unless the collector is
+// first registered, since the metric is lazily instantiated. It panics if opts.Buckets fails
+// ValidateHistogramBuckets for opts.StabilityLevel.
+func NewTimingHistogram(opts *TimingHistogramOpts) *TimingHistogram {
+	if opts.StabilityLevel == "" {
+		opts.StabilityLevel = ALPHA
+	}
+	if err := ValidateHistogramBuckets(opts.StabilityLevel, opts.Buckets); err != nil {
+		panic(err)
+	}
+	if opts.Resolution <= 0 {
+		opts.Resolution = time.Second
+	}
+	th := &TimingHistogram{
+		TimingHistogramOpts: opts,
+		lazyMetric:          lazyMetric{},
+		now:                 time.Now,
+	}
+	th.lazyInit(th)
+	return th
+}
+
+// Set records that the value being timed is now v, after first crediting however many
+// Resolution-sized ticks have elapsed since the last Set call to whatever value was previously
+// set. It is a no-op until the TimingHistogram is registered and created.
+func (h *TimingHistogram) Set(v float64) {
+	assertPlausibleValue(h.Name, h.Unit, v)
+	if !h.IsCreated() {
+		return
+	}
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.accumulate(h.now())
+	h.lastValue = v
+}
+
+// accumulate credits h.lastValue with however many whole Resolution ticks have elapsed since
+// h.lastSet, then advances h.lastSet by exactly that many ticks, leaving any leftover fractional
+// duration to accumulate towards the next tick rather than discarding it. Must be called with
+// h.lock held.
+func (h *TimingHistogram) accumulate(now time.Time) {
+	ticks := uint64(now.Sub(h.lastSet) / h.Resolution)
+	if ticks == 0 {
+		return
+	}
+	h.totalTicks += ticks
+	h.sumValue += h.lastValue * float64(ticks)
+	for i, upper := range h.buckets {
+		if h.lastValue <= upper {
+			h.bucketTicks[i] += ticks
+		}
+	}
+	h.lastSet = h.lastSet.Add(time.Duration(ticks) * h.Resolution)
+}
+
+// DeprecatedVersion returns a pointer to the Version or nil
+func (h *TimingHistogram) DeprecatedVersion() *semver.Version {
+	return h.HistogramOpts.DeprecatedVersion
+}
+
+// StabilityLevel returns the metric's declared StabilityLevel.
+func (h *TimingHistogram) StabilityLevel() StabilityLevel {
+	return h.HistogramOpts.StabilityLevel
+}
+
+// initializeMetric invocation prepares the TimingHistogram to start accumulating dwell time.
+// Until this method is called, Set is a no-op.
+func (h *TimingHistogram) initializeMetric() {
+	h.HistogramOpts.annotateStabilityLevel()
+	h.desc = prometheus.NewDesc(
+		prometheus.BuildFQName(h.Namespace, h.Subsystem, h.Name),
+		h.Help,
+		nil,
+		h.ConstLabels,
+	)
+	h.buckets = append([]float64(nil), h.Buckets...)
+	h.bucketTicks = make([]uint64, len(h.buckets))
+	h.lastSet = h.now()
+}
+
+// initializeDeprecatedMetric invocation prepares the (but deprecated) TimingHistogram. Until this
+// method is called, Set is a no-op.
+func (h *TimingHistogram) initializeDeprecatedMetric() {
+	h.HistogramOpts.markDeprecated()
+	h.initializeMetric()
+}
+
+// Describe implements prometheus.Collector.
+func (h *TimingHistogram) Describe(ch chan<- *prometheus.Desc) {
+	if !h.IsCreated() {
+		return
+	}
+	ch <- h.desc
+}
+
+// Collect implements prometheus.Collector, first crediting the value currently being timed with
+// its dwell time up to now, then exposing the accumulated ticks as a standard histogram whose
+// "count" and "sum" are in units of ticks and value*ticks rather than plain observations. See
+// TimingHistogram.
+func (h *TimingHistogram) Collect(ch chan<- prometheus.Metric) {
+	if !h.IsCreated() {
+		return
+	}
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.accumulate(h.now())
+	buckets := make(map[float64]uint64, len(h.buckets))
+	for i, upper := range h.buckets {
+		buckets[upper] = h.bucketTicks[i]
+	}
+	metric, err := prometheus.NewConstHistogram(h.desc, h.totalTicks, h.sumValue, buckets)
+	if err != nil {
+		klog.Errorf("failed to construct timing histogram %q: %v", h.Name, err)
+		return
+	}
+	ch <- metric
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+func TestTimingHistogram(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	current := time.Unix(0, 0)
+	th := NewTimingHistogram(&TimingHistogramOpts{
+		HistogramOpts: HistogramOpts{
+			Name:           "metric_test_name",
+			Help:           "timing histogram help",
+			StabilityLevel: ALPHA,
+			Buckets:        []float64{1, 2, 4},
+		},
+		Resolution: time.Second,
+	})
+	registry.MustRegister(th)
+	th.now = func() time.Time { return current }
+
+	th.Set(1) // starts timing value 1 at t=0s
+	current = current.Add(3 * time.Second)
+	th.Set(3) // credits 3 ticks to value 1, starts timing value 3 at t=3s
+	current = current.Add(2 * time.Second)
+	// value 3 has been held for 2 more ticks by the time Gather calls Collect
+
+	mfs, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed %v", err)
+	}
+	if len(mfs) != 1 {
+		t.Fatalf("Got %v metric families, want 1", len(mfs))
+	}
+	hist := mfs[0].GetMetric()[0].GetHistogram()
+	if got, want := hist.GetSampleCount(), uint64(5); got != want {
+		t.Errorf("Got %v total ticks, want %v", got, want)
+	}
+	if got, want := hist.GetSampleSum(), 1*3.0+3*2.0; got != want {
+		t.Errorf("Got %v weighted sum, want %v", got, want)
+	}
+	for _, b := range hist.GetBucket() {
+		if b.GetUpperBound() == 1 {
+			if got, want := b.GetCumulativeCount(), uint64(3); got != want {
+				t.Errorf("Got %v ticks in the <=1 bucket, want %v", got, want)
+			}
+		}
+	}
+}
+
+func TestTimingHistogramQuantizesSubResolutionDwell(t *testing.T) {
+	registry := NewKubeRegistry(apimachineryversion.Info{GitVersion: "v1.15.0"})
+	current := time.Unix(0, 0)
+	th := NewTimingHistogram(&TimingHistogramOpts{
+		HistogramOpts: HistogramOpts{
+			Name:           "metric_test_name",
+			Help:           "timing histogram help",
+			StabilityLevel: ALPHA,
+			Buckets:        []float64{1, 2, 4},
+		},
+		Resolution: time.Second,
+	})
+	registry.MustRegister(th)
+	th.now = func() time.Time { return current }
+
+	th.Set(1)
+	current = current.Add(500 * time.Millisecond)
+	th.Set(2) // less than a full Resolution elapsed, so no ticks are credited to value 1
+
+	mfs, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed %v", err)
+	}
+	if got, want := mfs[0].GetMetric()[0].GetHistogram().GetSampleCount(), uint64(0); got != want {
+		t.Errorf("Got %v ticks, want %v", got, want)
+	}
+}
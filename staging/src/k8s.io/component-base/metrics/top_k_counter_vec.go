@@ -0,0 +1,139 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// topKOtherValue is the value TopKCounterVec substitutes for traceLabel once a label
+// combination has fallen out of (or never made it into) the tracked top K.
+const topKOtherValue = "other"
+
+// TopKCounterVec wraps a CounterVec whose traceLabel dimension comes from a naturally
+// unbounded domain (e.g. resource names or webhook names), while its remaining labels
+// come from a small, known domain. Rather than letting every distinct traceLabel value
+// grow the metric's cardinality forever, it tracks approximate per-combination totals
+// with the Space-Saving streaming top-K algorithm and only ever creates series for the K
+// heaviest combinations it is currently tracking, folding everything else into a shared
+// traceLabel="other" bucket for the same combination of the remaining labels.
+type TopKCounterVec struct {
+	vec *CounterVec
+	k   int
+
+	traceLabelIndex int
+
+	mu      sync.Mutex
+	entries map[string]*topKEntry
+}
+
+// topKEntry is one tracked label combination's Space-Saving bookkeeping.
+type topKEntry struct {
+	lvs   []string
+	count int64
+}
+
+// NewTopKCounterVec returns a TopKCounterVec that tracks the k heaviest combinations of
+// labels, as measured by observation count, for each distinct combination of the labels
+// other than traceLabel. traceLabel must be one of labels.
+func NewTopKCounterVec(opts *CounterOpts, labels []string, traceLabel string, k int) *TopKCounterVec {
+	traceLabelIndex := -1
+	for i, l := range labels {
+		if l == traceLabel {
+			traceLabelIndex = i
+			break
+		}
+	}
+	if traceLabelIndex < 0 {
+		panic(fmt.Sprintf("metrics: traceLabel %q is not one of %v", traceLabel, labels))
+	}
+	return &TopKCounterVec{
+		vec:             NewCounterVec(opts, labels),
+		k:               k,
+		traceLabelIndex: traceLabelIndex,
+		entries:         make(map[string]*topKEntry, k),
+	}
+}
+
+// Registerables returns the collectors that must be registered for this TopKCounterVec
+// to be exposed and tracked: just the underlying vector.
+func (v *TopKCounterVec) Registerables() []KubeCollector {
+	return []KubeCollector{v.vec}
+}
+
+// Inc records one observation for lvs, running one step of the Space-Saving algorithm
+// to decide whether lvs earns its own tracked series or is folded into the "other"
+// bucket for its combination of the labels other than traceLabel.
+func (v *TopKCounterVec) Inc(lvs ...string) {
+	key := strings.Join(lvs, "\xff")
+
+	v.mu.Lock()
+	if e, ok := v.entries[key]; ok {
+		e.count++
+		v.mu.Unlock()
+		v.vec.WithLabelValues(lvs...).Inc()
+		return
+	}
+
+	if len(v.entries) < v.k {
+		v.entries[key] = &topKEntry{lvs: lvs, count: 1}
+		v.mu.Unlock()
+		v.vec.WithLabelValues(lvs...).Inc()
+		return
+	}
+
+	minKey, min := v.minEntry()
+	delete(v.entries, minKey)
+	v.entries[key] = &topKEntry{lvs: lvs, count: min.count + 1}
+	v.mu.Unlock()
+
+	// The evicted combination's accumulated count moves into the shared "other" bucket
+	// for its remaining labels rather than disappearing, so the metric's total stays
+	// accurate even as which combinations are tracked keeps rotating.
+	v.vec.WithLabelValues(otherLabelValues(min.lvs, v.traceLabelIndex)...).Add(float64(min.count))
+	v.vec.vec().DeleteLabelValues(min.lvs...)
+
+	// The newcomer inherits the evicted combination's count as its Space-Saving error
+	// bound (the most its true count could have been undercounted by), then this
+	// observation is counted on top of that.
+	newSeries := v.vec.WithLabelValues(lvs...)
+	newSeries.Add(float64(min.count))
+	newSeries.Inc()
+}
+
+// minEntry returns the key and entry with the smallest count, the candidate the
+// Space-Saving algorithm evicts to make room for a newly observed combination.
+func (v *TopKCounterVec) minEntry() (string, *topKEntry) {
+	var minKey string
+	var min *topKEntry
+	for key, e := range v.entries {
+		if min == nil || e.count < min.count {
+			minKey, min = key, e
+		}
+	}
+	return minKey, min
+}
+
+// otherLabelValues returns a copy of lvs with the traceLabelIndex position replaced by
+// topKOtherValue.
+func otherLabelValues(lvs []string, traceLabelIndex int) []string {
+	rewritten := append([]string(nil), lvs...)
+	rewritten[traceLabelIndex] = topKOtherValue
+	return rewritten
+}
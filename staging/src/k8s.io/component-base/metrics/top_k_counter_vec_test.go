@@ -0,0 +1,80 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+)
+
+func TestTopKCounterVec(t *testing.T) {
+	v := NewTopKCounterVec(&CounterOpts{
+		Name: "test_topk_counter",
+		Help: "help",
+	}, []string{"namespace", "resource"}, "resource", 2)
+
+	registry := NewKubeRegistry(apimachineryversion.Info{
+		Major: "1",
+		Minor: "15",
+	})
+	registry.MustRegister(v.Registerables()...)
+
+	v.Inc("ns", "widgets")
+	v.Inc("ns", "widgets")
+	v.Inc("ns", "gadgets")
+	// "widgets" and "gadgets" fill the two tracked slots; "gizmos" must evict one of
+	// them since the tracker is bounded to k=2.
+	v.Inc("ns", "gizmos")
+
+	if len(v.entries) != 2 {
+		t.Fatalf("expected 2 tracked combinations, got %d", len(v.entries))
+	}
+	if _, ok := v.entries["ns\xffgadgets"]; ok {
+		t.Errorf("expected combination 'ns/gadgets' to have been evicted as the lightest tracked entry")
+	}
+
+	metricFamilies, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := map[string]float64{}
+	for _, mf := range metricFamilies {
+		if mf.GetName() != "test_topk_counter" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			var resource string
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "resource" {
+					resource = l.GetValue()
+				}
+			}
+			values[resource] = m.GetCounter().GetValue()
+		}
+	}
+
+	if values["widgets"] != 2 {
+		t.Errorf("expected 'widgets' to have count 2, got %v", values["widgets"])
+	}
+	if values["gizmos"] != 2 {
+		t.Errorf("expected 'gizmos' to inherit the evicted entry's count of 1 plus its own observation, got %v", values["gizmos"])
+	}
+	if values["other"] != 1 {
+		t.Errorf("expected the evicted 'gadgets' observation to have moved to 'other', got %v", values["other"])
+	}
+}
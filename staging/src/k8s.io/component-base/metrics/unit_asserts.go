@@ -0,0 +1,46 @@
+// +build debug
+
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"k8s.io/klog"
+)
+
+// implausibleSecondsValue is a value no legitimate short-lived duration metric should ever
+// observe; it is far more likely to be a millisecond (or smaller) value mistakenly recorded into
+// a metric documented to be in seconds.
+const implausibleSecondsValue = 1e6
+
+// assertPlausibleValue warns (it never fails a build) when a value recorded against a metric
+// declaring the given Unit falls well outside that unit's plausible range, which is almost always
+// a sign of a unit-conversion bug (e.g. milliseconds recorded where seconds were expected) rather
+// than a legitimately extreme observation. It is compiled in only for binaries built with the
+// "debug" build tag, so it costs nothing in production builds.
+func assertPlausibleValue(name string, unit Unit, value float64) {
+	switch unit {
+	case Seconds:
+		if value >= implausibleSecondsValue {
+			klog.Errorf("metric %q declares Unit %q but observed implausible value %v; check for a millisecond-vs-second conversion bug", name, unit, value)
+		}
+	case Ratio:
+		if value < 0 || value > 1 {
+			klog.Errorf("metric %q declares Unit %q but observed value %v outside of [0, 1]", name, unit, value)
+		}
+	}
+}
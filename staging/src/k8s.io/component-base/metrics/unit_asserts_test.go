@@ -0,0 +1,44 @@
+// +build debug
+
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "testing"
+
+func TestAssertPlausibleValue(t *testing.T) {
+	// assertPlausibleValue only logs; it never panics or returns an error, so this test exists to
+	// exercise every branch and guard against a panic being introduced later (e.g. a nil map
+	// lookup added to the switch).
+	tests := []struct {
+		name  string
+		unit  Unit
+		value float64
+	}{
+		{"no unit", "", 1e9},
+		{"plausible seconds", Seconds, 1.5},
+		{"implausible seconds", Seconds, 1500},
+		{"plausible ratio", Ratio, 0.5},
+		{"implausible ratio", Ratio, 42},
+		{"bytes has no plausibility range", Bytes, 1e12},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assertPlausibleValue(test.name, test.unit, test.value)
+		})
+	}
+}
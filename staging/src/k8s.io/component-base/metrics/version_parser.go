@@ -31,7 +31,10 @@ var (
 	versionRe = regexp.MustCompile(versionRegexpString)
 )
 
-func parseVersion(ver apimachineryversion.Info) semver.Version {
+// ParseVersion extracts the major.minor.patch semver.Version out of ver's GitVersion. It is
+// exported so that a component can parse its own binary version once, up front, to pass to both
+// NewKubeRegistry and options.MetricsOptions.Validate.
+func ParseVersion(ver apimachineryversion.Info) semver.Version {
 	matches := versionRe.FindAllStringSubmatch(ver.String(), -1)
 
 	if len(matches) != 1 {
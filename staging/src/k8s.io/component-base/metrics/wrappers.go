@@ -56,6 +56,33 @@ type CounterVecMetric interface {
 	With(prometheus.Labels) CounterMetric
 }
 
+// GaugeMetric is an interface which defines a subset of the interface provided by prometheus.Gauge
+type GaugeMetric interface {
+	Set(float64)
+	Inc()
+	Dec()
+	Add(float64)
+}
+
+// GaugeVecMetric is an interface which prometheus.GaugeVec satisfies.
+type GaugeVecMetric interface {
+	WithLabelValues(...string) GaugeMetric
+	With(prometheus.Labels) GaugeMetric
+}
+
+// ObserverMetric captures individual observations, as does prometheus.Observer; it is satisfied
+// by both prometheus.Histogram and prometheus.Summary.
+type ObserverMetric interface {
+	Observe(float64)
+}
+
+// ObserverVecMetric is an interface which both prometheus.HistogramVec and prometheus.SummaryVec
+// satisfy.
+type ObserverVecMetric interface {
+	WithLabelValues(...string) ObserverMetric
+	With(prometheus.Labels) ObserverMetric
+}
+
 // PromRegistry is an interface which implements a subset of prometheus.Registerer and
 // prometheus.Gatherer interfaces
 type PromRegistry interface {
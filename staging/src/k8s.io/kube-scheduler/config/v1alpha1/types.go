@@ -68,6 +68,12 @@ type KubeSchedulerConfiguration struct {
 	// DisablePreemption disables the pod preemption feature.
 	DisablePreemption bool `json:"disablePreemption"`
 
+	// PreemptionSystemOnly restricts preemption so that only pods with a system priority
+	// (system-cluster-critical, system-node-critical, or higher) can trigger it. Pods scheduled
+	// at ordinary priorities are still eligible to be preempted, but never trigger preemption
+	// themselves. Has no effect when DisablePreemption is true.
+	PreemptionSystemOnly bool `json:"preemptionSystemOnly"`
+
 	// PercentageOfNodeToScore is the percentage of all nodes that once found feasible
 	// for running a pod, the scheduler stops its search for more feasible nodes in
 	// the cluster. This helps improve scheduler's performance. Scheduler always tries to find
@@ -82,6 +88,40 @@ type KubeSchedulerConfiguration struct {
 	// Value must be non-negative integer. The value zero indicates no waiting.
 	// If this value is nil, the default value will be used.
 	BindTimeoutSeconds *int64 `json:"bindTimeoutSeconds"`
+
+	// ProvisioningBacklogThreshold is the number of currently pending pods with strictly higher
+	// priority than a given pod, above which dynamic PV provisioning for that pod's unbound PVCs
+	// is delayed for the scheduling cycle rather than triggered immediately. This avoids expensive
+	// volume churn for low-priority pods that are unlikely to run soon, either because they will be
+	// queued behind the backlog or because they will be preempted. A value of 0 disables the check.
+	ProvisioningBacklogThreshold int32 `json:"provisioningBacklogThreshold"`
+
+	// MinVictimPriorityDelta is the minimum amount by which a preemption victim's priority must
+	// be lower than the preemptor's priority for the victim to be eligible for eviction. This
+	// prevents preemption churn between classes with adjacent priority values. A value of 0
+	// preserves the default behavior of evicting any pod with strictly lower priority.
+	MinVictimPriorityDelta int32 `json:"minVictimPriorityDelta"`
+
+	// PreemptionWaitSecondsByPriorityClass maps a PriorityClassName to the maximum number of
+	// seconds preemption will prefer waiting for a pod of that class to finish on its own, based
+	// on its estimated remaining runtime, over evicting it. A PriorityClassName with no entry is
+	// unaffected.
+	PreemptionWaitSecondsByPriorityClass map[string]int32 `json:"preemptionWaitSecondsByPriorityClass"`
+
+	// MaxPreemptedPodsByPriorityClass maps a PriorityClassName to the maximum number of pods of
+	// that class preemption may evict on a single node during a single preemption attempt. A
+	// PriorityClassName with no entry is unbounded.
+	MaxPreemptedPodsByPriorityClass map[string]int32 `json:"maxPreemptedPodsByPriorityClass"`
+
+	// PodPriorityAgingWindowSeconds is, when the PodPriorityAging feature gate is enabled, the
+	// number of seconds a pod must wait in the scheduling queue for its effective priority to
+	// increase by one aging step. A value of 0 disables aging even when the feature gate is
+	// enabled.
+	PodPriorityAgingWindowSeconds int64 `json:"podPriorityAgingWindowSeconds"`
+
+	// PodPriorityAgingMaxBoost caps the total number of aging steps a pod's effective priority
+	// can accumulate no matter how long it waits.
+	PodPriorityAgingMaxBoost int32 `json:"podPriorityAgingMaxBoost"`
 }
 
 // SchedulerAlgorithmSource is the source of a scheduler algorithm. One source
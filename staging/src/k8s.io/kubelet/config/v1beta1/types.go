@@ -396,6 +396,10 @@ type KubeletConfiguration struct {
 	// +optional
 	CgroupDriver string `json:"cgroupDriver,omitempty"`
 	// CPUManagerPolicy is the name of the policy to use.
+	// Possible values: "none", "static", "static-priority".
+	// "static-priority" behaves like "static", additionally preferring sockets already used by
+	// higher-priority Guaranteed pods over introducing a lower-priority pod to a new one, so a
+	// wave of low-priority pods cannot claim every socket before a high-priority pod arrives.
 	// Requires the CPUManager feature gate to be enabled.
 	// Dynamic Kubelet Config (beta): This field should not be updated without a full node
 	// reboot. It is safest to keep this value the same as the local config.
@@ -718,6 +722,36 @@ type KubeletConfiguration struct {
 	// Default: ["pods"]
 	// +optional
 	EnforceNodeAllocatable []string `json:"enforceNodeAllocatable,omitempty"`
+	// PriorityBandwidthShaping maps pod priority to a network bandwidth class that is applied
+	// when a pod does not already request explicit bandwidth via the
+	// kubernetes.io/{ingress,egress}-bandwidth annotations. Only network plugins that support
+	// bandwidth shaping (currently kubenet) honor this setting. Classes are matched by the
+	// highest configured threshold that is greater than or equal to the pod's priority; a pod
+	// whose priority exceeds every configured threshold is left unshaped.
+	// +optional
+	PriorityBandwidthShaping []PriorityBandwidthClass `json:"priorityBandwidthShaping,omitempty"`
+	// nonCriticalRestartPriorityThreshold, if set, marks pods whose priority is below it as
+	// non-critical for the purposes of CrashLoopBackOff restart deferral: while the node reports
+	// memory, disk, or PID pressure, such a pod's failed container is held in backoff for
+	// nonCriticalRestartBackOffUnderPressure instead of the normal exponential backoff, so
+	// repeated restart attempts do not add to the pressure that is already causing the node
+	// trouble. Pods at or above the threshold are restarted as if the node were not under
+	// pressure. Leave unset to disable this behavior.
+	// +optional
+	NonCriticalRestartPriorityThreshold *int32 `json:"nonCriticalRestartPriorityThreshold,omitempty"`
+	// nonCriticalRestartBackOffUnderPressure is the backoff period applied, in place of the
+	// normal exponential CrashLoopBackOff period, to a non-critical pod's restarts while the
+	// node is under pressure. Has no effect unless nonCriticalRestartPriorityThreshold is set.
+	// +optional
+	NonCriticalRestartBackOffUnderPressure metav1.Duration `json:"nonCriticalRestartBackOffUnderPressure,omitempty"`
+}
+
+// PriorityBandwidthClass caps egress bandwidth for pods at or below a given priority.
+type PriorityBandwidthClass struct {
+	// threshold is the highest pod priority this class applies to.
+	Threshold int32 `json:"threshold"`
+	// egressBandwidth is the egress bandwidth limit applied to pods in this class, e.g. "10M".
+	EgressBandwidth string `json:"egressBandwidth"`
 }
 
 type KubeletAuthorizationMode string
@@ -0,0 +1,235 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	schedulerapi "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/kubernetes/pkg/apis/scheduling"
+	"k8s.io/kubernetes/test/e2e/framework"
+	e2elog "k8s.io/kubernetes/test/e2e/framework/log"
+)
+
+// This file is a conformance-style companion to preemption.go and resource_quota.go: rather than
+// exercising any one feature in depth, it walks the priority admission and scheduling contract
+// end to end (defaulting, namespace restrictions on system PriorityClasses, preemption ordering,
+// and quota interaction) in one gated suite, so a distribution that changes admission or
+// scheduling has a single place to check it hasn't broken priority semantics a workload can rely
+// on.
+var _ = SIGDescribe("PrioritySemantics [Feature:PodPriority] [Serial]", func() {
+	var cs clientset.Interface
+	var ns string
+	f := framework.NewDefaultFramework("sched-priority-semantics")
+
+	BeforeEach(func() {
+		cs = f.ClientSet
+		ns = f.Namespace.Name
+	})
+
+	It("should default a pod's priority to the zero value when no default PriorityClass exists", func() {
+		pod := createPausePod(f, pausePodConfig{Name: "priority-semantics-no-default"})
+		defer cs.CoreV1().Pods(ns).Delete(pod.Name, metav1.NewDeleteOptions(0))
+
+		Expect(pod.Spec.Priority).NotTo(BeNil())
+		Expect(*pod.Spec.Priority).To(Equal(scheduling.DefaultPriorityWhenNoDefaultClassExists))
+	})
+
+	It("should default a pod's priority from the namespace's GlobalDefault PriorityClass", func() {
+		defaultClassName := f.BaseName + "-global-default"
+		defaultValue := int32(1000)
+		_, err := cs.SchedulingV1().PriorityClasses().Create(&schedulerapi.PriorityClass{
+			ObjectMeta:    metav1.ObjectMeta{Name: defaultClassName},
+			Value:         defaultValue,
+			GlobalDefault: true,
+		})
+		framework.ExpectNoError(err)
+		defer cs.SchedulingV1().PriorityClasses().Delete(defaultClassName, metav1.NewDeleteOptions(0))
+
+		pod := createPausePod(f, pausePodConfig{Name: "priority-semantics-global-default"})
+		defer cs.CoreV1().Pods(ns).Delete(pod.Name, metav1.NewDeleteOptions(0))
+
+		Expect(pod.Spec.PriorityClassName).To(Equal(defaultClassName))
+		Expect(pod.Spec.Priority).NotTo(BeNil())
+		Expect(*pod.Spec.Priority).To(Equal(defaultValue))
+	})
+
+	It("should reject a pod using a system PriorityClass outside the kube-system namespace", func() {
+		_, err := cs.CoreV1().Pods(ns).Create(initPausePod(f, pausePodConfig{
+			Name:              "priority-semantics-system-forbidden",
+			PriorityClassName: scheduling.SystemClusterCritical,
+		}))
+		Expect(err).To(HaveOccurred())
+		Expect(errors.IsForbidden(err)).To(BeTrue())
+	})
+
+	It("should admit a pod using a system PriorityClass inside the kube-system namespace", func() {
+		pod := createPausePod(f, pausePodConfig{
+			Name:              "priority-semantics-system-permitted",
+			Namespace:         metav1.NamespaceSystem,
+			PriorityClassName: scheduling.SystemClusterCritical,
+		})
+		defer cs.CoreV1().Pods(metav1.NamespaceSystem).Delete(pod.Name, metav1.NewDeleteOptions(0))
+
+		Expect(pod.Spec.Priority).NotTo(BeNil())
+		Expect(*pod.Spec.Priority).To(Equal(scheduling.SystemCriticalPriority))
+	})
+
+	It("should preempt a lower priority pod to schedule a higher priority pod", func() {
+		nodeList, err := cs.CoreV1().Nodes().List(metav1.ListOptions{})
+		framework.ExpectNoError(err)
+		if len(nodeList.Items) == 0 {
+			framework.Skipf("no nodes available for preemption test")
+		}
+
+		lowPriorityClassName := f.BaseName + "-preempt-low"
+		highPriorityClassName := f.BaseName + "-preempt-high"
+		for _, pair := range []struct {
+			name  string
+			value int32
+		}{{lowPriorityClassName, 1}, {highPriorityClassName, 1000000}} {
+			_, err := cs.SchedulingV1().PriorityClasses().Create(&schedulerapi.PriorityClass{
+				ObjectMeta: metav1.ObjectMeta{Name: pair.name},
+				Value:      pair.value,
+			})
+			framework.ExpectNoError(err)
+			defer cs.SchedulingV1().PriorityClasses().Delete(pair.name, metav1.NewDeleteOptions(0))
+		}
+
+		node := nodeList.Items[0]
+		allocatable := node.Status.Allocatable[corev1.ResourceCPU]
+		requestCPU := allocatable.DeepCopy()
+
+		lowPod := createPausePod(f, pausePodConfig{
+			Name:              "priority-semantics-victim",
+			PriorityClassName: lowPriorityClassName,
+			NodeName:          node.Name,
+			Resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: requestCPU},
+			},
+		})
+		defer cs.CoreV1().Pods(ns).Delete(lowPod.Name, metav1.NewDeleteOptions(0))
+		framework.ExpectNoError(framework.WaitForPodRunningInNamespace(cs, lowPod))
+
+		highPod := createPausePod(f, pausePodConfig{
+			Name:              "priority-semantics-preemptor",
+			PriorityClassName: highPriorityClassName,
+			NodeName:          node.Name,
+			Resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: requestCPU},
+			},
+		})
+		defer cs.CoreV1().Pods(ns).Delete(highPod.Name, metav1.NewDeleteOptions(0))
+
+		framework.ExpectNoError(wait.Poll(2*time.Second, framework.PodStartTimeout, func() (bool, error) {
+			victim, err := cs.CoreV1().Pods(ns).Get(lowPod.Name, metav1.GetOptions{})
+			if errors.IsNotFound(err) {
+				return true, nil
+			}
+			if err != nil {
+				return false, err
+			}
+			if victim.DeletionTimestamp != nil {
+				return true, nil
+			}
+			return false, nil
+		}), "expected the lower priority pod to be preempted")
+
+		framework.ExpectNoError(framework.WaitForPodRunningInNamespace(cs, highPod))
+	})
+
+	It("should only count pods matching a PriorityClass-scoped ResourceQuota", func() {
+		priorityClassName := f.BaseName + "-quota-scoped"
+		_, err := cs.SchedulingV1().PriorityClasses().Create(&schedulerapi.PriorityClass{
+			ObjectMeta: metav1.ObjectMeta{Name: priorityClassName},
+			Value:      1000,
+		})
+		framework.ExpectNoError(err)
+		defer cs.SchedulingV1().PriorityClasses().Delete(priorityClassName, metav1.NewDeleteOptions(0))
+
+		quota := &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "priority-semantics-quota"},
+			Spec: corev1.ResourceQuotaSpec{
+				Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("1")},
+				ScopeSelector: &corev1.ScopeSelector{
+					MatchExpressions: []corev1.ScopedResourceSelectorRequirement{
+						{
+							ScopeName: corev1.ResourceQuotaScopePriorityClass,
+							Operator:  corev1.ScopeSelectorOpIn,
+							Values:    []string{priorityClassName},
+						},
+					},
+				},
+			},
+		}
+		quota, err = cs.CoreV1().ResourceQuotas(ns).Create(quota)
+		framework.ExpectNoError(err)
+		defer cs.CoreV1().ResourceQuotas(ns).Delete(quota.Name, nil)
+
+		unscoped := createPausePod(f, pausePodConfig{Name: "priority-semantics-quota-unscoped"})
+		defer cs.CoreV1().Pods(ns).Delete(unscoped.Name, metav1.NewDeleteOptions(0))
+
+		err = wait.Poll(framework.Poll, framework.PodStartTimeout, func() (bool, error) {
+			q, err := cs.CoreV1().ResourceQuotas(ns).Get(quota.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			used, ok := q.Status.Used[corev1.ResourcePods]
+			if !ok {
+				return false, nil
+			}
+			return used.Cmp(resource.MustParse("0")) == 0, nil
+		})
+		framework.ExpectNoError(err, "unscoped pod should not have counted against the PriorityClass-scoped quota")
+
+		scoped := createPausePod(f, pausePodConfig{
+			Name:              "priority-semantics-quota-scoped",
+			PriorityClassName: priorityClassName,
+		})
+		defer cs.CoreV1().Pods(ns).Delete(scoped.Name, metav1.NewDeleteOptions(0))
+
+		err = wait.Poll(framework.Poll, framework.PodStartTimeout, func() (bool, error) {
+			q, err := cs.CoreV1().ResourceQuotas(ns).Get(quota.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			used, ok := q.Status.Used[corev1.ResourcePods]
+			if !ok {
+				return false, nil
+			}
+			return used.Cmp(resource.MustParse("1")) == 0, nil
+		})
+		framework.ExpectNoError(err, "pod using the scoped PriorityClass should have counted against the quota")
+
+		_, err = cs.CoreV1().Pods(ns).Create(initPausePod(f, pausePodConfig{
+			Name:              "priority-semantics-quota-over",
+			PriorityClassName: priorityClassName,
+		}))
+		Expect(err).To(HaveOccurred(), fmt.Sprintf("expected a second %v pod to be rejected once the quota's hard limit of 1 was reached", priorityClassName))
+		e2elog.Logf("second scoped pod correctly rejected: %v", err)
+	})
+})
@@ -251,6 +251,7 @@ priorities: []
 			informerFactory.Core().V1().ReplicationControllers(),
 			informerFactory.Apps().V1().ReplicaSets(),
 			informerFactory.Apps().V1().StatefulSets(),
+			informerFactory.Apps().V1().DaemonSets(),
 			informerFactory.Core().V1().Services(),
 			informerFactory.Policy().V1beta1().PodDisruptionBudgets(),
 			informerFactory.Storage().V1().StorageClasses(),
@@ -320,6 +321,7 @@ func TestSchedulerCreationFromNonExistentConfigMap(t *testing.T) {
 		informerFactory.Core().V1().ReplicationControllers(),
 		informerFactory.Apps().V1().ReplicaSets(),
 		informerFactory.Apps().V1().StatefulSets(),
+		informerFactory.Apps().V1().DaemonSets(),
 		informerFactory.Core().V1().Services(),
 		informerFactory.Policy().V1beta1().PodDisruptionBudgets(),
 		informerFactory.Storage().V1().StorageClasses(),